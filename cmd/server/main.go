@@ -46,6 +46,12 @@ func main() {
 
 	container.AlertSvc.Start()
 
+	container.EscalationSvc.Start()
+
+	container.ReminderSvc.Start()
+
+	container.SnoozeSvc.Start()
+
 	log.Info().Msg("all svc initialized")
 
 	router := app.NewRouter(container)
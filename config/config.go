@@ -44,12 +44,12 @@ func setDefaults(v *viper.Viper) {
 
 	// Auth
 	v.SetDefault("auth.secret", "my_secret")
-	v.SetDefault("auth.token_ttl", "30m")
+	v.SetDefault("auth.token_ttl", "15m")
+	v.SetDefault("auth.refresh_ttl", "720h")
 
 	// App channels
 	v.SetDefault("app.job_channel_size", 500)
 	v.SetDefault("app.result_channel_size", 500)
-	v.SetDefault("app.alert_channel_size", 500)
 	v.SetDefault("app.app_url", "http://localhost:3000")
 
 	// Scheduler
@@ -60,8 +60,35 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("executor.worker_count", 20)
 	v.SetDefault("executor.http_semaphore_count", 100)
 
+	// Monitor
+	v.SetDefault("monitor.check_rate_limit", 10)
+	v.SetDefault("monitor.check_rate_window", "1m")
+	v.SetDefault("monitor.revision_retention", "720h")
+
 	// Alert
 	v.SetDefault("alert.worker_count", 10)
+	v.SetDefault("alert.poll_interval", "5s")
+	v.SetDefault("alert.batch_size", 20)
+	v.SetDefault("alert.max_attempts", 8)
+	v.SetDefault("alert.base_backoff", "30s")
+	v.SetDefault("alert.max_backoff", "30m")
+	v.SetDefault("alert.claim_lease", "2m")
+	v.SetDefault("alert.group_window", "30s")
+	v.SetDefault("alert.group_max_size", 50)
+	v.SetDefault("alert.rate_limit", 20)
+	v.SetDefault("alert.rate_window", "1m")
+
+	// Escalation
+	v.SetDefault("escalation.poll_interval", "10s")
+	v.SetDefault("escalation.batch_size", 50)
+
+	// Reminder
+	v.SetDefault("reminder.poll_interval", "30s")
+	v.SetDefault("reminder.batch_size", 50)
+
+	// Snooze
+	v.SetDefault("snooze.poll_interval", "30s")
+	v.SetDefault("snooze.batch_size", 50)
 
 	// Result Processor
 	v.SetDefault("result_processor.success_worker_count", 10)
@@ -11,7 +11,11 @@ type Config struct {
 	App             AppConfig             `mapstructure:"app" validate:"required"`
 	Scheduler       SchedulerConfig       `mapstructure:"scheduler" validate:"required"`
 	Executor        ExecutorConfig        `mapstructure:"executor" validate:"required"`
+	Monitor         MonitorConfig         `mapstructure:"monitor" validate:"required"`
 	Alert           AlertConfig           `mapstructure:"alert" validate:"required"`
+	Escalation      EscalationConfig      `mapstructure:"escalation" validate:"required"`
+	Reminder        ReminderConfig        `mapstructure:"reminder" validate:"required"`
+	Snooze          SnoozeConfig          `mapstructure:"snooze" validate:"required"`
 	ResultProcessor ResultProcessorConfig `mapstructure:"result_processor" validate:"required"`
 	Redis           RedisConfig           `mapstructure:"redis" validate:"required"`
 	DB              DBConfig              `mapstructure:"db" validate:"required"`
@@ -20,12 +24,13 @@ type Config struct {
 type AuthConfig struct {
 	Secret   string        `mapstructure:"secret" validate:"required"`
 	TokenTTL time.Duration `mapstructure:"token_ttl" validate:"required"`
+	// RefreshTTL is how long a session lasts without being refreshed.
+	RefreshTTL time.Duration `mapstructure:"refresh_ttl" validate:"required"`
 }
 
 type AppConfig struct {
 	JobChannelSize    int    `mapstructure:"job_channel_size" validate:"gte=100,lte=5000"`
 	ResultChannelSize int    `mapstructure:"result_channel_size" validate:"gte=100,lte=5000"`
-	AlertChannelSize  int    `mapstructure:"alert_channel_size" validate:"gte=100,lte=5000"`
 	AppURL            string `mapstructure:"app_url"`
 }
 
@@ -39,8 +44,51 @@ type ExecutorConfig struct {
 	HTTPSemCount int `mapstructure:"http_semaphore_count" validate:"gte=5,lte=6000"`
 }
 
+type MonitorConfig struct {
+	// CheckRateLimit caps the on-demand checks a team can run in each
+	// CheckRateWindow. 0 means unlimited.
+	CheckRateLimit  int           `mapstructure:"check_rate_limit" validate:"gte=0"`
+	CheckRateWindow time.Duration `mapstructure:"check_rate_window" validate:"gte=1s"`
+	// RevisionRetention is how long a deleted monitor's history is kept,
+	// and so how long it can be restored.
+	RevisionRetention time.Duration `mapstructure:"revision_retention" validate:"gte=1h"`
+}
+
 type AlertConfig struct {
-	WorkerCount int `mapstructure:"worker_count" validate:"gte=5"`
+	WorkerCount  int           `mapstructure:"worker_count" validate:"gte=5"`
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"gt=0"`
+	BatchSize    int           `mapstructure:"batch_size" validate:"gt=0"`
+	MaxAttempts  int           `mapstructure:"max_attempts" validate:"gte=1"`
+	BaseBackoff  time.Duration `mapstructure:"base_backoff" validate:"gt=0"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff" validate:"gtefield=BaseBackoff"`
+	// ClaimLease is renewed before every send and must outlast the
+	// longest one, a 30s digest.
+	ClaimLease time.Duration `mapstructure:"claim_lease" validate:"gte=1m"`
+	// GroupWindow starts when an alert goes out; alerts for the same plugin
+	// instance and type that follow within it are held and go out as one
+	// digest. 0 disables grouping.
+	GroupWindow  time.Duration `mapstructure:"group_window" validate:"gte=0"`
+	GroupMaxSize int           `mapstructure:"group_max_size" validate:"gte=2"`
+	// RateLimit caps notifications per team and channel in each RateWindow;
+	// ChannelRateLimits overrides it per channel. 0 means unlimited.
+	RateLimit         int            `mapstructure:"rate_limit" validate:"gte=0"`
+	RateWindow        time.Duration  `mapstructure:"rate_window" validate:"gte=1s"`
+	ChannelRateLimits map[string]int `mapstructure:"channel_rate_limits" validate:"dive,gte=0"`
+}
+
+type EscalationConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"gte=1s"`
+	BatchSize    int           `mapstructure:"batch_size" validate:"gt=0"`
+}
+
+type ReminderConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"gte=1s"`
+	BatchSize    int           `mapstructure:"batch_size" validate:"gt=0"`
+}
+
+type SnoozeConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"gte=1s"`
+	BatchSize    int           `mapstructure:"batch_size" validate:"gt=0"`
 }
 
 type ResultProcessorConfig struct {
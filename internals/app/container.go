@@ -6,14 +6,21 @@ import (
 	"github.com/alkush-pipania/sofon/config"
 	middle "github.com/alkush-pipania/sofon/internals/middleware"
 	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/apitoken"
+	"github.com/alkush-pipania/sofon/internals/modules/audit"
+	"github.com/alkush-pipania/sofon/internals/modules/escalation"
 	"github.com/alkush-pipania/sofon/internals/modules/executor"
 	"github.com/alkush-pipania/sofon/internals/modules/incident"
 	"github.com/alkush-pipania/sofon/internals/modules/monitor"
 	"github.com/alkush-pipania/sofon/internals/modules/plugin"
+	"github.com/alkush-pipania/sofon/internals/modules/reminder"
 	"github.com/alkush-pipania/sofon/internals/modules/result"
+	"github.com/alkush-pipania/sofon/internals/modules/routing"
 	"github.com/alkush-pipania/sofon/internals/modules/scheduler"
+	"github.com/alkush-pipania/sofon/internals/modules/snooze"
 	"github.com/alkush-pipania/sofon/internals/modules/team"
 	"github.com/alkush-pipania/sofon/internals/modules/user"
+	"github.com/alkush-pipania/sofon/internals/modules/webhook"
 	"github.com/alkush-pipania/sofon/internals/security"
 	"github.com/alkush-pipania/sofon/pkg/crypto"
 	"github.com/alkush-pipania/sofon/pkg/redis"
@@ -23,24 +30,33 @@ import (
 )
 
 type Container struct {
-	DB              *pgxpool.Pool
-	RedisClient     redis.Client
-	Logger          *zerolog.Logger
-	userSvc         *user.Service
-	userHandler     *user.Handler
-	incidentHandler *incident.Handler
-	monitorHandler  *monitor.Handler
-	teamHandler     *team.Handler
-	pluginHandler   *plugin.Handler
-	authMW          *middle.AuthMiddleware
-	teamAccessMW    *middle.TeamAccessMiddleware
-	Scheduler       *scheduler.Scheduler
-	Executor        *executor.Executor
-	ResultPro       *result.ResultProcessor
-	AlertSvc        *alert.AlertService
-	JobChan         chan scheduler.JobPayload
-	ResultChan      chan executor.HTTPResult
-	AlertChan       chan alert.AlertEvent
+	DB                *pgxpool.Pool
+	RedisClient       redis.Client
+	Logger            *zerolog.Logger
+	userSvc           *user.Service
+	userHandler       *user.Handler
+	incidentHandler   *incident.Handler
+	monitorHandler    *monitor.Handler
+	teamHandler       *team.Handler
+	pluginHandler     *plugin.Handler
+	alertHandler      *alert.Handler
+	escalationHandler *escalation.Handler
+	routingHandler    *routing.Handler
+	webhookHandler    *webhook.Handler
+	auditHandler      *audit.Handler
+	apiTokenHandler   *apitoken.Handler
+	authMW            *middle.AuthMiddleware
+	teamAccessMW      *middle.TeamAccessMiddleware
+	auditMW           *middle.AuditMiddleware
+	Scheduler         *scheduler.Scheduler
+	Executor          *executor.Executor
+	ResultPro         *result.ResultProcessor
+	AlertSvc          *alert.AlertService
+	EscalationSvc     *escalation.Service
+	ReminderSvc       *reminder.Service
+	SnoozeSvc         *snooze.Service
+	JobChan           chan scheduler.JobPayload
+	ResultChan        chan executor.HTTPResult
 }
 
 func NewContainer(ctx context.Context, cfg *config.Config, logger *zerolog.Logger, db *pgxpool.Pool) (*Container, error) {
@@ -53,7 +69,6 @@ func NewContainer(ctx context.Context, cfg *config.Config, logger *zerolog.Logge
 
 	jobChan := make(chan scheduler.JobPayload, cfg.App.JobChannelSize)
 	resultChan := make(chan executor.HTTPResult, cfg.App.ResultChannelSize)
-	alertChan := make(chan alert.AlertEvent, cfg.App.AlertChannelSize)
 
 	monitorRepo := monitor.NewRepository(db, logger)
 	monitorIncidentRepo := result.NewMonitorIncidentRepo(db, logger)
@@ -62,49 +77,102 @@ func NewContainer(ctx context.Context, cfg *config.Config, logger *zerolog.Logge
 	enc := crypto.New(cfg.Auth.Secret)
 
 	userService := user.NewService(userRepo, tokenSvc)
-	monitorSvc := monitor.NewService(monitorRepo, redisClient, userService, logger)
-	incidentAPIRepo := incident.NewRepository(db, logger)
-	incidentSvc := incident.NewService(incidentAPIRepo)
+	monitorSvc := monitor.NewService(&cfg.Monitor, monitorRepo, redisClient, userService, logger)
+
+	notifiers := alert.NewRegistry(
+		alert.NewResendNotifier(),
+		alert.NewZendutyNotifier(),
+		alert.NewMSTeamsNotifier(),
+		alert.NewDiscordNotifier(),
+		alert.NewTelegramNotifier(),
+	)
 
 	pluginRepo := plugin.NewRepository(db, enc, logger)
-	pluginSvc := plugin.NewService(pluginRepo)
+
+	incidentAPIRepo := incident.NewRepository(db, logger)
+	timeline := incident.NewTimeline(incidentAPIRepo, logger)
+
+	routingRepo := routing.NewRepository(db, logger)
+	routingSvc := routing.NewService(routingRepo, notifiers, pluginRepo, logger)
+
+	alertRepo := alert.NewRepository(db, logger)
+	alertSvc := alert.NewAlertService(&cfg.Alert, alertRepo, notifiers, pluginRepo, redisClient, redisClient, timeline, routingSvc, logger)
+
+	monitorSvc.SetChannelChecker(alertSvc)
+
+	escalationRepo := escalation.NewRepository(db, logger)
+	escalationSvc := escalation.NewService(&cfg.Escalation, escalationRepo, alertSvc, redisClient, timeline, logger)
+
+	reminderRepo := reminder.NewRepository(db, logger)
+	reminderSvc := reminder.NewService(&cfg.Reminder, reminderRepo, alertSvc, timeline, logger)
+
+	snoozeRepo := snooze.NewRepository(db, logger)
+	snoozeSvc := snooze.NewService(&cfg.Snooze, snoozeRepo, alertSvc, logger)
+
+	incidentSvc := incident.NewService(incidentAPIRepo, timeline, escalationSvc, logger)
+
+	pluginSvc := plugin.NewService(pluginRepo, notifiers, redisClient, alertSvc)
 	pluginHandler := plugin.NewHandler(pluginSvc, logger)
 
+	webhookSvc := webhook.NewService(pluginRepo, incidentSvc, logger)
+	webhookHandler := webhook.NewHandler(webhookSvc, logger)
+
 	sch := scheduler.NewScheduler(ctx, &cfg.Scheduler, jobChan, redisClient, logger)
 	exec := executor.NewExecutor(ctx, &cfg.Executor, jobChan, resultChan, monitorSvc, logger)
-	resultPro := result.NewResultProcessor(ctx, &cfg.ResultProcessor, redisClient, resultChan, monitorIncidentRepo, monitorSvc, alertChan, logger)
-	alertSvc := alert.NewAlertService(&cfg.Alert, db, pluginRepo, redisClient, alertChan, logger)
+	monitorSvc.SetChecker(exec)
+	resultPro := result.NewResultProcessor(ctx, &cfg.ResultProcessor, redisClient, resultChan, monitorIncidentRepo, monitorSvc, alertSvc, escalationSvc, timeline, logger)
+
+	auditRepo := audit.NewRepository(db, logger)
+	auditSvc := audit.NewService(auditRepo, logger)
+
+	apiTokenRepo := apitoken.NewRepository(db, logger)
+	apiTokenSvc := apitoken.NewService(apiTokenRepo, logger)
 
 	teamRepo := team.NewRepository(db, logger)
-	teamSvc := team.NewService(teamRepo, cfg.App.AppURL)
+	teamSvc := team.NewService(teamRepo, cfg.App.AppURL, userService)
 
 	monitorHandler := monitor.NewHandler(monitorSvc, v, logger)
 	userHandler := user.NewHandler(userService, v, logger)
 	incidentHandler := incident.NewHandler(incidentSvc, logger)
+	alertHandler := alert.NewHandler(alertSvc, logger)
+	escalationHandler := escalation.NewHandler(escalationSvc, logger)
+	routingHandler := routing.NewHandler(routingSvc, logger)
 	teamHandler := team.NewHandler(teamSvc, v, logger)
+	auditHandler := audit.NewHandler(auditSvc, logger)
+	apiTokenHandler := apitoken.NewHandler(apiTokenSvc, v, logger)
 
-	authMW := middle.NewAuthMiddleware(tokenSvc, userService)
+	authMW := middle.NewAuthMiddleware(tokenSvc, userService, apiTokenSvc)
 	teamAccessMW := middle.NewTeamAccess(teamSvc)
+	auditMW := middle.NewAudit(auditSvc)
 
 	return &Container{
-		RedisClient:     *redisClient,
-		Logger:          logger,
-		DB:              db,
-		userSvc:         userService,
-		userHandler:     userHandler,
-		incidentHandler: incidentHandler,
-		authMW:          authMW,
-		teamAccessMW:    teamAccessMW,
-		monitorHandler:  monitorHandler,
-		teamHandler:     teamHandler,
-		pluginHandler:   pluginHandler,
-		Scheduler:       sch,
-		Executor:        exec,
-		ResultPro:       resultPro,
-		AlertSvc:        alertSvc,
-		JobChan:         jobChan,
-		ResultChan:      resultChan,
-		AlertChan:       alertChan,
+		RedisClient:       *redisClient,
+		Logger:            logger,
+		DB:                db,
+		userSvc:           userService,
+		userHandler:       userHandler,
+		incidentHandler:   incidentHandler,
+		authMW:            authMW,
+		teamAccessMW:      teamAccessMW,
+		auditMW:           auditMW,
+		monitorHandler:    monitorHandler,
+		teamHandler:       teamHandler,
+		pluginHandler:     pluginHandler,
+		alertHandler:      alertHandler,
+		escalationHandler: escalationHandler,
+		routingHandler:    routingHandler,
+		webhookHandler:    webhookHandler,
+		auditHandler:      auditHandler,
+		apiTokenHandler:   apiTokenHandler,
+		Scheduler:         sch,
+		Executor:          exec,
+		ResultPro:         resultPro,
+		AlertSvc:          alertSvc,
+		EscalationSvc:     escalationSvc,
+		ReminderSvc:       reminderSvc,
+		SnoozeSvc:         snoozeSvc,
+		JobChan:           jobChan,
+		ResultChan:        resultChan,
 	}, nil
 }
 
@@ -117,7 +185,17 @@ func (c *Container) Shutdown() error {
 
 	c.ResultPro.WorkersClosingWait()
 
-	close(c.AlertChan)
+	// escalations, reminders and snooze notices send alerts, so they stop
+	// before the alert workers
+	c.EscalationSvc.Stop()
+	c.ReminderSvc.Stop()
+	c.SnoozeSvc.Stop()
+
+	c.EscalationSvc.WorkerClosingWait()
+	c.ReminderSvc.WorkerClosingWait()
+	c.SnoozeSvc.WorkerClosingWait()
+
+	c.AlertSvc.Stop()
 
 	c.AlertSvc.WorkerClosingWait()
 
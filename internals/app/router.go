@@ -3,11 +3,18 @@ package app
 import (
 	"net/http"
 
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/apitoken"
+	"github.com/alkush-pipania/sofon/internals/modules/audit"
+	"github.com/alkush-pipania/sofon/internals/modules/escalation"
 	"github.com/alkush-pipania/sofon/internals/modules/incident"
 	"github.com/alkush-pipania/sofon/internals/modules/monitor"
 	"github.com/alkush-pipania/sofon/internals/modules/plugin"
+	"github.com/alkush-pipania/sofon/internals/modules/routing"
 	"github.com/alkush-pipania/sofon/internals/modules/team"
 	"github.com/alkush-pipania/sofon/internals/modules/user"
+	"github.com/alkush-pipania/sofon/internals/modules/webhook"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
@@ -16,6 +23,8 @@ import (
 func NewRouter(container *Container) http.Handler {
 	r := chi.NewRouter()
 
+	r.Use(middle.RequestID)
+	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 
@@ -39,16 +48,45 @@ func NewRouter(container *Container) http.Handler {
 	})
 
 	r.Route("/api/v1", func(v1 chi.Router) {
-		v1.Mount("/users", user.Routes(container.userHandler, container.authMW))
+		v1.Mount("/users", user.Routes(
+			container.userHandler,
+			container.authMW,
+			func(r chi.Router) { r.Mount("/tokens", apitoken.Routes(container.apiTokenHandler)) },
+		))
+		v1.Mount("/webhooks", webhook.Routes(container.webhookHandler))
 
-		// Teams: list/create at root; team-scoped resources under /{teamID}
+		// Teams: list/create at root; team-scoped resources under /{teamID}.
+		// API tokens only reach the resources mounted with RequireScope.
+		monitorScope := middle.RequireScope(apitoken.ScopeMonitorsRead, apitoken.ScopeMonitorsWrite)
 		v1.Mount("/teams", team.Routes(
 			container.teamHandler,
 			container.authMW,
 			container.teamAccessMW,
-			func(r chi.Router) { r.Mount("/monitors", monitor.Routes(container.monitorHandler)) },
-			func(r chi.Router) { r.Mount("/incidents", incident.Routes(container.incidentHandler)) },
-			func(r chi.Router) { r.Mount("/plugins", plugin.Routes(container.pluginHandler)) },
+			container.auditMW,
+			func(r chi.Router) { r.With(monitorScope).Mount("/monitors", monitor.Routes(container.monitorHandler)) },
+			func(r chi.Router) {
+				r.With(monitorScope).Mount("/monitor-groups", monitor.GroupRoutes(container.monitorHandler))
+			},
+			func(r chi.Router) {
+				r.With(middle.RequireScope(apitoken.ScopeIncidentsRead, "")).Mount("/incidents", incident.Routes(
+					container.incidentHandler,
+					alert.IncidentRoutes(container.alertHandler),
+					escalation.IncidentRoutes(container.escalationHandler),
+				))
+			},
+			func(r chi.Router) { r.Mount("/alerts", alert.Routes(container.alertHandler)) },
+			func(r chi.Router) { r.Mount("/alert-templates", alert.TemplateRoutes(container.alertHandler)) },
+			func(r chi.Router) {
+				r.With(middle.RequireScope("", apitoken.ScopePluginsWrite)).Mount("/plugins", plugin.Routes(container.pluginHandler))
+			},
+			func(r chi.Router) { r.Mount("/escalation-policies", escalation.Routes(container.escalationHandler)) },
+			func(r chi.Router) { r.Mount("/routing-rules", routing.Routes(container.routingHandler)) },
+			func(r chi.Router) {
+				r.With(middle.RequireTeamRole(team.RoleOwner, team.RoleAdmin)).Mount("/audit", audit.Routes(container.auditHandler))
+			},
+			func(r chi.Router) {
+				r.With(middle.RequireTeamRole(team.RoleOwner, team.RoleAdmin)).Mount("/tokens", apitoken.TeamRoutes(container.apiTokenHandler))
+			},
 		))
 	})
 
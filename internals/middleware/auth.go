@@ -27,17 +27,22 @@ var userCtxKey = userCtxKeyType{}
 // ActiveChecker is implemented by the user service to verify a user is still active.
 type ActiveChecker interface {
 	IsUserActive(ctx context.Context, userID uuid.UUID) (bool, error)
+	// IsSessionActive reports whether the session an access token was
+	// issued for has neither ended nor been revoked.
+	IsSessionActive(ctx context.Context, sessionID, userID uuid.UUID) (bool, error)
 }
 
 type AuthMiddleware struct {
-	tokenSvc     *security.TokenService
+	tokenSvc      *security.TokenService
 	activeChecker ActiveChecker
+	apiTokens     APITokenAuthenticator
 }
 
-func NewAuthMiddleware(tokenSvc *security.TokenService, activeChecker ActiveChecker) *AuthMiddleware {
+func NewAuthMiddleware(tokenSvc *security.TokenService, activeChecker ActiveChecker, apiTokens APITokenAuthenticator) *AuthMiddleware {
 	return &AuthMiddleware{
-		tokenSvc:     tokenSvc,
+		tokenSvc:      tokenSvc,
 		activeChecker: activeChecker,
+		apiTokens:     apiTokens,
 	}
 }
 
@@ -50,10 +55,22 @@ func (a *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
-		claims, err := a.tokenSvc.ValidateAccessToken(token)
-		if err != nil {
-			utils.WriteError(w, http.StatusUnauthorized, "", apperror.Unauthorised, "invalid or expired token")
-			return
+		// API tokens are accepted alongside JWTs, but only on routes that
+		// grant one of their scopes (see RequireScope)
+		var apiToken *APIToken
+		var claims *security.RequestClaims
+		if strings.HasPrefix(token, APITokenPrefix) {
+			apiToken, claims, err = a.apiTokens.AuthenticateAPIToken(r.Context(), token)
+			if err != nil {
+				utils.WriteError(w, http.StatusUnauthorized, "", apperror.Unauthorised, "invalid, expired or revoked API token")
+				return
+			}
+		} else {
+			claims, err = a.tokenSvc.ValidateAccessToken(token)
+			if err != nil {
+				utils.WriteError(w, http.StatusUnauthorized, "", apperror.Unauthorised, "invalid or expired token")
+				return
+			}
 		}
 
 		if claims.UserID == "" || claims.Email == "" {
@@ -73,7 +90,25 @@ func (a *AuthMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
+		// an access token dies with its session (logout, revocation,
+		// password change), not only when it expires
+		if apiToken == nil {
+			sessionID, err := uuid.Parse(claims.SessionID)
+			if err != nil {
+				utils.WriteError(w, http.StatusUnauthorized, "", apperror.Unauthorised, "invalid or expired token")
+				return
+			}
+			live, err := a.activeChecker.IsSessionActive(r.Context(), sessionID, userID)
+			if err != nil || !live {
+				utils.WriteError(w, http.StatusUnauthorized, "", apperror.Unauthorised, "your session has ended, please sign in again")
+				return
+			}
+		}
+
 		ctx := context.WithValue(r.Context(), userCtxKey, claims)
+		if apiToken != nil {
+			ctx = context.WithValue(ctx, apiTokenCtxKey, apiToken)
+		}
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 
@@ -95,7 +130,16 @@ func (_ *AuthMiddleware) extractBearerToken(r *http.Request) (string, error) {
 	return parts[1], nil
 }
 
+// UserFromContext returns the authenticated user. A request made with an
+// API token has none until a route grants one of the token's scopes.
 func UserFromContext(ctx context.Context) (*security.RequestClaims, bool) {
+	if ungrantedAPIToken(ctx) {
+		return nil, false
+	}
+	return claimsFromContext(ctx)
+}
+
+func claimsFromContext(ctx context.Context) (*security.RequestClaims, bool) {
 	claims, ok := ctx.Value(userCtxKey).(*security.RequestClaims)
 	return claims, ok
 }
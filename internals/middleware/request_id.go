@@ -4,17 +4,26 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/google/uuid"
 )
 
+// maxRequestIDLen bounds a client supplied X-Request-ID, which ends up in
+// logs and the audit log.
+const maxRequestIDLen = 128
+
+// RequestID tags each request with the caller's X-Request-ID, or a new one,
+// and echoes it back. It is stored under chi's key so middleware.GetReqID
+// finds it.
 func RequestID(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		requestID := r.Header.Get("X-Request-ID")
-		if requestID == "" {
+		if requestID == "" || len(requestID) > maxRequestIDLen {
 			requestID = uuid.NewString()
 		}
 
-		ctx := context.WithValue(r.Context(), "request_id", requestID)
+		w.Header().Set("X-Request-ID", requestID)
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
 
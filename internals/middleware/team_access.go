@@ -43,7 +43,8 @@ func (t *TeamAccessMiddleware) Handle(next http.Handler) http.Handler {
 		ctx := r.Context()
 		reqID := middleware.GetReqID(ctx)
 
-		claims, ok := UserFromContext(ctx)
+		// an API token's scopes are checked further down the route
+		claims, ok := claimsFromContext(ctx)
 		if !ok || claims == nil {
 			utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
 			return
@@ -62,6 +63,11 @@ func (t *TeamAccessMiddleware) Handle(next http.Handler) http.Handler {
 			return
 		}
 
+		if tok, ok := APITokenFromContext(ctx); ok && tok.TeamID != nil && *tok.TeamID != teamID {
+			utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "this API token belongs to another team")
+			return
+		}
+
 		member, err := t.teamSvc.GetMembership(ctx, userID, teamID)
 		if err != nil {
 			utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "you are not a member of this team")
@@ -77,7 +83,12 @@ func (t *TeamAccessMiddleware) Handle(next http.Handler) http.Handler {
 	})
 }
 
+// TeamMemberFromContext returns the caller's membership. Like
+// UserFromContext it finds none for an API token no route has granted.
 func TeamMemberFromContext(ctx context.Context) (*TeamMemberCtx, bool) {
+	if ungrantedAPIToken(ctx) {
+		return nil, false
+	}
 	m, ok := ctx.Value(teamMemberCtxKey).(*TeamMemberCtx)
 	return m, ok
 }
@@ -9,7 +9,8 @@ import (
 )
 
 // RequireTeamRole checks the caller's role within the team (from TeamMemberCtx)
-// against the allowed roles. Must run after TeamAccessMiddleware.
+// against the allowed roles. Must run after TeamAccessMiddleware. API tokens
+// only pass where RequireScope has granted them a scope first.
 func RequireTeamRole(allowed ...string) func(http.Handler) http.Handler {
 	set := make(map[string]struct{}, len(allowed))
 	for _, r := range allowed {
@@ -20,6 +21,11 @@ func RequireTeamRole(allowed ...string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			reqID := middleware.GetReqID(r.Context())
 
+			if ungrantedAPIToken(r.Context()) {
+				utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "API tokens can't be used for this request")
+				return
+			}
+
 			member, ok := TeamMemberFromContext(r.Context())
 			if !ok || member == nil {
 				utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
@@ -0,0 +1,118 @@
+package alert
+
+import (
+	"fmt"
+	"time"
+)
+
+// chatFact is a single label/value line shared by all chat notifiers.
+type chatFact struct {
+	Label string
+	Value string
+}
+
+// chatTemplates are the defaults shared by all chat notifiers: the subject
+// is the message title, the body a short description shown above the facts.
+func chatTemplates(htmlBody bool) Templates {
+	return Templates{
+		HTMLBody: htmlBody,
+		Defaults: map[AlertType]TemplateSet{
+			AlertTypeDown: {
+				Subject: "DOWN: {{ .MonitorName }} is down",
+				Body:    "{{ .MonitorName }} failed its health check{{ if .Reason }} ({{ .Reason }}){{ end }}.",
+			},
+			AlertTypeReminder: {
+				Subject: "STILL DOWN: {{ .MonitorName }} has been down for {{ .Downtime }}",
+				Body:    "{{ .MonitorName }} is still failing its health check{{ if .Reason }} ({{ .Reason }}){{ end }}. Reminder #{{ .ReminderCount }}.",
+			},
+			AlertTypeRecovered: {
+				Subject: "RECOVERED: {{ .MonitorName }} is back up",
+				Body:    "{{ .MonitorName }} is responding normally again.",
+			},
+			AlertTypeTest: {
+				Subject: "TEST: Sofon test notification (no monitor is affected)",
+				Body:    "This is a test notification sent from the Sofon plugin settings.",
+			},
+			AlertTypeSnoozeEnded: {
+				Subject: "SNOOZE ENDED: {{ .MonitorName }} is alerting again",
+				Body:    "The snooze on {{ .MonitorName }} has ended. Failures will open incidents and notify again.",
+			},
+		},
+		Digests: map[AlertType]TemplateSet{
+			AlertTypeDown: {
+				Subject: "DOWN: {{ .Count }} monitors are down",
+				Body:    "{{ .Count }} monitors failed their health checks at about the same time.",
+			},
+			AlertTypeReminder: {
+				Subject: "STILL DOWN: {{ .Count }} monitors are still down",
+				Body:    "These monitors are still failing their health checks.",
+			},
+			AlertTypeRecovered: {
+				Subject: "RECOVERED: {{ .Count }} monitors are back up",
+				Body:    "These monitors are responding normally again.",
+			},
+		},
+	}
+}
+
+// messageFacts returns the facts of a message: the details of a single
+// alert, or one line per monitor for a digest, capped at limit lines.
+func messageFacts(msg Message, limit int) []chatFact {
+	if len(msg.Group) == 0 {
+		return chatFacts(msg.Event)
+	}
+	facts := make([]chatFact, 0, min(len(msg.Group), limit))
+	for i, e := range msg.Group {
+		if i == limit-1 && len(msg.Group) > limit {
+			facts = append(facts, chatFact{Label: "…", Value: fmt.Sprintf("and %d more", len(msg.Group)-i)})
+			break
+		}
+		facts = append(facts, chatFact{Label: e.DisplayName(), Value: digestLine(e)})
+	}
+	return facts
+}
+
+// digestLine summarizes one alert of a digest.
+func digestLine(e AlertEvent) string {
+	line := fmt.Sprintf("HTTP %d, %d ms", e.StatusCode, e.LatencyMs)
+	if e.Type != AlertTypeRecovered && e.Reason != "" {
+		line = e.Reason + ", " + line
+	}
+	if e.DowntimeSec > 0 {
+		line += ", down for " + FormatDowntime(e.DowntimeSec)
+	}
+	return line
+}
+
+func chatFacts(event AlertEvent) []chatFact {
+	facts := make([]chatFact, 0, 10)
+	if event.GroupName != "" {
+		facts = append(facts, chatFact{Label: "Group", Value: event.GroupName})
+	}
+	if event.MonitorName != "" {
+		facts = append(facts, chatFact{Label: "Monitor", Value: event.MonitorName})
+	}
+	facts = append(facts, chatFact{Label: "URL", Value: event.MonitorURL})
+	if event.RunbookURL != "" {
+		facts = append(facts, chatFact{Label: "Runbook", Value: event.RunbookURL})
+	}
+	if event.Type == AlertTypeSnoozeEnded {
+		return append(facts, chatFact{Label: "Snoozed Until (UTC)", Value: event.CheckedAt.UTC().Format(time.RFC1123Z)})
+	}
+	if (event.Type == AlertTypeDown || event.Type == AlertTypeReminder) && event.Reason != "" {
+		facts = append(facts, chatFact{Label: "Reason", Value: event.Reason})
+	}
+	if event.DowntimeSec > 0 {
+		facts = append(facts, chatFact{Label: "Down For", Value: FormatDowntime(event.DowntimeSec)})
+	}
+	facts = append(facts,
+		chatFact{Label: "HTTP Status", Value: fmt.Sprintf("%d", event.StatusCode)},
+		chatFact{Label: "Latency", Value: fmt.Sprintf("%d ms", event.LatencyMs)},
+		chatFact{Label: "Checked At (UTC)", Value: event.CheckedAt.UTC().Format(time.RFC1123Z)},
+		chatFact{Label: "Incident ID", Value: event.IncidentID.String()},
+	)
+	if event.EscalationLevel > 0 {
+		facts = append(facts, chatFact{Label: "Escalation Level", Value: fmt.Sprintf("%d", event.EscalationLevel)})
+	}
+	return facts
+}
@@ -0,0 +1,50 @@
+package alert
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type cursorPayload struct {
+	CreatedAt string `json:"c"`
+	AlertID   string `json:"i"`
+}
+
+func EncodeCursor(c Cursor) (string, error) {
+	p := cursorPayload{
+		CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		AlertID:   c.AlertID,
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(v string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if p.CreatedAt == "" || p.AlertID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, p.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &Cursor{
+		CreatedAt: created,
+		AlertID:   p.AlertID,
+	}, nil
+}
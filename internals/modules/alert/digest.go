@@ -0,0 +1,317 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/google/uuid"
+)
+
+// groupKey identifies the alerts that can share a digest.
+type groupKey struct {
+	teamID    uuid.UUID
+	pluginID  uuid.UUID
+	channel   string
+	alertType AlertType
+}
+
+// claimedAlert is a claimed outbox row with its decoded payload.
+type claimedAlert struct {
+	entry OutboxEntry
+	event AlertEvent
+}
+
+// groupable reports whether an event may be held back and sent in a
+// digest. Alerts addressed by an escalation level always go out on their
+// own.
+func (s *AlertService) groupable(event AlertEvent) bool {
+	if s.groupWindow <= 0 || event.EscalationLevel > 0 || len(event.Recipients) > 0 {
+		return false
+	}
+	switch event.Type {
+	case AlertTypeDown, AlertTypeReminder, AlertTypeRecovered:
+		return true
+	}
+	return false
+}
+
+// groupHold returns how long a groupable alert waits before it is due.
+// The first one for a plugin instance and type opens a grouping window and
+// is sent right away, so a lone alert is never delayed; those that follow
+// within the window are held for it and go out together. If the window
+// can't be checked the alert is sent right away.
+func (s *AlertService) groupHold(ctx context.Context, pluginID uuid.UUID, alertType AlertType) time.Duration {
+	opened, err := s.rates.OpenAlertWindow(ctx, pluginID, string(alertType), s.groupWindow)
+	if err != nil {
+		s.logger.Error().Err(err).Str("plugin_id", pluginID.String()).Msg("failed to open alert grouping window, sending right away")
+		return 0
+	}
+	if opened {
+		return 0
+	}
+	return s.groupWindow
+}
+
+// deliverEntries delivers a claimed batch. Groupable rows are bundled by
+// plugin instance and type together with pending rows of the same key that
+// are due within the grouping window, and sent as digests of at most
+// GroupMaxSize alerts. The batch is sent one send at a time, so each send
+// renews its rows' claim first; a whole batch may take longer than one
+// lease.
+func (s *AlertService) deliverEntries(entries []OutboxEntry) {
+	var order []groupKey
+	groups := make(map[groupKey][]claimedAlert)
+
+	for i := range entries {
+		event, ok := s.decode(&entries[i])
+		if !ok {
+			continue
+		}
+		if !s.groupable(event) {
+			if held := s.renewClaims([]claimedAlert{{entry: entries[i], event: event}}); len(held) == 1 {
+				s.deliver(&held[0].entry, held[0].event)
+			}
+			continue
+		}
+		key := groupKey{teamID: event.TeamID, pluginID: entries[i].PluginID, channel: entries[i].Channel, alertType: event.Type}
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], claimedAlert{entry: entries[i], event: event})
+	}
+
+	for _, key := range order {
+		members := s.fillGroup(key, groups[key])
+		for len(members) > 0 {
+			n := min(len(members), s.groupMaxSize)
+			held := s.renewClaims(members[:n])
+			switch len(held) {
+			case 0:
+			case 1:
+				s.deliver(&held[0].entry, held[0].event)
+			default:
+				s.deliverGroup(key, held)
+			}
+			members = members[n:]
+		}
+	}
+}
+
+// renewClaims extends the claim on members just before they are sent and
+// returns those still held. Rows whose lease ran out and that another
+// worker claimed are dropped, so they aren't sent twice. When the claim
+// can't be renewed none are sent; they become due again once their lease
+// ends.
+func (s *AlertService) renewClaims(members []claimedAlert) []claimedAlert {
+	entries := make([]OutboxEntry, 0, len(members))
+	for _, m := range members {
+		entries = append(entries, m.entry)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	held, err := s.repo.ExtendLeases(ctx, entries, s.claimLease)
+	if err != nil {
+		s.logger.Error().Err(err).Int("alerts", len(members)).Msg("failed to renew alert claims")
+		return nil
+	}
+
+	out := make([]claimedAlert, 0, len(members))
+	for _, m := range members {
+		if held[m.entry.ID] {
+			out = append(out, m)
+			continue
+		}
+		s.logger.Warn().Str("alert_id", m.entry.ID.String()).Msg("alert claim lost to another worker, not sending")
+	}
+	return out
+}
+
+// fillGroup claims pending rows that can join the group, up to
+// GroupMaxSize members in total.
+func (s *AlertService) fillGroup(key groupKey, members []claimedAlert) []claimedAlert {
+	if len(members) >= s.groupMaxSize {
+		return members
+	}
+
+	exclude := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		exclude = append(exclude, m.entry.ID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	more, err := s.repo.ClaimGroup(ctx, key, exclude, s.groupWindow, s.groupMaxSize-len(members), s.claimLease)
+	if err != nil {
+		// the rows stay pending and are picked up on their own
+		s.logger.Error().Err(err).Str("plugin", key.channel).Msg("failed to claim alert group")
+		return members
+	}
+	for i := range more {
+		event, ok := s.decode(&more[i])
+		if !ok {
+			continue
+		}
+		members = append(members, claimedAlert{entry: more[i], event: event})
+	}
+	return members
+}
+
+// deliverGroup sends a digest for several alerts. The rows share the
+// outcome: all are marked sent together, or each is retried or
+// dead-lettered according to its own attempt count.
+func (s *AlertService) deliverGroup(key groupKey, members []claimedAlert) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	groupID := uuid.New()
+	ids := make([]uuid.UUID, 0, len(members))
+	events := make([]AlertEvent, 0, len(members))
+	for _, m := range members {
+		ids = append(ids, m.entry.ID)
+		events = append(events, m.event)
+	}
+
+	log := s.logger.With().
+		Str("group_id", groupID.String()).
+		Str("team_id", key.teamID.String()).
+		Str("plugin", key.channel).
+		Str("plugin_id", key.pluginID.String()).
+		Str("alert_type", string(key.alertType)).
+		Int("group_size", len(members)).
+		Logger()
+
+	n, ok := s.notifiers.Get(key.channel)
+	if !ok {
+		log.Error().Msg("unknown alert channel, dead-lettering")
+		for i := range members {
+			s.close(&members[i].entry, StatusDead, ErrUnknownNotifier.Error())
+		}
+		return
+	}
+
+	cfg, found, err := s.pluginConfig(ctx, key.teamID, key.pluginID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load plugin config")
+		s.retryGroup(ctx, members, err)
+		return
+	}
+	if !found {
+		log.Info().Msg("plugin removed or disabled since enqueue, skipping")
+		for i := range members {
+			s.close(&members[i].entry, StatusSkippedNoPlugin, "")
+		}
+		return
+	}
+
+	if s.rateLimited(ctx, key.teamID, key.channel, key.pluginID, ids) {
+		return
+	}
+
+	msg, err := s.renderDigest(n, events, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to render alert digest")
+		s.retryGroup(ctx, members, err)
+		return
+	}
+	s.attachDownGroups(ctx, &msg, key.pluginID)
+
+	delivery, err := n.Send(ctx, cfg, msg)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to send alert digest")
+		s.retryGroup(ctx, members, err)
+		return
+	}
+
+	if err := s.repo.MarkGroupSent(context.Background(), ids, groupID, delivery.Target); err != nil {
+		log.Error().Err(err).Msg("alert digest sent but failed to mark it sent")
+	}
+	log.Info().Str("external_id", delivery.ExternalID).Msg("alert digest sent")
+
+	for i := range members {
+		m := &members[i]
+		data := deliveryEventData(&m.entry, &m.event)
+		data["group_id"] = groupID.String()
+		data["group_size"] = len(members)
+		if delivery.Target != "" {
+			data["target"] = delivery.Target
+		}
+		s.timeline.Record(ctx, incident.Event{
+			IncidentID: m.entry.IncidentID,
+			Kind:       incident.EventAlertSent,
+			Message:    fmt.Sprintf("%s alert sent via %s in a digest of %d", m.event.Type, key.channel, len(members)),
+			Data:       data,
+		})
+	}
+}
+
+func (s *AlertService) retryGroup(ctx context.Context, members []claimedAlert, sendErr error) {
+	for i := range members {
+		s.retry(ctx, &members[i].entry, &members[i].event, sendErr)
+	}
+}
+
+// rateLimited counts one notification against the plugin instance and,
+// when the instance is over its plugin type's limit for the current window,
+// puts the claimed rows back until the next window, where they are
+// typically merged into a digest. Rate limiting fails open when Redis is
+// unavailable.
+func (s *AlertService) rateLimited(ctx context.Context, teamID uuid.UUID, channel string, pluginID uuid.UUID, ids []uuid.UUID) bool {
+	limit := s.rateLimit
+	if l, ok := s.channelRateLimits[channel]; ok {
+		limit = l
+	}
+	if limit <= 0 {
+		return false
+	}
+
+	windowStart := time.Now().Truncate(s.rateWindow)
+	count, err := s.rates.IncrementAlertRate(ctx, teamID, pluginID, windowStart, s.rateWindow)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("plugin", channel).Msg("failed to count alert rate, sending anyway")
+		return false
+	}
+	if count <= int64(limit) {
+		return false
+	}
+
+	until := windowStart.Add(s.rateWindow)
+	if err := s.repo.Defer(ctx, ids, until); err != nil {
+		// the claim lease still brings the rows back
+		s.logger.Error().Err(err).Str("plugin", channel).Msg("failed to defer rate limited alerts")
+	}
+	s.logger.Info().
+		Str("team_id", teamID.String()).
+		Str("plugin", channel).
+		Int("alerts", len(ids)).
+		Time("until", until).
+		Msg("alert channel rate limited, deferring")
+	return true
+}
+
+// attachDownGroups tells notifiers which reminders and recoveries belong
+// to incidents that were announced in a DOWN digest, so updates reach the
+// same place the digest went.
+func (s *AlertService) attachDownGroups(ctx context.Context, msg *Message, pluginID uuid.UUID) {
+	if msg.Event.Type != AlertTypeRecovered && msg.Event.Type != AlertTypeReminder {
+		return
+	}
+	for _, e := range msg.Events() {
+		dg, found, err := s.repo.DownGroup(ctx, e.IncidentID, pluginID)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("incident_id", e.IncidentID.String()).Msg("failed to look up alert digest")
+			continue
+		}
+		if !found {
+			continue
+		}
+		if msg.DownGroups == nil {
+			msg.DownGroups = make(map[uuid.UUID]DownGroup)
+		}
+		msg.DownGroups[e.IncidentID] = dg
+	}
+}
@@ -0,0 +1,96 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/discord"
+)
+
+const NotifierDiscord = "discord"
+
+const (
+	discordColorDown      = 0xdc2626
+	discordColorRecovered = 0x16a34a
+	discordColorTest      = 0x2563eb
+
+	// discordMaxFields is Discord's limit of fields per embed.
+	discordMaxFields = 25
+)
+
+type discordNotifier struct{}
+
+func NewDiscordNotifier() Notifier {
+	return discordNotifier{}
+}
+
+func (discordNotifier) Schema() Schema {
+	return Schema{
+		Type:        NotifierDiscord,
+		Name:        "Discord",
+		Description: "Post alert embeds to a Discord channel via a webhook.",
+		Category:    "Chat",
+		Fields: []ConfigField{
+			{Key: "webhook_url", Label: "Webhook URL", Placeholder: "https://discord.com/api/webhooks/…", Required: true, Secret: true},
+		},
+	}
+}
+
+func (discordNotifier) Templates() Templates {
+	return chatTemplates(false)
+}
+
+func (discordNotifier) Validate(cfg map[string]string) error {
+	u, err := url.Parse(strings.TrimSpace(cfg["webhook_url"]))
+	if err != nil || u.Scheme != "https" ||
+		(u.Host != "discord.com" && u.Host != "discordapp.com") ||
+		!strings.HasPrefix(u.Path, "/api/webhooks/") {
+		return errors.New("discord webhook_url must be a https://discord.com/api/webhooks/... URL")
+	}
+	return nil
+}
+
+func (discordNotifier) Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error) {
+	event := msg.Event
+	color := discordColorDown
+	switch event.Type {
+	case AlertTypeRecovered:
+		color = discordColorRecovered
+	case AlertTypeTest, AlertTypeSnoozeEnded:
+		color = discordColorTest
+	}
+
+	digest := len(msg.Group) > 0
+	url := event.MonitorURL
+	if digest {
+		url = ""
+	}
+
+	fields := make([]discord.EmbedField, 0, 6)
+	for _, f := range messageFacts(msg, discordMaxFields) {
+		fields = append(fields, discord.EmbedField{Name: f.Label, Value: f.Value, Inline: !digest && f.Label != "URL" && f.Label != "Runbook" && f.Label != "Reason"})
+	}
+
+	req := &discord.WebhookRequest{
+		Username: "Sofon",
+		Embeds: []discord.Embed{
+			{
+				Title:       msg.Subject,
+				Description: msg.Body,
+				URL:         url,
+				Color:       color,
+				Timestamp:   event.CheckedAt.UTC().Format(time.RFC3339),
+				Fields:      fields,
+			},
+		},
+	}
+
+	resp, err := discord.NewClient(cfg["webhook_url"]).Send(ctx, req)
+	if err != nil {
+		return Delivery{}, err
+	}
+	return Delivery{ExternalID: resp.ID}, nil
+}
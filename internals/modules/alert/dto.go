@@ -0,0 +1,74 @@
+package alert
+
+type AlertResponse struct {
+	ID            string  `json:"id"`
+	IncidentID    string  `json:"incident_id"`
+	MonitorID     string  `json:"monitor_id"`
+	MonitorURL    string  `json:"monitor_url"`
+	Channel       string  `json:"channel"`
+	PluginID      *string `json:"plugin_id,omitempty"`
+	PluginName    string  `json:"plugin_name,omitempty"`
+	AlertType     string  `json:"alert_type"`
+	Status        string  `json:"status"`
+	Recipient     string  `json:"recipient,omitempty"`
+	Attempts      int32   `json:"attempts"`
+	LastError     string  `json:"last_error,omitempty"`
+	NextAttemptAt *string `json:"next_attempt_at,omitempty"`
+	SentAt        *string `json:"sent_at,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+	GroupID       *string `json:"group_id,omitempty"`
+}
+
+type ListAlertsResponse struct {
+	Limit          int32           `json:"limit"`
+	HasMore        bool            `json:"has_more"`
+	NextCursor     *string         `json:"next_cursor,omitempty"`
+	AppliedFilters AppliedFilters  `json:"applied_filters"`
+	Alerts         []AlertResponse `json:"alerts"`
+}
+
+type AppliedFilters struct {
+	Channel    string  `json:"channel,omitempty"`
+	Status     string  `json:"status,omitempty"`
+	AlertType  string  `json:"alert_type,omitempty"`
+	MonitorID  *string `json:"monitor_id,omitempty"`
+	IncidentID *string `json:"incident_id,omitempty"`
+	From       *string `json:"from,omitempty"`
+	To         *string `json:"to,omitempty"`
+}
+
+type TemplateResponse struct {
+	Channel   string  `json:"channel"`
+	AlertType string  `json:"alert_type"`
+	Subject   string  `json:"subject"`
+	Body      string  `json:"body"`
+	HTMLBody  bool    `json:"html_body"`
+	Custom    bool    `json:"custom"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+type ListTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+type SaveTemplateRequest struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// PreviewTemplateRequest renders the given subject/body; omitted fields use
+// the team's current template for the channel.
+type PreviewTemplateRequest struct {
+	Channel   string  `json:"channel"`
+	AlertType string  `json:"alert_type"`
+	Subject   *string `json:"subject"`
+	Body      *string `json:"body"`
+}
+
+type PreviewTemplateResponse struct {
+	Channel   string `json:"channel"`
+	AlertType string `json:"alert_type"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
@@ -0,0 +1,245 @@
+package alert
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	service *AlertService
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *AlertService, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.list_alerts"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	opts, msg := h.parseListOptions(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	if incidentIDStr := strings.TrimSpace(r.URL.Query().Get("incident_id")); incidentIDStr != "" {
+		id, err := uuid.Parse(incidentIDStr)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident_id")
+			return
+		}
+		opts.Filters.IncidentID = &id
+	}
+
+	page, err := h.service.ListByTeamID(ctx, tm.TeamID, opts)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list alerts")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alerts retrieved", toListResponse(&page))
+}
+
+func (h *Handler) ListIncidentAlerts(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.list_incident_alerts"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	opts, msg := h.parseListOptions(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	page, err := h.service.ListByIncident(ctx, tm.TeamID, incidentID, opts)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list incident alerts")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alerts retrieved", toListResponse(&page))
+}
+
+// parseListOptions reads the query parameters shared by both list endpoints.
+// A non-empty message means the request is invalid.
+func (h *Handler) parseListOptions(r *http.Request) (ListAlertsOptions, string) {
+	q := r.URL.Query()
+	opts := ListAlertsOptions{Limit: 20}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		l, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || l <= 0 || l > 100 {
+			return opts, "invalid limit"
+		}
+		opts.Limit = int32(l)
+	}
+
+	if channel := strings.TrimSpace(strings.ToLower(q.Get("channel"))); channel != "" {
+		if !h.service.IsKnownChannel(channel) {
+			return opts, "invalid channel"
+		}
+		opts.Filters.Channel = channel
+	}
+
+	if status := strings.TrimSpace(strings.ToLower(q.Get("status"))); status != "" {
+		switch status {
+		case StatusPending, StatusSent, StatusFailed, StatusDead, StatusSkippedNoPlugin:
+		default:
+			return opts, "invalid status"
+		}
+		opts.Filters.Status = status
+	}
+
+	if alertType := strings.TrimSpace(strings.ToUpper(q.Get("alert_type"))); alertType != "" {
+		switch AlertType(alertType) {
+		case AlertTypeDown, AlertTypeReminder, AlertTypeRecovered:
+		default:
+			return opts, "invalid alert_type"
+		}
+		opts.Filters.AlertType = AlertType(alertType)
+	}
+
+	if monitorIDStr := strings.TrimSpace(q.Get("monitor_id")); monitorIDStr != "" {
+		id, err := uuid.Parse(monitorIDStr)
+		if err != nil {
+			return opts, "invalid monitor_id"
+		}
+		opts.Filters.MonitorID = &id
+	}
+
+	if fromStr := strings.TrimSpace(q.Get("from")); fromStr != "" {
+		t, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return opts, "invalid from"
+		}
+		opts.Filters.From = &t
+	}
+
+	if toStr := strings.TrimSpace(q.Get("to")); toStr != "" {
+		t, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return opts, "invalid to"
+		}
+		opts.Filters.To = &t
+	}
+
+	if opts.Filters.From != nil && opts.Filters.To != nil && opts.Filters.From.After(*opts.Filters.To) {
+		return opts, "`from` must be before `to`"
+	}
+
+	if cursorStr := strings.TrimSpace(q.Get("cursor")); cursorStr != "" {
+		decoded, err := DecodeCursor(cursorStr)
+		if err != nil {
+			return opts, "invalid cursor"
+		}
+		if _, err := uuid.Parse(decoded.AlertID); err != nil {
+			return opts, "invalid cursor"
+		}
+		opts.Cursor = decoded
+	}
+
+	return opts, ""
+}
+
+func toListResponse(page *ListAlertsPage) ListAlertsResponse {
+	items := make([]AlertResponse, 0, len(page.Alerts))
+	for i := range page.Alerts {
+		items = append(items, toAlertResponse(&page.Alerts[i]))
+	}
+
+	applied := AppliedFilters{
+		Channel:   page.Applied.Channel,
+		Status:    page.Applied.Status,
+		AlertType: string(page.Applied.AlertType),
+		From:      toRFC3339Ptr(page.Applied.From),
+		To:        toRFC3339Ptr(page.Applied.To),
+	}
+	if page.Applied.MonitorID != nil {
+		v := page.Applied.MonitorID.String()
+		applied.MonitorID = &v
+	}
+	if page.Applied.IncidentID != nil {
+		v := page.Applied.IncidentID.String()
+		applied.IncidentID = &v
+	}
+
+	return ListAlertsResponse{
+		Limit:          page.Limit,
+		HasMore:        page.HasMore,
+		NextCursor:     page.NextCursor,
+		AppliedFilters: applied,
+		Alerts:         items,
+	}
+}
+
+func toAlertResponse(a *Alert) AlertResponse {
+	resp := AlertResponse{
+		ID:         a.ID,
+		IncidentID: a.IncidentID,
+		MonitorID:  a.MonitorID,
+		MonitorURL: a.MonitorURL,
+		Channel:    a.Channel,
+		PluginID:   a.PluginID,
+		PluginName: a.PluginName,
+		AlertType:  string(a.Type),
+		Status:     a.Status,
+		Recipient:  a.Recipient,
+		Attempts:   a.Attempts,
+		LastError:  a.LastError,
+		SentAt:     toRFC3339Ptr(a.SentAt),
+		CreatedAt:  a.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:  a.UpdatedAt.UTC().Format(time.RFC3339),
+		GroupID:    a.GroupID,
+	}
+	// next_attempt_at is only meaningful while the row is still queued
+	if a.Status == StatusPending {
+		resp.NextAttemptAt = toRFC3339Ptr(a.NextAttemptAt)
+	}
+	return resp
+}
+
+func toRFC3339Ptr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC().Format(time.RFC3339)
+	return &v
+}
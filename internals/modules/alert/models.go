@@ -11,22 +11,161 @@ type AlertType string
 const (
 	AlertTypeDown      AlertType = "DOWN"
 	AlertTypeRecovered AlertType = "RECOVERED"
+	// AlertTypeReminder re-notifies about an incident that is still open.
+	AlertTypeReminder AlertType = "REMINDER"
+	// AlertTypeTest marks the synthetic alert sent by the plugin test endpoint.
+	AlertTypeTest AlertType = "TEST"
+	// AlertTypeSnoozeEnded tells a monitor's channels that its snooze is
+	// over. It belongs to no incident and is sent directly, not queued.
+	AlertTypeSnoozeEnded AlertType = "SNOOZE_ENDED"
 )
 
-// ZendutyConfig holds what the alert service needs from a Zenduty plugin.
-type ZendutyConfig struct {
-	IntegrationURL string `json:"integration_url"`
+// PluginInstance is one of a team's configured plugins. A team can have
+// several instances of the same type, e.g. one Zenduty service per product.
+type PluginInstance struct {
+	ID      uuid.UUID
+	Type    string
+	Name    string
+	Enabled bool
 }
 
+// SelectedBy reports whether a list of channel references picks the
+// instance. A reference is an instance ID, or a plugin type selecting every
+// instance of that type; an empty list selects every instance.
+func (p PluginInstance) SelectedBy(refs []string) bool {
+	if len(refs) == 0 {
+		return true
+	}
+	id := p.ID.String()
+	for _, ref := range refs {
+		if ref == id || ref == p.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertEvent is stored as the outbox payload of every alerts row.
 type AlertEvent struct {
-	IncidentID           uuid.UUID
-	Type                 AlertType
-	MonitorID            uuid.UUID
-	TeamID               uuid.UUID
-	MonitorURL           string
-	NotificationChannels []string
-	Reason               string
-	StatusCode           int
-	LatencyMs            int64
-	CheckedAt            time.Time
+	IncidentID           uuid.UUID `json:"incident_id"`
+	Type                 AlertType `json:"type"`
+	MonitorID            uuid.UUID `json:"monitor_id"`
+	TeamID               uuid.UUID `json:"team_id"`
+	MonitorURL           string    `json:"monitor_url"`
+	MonitorName          string    `json:"monitor_name,omitempty"`
+	RunbookURL           string    `json:"runbook_url,omitempty"`
+	NotificationChannels []string  `json:"notification_channels,omitempty"`
+	Reason               string    `json:"reason"`
+	StatusCode           int       `json:"status_code"`
+	LatencyMs            int64     `json:"latency_ms"`
+	CheckedAt            time.Time `json:"checked_at"`
+	// EscalationLevel is the 1-based escalation policy level that produced
+	// this alert, 0 for regular alerts.
+	EscalationLevel int `json:"escalation_level,omitempty"`
+	// Recipients overrides the configured recipients of notifiers that
+	// address people directly (email), e.g. users named by an escalation
+	// level.
+	Recipients []string `json:"recipients,omitempty"`
+	// DowntimeSec is how long the incident has been open, set on reminders.
+	DowntimeSec int64 `json:"downtime_sec,omitempty"`
+	// ReminderCount is the 1-based number of the reminder.
+	ReminderCount int `json:"reminder_count,omitempty"`
+	// RoutingRules names the team routing rules that picked the channels.
+	RoutingRules []string `json:"routing_rules,omitempty"`
+	// GroupName is set on alerts sent for a monitor group in group alert
+	// mode; the monitor fields then describe the member that tripped it.
+	GroupName string `json:"group_name,omitempty"`
+}
+
+// DisplayName is the monitor group's name for group alerts, otherwise the
+// monitor's name, or its URL when it has none.
+func (e AlertEvent) DisplayName() string {
+	if e.GroupName != "" {
+		return e.GroupName
+	}
+	if e.MonitorName != "" {
+		return e.MonitorName
+	}
+	return e.MonitorURL
+}
+
+// Alert is a single row of the delivery log.
+type Alert struct {
+	ID            string
+	IncidentID    string
+	MonitorID     string
+	MonitorURL    string
+	Channel       string
+	Type          AlertType
+	Status        string
+	Recipient     string
+	Attempts      int32
+	LastError     string
+	NextAttemptAt *time.Time
+	SentAt        *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	// GroupID is set on rows delivered together in one digest.
+	GroupID *string
+	// PluginID and PluginName identify the plugin instance; both are empty
+	// once the plugin is deleted.
+	PluginID   *string
+	PluginName string
+}
+
+type Cursor struct {
+	CreatedAt time.Time
+	AlertID   string
+}
+
+type ListFilters struct {
+	Channel    string
+	Status     string
+	AlertType  AlertType
+	MonitorID  *uuid.UUID
+	IncidentID *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+}
+
+type ListAlertsOptions struct {
+	Limit   int32
+	Cursor  *Cursor
+	Filters ListFilters
+}
+
+type ListAlertsPage struct {
+	Alerts     []Alert
+	HasMore    bool
+	NextCursor *string
+	Applied    ListFilters
+	Limit      int32
+}
+
+// CustomTemplate is a team's override of a channel's default template.
+type CustomTemplate struct {
+	Channel   string
+	Type      AlertType
+	Subject   string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// EffectiveTemplate is the template a channel uses for an alert type: the
+// team's override when one exists, the built-in default otherwise.
+type EffectiveTemplate struct {
+	Channel   string
+	Type      AlertType
+	Subject   string
+	Body      string
+	HTMLBody  bool
+	Custom    bool
+	UpdatedAt *time.Time
+}
+
+type TemplatePreview struct {
+	Channel string
+	Type    AlertType
+	Subject string
+	Body    string
 }
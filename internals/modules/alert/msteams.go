@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alkush-pipania/sofon/pkg/msteams"
+)
+
+const NotifierMSTeams = "msteams"
+
+// msteamsMaxFacts keeps digest cards readable.
+const msteamsMaxFacts = 50
+
+type msteamsNotifier struct{}
+
+func NewMSTeamsNotifier() Notifier {
+	return msteamsNotifier{}
+}
+
+func (msteamsNotifier) Schema() Schema {
+	return Schema{
+		Type:        NotifierMSTeams,
+		Name:        "Microsoft Teams",
+		Description: "Post alert cards to a Teams channel via an incoming webhook.",
+		Category:    "Chat",
+		Fields: []ConfigField{
+			{Key: "webhook_url", Label: "Webhook URL", Placeholder: "https://….webhook.office.com/…", Required: true, Secret: true},
+		},
+	}
+}
+
+func (msteamsNotifier) Templates() Templates {
+	return chatTemplates(false)
+}
+
+func (msteamsNotifier) Validate(cfg map[string]string) error {
+	if !isHTTPSURL(cfg["webhook_url"]) {
+		return errors.New("msteams webhook_url must be a valid https URL")
+	}
+	return nil
+}
+
+func (msteamsNotifier) Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error) {
+	event := msg.Event
+	color := "Attention"
+	switch event.Type {
+	case AlertTypeRecovered:
+		color = "Good"
+	case AlertTypeTest, AlertTypeSnoozeEnded:
+		color = "Accent"
+	}
+
+	facts := make([]msteams.Fact, 0, 6)
+	for _, f := range messageFacts(msg, msteamsMaxFacts) {
+		facts = append(facts, msteams.Fact{Title: f.Label, Value: f.Value})
+	}
+
+	body := []msteams.CardElement{
+		{Type: "TextBlock", Text: "Sofon Alert", Size: "Small", Weight: "Lighter"},
+		{Type: "TextBlock", Text: msg.Subject, Size: "Large", Weight: "Bolder", Color: color, Wrap: true},
+	}
+	if msg.Body != "" {
+		body = append(body, msteams.CardElement{Type: "TextBlock", Text: msg.Body, Wrap: true})
+	}
+	body = append(body, msteams.CardElement{Type: "FactSet", Facts: facts})
+
+	var actions []msteams.CardAction
+	if len(msg.Group) == 0 {
+		actions = []msteams.CardAction{
+			{Type: "Action.OpenUrl", Title: "Open URL", URL: event.MonitorURL},
+		}
+		if event.RunbookURL != "" {
+			actions = append(actions, msteams.CardAction{Type: "Action.OpenUrl", Title: "Open Runbook", URL: event.RunbookURL})
+		}
+	}
+	card := msteams.NewCard(body, actions)
+
+	if err := msteams.NewClient(cfg["webhook_url"]).SendCard(ctx, card); err != nil {
+		return Delivery{}, err
+	}
+	return Delivery{}, nil
+}
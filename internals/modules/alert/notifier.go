@@ -0,0 +1,152 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrUnknownNotifier = errors.New("unsupported plugin type")
+
+// ConfigField describes a single key of a notifier's plugin config.
+type ConfigField struct {
+	Key         string
+	Label       string
+	Placeholder string
+	Required    bool
+	// Secret fields are masked when a plugin config is read back.
+	Secret bool
+	// MultiValue fields hold a comma-separated list.
+	MultiValue bool
+}
+
+// Schema describes a notifier to plugin CRUD and the UI.
+type Schema struct {
+	Type        string
+	Name        string
+	Description string
+	Category    string
+	Fields      []ConfigField
+}
+
+// Delivery is what a notifier reports back after a successful send.
+type Delivery struct {
+	// Target is a non-secret description of where the alert went
+	// (recipient list, chat id…). Empty for webhook-based notifiers.
+	Target string
+	// ExternalID is the provider's reference for the message, if any.
+	ExternalID string
+}
+
+// Notifier is a single alert channel. Config maps are the decrypted plugin
+// config exactly as stored; required fields have already been checked by
+// the registry when Validate is called. Send receives the message already
+// rendered from the notifier's templates (or the team's overrides).
+type Notifier interface {
+	Schema() Schema
+	Templates() Templates
+	Validate(cfg map[string]string) error
+	Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error)
+}
+
+// Registry holds the available notifiers in registration order.
+type Registry struct {
+	order     []string
+	notifiers map[string]Notifier
+}
+
+func NewRegistry(notifiers ...Notifier) *Registry {
+	r := &Registry{notifiers: make(map[string]Notifier, len(notifiers))}
+	for _, n := range notifiers {
+		t := n.Schema().Type
+		if _, dup := r.notifiers[t]; dup {
+			panic(fmt.Sprintf("alert: notifier %q registered twice", t))
+		}
+		r.order = append(r.order, t)
+		r.notifiers[t] = n
+	}
+	return r
+}
+
+func (r *Registry) Get(pluginType string) (Notifier, bool) {
+	n, ok := r.notifiers[pluginType]
+	return n, ok
+}
+
+// KnownChannel reports whether a channel reference names a registered
+// plugin type or one of the given plugin instances.
+func (r *Registry) KnownChannel(ref string, instances []PluginInstance) bool {
+	if _, ok := r.notifiers[ref]; ok {
+		return true
+	}
+	for _, p := range instances {
+		if p.ID.String() == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns the registered notifiers in registration order.
+func (r *Registry) All() []Notifier {
+	out := make([]Notifier, 0, len(r.order))
+	for _, t := range r.order {
+		out = append(out, r.notifiers[t])
+	}
+	return out
+}
+
+func (r *Registry) Schemas() []Schema {
+	out := make([]Schema, 0, len(r.order))
+	for _, t := range r.order {
+		out = append(out, r.notifiers[t].Schema())
+	}
+	return out
+}
+
+// Validate checks required fields against the schema and then runs the
+// notifier's own validation.
+func (r *Registry) Validate(pluginType string, cfg map[string]string) error {
+	n, ok := r.notifiers[pluginType]
+	if !ok {
+		return ErrUnknownNotifier
+	}
+	for _, f := range n.Schema().Fields {
+		if f.Required && strings.TrimSpace(cfg[f.Key]) == "" {
+			return fmt.Errorf("%s is required", f.Key)
+		}
+	}
+	return n.Validate(cfg)
+}
+
+// SecretKeys returns the config keys a notifier marks as secret.
+func (r *Registry) SecretKeys(pluginType string) map[string]bool {
+	n, ok := r.notifiers[pluginType]
+	if !ok {
+		return nil
+	}
+	keys := make(map[string]bool)
+	for _, f := range n.Schema().Fields {
+		if f.Secret {
+			keys[f.Key] = true
+		}
+	}
+	return keys
+}
+
+func splitList(raw string) []string {
+	var out []string
+	for _, v := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(v); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+func isHTTPSURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
@@ -0,0 +1,504 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+// Outbox statuses stored in alerts.status.
+const (
+	StatusPending         = "pending"
+	StatusSent            = "sent"
+	StatusFailed          = "failed" // only written before the outbox existed
+	StatusDead            = "dead"
+	StatusSkippedNoPlugin = "skipped_no_plugin"
+)
+
+// OutboxEntry is a claimed alerts row waiting for delivery.
+type OutboxEntry struct {
+	ID         uuid.UUID
+	IncidentID uuid.UUID
+	// Channel is the plugin type, PluginID the instance it is sent through.
+	Channel  string
+	PluginID uuid.UUID
+	Payload  []byte
+	Attempts int
+}
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// Enqueue writes one pending row per plugin instance in a single
+// statement. Each row becomes due after the hold at the same index.
+func (r *Repository) Enqueue(ctx context.Context, incidentID uuid.UUID, alertType AlertType, plugins []PluginInstance, payload []byte, holds []time.Duration) error {
+	const op string = "repo.alert.enqueue"
+
+	channels := make([]string, 0, len(plugins))
+	pluginIDs := make([]uuid.UUID, 0, len(plugins))
+	holdSecs := make([]int32, 0, len(plugins))
+	for i, p := range plugins {
+		channels = append(channels, p.Type)
+		pluginIDs = append(pluginIDs, p.ID)
+		holdSecs = append(holdSecs, int32(holds[i].Seconds()))
+	}
+
+	err := r.querier.EnqueueAlerts(ctx, db.EnqueueAlertsParams{
+		IncidentID: utils.ToPgUUID(incidentID),
+		Column2:    channels,
+		Column3:    toPgUUIDs(pluginIDs),
+		Payload:    payload,
+		AlertType:  string(alertType),
+		Column6:    holdSecs,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+// ClaimDue locks up to limit due rows, bumps their attempt counter and pushes
+// next_attempt_at out by lease so no other worker picks them up meanwhile.
+// If the claiming worker dies, the rows become due again once the lease ends.
+func (r *Repository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]OutboxEntry, error) {
+	const op string = "repo.alert.claim_due"
+
+	rows, err := r.querier.ClaimDueAlerts(ctx, db.ClaimDueAlertsParams{
+		Limit:   int32(limit),
+		Column2: int32(lease.Seconds()),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	entries := make([]OutboxEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, OutboxEntry{
+			ID:         utils.FromPgUUID(row.ID),
+			IncidentID: utils.FromPgUUID(row.IncidentID),
+			Channel:    row.Channel,
+			PluginID:   utils.FromPgUUID(row.PluginID),
+			Payload:    row.Payload,
+			Attempts:   int(row.Attempts),
+		})
+	}
+	return entries, nil
+}
+
+// ExtendLeases pushes the claim on entries out by lease again and returns
+// the IDs of those still held. An entry whose lease ran out and was claimed
+// by another worker meanwhile has a different attempt count and is left
+// alone.
+func (r *Repository) ExtendLeases(ctx context.Context, entries []OutboxEntry, lease time.Duration) (map[uuid.UUID]bool, error) {
+	const op string = "repo.alert.extend_leases"
+
+	ids := make([]pgtype.UUID, 0, len(entries))
+	attempts := make([]int32, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, utils.ToPgUUID(e.ID))
+		attempts = append(attempts, int32(e.Attempts))
+	}
+
+	rows, err := r.querier.ExtendAlertLeases(ctx, db.ExtendAlertLeasesParams{
+		Column1: ids,
+		Column2: attempts,
+		Column3: int32(lease.Seconds()),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	held := make(map[uuid.UUID]bool, len(rows))
+	for _, id := range rows {
+		held[utils.FromPgUUID(id)] = true
+	}
+	return held, nil
+}
+
+// ClaimGroup claims up to limit more pending rows that can join a digest
+// with the given key: same team, plugin instance and type, not addressed by an
+// escalation level, and due within horizon. Rows in exclude are already
+// part of the digest.
+func (r *Repository) ClaimGroup(ctx context.Context, key groupKey, exclude []uuid.UUID, horizon time.Duration, limit int, lease time.Duration) ([]OutboxEntry, error) {
+	const op string = "repo.alert.claim_group"
+
+	rows, err := r.querier.ClaimAlertGroup(ctx, db.ClaimAlertGroupParams{
+		PluginID:  utils.ToPgUUID(key.pluginID),
+		AlertType: string(key.alertType),
+		Column3:   key.teamID.String(),
+		Column4:   int32(horizon.Seconds()),
+		Column5:   toPgUUIDs(exclude),
+		Limit:     int32(limit),
+		Column7:   int32(lease.Seconds()),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	entries := make([]OutboxEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, OutboxEntry{
+			ID:         utils.FromPgUUID(row.ID),
+			IncidentID: utils.FromPgUUID(row.IncidentID),
+			Channel:    row.Channel,
+			PluginID:   utils.FromPgUUID(row.PluginID),
+			Payload:    row.Payload,
+			Attempts:   int(row.Attempts),
+		})
+	}
+	return entries, nil
+}
+
+// MarkGroupSent marks every row of a delivered digest sent.
+func (r *Repository) MarkGroupSent(ctx context.Context, ids []uuid.UUID, groupID uuid.UUID, target string) error {
+	const op string = "repo.alert.mark_group_sent"
+
+	err := r.querier.MarkAlertGroupSent(ctx, db.MarkAlertGroupSentParams{
+		Column1:    toPgUUIDs(ids),
+		GroupID:    utils.ToPgUUID(groupID),
+		AlertEmail: target,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+// Defer puts claimed rows back until the given time without spending an
+// attempt, e.g. when the channel is rate limited.
+func (r *Repository) Defer(ctx context.Context, ids []uuid.UUID, until time.Time) error {
+	const op string = "repo.alert.defer"
+
+	err := r.querier.DeferAlerts(ctx, db.DeferAlertsParams{
+		Column1:       toPgUUIDs(ids),
+		NextAttemptAt: utils.ToPgTimestamptz(until),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+// DownGroup returns the digest the incident's DOWN alert went out in on
+// the plugin instance. found is false when it was sent on its own.
+func (r *Repository) DownGroup(ctx context.Context, incidentID, pluginID uuid.UUID) (DownGroup, bool, error) {
+	const op string = "repo.alert.down_group"
+
+	row, err := r.querier.GetIncidentDownGroup(ctx, db.GetIncidentDownGroupParams{
+		IncidentID: utils.ToPgUUID(incidentID),
+		PluginID:   utils.ToPgUUID(pluginID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DownGroup{}, false, nil
+		}
+		return DownGroup{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return DownGroup{
+		ID:       utils.FromPgUUID(row.GroupID),
+		Resolved: row.OpenIncidents == 0,
+	}, true, nil
+}
+
+func (r *Repository) MarkSent(ctx context.Context, id uuid.UUID, target string) error {
+	const op string = "repo.alert.mark_sent"
+
+	err := r.querier.MarkAlertSent(ctx, db.MarkAlertSentParams{
+		ID:         utils.ToPgUUID(id),
+		AlertEmail: target,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+func (r *Repository) Reschedule(ctx context.Context, id uuid.UUID, lastErr string, next time.Time) error {
+	const op string = "repo.alert.reschedule"
+
+	err := r.querier.RescheduleAlert(ctx, db.RescheduleAlertParams{
+		ID:            utils.ToPgUUID(id),
+		LastError:     utils.ToPgText(lastErr),
+		NextAttemptAt: utils.ToPgTimestamptz(next),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+// Close moves a row to a terminal status other than sent.
+func (r *Repository) Close(ctx context.Context, id uuid.UUID, status, lastErr string) error {
+	const op string = "repo.alert.close"
+
+	err := r.querier.CloseAlert(ctx, db.CloseAlertParams{
+		ID:        utils.ToPgUUID(id),
+		Status:    status,
+		LastError: utils.ToPgText(lastErr),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+func (r *Repository) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts ListAlertsOptions) ([]Alert, bool, error) {
+	const op string = "repo.alert.list_by_team_id"
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	fetchLimit := limit + 1
+
+	var monitorID pgtype.UUID
+	if opts.Filters.MonitorID != nil {
+		monitorID = utils.ToPgUUID(*opts.Filters.MonitorID)
+	}
+
+	var incidentID pgtype.UUID
+	if opts.Filters.IncidentID != nil {
+		incidentID = utils.ToPgUUID(*opts.Filters.IncidentID)
+	}
+
+	var fromTS pgtype.Timestamptz
+	if opts.Filters.From != nil {
+		fromTS = utils.ToPgTimestamptz(opts.Filters.From.UTC())
+	}
+
+	var toTS pgtype.Timestamptz
+	if opts.Filters.To != nil {
+		toTS = utils.ToPgTimestamptz(opts.Filters.To.UTC())
+	}
+
+	var cursorCreated pgtype.Timestamptz
+	var cursorID pgtype.UUID
+	if opts.Cursor != nil {
+		parsedCursorID, err := uuid.Parse(opts.Cursor.AlertID)
+		if err != nil {
+			return nil, false, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "invalid cursor",
+			}
+		}
+		cursorCreated = utils.ToPgTimestamptz(opts.Cursor.CreatedAt.UTC())
+		cursorID = utils.ToPgUUID(parsedCursorID)
+	}
+
+	rows, err := r.querier.ListAlertsByTeamCursor(ctx, db.ListAlertsByTeamCursorParams{
+		TeamID:   utils.ToPgUUID(teamID),
+		Column2:  opts.Filters.Channel,
+		Column3:  opts.Filters.Status,
+		Column4:  string(opts.Filters.AlertType),
+		Column5:  monitorID,
+		Column6:  incidentID,
+		Column7:  fromTS,
+		Column8:  toTS,
+		Column9:  cursorCreated,
+		Column10: cursorID,
+		Limit:    fetchLimit,
+	})
+	if err != nil {
+		return nil, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	alerts := make([]Alert, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		alerts = append(alerts, Alert{
+			ID:            utils.FromPgUUID(row.ID).String(),
+			IncidentID:    utils.FromPgUUID(row.IncidentID).String(),
+			MonitorID:     utils.FromPgUUID(row.MonitorID).String(),
+			MonitorURL:    row.MonitorUrl,
+			Channel:       row.Channel,
+			Type:          AlertType(row.AlertType),
+			Status:        row.Status,
+			Recipient:     row.AlertEmail,
+			Attempts:      row.Attempts,
+			LastError:     utils.FromPgText(row.LastError),
+			NextAttemptAt: timePtr(row.NextAttemptAt),
+			SentAt:        timePtr(row.SentAt),
+			CreatedAt:     utils.FromPgTimestamptz(row.CreatedAt),
+			UpdatedAt:     utils.FromPgTimestamptz(row.UpdatedAt),
+			GroupID:       uuidPtrString(row.GroupID),
+			PluginID:      uuidPtrString(row.PluginID),
+			PluginName:    row.PluginName,
+		})
+	}
+
+	hasMore := len(alerts) > int(limit)
+	if hasMore {
+		alerts = alerts[:limit]
+	}
+
+	return alerts, hasMore, nil
+}
+
+func (r *Repository) IncidentExistsForTeam(ctx context.Context, incidentID, teamID uuid.UUID) (bool, error) {
+	const op string = "repo.alert.incident_exists_for_team"
+
+	exists, err := r.querier.IncidentExistsForTeam(ctx, db.IncidentExistsForTeamParams{
+		ID:     utils.ToPgUUID(incidentID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return exists, nil
+}
+
+// MonitorSnoozed reports whether the monitor's alerts are muted right now.
+func (r *Repository) MonitorSnoozed(ctx context.Context, monitorID uuid.UUID) (bool, error) {
+	const op string = "repo.alert.monitor_snoozed"
+
+	snoozed, err := r.querier.MonitorSnoozed(ctx, utils.ToPgUUID(monitorID))
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return snoozed, nil
+}
+
+func (r *Repository) ListTemplates(ctx context.Context, teamID uuid.UUID) ([]CustomTemplate, error) {
+	const op string = "repo.alert.list_templates"
+
+	rows, err := r.querier.ListAlertTemplates(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	templates := make([]CustomTemplate, 0, len(rows))
+	for _, row := range rows {
+		templates = append(templates, toCustomTemplate(row))
+	}
+	return templates, nil
+}
+
+// GetTemplate returns the team's override for a channel and alert type.
+// found is false when the team uses the channel's default.
+func (r *Repository) GetTemplate(ctx context.Context, teamID uuid.UUID, channel string, alertType AlertType) (CustomTemplate, bool, error) {
+	const op string = "repo.alert.get_template"
+
+	row, err := r.querier.GetAlertTemplate(ctx, db.GetAlertTemplateParams{
+		TeamID:    utils.ToPgUUID(teamID),
+		Channel:   channel,
+		AlertType: string(alertType),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return CustomTemplate{}, false, nil
+		}
+		return CustomTemplate{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toCustomTemplate(row), true, nil
+}
+
+func (r *Repository) UpsertTemplate(ctx context.Context, teamID uuid.UUID, tpl CustomTemplate) (CustomTemplate, error) {
+	const op string = "repo.alert.upsert_template"
+
+	row, err := r.querier.UpsertAlertTemplate(ctx, db.UpsertAlertTemplateParams{
+		TeamID:    utils.ToPgUUID(teamID),
+		Channel:   tpl.Channel,
+		AlertType: string(tpl.Type),
+		Subject:   tpl.Subject,
+		Body:      tpl.Body,
+	})
+	if err != nil {
+		return CustomTemplate{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toCustomTemplate(row), nil
+}
+
+func (r *Repository) DeleteTemplate(ctx context.Context, teamID uuid.UUID, channel string, alertType AlertType) error {
+	const op string = "repo.alert.delete_template"
+
+	n, err := r.querier.DeleteAlertTemplate(ctx, db.DeleteAlertTemplateParams{
+		TeamID:    utils.ToPgUUID(teamID),
+		Channel:   channel,
+		AlertType: string(alertType),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "template not customized"}
+	}
+	return nil
+}
+
+// GetMonitorMeta loads the monitor fields exposed to templates. found is
+// false when the monitor has been deleted since the alert was enqueued.
+func (r *Repository) GetMonitorMeta(ctx context.Context, monitorID uuid.UUID) (MonitorMeta, bool, error) {
+	const op string = "repo.alert.get_monitor_meta"
+
+	row, err := r.querier.GetAlertMonitorMeta(ctx, utils.ToPgUUID(monitorID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return MonitorMeta{}, false, nil
+		}
+		return MonitorMeta{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return MonitorMeta{
+		ID:                 utils.FromPgUUID(row.ID).String(),
+		URL:                row.Url,
+		IntervalSec:        row.IntervalSec,
+		TimeoutSec:         row.TimeoutSec,
+		ExpectedStatus:     row.ExpectedStatus.Int32,
+		LatencyThresholdMs: row.LatencyThresholdMs.Int32,
+		Name:               row.Name,
+		Description:        row.Description,
+		RunbookURL:         row.RunbookUrl,
+	}, true, nil
+}
+
+func toCustomTemplate(row db.AlertTemplate) CustomTemplate {
+	return CustomTemplate{
+		Channel:   row.Channel,
+		Type:      AlertType(row.AlertType),
+		Subject:   row.Subject,
+		Body:      row.Body,
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+}
+
+func toPgUUIDs(ids []uuid.UUID) []pgtype.UUID {
+	out := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, utils.ToPgUUID(id))
+	}
+	return out
+}
+
+func uuidPtrString(id pgtype.UUID) *string {
+	if !id.Valid {
+		return nil
+	}
+	s := utils.FromPgUUID(id).String()
+	return &s
+}
+
+func timePtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid || ts.InfinityModifier != pgtype.Finite {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
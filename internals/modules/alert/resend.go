@@ -0,0 +1,268 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"text/template"
+	"time"
+
+	resendpkg "github.com/alkush-pipania/sofon/pkg/redis/resend"
+)
+
+const NotifierResend = "resend"
+
+type resendNotifier struct{}
+
+func NewResendNotifier() Notifier {
+	return resendNotifier{}
+}
+
+func (resendNotifier) Schema() Schema {
+	return Schema{
+		Type:        NotifierResend,
+		Name:        "Resend Email",
+		Description: "Send incident alert emails via the Resend API.",
+		Category:    "Email",
+		Fields: []ConfigField{
+			{Key: "api_key", Label: "API Key", Placeholder: "re_xxxxxxxxxxxxxxxxxxxx", Required: true, Secret: true},
+			{Key: "sender_email", Label: "From", Placeholder: "alerts@yourdomain.com", Required: true},
+			{Key: "recipient_emails", Label: "Recipients", Placeholder: "you@example.com", MultiValue: true},
+		},
+	}
+}
+
+func (resendNotifier) Templates() Templates {
+	return Templates{
+		HTMLBody: true,
+		Defaults: map[AlertType]TemplateSet{
+			AlertTypeDown:        {Subject: "[SOFON][DOWN] {{ .MonitorName }} is down", Body: emailBodyTpl},
+			AlertTypeReminder:    {Subject: "[SOFON][STILL DOWN] {{ .MonitorName }} has been down for {{ .Downtime }}", Body: emailBodyTpl},
+			AlertTypeRecovered:   {Subject: "[SOFON][RECOVERED] {{ .MonitorName }} is back up", Body: emailBodyTpl},
+			AlertTypeTest:        {Subject: "[SOFON][TEST] Test notification", Body: emailBodyTpl},
+			AlertTypeSnoozeEnded: {Subject: "[SOFON][SNOOZE ENDED] {{ .MonitorName }} is alerting again", Body: emailBodyTpl},
+		},
+		Digests: map[AlertType]TemplateSet{
+			AlertTypeDown:      {Subject: "[SOFON][DOWN] {{ .Count }} monitors are down", Body: emailDigestBodyTpl},
+			AlertTypeReminder:  {Subject: "[SOFON][STILL DOWN] {{ .Count }} monitors are still down", Body: emailDigestBodyTpl},
+			AlertTypeRecovered: {Subject: "[SOFON][RECOVERED] {{ .Count }} monitors are back up", Body: emailDigestBodyTpl},
+		},
+	}
+}
+
+func (resendNotifier) Validate(cfg map[string]string) error {
+	if _, err := mail.ParseAddress(cfg["sender_email"]); err != nil {
+		return errors.New("sender_email must be a valid email address")
+	}
+	for _, e := range splitList(cfg["recipient_emails"]) {
+		if _, err := mail.ParseAddress(e); err != nil {
+			return fmt.Errorf("recipient_emails contains an invalid address: %s", e)
+		}
+	}
+	return nil
+}
+
+func (resendNotifier) Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error) {
+	// Build recipient list; fall back to sender if none configured.
+	recipients := msg.Event.Recipients
+	if len(recipients) == 0 {
+		recipients = splitList(cfg["recipient_emails"])
+	}
+	if len(recipients) == 0 && cfg["sender_email"] != "" {
+		recipients = []string{cfg["sender_email"]}
+	}
+	if len(recipients) == 0 {
+		return Delivery{}, errors.New("no recipient email configured")
+	}
+	delivery := Delivery{Target: strings.Join(recipients, ",")}
+
+	textTpl := emailTextTpl
+	if len(msg.Group) > 0 {
+		textTpl = emailDigestTextTpl
+	}
+	textBody, err := buildEmailText(textTpl, msg.Data)
+	if err != nil {
+		return delivery, err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	id, err := resendpkg.NewResendClient(cfg["api_key"]).SendEmail(sendCtx, &resendpkg.SendEmailRequest{
+		From:    cfg["sender_email"],
+		To:      recipients,
+		Subject: msg.Subject,
+		Html:    msg.Body,
+		Text:    textBody,
+	})
+	if err != nil {
+		return delivery, err
+	}
+	delivery.ExternalID = id
+	return delivery, nil
+}
+
+// buildEmailText renders the plain-text alternative, which is not
+// customizable.
+func buildEmailText(tpl string, data TemplateData) (string, error) {
+	textT, err := template.New("monitor_alert_text").Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	var textBuf strings.Builder
+	if err := textT.Execute(&textBuf, data); err != nil {
+		return "", err
+	}
+	return textBuf.String(), nil
+}
+
+const emailStateTitle = `{{ if eq .Type "RECOVERED" }}Monitor Recovered{{ else if eq .Type "TEST" }}Test Notification{{ else if eq .Type "SNOOZE_ENDED" }}Snooze Ended{{ else if eq .Type "REMINDER" }}Monitor Still Down{{ else }}Monitor Down{{ end }}`
+
+const emailBodyTpl = `
+<!doctype html>
+<html>
+  <body style="margin:0;padding:0;background:#f5f7fb;font-family:Arial,sans-serif;color:#0f172a;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="padding:24px 12px;">
+      <tr>
+        <td align="center">
+          <table width="640" cellpadding="0" cellspacing="0" style="max-width:640px;background:#ffffff;border:1px solid #e2e8f0;border-radius:12px;overflow:hidden;">
+            <tr>
+              <td style="background:{{ if eq .Type "RECOVERED" }}#16a34a{{ else if or (eq .Type "TEST") (eq .Type "SNOOZE_ENDED") }}#2563eb{{ else }}#dc2626{{ end }};color:#ffffff;padding:16px 24px;font-size:18px;font-weight:700;">
+                Sofon Alert: ` + emailStateTitle + `
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:20px 24px 8px 24px;font-size:14px;line-height:1.6;">
+                {{ if eq .Type "RECOVERED" -}}
+                Good news. Your monitor is responding again and the incident has been marked as resolved.
+                {{- else if eq .Type "TEST" -}}
+                This is a test notification sent from the Sofon plugin settings. No monitor is affected and no action is needed.
+                {{- else if eq .Type "SNOOZE_ENDED" -}}
+                The snooze on this monitor has ended. Failures will open incidents and send alerts again.
+                {{- else if eq .Type "REMINDER" -}}
+                Your monitor has been down for {{ .Downtime }} and the incident is still open. This is reminder #{{ .ReminderCount }}.
+                {{- else -}}
+                We detected an outage for one of your monitors. Please review the details below and take action.
+                {{- end }}
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:0 24px 24px 24px;">
+                <table width="100%" cellpadding="8" cellspacing="0" style="border-collapse:collapse;font-size:14px;">
+                  {{- if ne .Type "SNOOZE_ENDED" }}
+                  <tr><td style="font-weight:700;width:170px;border-bottom:1px solid #e2e8f0;">Incident ID</td><td style="border-bottom:1px solid #e2e8f0;">{{ .IncidentID }}</td></tr>
+                  {{- end }}
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Monitor</td><td style="border-bottom:1px solid #e2e8f0;">{{ .MonitorName }}</td></tr>
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Monitor ID</td><td style="border-bottom:1px solid #e2e8f0;">{{ .MonitorID }}</td></tr>
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">URL</td><td style="border-bottom:1px solid #e2e8f0;word-break:break-word;">{{ .MonitorURL }}</td></tr>
+                  {{- if .RunbookURL }}
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Runbook</td><td style="border-bottom:1px solid #e2e8f0;word-break:break-word;"><a href="{{ .RunbookURL }}">{{ .RunbookURL }}</a></td></tr>
+                  {{- end }}
+                  {{- if eq .Type "SNOOZE_ENDED" }}
+                  <tr><td style="font-weight:700;">Snoozed Until (UTC)</td><td>{{ .CheckedAt }}</td></tr>
+                  {{- else }}
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Reason</td><td style="border-bottom:1px solid #e2e8f0;">{{ .Reason }}</td></tr>
+                  {{- if .Downtime }}
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Down For</td><td style="border-bottom:1px solid #e2e8f0;">{{ .Downtime }}</td></tr>
+                  {{- end }}
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">HTTP Status</td><td style="border-bottom:1px solid #e2e8f0;">{{ .StatusCode }}</td></tr>
+                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Latency</td><td style="border-bottom:1px solid #e2e8f0;">{{ .LatencyMs }} ms</td></tr>
+                  <tr><td style="font-weight:700;">Checked At (UTC)</td><td>{{ .CheckedAt }}</td></tr>
+                  {{- end }}
+                </table>
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:12px 24px 20px 24px;font-size:12px;color:#475569;background:#f8fafc;border-top:1px solid #e2e8f0;">
+                You are receiving this email because this monitor is configured with your alert email in Sofon.
+              </td>
+            </tr>
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>
+`
+
+const emailTextTpl = `Sofon Alert: ` + emailStateTitle + `
+
+{{ if ne .Type "SNOOZE_ENDED" }}Incident ID: {{ .IncidentID }}
+{{ end }}Monitor: {{ .MonitorName }}
+Monitor ID: {{ .MonitorID }}
+URL: {{ .MonitorURL }}
+{{ if .RunbookURL }}Runbook: {{ .RunbookURL }}
+{{ end }}{{ if eq .Type "SNOOZE_ENDED" }}Snoozed Until (UTC): {{ .CheckedAt }}
+{{ else }}Reason: {{ .Reason }}
+{{ if .Downtime }}Down For: {{ .Downtime }}
+{{ end }}HTTP Status: {{ .StatusCode }}
+Latency: {{ .LatencyMs }} ms
+Checked At (UTC): {{ .CheckedAt }}
+{{ end }}`
+
+const emailDigestStateTitle = `{{ if eq .Type "RECOVERED" }}{{ .Count }} Monitors Recovered{{ else if eq .Type "REMINDER" }}{{ .Count }} Monitors Still Down{{ else }}{{ .Count }} Monitors Down{{ end }}`
+
+const emailDigestBodyTpl = `
+<!doctype html>
+<html>
+  <body style="margin:0;padding:0;background:#f5f7fb;font-family:Arial,sans-serif;color:#0f172a;">
+    <table width="100%" cellpadding="0" cellspacing="0" style="padding:24px 12px;">
+      <tr>
+        <td align="center">
+          <table width="640" cellpadding="0" cellspacing="0" style="max-width:640px;background:#ffffff;border:1px solid #e2e8f0;border-radius:12px;overflow:hidden;">
+            <tr>
+              <td style="background:{{ if eq .Type "RECOVERED" }}#16a34a{{ else }}#dc2626{{ end }};color:#ffffff;padding:16px 24px;font-size:18px;font-weight:700;">
+                Sofon Alert: ` + emailDigestStateTitle + `
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:20px 24px 8px 24px;font-size:14px;line-height:1.6;">
+                {{ if eq .Type "RECOVERED" -}}
+                Good news. These monitors are responding again and their incidents have been marked as resolved.
+                {{- else if eq .Type "REMINDER" -}}
+                These monitors are still down and their incidents are still open.
+                {{- else -}}
+                We detected outages for several of your monitors at about the same time, which often points to a shared dependency. Please review the details below and take action.
+                {{- end }}
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:0 24px 24px 24px;">
+                <table width="100%" cellpadding="8" cellspacing="0" style="border-collapse:collapse;font-size:14px;">
+                  <tr>
+                    <td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Monitor</td>
+                    <td style="font-weight:700;border-bottom:1px solid #e2e8f0;">{{ if eq .Type "RECOVERED" }}Status{{ else }}Reason{{ end }}</td>
+                    <td style="font-weight:700;border-bottom:1px solid #e2e8f0;">{{ if eq .Type "REMINDER" }}Down For{{ else }}Latency{{ end }}</td>
+                  </tr>
+                  {{- range .Alerts }}
+                  <tr>
+                    <td style="border-bottom:1px solid #e2e8f0;word-break:break-word;">{{ .MonitorName }}{{ if .RunbookURL }} (<a href="{{ .RunbookURL }}">runbook</a>){{ end }}</td>
+                    <td style="border-bottom:1px solid #e2e8f0;">{{ if eq .Type "RECOVERED" }}HTTP {{ .StatusCode }}{{ else }}{{ .Reason }}{{ end }}</td>
+                    <td style="border-bottom:1px solid #e2e8f0;">{{ if eq .Type "REMINDER" }}{{ .Downtime }}{{ else }}{{ .LatencyMs }} ms{{ end }}</td>
+                  </tr>
+                  {{- end }}
+                </table>
+              </td>
+            </tr>
+            <tr>
+              <td style="padding:12px 24px 20px 24px;font-size:12px;color:#475569;background:#f8fafc;border-top:1px solid #e2e8f0;">
+                These alerts arrived close together and were grouped into one email.
+              </td>
+            </tr>
+          </table>
+        </td>
+      </tr>
+    </table>
+  </body>
+</html>
+`
+
+const emailDigestTextTpl = `Sofon Alert: ` + emailDigestStateTitle + `
+{{ range .Alerts }}
+{{ .MonitorName }}{{ if ne .MonitorName .MonitorURL }} ({{ .MonitorURL }}){{ end }}
+  {{ if eq .Type "RECOVERED" }}HTTP {{ .StatusCode }}, {{ .LatencyMs }} ms{{ else }}{{ .Reason }}, HTTP {{ .StatusCode }}{{ if .Downtime }}, down for {{ .Downtime }}{{ end }}{{ end }}
+  Incident ID: {{ .IncidentID }}
+{{ if .RunbookURL }}  Runbook: {{ .RunbookURL }}
+{{ end }}{{ end }}`
@@ -0,0 +1,32 @@
+package alert
+
+import "github.com/go-chi/chi/v5"
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListAlerts)
+
+	return r
+}
+
+// IncidentRoutes registers the per-incident delivery log on the incidents
+// router.
+func IncidentRoutes(h *Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/{incidentID}/alerts", h.ListIncidentAlerts)
+	}
+}
+
+// TemplateRoutes serves the team's alert message templates.
+func TemplateRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListTemplates)
+	r.Post("/preview", h.PreviewTemplate)
+	r.Get("/{channel}/{alertType}", h.GetTemplate)
+	r.Put("/{channel}/{alertType}", h.SaveTemplate)
+	r.Delete("/{channel}/{alertType}", h.DeleteTemplate)
+
+	return r
+}
@@ -0,0 +1,97 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/google/uuid"
+)
+
+// Router is satisfied by *routing.Service.
+type Router interface {
+	Route(ctx context.Context, in RoutingInput) (Routing, error)
+}
+
+// RoutingInput is what team routing rules are evaluated against.
+type RoutingInput struct {
+	TeamID    uuid.UUID
+	MonitorID uuid.UUID
+	Type      AlertType
+	At        time.Time
+}
+
+// Routing is the outcome of evaluating a team's routing rules. When no
+// rule matched, the monitor's own notification channels apply.
+type Routing struct {
+	Matched bool
+	// Channels and Recipients are the union over the matched rules. Both
+	// empty on a match means the alert is suppressed.
+	Channels   []string
+	Recipients []string
+	// Rules names the matched rules in evaluation order.
+	Rules []string
+}
+
+// route applies the team's routing rules to an event and returns the
+// events to enqueue: one for the routed channels and one emailing the
+// routed users. Alerts sent by an escalation level or addressed to people
+// already are left alone, as is every alert when the rules can't be
+// evaluated.
+func (s *AlertService) route(ctx context.Context, event AlertEvent) []AlertEvent {
+	if s.router == nil || event.EscalationLevel > 0 || len(event.Recipients) > 0 || event.MonitorID == uuid.Nil {
+		return []AlertEvent{event}
+	}
+
+	routing, err := s.router.Route(ctx, RoutingInput{
+		TeamID:    event.TeamID,
+		MonitorID: event.MonitorID,
+		Type:      event.Type,
+		At:        time.Now(),
+	})
+	if err != nil {
+		s.logger.Warn().Err(err).
+			Str("team_id", event.TeamID.String()).
+			Str("monitor_id", event.MonitorID.String()).
+			Msg("failed to evaluate routing rules, using monitor channels")
+		return []AlertEvent{event}
+	}
+	if !routing.Matched {
+		return []AlertEvent{event}
+	}
+
+	event.RoutingRules = routing.Rules
+
+	var events []AlertEvent
+	if len(routing.Channels) > 0 {
+		channelEvent := event
+		channelEvent.NotificationChannels = routing.Channels
+		events = append(events, channelEvent)
+	}
+	if len(routing.Recipients) > 0 {
+		userEvent := event
+		userEvent.NotificationChannels = []string{NotifierResend}
+		userEvent.Recipients = routing.Recipients
+		events = append(events, userEvent)
+	}
+
+	if len(events) == 0 {
+		s.logger.Info().
+			Str("incident_id", event.IncidentID.String()).
+			Str("alert_type", string(event.Type)).
+			Strs("rules", routing.Rules).
+			Msg("alert suppressed by routing rules")
+		s.timeline.Record(ctx, incident.Event{
+			IncidentID: event.IncidentID,
+			Kind:       incident.EventAlertSuppressed,
+			Message:    fmt.Sprintf("%s alert suppressed by routing rule %s", event.Type, strings.Join(routing.Rules, ", ")),
+			Data: map[string]any{
+				"alert_type":    string(event.Type),
+				"routing_rules": routing.Rules,
+			},
+		})
+	}
+	return events
+}
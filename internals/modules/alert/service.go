@@ -2,68 +2,118 @@ package alert
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"html/template"
-	"strings"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/alkush-pipania/sofon/config"
-	resendpkg "github.com/alkush-pipania/sofon/pkg/redis/resend"
-	"github.com/alkush-pipania/sofon/pkg/zenduty"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/zerolog"
 )
 
 // PluginConfigGetter is satisfied by *plugin.Repository.
 // Defined here to avoid importing the plugin package from alert.
 type PluginConfigGetter interface {
-	GetResendConfig(ctx context.Context, teamID uuid.UUID) (ResendEmailConfig, bool, error)
-	GetZendutyConfig(ctx context.Context, teamID uuid.UUID) (ZendutyConfig, bool, error)
-}
-
-// ResendEmailConfig holds only what the alert service needs from a Resend plugin.
-type ResendEmailConfig struct {
-	APIKey          string
-	SenderEmail     string
-	RecipientEmails []string
+	// GetPluginConfig returns the decrypted config of an enabled plugin
+	// instance. A missing or disabled plugin is reported as not found
+	// without an error.
+	GetPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) (map[string]string, bool, error)
+	ListPluginInstances(ctx context.Context, teamID uuid.UUID) ([]PluginInstance, error)
 }
 
 // PluginCacheClient is satisfied by *redis.Client.
 type PluginCacheClient interface {
-	GetCachedResendConfig(ctx context.Context, teamID uuid.UUID) (ResendEmailConfig, bool)
-	SetCachedResendConfig(ctx context.Context, teamID uuid.UUID, cfg ResendEmailConfig, ttl time.Duration) error
-	GetCachedZendutyConfig(ctx context.Context, teamID uuid.UUID) (ZendutyConfig, bool)
-	SetCachedZendutyConfig(ctx context.Context, teamID uuid.UUID, cfg ZendutyConfig, ttl time.Duration) error
+	GetCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) (map[string]string, bool)
+	SetCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID, cfg map[string]string, ttl time.Duration) error
 }
 
+// AlertRateCounter is satisfied by *redis.Client. It backs the rate limit
+// and the grouping windows.
+type AlertRateCounter interface {
+	IncrementAlertRate(ctx context.Context, teamID, pluginID uuid.UUID, windowStart time.Time, window time.Duration) (int64, error)
+	OpenAlertWindow(ctx context.Context, pluginID uuid.UUID, alertType string, window time.Duration) (bool, error)
+}
+
+// IncidentRecorder is satisfied by *incident.Timeline.
+type IncidentRecorder interface {
+	Record(ctx context.Context, e incident.Event)
+}
+
+const pluginConfigCacheTTL = 5 * time.Minute
+
+// AlertService delivers alerts from the Postgres outbox (the alerts table).
+// Enqueue writes one pending row per plugin instance; workers claim due
+// rows, send them and either mark them sent or reschedule them with
+// exponential backoff until MaxAttempts, after which the row is
+// dead-lettered. Rows that arrive close together for the same plugin
+// instance are sent as one digest, and each instance is rate limited.
 type AlertService struct {
-	workerCount int
-	workerWG    sync.WaitGroup
-
-	pluginRepo  PluginConfigGetter
-	redisCache  PluginCacheClient
-	db          *pgxpool.Pool
-	alertChan   chan AlertEvent
-	logger      *zerolog.Logger
+	workerCount       int
+	pollInterval      time.Duration
+	batchSize         int
+	maxAttempts       int
+	baseBackoff       time.Duration
+	maxBackoff        time.Duration
+	claimLease        time.Duration
+	groupWindow       time.Duration
+	groupMaxSize      int
+	rateLimit         int
+	rateWindow        time.Duration
+	channelRateLimits map[string]int
+
+	workerWG sync.WaitGroup
+	stop     chan struct{}
+	wake     chan struct{}
+
+	notifiers  *Registry
+	repo       *Repository
+	pluginRepo PluginConfigGetter
+	redisCache PluginCacheClient
+	rates      AlertRateCounter
+	timeline   IncidentRecorder
+	router     Router
+	logger     *zerolog.Logger
 }
 
 func NewAlertService(
 	alertConfig *config.AlertConfig,
-	db *pgxpool.Pool,
+	repo *Repository,
+	notifiers *Registry,
 	pluginRepo PluginConfigGetter,
 	redisCache PluginCacheClient,
-	alertChan chan AlertEvent,
+	rates AlertRateCounter,
+	timeline IncidentRecorder,
+	router Router,
 	logger *zerolog.Logger,
 ) *AlertService {
 	return &AlertService{
-		workerCount: alertConfig.WorkerCount,
-		pluginRepo:  pluginRepo,
-		redisCache:  redisCache,
-		db:          db,
-		alertChan:   alertChan,
-		logger:      logger,
+		workerCount:       alertConfig.WorkerCount,
+		pollInterval:      alertConfig.PollInterval,
+		batchSize:         alertConfig.BatchSize,
+		maxAttempts:       alertConfig.MaxAttempts,
+		baseBackoff:       alertConfig.BaseBackoff,
+		maxBackoff:        alertConfig.MaxBackoff,
+		claimLease:        alertConfig.ClaimLease,
+		groupWindow:       alertConfig.GroupWindow,
+		groupMaxSize:      alertConfig.GroupMaxSize,
+		rateLimit:         alertConfig.RateLimit,
+		rateWindow:        alertConfig.RateWindow,
+		channelRateLimits: alertConfig.ChannelRateLimits,
+		stop:              make(chan struct{}),
+		wake:              make(chan struct{}, 1),
+		notifiers:         notifiers,
+		repo:              repo,
+		pluginRepo:        pluginRepo,
+		redisCache:        redisCache,
+		rates:             rates,
+		timeline:          timeline,
+		router:            router,
+		logger:            logger,
 	}
 }
 
@@ -75,319 +125,469 @@ func (s *AlertService) Start() {
 	s.logger.Info().Msg("Alert workers started")
 }
 
+// Stop signals the workers to exit once their current batch is done.
+func (s *AlertService) Stop() {
+	close(s.stop)
+}
+
 func (s *AlertService) handleAlerts() {
 	defer s.workerWG.Done()
-	for alert := range s.alertChan {
-		s.processAlert(alert)
-	}
-}
 
-func channelEnabled(channels []string, plugin string) bool {
-	if len(channels) == 0 {
-		return true // backward compat: empty = all plugins enabled
-	}
-	for _, c := range channels {
-		if c == plugin {
-			return true
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// drain everything that is due before waiting again
+		for s.deliverBatch() {
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
 		}
 	}
-	return false
 }
 
-func (s *AlertService) processAlert(event AlertEvent) {
+// Enqueue durably records the event for every enabled plugin instance that
+// the team's routing rules pick, or that is selected on the monitor when no
+// rule matches. It returns once the rows
+// are committed; delivery happens asynchronously. The first groupable
+// alert for a plugin instance goes out right away, and those that follow
+// it within the grouping window wait to be sent as a digest. Alerts of a
+// snoozed monitor are dropped.
+func (s *AlertService) Enqueue(ctx context.Context, event AlertEvent) error {
 	if event.Type == "" {
 		event.Type = AlertTypeDown
 	}
+	if s.snoozed(ctx, event) {
+		s.logger.Debug().
+			Str("incident_id", event.IncidentID.String()).
+			Str("monitor_id", event.MonitorID.String()).
+			Str("alert_type", string(event.Type)).
+			Msg("monitor is snoozed, skipping alert")
+		return nil
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	for _, e := range s.route(ctx, event) {
+		if err := s.enqueue(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if channelEnabled(event.NotificationChannels, "resend") {
-		s.handleResend(ctx, event)
+// snoozed reports whether the event's monitor is snoozed. Group alerts
+// speak for the whole group and are never muted by one member. If the
+// snooze can't be read the alert goes out.
+func (s *AlertService) snoozed(ctx context.Context, event AlertEvent) bool {
+	if event.MonitorID == uuid.Nil || event.GroupName != "" {
+		return false
 	}
-	if channelEnabled(event.NotificationChannels, "zenduty") {
-		s.handleZenduty(ctx, event)
+	snoozed, err := s.repo.MonitorSnoozed(ctx, event.MonitorID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("monitor_id", event.MonitorID.String()).Msg("failed to check monitor snooze, sending alert")
+		return false
 	}
+	return snoozed
 }
 
-func (s *AlertService) handleResend(ctx context.Context, event AlertEvent) {
-	resendCfg, ok := s.redisCache.GetCachedResendConfig(ctx, event.TeamID)
-	if !ok {
-		dbCfg, found, err := s.pluginRepo.GetResendConfig(ctx, event.TeamID)
-		if err != nil {
-			s.logger.Error().Err(err).Str("team_id", event.TeamID.String()).Msg("resend: failed to load plugin config")
-			_ = s.persistAlert(event.IncidentID, "", "failed", time.Time{})
-			return
-		}
-		if !found {
-			s.logger.Debug().
-				Str("incident_id", event.IncidentID.String()).
-				Str("team_id", event.TeamID.String()).
-				Msg("resend: plugin not configured or disabled, skipping")
-			_ = s.persistAlert(event.IncidentID, "", "skipped_no_plugin", time.Time{})
-			return
+func (s *AlertService) enqueue(ctx context.Context, event AlertEvent) error {
+	instances, err := s.pluginRepo.ListPluginInstances(ctx, event.TeamID)
+	if err != nil {
+		return err
+	}
+
+	var targets []PluginInstance
+	for _, p := range instances {
+		if !p.Enabled || !p.SelectedBy(event.NotificationChannels) {
+			continue
 		}
-		resendCfg = ResendEmailConfig{
-			APIKey:          dbCfg.APIKey,
-			SenderEmail:     dbCfg.SenderEmail,
-			RecipientEmails: dbCfg.RecipientEmails,
+		if _, ok := s.notifiers.Get(p.Type); !ok {
+			continue
 		}
-		_ = s.redisCache.SetCachedResendConfig(ctx, event.TeamID, resendCfg, 5*time.Minute)
+		targets = append(targets, p)
+	}
+	if len(targets) == 0 {
+		s.logger.Debug().
+			Str("incident_id", event.IncidentID.String()).
+			Str("team_id", event.TeamID.String()).
+			Strs("channels", event.NotificationChannels).
+			Msg("no enabled plugin selected, skipping")
+		return nil
 	}
 
-	// Build recipient list; fall back to sender if none configured.
-	recipients := resendCfg.RecipientEmails
-	if len(recipients) == 0 && resendCfg.SenderEmail != "" {
-		recipients = []string{resendCfg.SenderEmail}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
 	}
-	if len(recipients) == 0 {
-		s.logger.Error().Str("incident_id", event.IncidentID.String()).Msg("resend: no recipient email configured, skipping")
-		_ = s.persistAlert(event.IncidentID, "", "failed", time.Time{})
-		return
+	holds := make([]time.Duration, len(targets))
+	if s.groupable(event) {
+		for i, p := range targets {
+			holds[i] = s.groupHold(ctx, p.ID, event.Type)
+		}
+	}
+	if err := s.repo.Enqueue(ctx, event.IncidentID, event.Type, targets, payload, holds); err != nil {
+		return err
 	}
 
-	status := "sent"
-	sentAt := time.Now().UTC()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
 
-	resendID, err := s.sendAlertEmail(resendCfg, event, recipients)
-	recipientList := strings.Join(recipients, ",")
+// deliverBatch claims and delivers one batch. It reports whether the batch
+// was full, i.e. whether more rows are likely due.
+func (s *AlertService) deliverBatch() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	entries, err := s.repo.ClaimDue(ctx, s.batchSize, s.claimLease)
+	cancel()
 	if err != nil {
-		status = "failed"
-		sentAt = time.Time{}
-		s.logger.Error().Err(err).
-			Str("incident_id", event.IncidentID.String()).
-			Str("recipients", recipientList).
-			Msg("resend: failed to send incident email")
-	} else {
-		s.logger.Info().
-			Str("incident_id", event.IncidentID.String()).
-			Str("recipients", recipientList).
-			Str("resend_email_id", resendID).
-			Msg("resend: incident email sent")
+		s.logger.Error().Err(err).Msg("failed to claim due alerts")
+		return false
 	}
 
-	_ = s.persistAlert(event.IncidentID, recipientList, status, sentAt)
+	s.deliverEntries(entries)
+	return len(entries) == s.batchSize
+}
+
+// decode reads a claimed row's payload, dead-lettering rows that can't be
+// read.
+func (s *AlertService) decode(entry *OutboxEntry) (AlertEvent, bool) {
+	var event AlertEvent
+	if err := json.Unmarshal(entry.Payload, &event); err != nil {
+		s.logger.Error().
+			Err(err).
+			Str("alert_id", entry.ID.String()).
+			Str("incident_id", entry.IncidentID.String()).
+			Msg("invalid alert payload, dead-lettering")
+		s.close(entry, StatusDead, fmt.Sprintf("invalid payload: %v", err))
+		return AlertEvent{}, false
+	}
+	return event, true
 }
 
-func (s *AlertService) handleZenduty(ctx context.Context, event AlertEvent) {
-	cfg, ok := s.redisCache.GetCachedZendutyConfig(ctx, event.TeamID)
+func (s *AlertService) deliver(entry *OutboxEntry, event AlertEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	log := s.logger.With().
+		Str("alert_id", entry.ID.String()).
+		Str("incident_id", entry.IncidentID.String()).
+		Str("plugin", entry.Channel).
+		Str("plugin_id", entry.PluginID.String()).
+		Int("attempt", entry.Attempts).
+		Logger()
+
+	n, ok := s.notifiers.Get(entry.Channel)
 	if !ok {
-		dbCfg, found, err := s.pluginRepo.GetZendutyConfig(ctx, event.TeamID)
-		if err != nil {
-			s.logger.Error().Err(err).Str("team_id", event.TeamID.String()).Msg("zenduty: failed to load plugin config")
-			return
-		}
-		if !found {
-			s.logger.Debug().
-				Str("incident_id", event.IncidentID.String()).
-				Str("team_id", event.TeamID.String()).
-				Msg("zenduty: plugin not configured or disabled, skipping")
-			return
-		}
-		cfg = dbCfg
-		_ = s.redisCache.SetCachedZendutyConfig(ctx, event.TeamID, cfg, 5*time.Minute)
-	}
-
-	alertType := zenduty.AlertTypeCritical
-	message := fmt.Sprintf("%s is DOWN", event.MonitorURL)
-	summary := event.Reason
-	if event.Type == AlertTypeRecovered {
-		alertType = zenduty.AlertTypeResolved
-		message = fmt.Sprintf("%s is UP", event.MonitorURL)
-		summary = "Monitor has recovered and is responding normally"
-	}
-
-	req := &zenduty.EventRequest{
-		AlertType: alertType,
-		Message:   message,
-		Summary:   summary,
-		EntityID:  event.MonitorID.String(),
-		Payload: map[string]string{
-			"status_code": fmt.Sprintf("%d", event.StatusCode),
-			"monitor_url": event.MonitorURL,
-			"latency_ms":  fmt.Sprintf("%d", event.LatencyMs),
-			"incident_id": event.IncidentID.String(),
-		},
-		URLs: []zenduty.EventURL{
-			{LinkURL: event.MonitorURL, LinkText: "Affected URL"},
-		},
-	}
-
-	client := zenduty.NewClient(cfg.IntegrationURL)
-	resp, err := client.SendEvent(ctx, req)
+		log.Error().Msg("unknown alert channel, dead-lettering")
+		s.close(entry, StatusDead, ErrUnknownNotifier.Error())
+		return
+	}
+
+	cfg, found, err := s.pluginConfig(ctx, event.TeamID, entry.PluginID)
 	if err != nil {
-		s.logger.Error().Err(err).
-			Str("incident_id", event.IncidentID.String()).
-			Str("alert_type", string(alertType)).
-			Msg("zenduty: failed to send event")
+		log.Error().Err(err).Msg("failed to load plugin config")
+		s.retry(ctx, entry, &event, err)
+		return
+	}
+	if !found {
+		log.Info().Msg("plugin removed or disabled since enqueue, skipping")
+		s.close(entry, StatusSkippedNoPlugin, "")
+		return
+	}
+
+	if s.rateLimited(ctx, event.TeamID, entry.Channel, entry.PluginID, []uuid.UUID{entry.ID}) {
+		return
+	}
+
+	msg, err := s.render(ctx, n, event)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to render alert")
+		s.retry(ctx, entry, &event, err)
 		return
 	}
+	s.attachDownGroups(ctx, &msg, entry.PluginID)
 
-	s.logger.Info().
-		Str("incident_id", event.IncidentID.String()).
-		Str("alert_type", string(alertType)).
-		Str("trace_id", resp.TraceID).
-		Msg("zenduty: event sent successfully")
+	delivery, err := n.Send(ctx, cfg, msg)
+	if err != nil {
+		log.Error().Err(err).Str("alert_type", string(event.Type)).Msg("failed to send alert")
+		s.retry(ctx, entry, &event, err)
+		return
+	}
+
+	if err := s.repo.MarkSent(context.Background(), entry.ID, delivery.Target); err != nil {
+		log.Error().Err(err).Msg("alert sent but failed to mark it sent")
+	}
+	log.Info().
+		Str("alert_type", string(event.Type)).
+		Str("external_id", delivery.ExternalID).
+		Msg("alert sent")
+
+	data := deliveryEventData(entry, &event)
+	if delivery.Target != "" {
+		data["target"] = delivery.Target
+	}
+	s.timeline.Record(ctx, incident.Event{
+		IncidentID: entry.IncidentID,
+		Kind:       incident.EventAlertSent,
+		Message:    fmt.Sprintf("%s alert sent via %s", event.Type, entry.Channel),
+		Data:       data,
+	})
 }
 
-func (s *AlertService) sendAlertEmail(cfg ResendEmailConfig, event AlertEvent, recipients []string) (string, error) {
-	client := resendpkg.NewResendClient(cfg.APIKey)
+// retry reschedules a failed attempt or dead-letters it once the attempt
+// budget is spent.
+func (s *AlertService) retry(ctx context.Context, entry *OutboxEntry, event *AlertEvent, sendErr error) {
+	final := entry.Attempts >= s.maxAttempts
+
+	data := deliveryEventData(entry, event)
+	data["error"] = sendErr.Error()
+	data["final"] = final
+	s.timeline.Record(ctx, incident.Event{
+		IncidentID: entry.IncidentID,
+		Kind:       incident.EventAlertFailed,
+		Message:    fmt.Sprintf("%s alert via %s failed (attempt %d)", event.Type, entry.Channel, entry.Attempts),
+		Data:       data,
+	})
 
-	subject := fmt.Sprintf("[SOFON][DOWN] Monitor %s is down", event.MonitorID.String())
-	if event.Type == AlertTypeRecovered {
-		subject = fmt.Sprintf("[SOFON][RECOVERED] Monitor %s is back up", event.MonitorID.String())
+	if final {
+		s.logger.Warn().
+			Str("alert_id", entry.ID.String()).
+			Str("plugin", entry.Channel).
+			Int("attempts", entry.Attempts).
+			Msg("alert exhausted its attempts, dead-lettering")
+		s.close(entry, StatusDead, sendErr.Error())
+		return
 	}
 
-	htmlBody, textBody, err := buildMonitorEmail(event)
-	if err != nil {
-		return "", err
+	next := time.Now().Add(s.backoff(entry.Attempts))
+	if err := s.repo.Reschedule(context.Background(), entry.ID, sendErr.Error(), next); err != nil {
+		// the claim lease still brings the row back, just later than planned
+		s.logger.Error().Err(err).Str("alert_id", entry.ID.String()).Msg("failed to reschedule alert")
+	}
+}
+
+func (s *AlertService) close(entry *OutboxEntry, status, reason string) {
+	if err := s.repo.Close(context.Background(), entry.ID, status, reason); err != nil {
+		s.logger.Error().Err(err).Str("alert_id", entry.ID.String()).Str("status", status).Msg("failed to close alert")
 	}
+}
 
-	sendCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func deliveryEventData(entry *OutboxEntry, event *AlertEvent) map[string]any {
+	data := map[string]any{
+		"alert_id":   entry.ID.String(),
+		"channel":    entry.Channel,
+		"plugin_id":  entry.PluginID.String(),
+		"alert_type": string(event.Type),
+		"attempt":    entry.Attempts,
+	}
+	if event.EscalationLevel > 0 {
+		data["escalation_level"] = event.EscalationLevel
+	}
+	if len(event.RoutingRules) > 0 {
+		data["routing_rules"] = event.RoutingRules
+	}
+	return data
+}
+
+// backoff returns BaseBackoff * 2^(attempt-1), capped at MaxBackoff.
+func (s *AlertService) backoff(attempt int) time.Duration {
+	d := s.baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= s.maxBackoff {
+			return s.maxBackoff
+		}
+	}
+	return min(d, s.maxBackoff)
+}
+
+// pluginConfig reads a plugin instance's config through the Redis cache.
+// Rows written before plugin instances existed and never matched to one
+// have no plugin ID and are reported as not found.
+func (s *AlertService) pluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) (map[string]string, bool, error) {
+	if pluginID == uuid.Nil {
+		return nil, false, nil
+	}
+	if cfg, ok := s.redisCache.GetCachedPluginConfig(ctx, teamID, pluginID); ok {
+		return cfg, true, nil
+	}
+	cfg, found, err := s.pluginRepo.GetPluginConfig(ctx, teamID, pluginID)
+	if err != nil || !found {
+		return nil, found, err
+	}
+	_ = s.redisCache.SetCachedPluginConfig(ctx, teamID, pluginID, cfg, pluginConfigCacheTTL)
+	return cfg, true, nil
+}
+
+// SendTest sends a synthetic TEST alert through the notifier with the given
+// config and reports the provider's answer synchronously. Nothing is written
+// to the outbox.
+func (s *AlertService) SendTest(ctx context.Context, teamID uuid.UUID, pluginType string, cfg map[string]string) (Delivery, error) {
+	const op = "service.alert.send_test"
+
+	n, ok := s.notifiers.Get(pluginType)
+	if !ok {
+		return Delivery{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: ErrUnknownNotifier.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	return client.SendEmail(sendCtx, &resendpkg.SendEmailRequest{
-		From:    cfg.SenderEmail,
-		To:      recipients,
-		Subject: subject,
-		Html:    htmlBody,
-		Text:    textBody,
+	msg, err := s.render(ctx, n, AlertEvent{
+		Type:       AlertTypeTest,
+		TeamID:     teamID,
+		MonitorURL: "https://example.com",
+		Reason:     "TEST_NOTIFICATION",
+		StatusCode: 200,
+		CheckedAt:  time.Now().UTC(),
 	})
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	delivery, err := n.Send(ctx, cfg, msg)
+	if err != nil {
+		return delivery, &apperror.Error{
+			Kind:    apperror.Dependency,
+			Op:      op,
+			Message: "test notification failed: " + providerError(err).Error(),
+			Err:     err,
+		}
+	}
+	return delivery, nil
 }
 
-func (s *AlertService) persistAlert(incidentID uuid.UUID, alertEmail string, status string, sentAt time.Time) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
+// Notify sends an event that belongs to no incident, such as the end of a
+// snooze, straight to the plugin instances the routing rules or monitor
+// select. It skips the outbox, so a failed send is reported but not
+// retried.
+func (s *AlertService) Notify(ctx context.Context, event AlertEvent) error {
+	instances, err := s.pluginRepo.ListPluginInstances(ctx, event.TeamID)
+	if err != nil {
+		return err
+	}
 
-	const query = `
-INSERT INTO alerts (incident_id, alert_email, status, sent_at)
-VALUES ($1, $2, $3, $4)
-`
-	var nullableSentAt any
-	if !sentAt.IsZero() {
-		nullableSentAt = sentAt
+	var errs []error
+	for _, e := range s.route(ctx, event) {
+		for _, p := range instances {
+			if !p.Enabled || !p.SelectedBy(e.NotificationChannels) {
+				continue
+			}
+			n, ok := s.notifiers.Get(p.Type)
+			if !ok {
+				continue
+			}
+			log := s.logger.With().
+				Str("monitor_id", e.MonitorID.String()).
+				Str("plugin", p.Type).
+				Str("plugin_id", p.ID.String()).
+				Str("alert_type", string(e.Type)).
+				Logger()
+
+			cfg, found, err := s.pluginConfig(ctx, e.TeamID, p.ID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !found {
+				continue
+			}
+			msg, err := s.render(ctx, n, e)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if _, err := n.Send(ctx, cfg, msg); err != nil {
+				log.Warn().Err(providerError(err)).Msg("failed to send notification")
+				errs = append(errs, fmt.Errorf("%s: %w", p.Type, providerError(err)))
+				continue
+			}
+			log.Info().Msg("notification sent")
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	_, err := s.db.Exec(ctx, query, incidentID, alertEmail, status, nullableSentAt)
+// providerError strips the request URL from HTTP client errors, since
+// webhook URLs embed credentials.
+func providerError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
 	return err
 }
 
-func (s *AlertService) WorkerClosingWait() {
-	s.workerWG.Wait()
+func (s *AlertService) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts ListAlertsOptions) (ListAlertsPage, error) {
+	alerts, hasMore, err := s.repo.ListByTeamID(ctx, teamID, opts)
+	if err != nil {
+		return ListAlertsPage{}, err
+	}
+
+	var nextCursor *string
+	if hasMore && len(alerts) > 0 {
+		last := alerts[len(alerts)-1]
+		cursor, err := EncodeCursor(Cursor{
+			CreatedAt: last.CreatedAt,
+			AlertID:   last.ID,
+		})
+		if err != nil {
+			return ListAlertsPage{}, err
+		}
+		nextCursor = &cursor
+	}
+
+	return ListAlertsPage{
+		Alerts:     alerts,
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+		Applied:    opts.Filters,
+		Limit:      opts.Limit,
+	}, nil
 }
 
-func buildMonitorEmail(event AlertEvent) (string, string, error) {
-	type templateData struct {
-		IncidentID string
-		MonitorID  string
-		MonitorURL string
-		Reason     string
-		StatusCode int
-		LatencyMs  int64
-		CheckedAt  string
-		StateTitle string
-		BannerBg   string
-		BannerFg   string
-		Message    string
-	}
-
-	stateTitle := "Monitor Down"
-	bannerBg := "#dc2626"
-	bannerFg := "#ffffff"
-	message := "We detected an outage for one of your monitors. Please review the details below and take action."
-
-	if event.Type == AlertTypeRecovered {
-		stateTitle = "Monitor Recovered"
-		bannerBg = "#16a34a"
-		message = "Good news. Your monitor is responding again and the incident has been marked as resolved."
-	}
-
-	data := templateData{
-		IncidentID: event.IncidentID.String(),
-		MonitorID:  event.MonitorID.String(),
-		MonitorURL: event.MonitorURL,
-		Reason:     event.Reason,
-		StatusCode: event.StatusCode,
-		LatencyMs:  event.LatencyMs,
-		CheckedAt:  event.CheckedAt.UTC().Format(time.RFC1123Z),
-		StateTitle: stateTitle,
-		BannerBg:   bannerBg,
-		BannerFg:   bannerFg,
-		Message:    message,
-	}
-
-	const htmlTpl = `
-<!doctype html>
-<html>
-  <body style="margin:0;padding:0;background:#f5f7fb;font-family:Arial,sans-serif;color:#0f172a;">
-    <table width="100%" cellpadding="0" cellspacing="0" style="padding:24px 12px;">
-      <tr>
-        <td align="center">
-          <table width="640" cellpadding="0" cellspacing="0" style="max-width:640px;background:#ffffff;border:1px solid #e2e8f0;border-radius:12px;overflow:hidden;">
-            <tr>
-              <td style="background:{{ .BannerBg }};color:{{ .BannerFg }};padding:16px 24px;font-size:18px;font-weight:700;">
-                Sofon Alert: {{ .StateTitle }}
-              </td>
-            </tr>
-            <tr>
-              <td style="padding:20px 24px 8px 24px;font-size:14px;line-height:1.6;">
-                {{ .Message }}
-              </td>
-            </tr>
-            <tr>
-              <td style="padding:0 24px 24px 24px;">
-                <table width="100%" cellpadding="8" cellspacing="0" style="border-collapse:collapse;font-size:14px;">
-                  <tr><td style="font-weight:700;width:170px;border-bottom:1px solid #e2e8f0;">Incident ID</td><td style="border-bottom:1px solid #e2e8f0;">{{ .IncidentID }}</td></tr>
-                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Monitor ID</td><td style="border-bottom:1px solid #e2e8f0;">{{ .MonitorID }}</td></tr>
-                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">URL</td><td style="border-bottom:1px solid #e2e8f0;word-break:break-word;">{{ .MonitorURL }}</td></tr>
-                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Reason</td><td style="border-bottom:1px solid #e2e8f0;">{{ .Reason }}</td></tr>
-                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">HTTP Status</td><td style="border-bottom:1px solid #e2e8f0;">{{ .StatusCode }}</td></tr>
-                  <tr><td style="font-weight:700;border-bottom:1px solid #e2e8f0;">Latency</td><td style="border-bottom:1px solid #e2e8f0;">{{ .LatencyMs }} ms</td></tr>
-                  <tr><td style="font-weight:700;">Checked At (UTC)</td><td>{{ .CheckedAt }}</td></tr>
-                </table>
-              </td>
-            </tr>
-            <tr>
-              <td style="padding:12px 24px 20px 24px;font-size:12px;color:#475569;background:#f8fafc;border-top:1px solid #e2e8f0;">
-                You are receiving this email because this monitor is configured with your alert email in Sofon.
-              </td>
-            </tr>
-          </table>
-        </td>
-      </tr>
-    </table>
-  </body>
-</html>
-`
-
-	const textTpl = `Sofon Alert: {{ .StateTitle }}
-
-Incident ID: {{ .IncidentID }}
-Monitor ID: {{ .MonitorID }}
-URL: {{ .MonitorURL }}
-Reason: {{ .Reason }}
-HTTP Status: {{ .StatusCode }}
-Latency: {{ .LatencyMs }} ms
-Checked At (UTC): {{ .CheckedAt }}
-`
-
-	htmlT, err := template.New("monitor_down_html").Parse(htmlTpl)
+// ListByIncident lists the delivery log of a single incident, which must
+// belong to the team.
+func (s *AlertService) ListByIncident(ctx context.Context, teamID, incidentID uuid.UUID, opts ListAlertsOptions) (ListAlertsPage, error) {
+	const op = "service.alert.list_by_incident"
+
+	exists, err := s.repo.IncidentExistsForTeam(ctx, incidentID, teamID)
 	if err != nil {
-		return "", "", err
+		return ListAlertsPage{}, err
 	}
-	textT, err := template.New("monitor_down_text").Parse(textTpl)
-	if err != nil {
-		return "", "", err
+	if !exists {
+		return ListAlertsPage{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "incident not found"}
 	}
 
-	var htmlBuf strings.Builder
-	if err := htmlT.Execute(&htmlBuf, data); err != nil {
-		return "", "", err
+	opts.Filters.IncidentID = &incidentID
+	return s.ListByTeamID(ctx, teamID, opts)
+}
+
+// IsKnownChannel reports whether a notifier with that type is registered.
+func (s *AlertService) IsKnownChannel(channel string) bool {
+	_, ok := s.notifiers.Get(channel)
+	return ok
+}
+
+// ChannelExists reports whether a channel reference names a registered
+// plugin type or one of the team's plugin instances.
+func (s *AlertService) ChannelExists(ctx context.Context, teamID uuid.UUID, ref string) (bool, error) {
+	if s.IsKnownChannel(ref) {
+		return true, nil
 	}
-	var textBuf strings.Builder
-	if err := textT.Execute(&textBuf, data); err != nil {
-		return "", "", err
+	instances, err := s.pluginRepo.ListPluginInstances(ctx, teamID)
+	if err != nil {
+		return false, err
 	}
+	return s.notifiers.KnownChannel(ref, instances), nil
+}
 
-	return htmlBuf.String(), textBuf.String(), nil
+func (s *AlertService) WorkerClosingWait() {
+	s.workerWG.Wait()
 }
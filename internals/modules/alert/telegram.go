@@ -0,0 +1,85 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/telegram"
+)
+
+const NotifierTelegram = "telegram"
+
+// telegramMaxFacts keeps digests well under Telegram's 4096 character
+// message limit.
+const telegramMaxFacts = 30
+
+var telegramTokenRe = regexp.MustCompile(`^[0-9]+:[A-Za-z0-9_-]+$`)
+
+type telegramNotifier struct{}
+
+func NewTelegramNotifier() Notifier {
+	return telegramNotifier{}
+}
+
+func (telegramNotifier) Schema() Schema {
+	return Schema{
+		Type:        NotifierTelegram,
+		Name:        "Telegram",
+		Description: "Send alert messages to a Telegram chat via a bot.",
+		Category:    "Chat",
+		Fields: []ConfigField{
+			{Key: "bot_token", Label: "Bot Token", Placeholder: "123456789:AAxxxxxxxxxxxxxxxxxxxx", Required: true, Secret: true},
+			{Key: "chat_id", Label: "Chat ID", Placeholder: "-1001234567890", Required: true},
+		},
+	}
+}
+
+// Templates renders the body with html/template since messages are sent
+// with the HTML parse mode.
+func (telegramNotifier) Templates() Templates {
+	return chatTemplates(true)
+}
+
+func (telegramNotifier) Validate(cfg map[string]string) error {
+	if !telegramTokenRe.MatchString(strings.TrimSpace(cfg["bot_token"])) {
+		return errors.New("telegram bot_token must look like <bot_id>:<secret>")
+	}
+	return nil
+}
+
+func (telegramNotifier) Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error) {
+	event := msg.Event
+	var sb strings.Builder
+	icon := "🔴"
+	switch event.Type {
+	case AlertTypeRecovered:
+		icon = "🟢"
+	case AlertTypeTest, AlertTypeSnoozeEnded:
+		icon = "🔵"
+	}
+	fmt.Fprintf(&sb, "%s <b>%s</b>\n\n", icon, html.EscapeString(msg.Subject))
+	if msg.Body != "" {
+		fmt.Fprintf(&sb, "%s\n\n", msg.Body)
+	}
+	for _, f := range messageFacts(msg, telegramMaxFacts) {
+		fmt.Fprintf(&sb, "<b>%s:</b> %s\n", html.EscapeString(f.Label), html.EscapeString(f.Value))
+	}
+
+	delivery := Delivery{Target: cfg["chat_id"]}
+	sent, err := telegram.NewClient(cfg["bot_token"]).SendMessage(ctx, &telegram.SendMessageRequest{
+		ChatID:                cfg["chat_id"],
+		Text:                  sb.String(),
+		ParseMode:             telegram.ParseModeHTML,
+		DisableWebPagePreview: true,
+	})
+	if err != nil {
+		return delivery, err
+	}
+	delivery.ExternalID = strconv.FormatInt(sent.MessageID, 10)
+	return delivery, nil
+}
@@ -0,0 +1,242 @@
+package alert
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxTemplateSubjectLen = 500
+	maxTemplateBodyLen    = 20000
+)
+
+// TemplateSet is a subject/body template pair. What subject and body mean
+// is up to the notifier (email subject and HTML body, chat title and text,
+// Zenduty message and summary…).
+type TemplateSet struct {
+	Subject string
+	Body    string
+}
+
+// Templates declares how a notifier's message is rendered.
+type Templates struct {
+	// HTMLBody renders Body with html/template instead of text/template.
+	HTMLBody bool
+	// Defaults holds the built-in templates per alert type. Types without
+	// an entry fall back to the DOWN templates.
+	Defaults map[AlertType]TemplateSet
+	// Digests holds the templates of grouped alerts, which list every
+	// monitor in .Alerts. They fall back the same way as Defaults.
+	Digests map[AlertType]TemplateSet
+}
+
+func (t Templates) defaultFor(alertType AlertType) TemplateSet {
+	if set, ok := t.Defaults[alertType]; ok {
+		return set
+	}
+	return t.Defaults[AlertTypeDown]
+}
+
+func (t Templates) digestFor(alertType AlertType) TemplateSet {
+	if set, ok := t.Digests[alertType]; ok {
+		return set
+	}
+	return t.Digests[AlertTypeDown]
+}
+
+// MonitorMeta is the monitor information exposed to templates.
+type MonitorMeta struct {
+	ID                 string
+	URL                string
+	IntervalSec        int32
+	TimeoutSec         int32
+	ExpectedStatus     int32
+	LatencyThresholdMs int32
+	Name               string
+	Description        string
+	RunbookURL         string
+}
+
+// TemplateData is what alert templates are executed against.
+type TemplateData struct {
+	Type       AlertType
+	IncidentID string
+	MonitorID  string
+	MonitorURL string
+	// MonitorName is the monitor's name, or its URL when it has none. On
+	// group alerts it is the group's name.
+	MonitorName string
+	RunbookURL  string
+	Reason      string
+	StatusCode  int
+	LatencyMs   int64
+	CheckedAt   string
+	// EscalationLevel is 0 unless the alert was sent by an escalation policy.
+	EscalationLevel int
+	// Downtime ("1h 30m") and ReminderCount are only set on reminders.
+	Downtime      string
+	DowntimeSec   int64
+	ReminderCount int
+	// GroupName is only set on alerts for a monitor group.
+	GroupName string
+	Monitor   MonitorMeta
+	// Count and Alerts are only set on digests; the fields above then
+	// describe the first alert of the group.
+	Count  int
+	Alerts []TemplateData
+}
+
+// Message is an alert rendered for one channel.
+type Message struct {
+	Event   AlertEvent
+	Data    TemplateData
+	Subject string
+	Body    string
+	// Group holds every event of a digest, Event being the first of them.
+	// It is nil for single alerts.
+	Group   []AlertEvent
+	GroupID uuid.UUID
+	// DownGroups maps the incidents of a RECOVERED message whose DOWN alert
+	// went out in a digest on this channel to that digest.
+	DownGroups map[uuid.UUID]DownGroup
+}
+
+// DownGroup is the DOWN digest an incident was announced in.
+type DownGroup struct {
+	ID uuid.UUID
+	// Resolved is true once every incident of the digest is resolved.
+	Resolved bool
+}
+
+// Events returns the events a message covers: the group of a digest or
+// the single event otherwise.
+func (m Message) Events() []AlertEvent {
+	if len(m.Group) > 0 {
+		return m.Group
+	}
+	return []AlertEvent{m.Event}
+}
+
+func newTemplateData(event AlertEvent, meta MonitorMeta) TemplateData {
+	if meta.ID == "" {
+		meta.ID = event.MonitorID.String()
+		meta.URL = event.MonitorURL
+		meta.Name = event.MonitorName
+		meta.RunbookURL = event.RunbookURL
+	}
+	return TemplateData{
+		Type:            event.Type,
+		IncidentID:      event.IncidentID.String(),
+		MonitorID:       event.MonitorID.String(),
+		MonitorURL:      event.MonitorURL,
+		MonitorName:     event.DisplayName(),
+		GroupName:       event.GroupName,
+		RunbookURL:      event.RunbookURL,
+		Reason:          event.Reason,
+		StatusCode:      event.StatusCode,
+		LatencyMs:       event.LatencyMs,
+		CheckedAt:       event.CheckedAt.UTC().Format(time.RFC1123Z),
+		EscalationLevel: event.EscalationLevel,
+		Downtime:        FormatDowntime(event.DowntimeSec),
+		DowntimeSec:     event.DowntimeSec,
+		ReminderCount:   event.ReminderCount,
+		Monitor:         meta,
+	}
+}
+
+// FormatDowntime renders a duration in seconds as "2d 3h", "1h 30m" or
+// "45m", keeping the two largest units.
+func FormatDowntime(sec int64) string {
+	if sec <= 0 {
+		return ""
+	}
+	d, rem := sec/86400, sec%86400
+	h, rem := rem/3600, rem%3600
+	m := rem / 60
+	switch {
+	case d > 0:
+		return fmt.Sprintf("%dd %dh", d, h)
+	case h > 0:
+		return fmt.Sprintf("%dh %dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm", m)
+	default:
+		return fmt.Sprintf("%ds", sec)
+	}
+}
+
+// sampleEvent is the data previews and template validation render against.
+func sampleEvent(alertType AlertType) (AlertEvent, MonitorMeta) {
+	monitorID := uuid.MustParse("00000000-0000-4000-8000-000000000001")
+	event := AlertEvent{
+		IncidentID:  uuid.MustParse("00000000-0000-4000-8000-000000000002"),
+		Type:        alertType,
+		MonitorID:   monitorID,
+		MonitorURL:  "https://api.example.com/health",
+		MonitorName: "Checkout API",
+		RunbookURL:  "https://wiki.example.com/runbooks/checkout-api",
+		Reason:      "STATUS_MISMATCH",
+		StatusCode:  503,
+		LatencyMs:   1240,
+		CheckedAt:   time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+	}
+	switch alertType {
+	case AlertTypeRecovered:
+		event.Reason = "RECOVERED"
+		event.StatusCode = 200
+		event.LatencyMs = 182
+	case AlertTypeReminder:
+		event.DowntimeSec = 5400
+		event.ReminderCount = 3
+	}
+	return event, MonitorMeta{
+		ID:                 monitorID.String(),
+		URL:                event.MonitorURL,
+		IntervalSec:        60,
+		TimeoutSec:         10,
+		ExpectedStatus:     200,
+		LatencyThresholdMs: 1000,
+		Name:               event.MonitorName,
+		Description:        "Public checkout API behind the storefront.",
+		RunbookURL:         event.RunbookURL,
+	}
+}
+
+// render executes a template set. The subject is always plain text; the
+// body uses html/template when the notifier asks for it.
+func (t Templates) render(set TemplateSet, data TemplateData) (string, string, error) {
+	subjectT, err := template.New("subject").Option("missingkey=error").Parse(set.Subject)
+	if err != nil {
+		return "", "", err
+	}
+	var subject strings.Builder
+	if err := subjectT.Execute(&subject, data); err != nil {
+		return "", "", err
+	}
+
+	var body strings.Builder
+	if t.HTMLBody {
+		bodyT, err := htmltemplate.New("body").Option("missingkey=error").Parse(set.Body)
+		if err != nil {
+			return "", "", err
+		}
+		if err := bodyT.Execute(&body, data); err != nil {
+			return "", "", err
+		}
+	} else {
+		bodyT, err := template.New("body").Option("missingkey=error").Parse(set.Body)
+		if err != nil {
+			return "", "", err
+		}
+		if err := bodyT.Execute(&body, data); err != nil {
+			return "", "", err
+		}
+	}
+
+	return strings.TrimSpace(subject.String()), strings.TrimSpace(body.String()), nil
+}
@@ -0,0 +1,178 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func (h *Handler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.list_templates"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	templates, err := h.service.ListTemplates(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list alert templates")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	items := make([]TemplateResponse, 0, len(templates))
+	for i := range templates {
+		items = append(items, toTemplateResponse(&templates[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alert templates retrieved", ListTemplatesResponse{Templates: items})
+}
+
+func (h *Handler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.get_template"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	channel, alertType := templateKey(r)
+	tpl, err := h.service.GetTemplate(ctx, tm.TeamID, channel, alertType)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get alert template")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alert template retrieved", toTemplateResponse(&tpl))
+}
+
+func (h *Handler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.save_template"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	var req SaveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	channel, alertType := templateKey(r)
+	tpl, err := h.service.SaveTemplate(ctx, tm.TeamID, CustomTemplate{
+		Channel: channel,
+		Type:    alertType,
+		Subject: req.Subject,
+		Body:    req.Body,
+	})
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to save alert template")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alert template saved", toTemplateResponse(&tpl))
+}
+
+func (h *Handler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.delete_template"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	channel, alertType := templateKey(r)
+	if err := h.service.DeleteTemplate(ctx, tm.TeamID, channel, alertType); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to delete alert template")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alert template reset to default", struct{}{})
+}
+
+func (h *Handler) PreviewTemplate(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.alert.preview_template"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	middle.SkipAudit(ctx)
+
+	var req PreviewTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	if req.Channel == "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "channel is required")
+		return
+	}
+	alertType := AlertType(strings.ToUpper(strings.TrimSpace(req.AlertType)))
+	if alertType == "" {
+		alertType = AlertTypeDown
+	}
+
+	preview, err := h.service.PreviewTemplate(ctx, tm.TeamID, req.Channel, alertType, req.Subject, req.Body)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to preview alert template")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "alert template rendered", PreviewTemplateResponse{
+		Channel:   preview.Channel,
+		AlertType: string(preview.Type),
+		Subject:   preview.Subject,
+		Body:      preview.Body,
+	})
+}
+
+func templateKey(r *http.Request) (string, AlertType) {
+	return chi.URLParam(r, "channel"), AlertType(strings.ToUpper(chi.URLParam(r, "alertType")))
+}
+
+func toTemplateResponse(t *EffectiveTemplate) TemplateResponse {
+	return TemplateResponse{
+		Channel:   t.Channel,
+		AlertType: string(t.Type),
+		Subject:   t.Subject,
+		Body:      t.Body,
+		HTMLBody:  t.HTMLBody,
+		Custom:    t.Custom,
+		UpdatedAt: toRFC3339Ptr(t.UpdatedAt),
+	}
+}
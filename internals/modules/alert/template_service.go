@@ -0,0 +1,263 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// customizableTypes are the alert types teams can override. TEST always
+// uses the built-in templates.
+var customizableTypes = []AlertType{AlertTypeDown, AlertTypeReminder, AlertTypeRecovered}
+
+func isCustomizableType(alertType AlertType) bool {
+	for _, t := range customizableTypes {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// render builds the message a notifier sends for an event. Custom templates
+// that can't be loaded or fail to render fall back to the built-in ones so
+// a broken template never blocks an alert.
+func (s *AlertService) render(ctx context.Context, n Notifier, event AlertEvent) (Message, error) {
+	tpls := n.Templates()
+	channel := n.Schema().Type
+	set := tpls.defaultFor(event.Type)
+
+	var meta MonitorMeta
+	if event.MonitorID != uuid.Nil {
+		m, found, err := s.repo.GetMonitorMeta(ctx, event.MonitorID)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("monitor_id", event.MonitorID.String()).Msg("failed to load monitor for alert template")
+		} else if found {
+			meta = m
+		}
+	}
+	data := newTemplateData(event, meta)
+
+	if isCustomizableType(event.Type) {
+		custom, found, err := s.repo.GetTemplate(ctx, event.TeamID, channel, event.Type)
+		if err != nil {
+			s.logger.Warn().Err(err).Str("plugin", channel).Msg("failed to load alert template, using default")
+		} else if found {
+			subject, body, err := tpls.render(mergeTemplate(custom.Subject, custom.Body, set), data)
+			if err == nil {
+				return Message{Event: event, Data: data, Subject: subject, Body: body}, nil
+			}
+			s.logger.Warn().Err(err).
+				Str("team_id", event.TeamID.String()).
+				Str("plugin", channel).
+				Str("alert_type", string(event.Type)).
+				Msg("custom alert template failed to render, using default")
+		}
+	}
+
+	subject, body, err := tpls.render(set, data)
+	if err != nil {
+		return Message{}, fmt.Errorf("render default %s template: %w", channel, err)
+	}
+	return Message{Event: event, Data: data, Subject: subject, Body: body}, nil
+}
+
+// renderDigest builds the message for a group of alerts from the
+// notifier's digest templates. Team overrides only apply to single alerts.
+func (s *AlertService) renderDigest(n Notifier, events []AlertEvent, groupID uuid.UUID) (Message, error) {
+	tpls := n.Templates()
+
+	items := make([]TemplateData, 0, len(events))
+	for _, e := range events {
+		items = append(items, newTemplateData(e, MonitorMeta{}))
+	}
+	data := items[0]
+	data.Count = len(items)
+	data.Alerts = items
+
+	subject, body, err := tpls.render(tpls.digestFor(events[0].Type), data)
+	if err != nil {
+		return Message{}, fmt.Errorf("render %s digest template: %w", n.Schema().Type, err)
+	}
+	return Message{
+		Event:   events[0],
+		Data:    data,
+		Subject: subject,
+		Body:    body,
+		Group:   events,
+		GroupID: groupID,
+	}, nil
+}
+
+// mergeTemplate fills an empty subject or body from the default set.
+func mergeTemplate(subject, body string, def TemplateSet) TemplateSet {
+	if strings.TrimSpace(subject) == "" {
+		subject = def.Subject
+	}
+	if strings.TrimSpace(body) == "" {
+		body = def.Body
+	}
+	return TemplateSet{Subject: subject, Body: body}
+}
+
+func (s *AlertService) ListTemplates(ctx context.Context, teamID uuid.UUID) ([]EffectiveTemplate, error) {
+	custom, err := s.repo.ListTemplates(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]CustomTemplate, len(custom))
+	for _, c := range custom {
+		byKey[c.Channel+"/"+string(c.Type)] = c
+	}
+
+	var out []EffectiveTemplate
+	for _, n := range s.notifiers.All() {
+		channel := n.Schema().Type
+		for _, t := range customizableTypes {
+			if c, ok := byKey[channel+"/"+string(t)]; ok {
+				out = append(out, toEffective(n, t, &c))
+			} else {
+				out = append(out, toEffective(n, t, nil))
+			}
+		}
+	}
+	return out, nil
+}
+
+func (s *AlertService) GetTemplate(ctx context.Context, teamID uuid.UUID, channel string, alertType AlertType) (EffectiveTemplate, error) {
+	const op = "service.alert.get_template"
+
+	n, err := s.templateNotifier(op, channel, alertType)
+	if err != nil {
+		return EffectiveTemplate{}, err
+	}
+
+	custom, found, err := s.repo.GetTemplate(ctx, teamID, channel, alertType)
+	if err != nil {
+		return EffectiveTemplate{}, err
+	}
+	if !found {
+		return toEffective(n, alertType, nil), nil
+	}
+	return toEffective(n, alertType, &custom), nil
+}
+
+// SaveTemplate validates a template against sample data and stores it as
+// the team's override. An empty subject or body keeps the default for that
+// part.
+func (s *AlertService) SaveTemplate(ctx context.Context, teamID uuid.UUID, tpl CustomTemplate) (EffectiveTemplate, error) {
+	const op = "service.alert.save_template"
+
+	n, err := s.templateNotifier(op, tpl.Channel, tpl.Type)
+	if err != nil {
+		return EffectiveTemplate{}, err
+	}
+	if strings.TrimSpace(tpl.Subject) == "" && strings.TrimSpace(tpl.Body) == "" {
+		return EffectiveTemplate{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "subject or body is required"}
+	}
+	if err := validateTemplate(op, n, tpl.Type, tpl.Subject, tpl.Body); err != nil {
+		return EffectiveTemplate{}, err
+	}
+
+	saved, err := s.repo.UpsertTemplate(ctx, teamID, tpl)
+	if err != nil {
+		return EffectiveTemplate{}, err
+	}
+	return toEffective(n, tpl.Type, &saved), nil
+}
+
+func (s *AlertService) DeleteTemplate(ctx context.Context, teamID uuid.UUID, channel string, alertType AlertType) error {
+	const op = "service.alert.delete_template"
+
+	if _, err := s.templateNotifier(op, channel, alertType); err != nil {
+		return err
+	}
+	return s.repo.DeleteTemplate(ctx, teamID, channel, alertType)
+}
+
+// PreviewTemplate renders a template against sample data. Nil subject or
+// body use the team's stored template, or the default when there is none.
+func (s *AlertService) PreviewTemplate(ctx context.Context, teamID uuid.UUID, channel string, alertType AlertType, subject, body *string) (TemplatePreview, error) {
+	const op = "service.alert.preview_template"
+
+	current, err := s.GetTemplate(ctx, teamID, channel, alertType)
+	if err != nil {
+		return TemplatePreview{}, err
+	}
+	if subject != nil {
+		current.Subject = *subject
+	}
+	if body != nil {
+		current.Body = *body
+	}
+	if err := checkTemplateLength(op, current.Subject, current.Body); err != nil {
+		return TemplatePreview{}, err
+	}
+
+	n, _ := s.notifiers.Get(channel)
+	tpls := n.Templates()
+	event, meta := sampleEvent(alertType)
+	event.TeamID = teamID
+	renderedSubject, renderedBody, err := tpls.render(mergeTemplate(current.Subject, current.Body, tpls.defaultFor(alertType)), newTemplateData(event, meta))
+	if err != nil {
+		return TemplatePreview{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "invalid template: " + err.Error()}
+	}
+	return TemplatePreview{Channel: channel, Type: alertType, Subject: renderedSubject, Body: renderedBody}, nil
+}
+
+func (s *AlertService) templateNotifier(op, channel string, alertType AlertType) (Notifier, error) {
+	n, ok := s.notifiers.Get(channel)
+	if !ok {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: ErrUnknownNotifier.Error()}
+	}
+	if !isCustomizableType(alertType) {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "alert_type must be DOWN, REMINDER or RECOVERED"}
+	}
+	return n, nil
+}
+
+func validateTemplate(op string, n Notifier, alertType AlertType, subject, body string) error {
+	if err := checkTemplateLength(op, subject, body); err != nil {
+		return err
+	}
+	tpls := n.Templates()
+	event, meta := sampleEvent(alertType)
+	if _, _, err := tpls.render(mergeTemplate(subject, body, tpls.defaultFor(alertType)), newTemplateData(event, meta)); err != nil {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "invalid template: " + err.Error()}
+	}
+	return nil
+}
+
+func checkTemplateLength(op, subject, body string) error {
+	if len(subject) > maxTemplateSubjectLen {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("subject must be at most %d characters", maxTemplateSubjectLen)}
+	}
+	if len(body) > maxTemplateBodyLen {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("body must be at most %d characters", maxTemplateBodyLen)}
+	}
+	return nil
+}
+
+func toEffective(n Notifier, alertType AlertType, custom *CustomTemplate) EffectiveTemplate {
+	tpls := n.Templates()
+	def := tpls.defaultFor(alertType)
+	out := EffectiveTemplate{
+		Channel:  n.Schema().Type,
+		Type:     alertType,
+		Subject:  def.Subject,
+		Body:     def.Body,
+		HTMLBody: tpls.HTMLBody,
+	}
+	if custom != nil {
+		merged := mergeTemplate(custom.Subject, custom.Body, def)
+		updatedAt := custom.UpdatedAt
+		out.Subject = merged.Subject
+		out.Body = merged.Body
+		out.Custom = true
+		out.UpdatedAt = &updatedAt
+	}
+	return out
+}
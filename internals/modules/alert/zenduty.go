@@ -0,0 +1,210 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/zenduty"
+	"github.com/google/uuid"
+)
+
+const NotifierZenduty = "zenduty"
+
+type zendutyNotifier struct{}
+
+func NewZendutyNotifier() Notifier {
+	return zendutyNotifier{}
+}
+
+func (zendutyNotifier) Schema() Schema {
+	return Schema{
+		Type:        NotifierZenduty,
+		Name:        "Zenduty",
+		Description: "Create and auto-resolve Zenduty incidents when monitors go down.",
+		Category:    "Incident Management",
+		Fields: []ConfigField{
+			{Key: "integration_url", Label: "Webhook URL", Placeholder: "https://events.zenduty.com/integration/…", Required: true, Secret: true},
+			{Key: "webhook_secret", Label: "Inbound webhook secret", Placeholder: "at least 16 characters", Secret: true},
+		},
+	}
+}
+
+// Templates maps the subject to the Zenduty event message and the body to
+// its summary.
+func (zendutyNotifier) Templates() Templates {
+	return Templates{
+		Defaults: map[AlertType]TemplateSet{
+			AlertTypeDown: {
+				Subject: "{{ .MonitorName }} is DOWN",
+				Body:    "{{ .Reason }}",
+			},
+			AlertTypeReminder: {
+				Subject: "{{ .MonitorName }} is still DOWN ({{ .Downtime }})",
+				Body:    "{{ .Reason }}",
+			},
+			AlertTypeRecovered: {
+				Subject: "{{ .MonitorName }} is UP",
+				Body:    "Monitor has recovered and is responding normally",
+			},
+			AlertTypeTest: {
+				Subject: "Sofon test notification",
+				Body:    "This is a test event sent from the Sofon plugin settings. No monitor is affected.",
+			},
+			AlertTypeSnoozeEnded: {
+				Subject: "{{ .MonitorName }} snooze ended",
+				Body:    "The snooze has ended; failures will open incidents again",
+			},
+		},
+		Digests: map[AlertType]TemplateSet{
+			AlertTypeDown: {
+				Subject: "{{ .Count }} monitors are DOWN",
+				Body:    "{{ range .Alerts }}{{ .MonitorName }}{{ if .Reason }} ({{ .Reason }}){{ end }}\n{{ end }}",
+			},
+			AlertTypeReminder: {
+				Subject: "{{ .Count }} monitors are still DOWN",
+				Body:    "{{ range .Alerts }}{{ .MonitorName }} down for {{ .Downtime }}\n{{ end }}",
+			},
+			AlertTypeRecovered: {
+				Subject: "{{ .Count }} monitors are UP",
+				Body:    "{{ range .Alerts }}{{ .MonitorName }}\n{{ end }}",
+			},
+		},
+	}
+}
+
+// zendutyMinSecretLen is the shortest accepted inbound webhook secret.
+const zendutyMinSecretLen = 16
+
+func (zendutyNotifier) Validate(cfg map[string]string) error {
+	if !isHTTPSURL(cfg["integration_url"]) {
+		return errors.New("zenduty integration_url must be a valid https URL")
+	}
+	if secret := cfg["webhook_secret"]; secret != "" && len(secret) < zendutyMinSecretLen {
+		return fmt.Errorf("zenduty webhook_secret must be at least %d characters", zendutyMinSecretLen)
+	}
+	return nil
+}
+
+func (zendutyNotifier) Send(ctx context.Context, cfg map[string]string, msg Message) (Delivery, error) {
+	client := zenduty.NewClient(cfg["integration_url"])
+	event := msg.Event
+
+	switch {
+	case len(msg.Group) > 0 && event.Type == AlertTypeDown:
+		// a digest opens a single Zenduty incident
+		urls := make([]string, 0, len(msg.Group))
+		names := make([]string, 0, len(msg.Group))
+		incidents := make([]string, 0, len(msg.Group))
+		for _, e := range msg.Group {
+			urls = append(urls, e.MonitorURL)
+			names = append(names, e.DisplayName())
+			incidents = append(incidents, e.IncidentID.String())
+		}
+		req := &zenduty.EventRequest{
+			AlertType: zenduty.AlertTypeCritical,
+			Message:   msg.Subject,
+			Summary:   msg.Body,
+			EntityID:  zendutyDigestEntity(msg.GroupID),
+			Payload: map[string]string{
+				"monitor_count": fmt.Sprintf("%d", len(msg.Group)),
+				"monitor_urls":  strings.Join(urls, ","),
+				"monitor_names": strings.Join(names, ","),
+				"incident_ids":  strings.Join(incidents, ","),
+			},
+		}
+		resp, err := client.SendEvent(ctx, req)
+		if err != nil {
+			return Delivery{}, err
+		}
+		return Delivery{ExternalID: resp.TraceID}, nil
+
+	case event.Type == AlertTypeRecovered || event.Type == AlertTypeReminder:
+		return sendZendutyUpdates(ctx, client, msg)
+	}
+
+	alertType := zenduty.AlertTypeCritical
+	if event.Type == AlertTypeTest || event.Type == AlertTypeSnoozeEnded {
+		alertType = zenduty.AlertTypeInfo
+	}
+	resp, err := client.SendEvent(ctx, zendutyRequest(alertType, event.MonitorID.String(), msg.Subject, msg.Body, event))
+	if err != nil {
+		return Delivery{}, err
+	}
+	return Delivery{ExternalID: resp.TraceID}, nil
+}
+
+// sendZendutyUpdates sends reminders and recoveries to the Zenduty incident
+// each alert was opened in: the monitor's own, or its DOWN digest's. A
+// digest's incident is only resolved once all of its monitors recovered.
+func sendZendutyUpdates(ctx context.Context, client zenduty.Client, msg Message) (Delivery, error) {
+	alertType := zenduty.AlertTypeCritical
+	if msg.Event.Type == AlertTypeRecovered {
+		alertType = zenduty.AlertTypeResolved
+	}
+
+	seen := make(map[string]bool)
+	var traceIDs []string
+	for _, e := range msg.Events() {
+		entity := e.MonitorID.String()
+		if dg, ok := msg.DownGroups[e.IncidentID]; ok {
+			if alertType == zenduty.AlertTypeResolved && !dg.Resolved {
+				continue
+			}
+			entity = zendutyDigestEntity(dg.ID)
+		}
+		if seen[entity] {
+			continue
+		}
+		seen[entity] = true
+
+		resp, err := client.SendEvent(ctx, zendutyRequest(alertType, entity, msg.Subject, msg.Body, e))
+		if err != nil {
+			return Delivery{ExternalID: strings.Join(traceIDs, ",")}, err
+		}
+		traceIDs = append(traceIDs, resp.TraceID)
+	}
+	return Delivery{ExternalID: strings.Join(traceIDs, ",")}, nil
+}
+
+func zendutyRequest(alertType zenduty.AlertType, entityID, subject, body string, event AlertEvent) *zenduty.EventRequest {
+	req := &zenduty.EventRequest{
+		AlertType: alertType,
+		Message:   subject,
+		Summary:   body,
+		EntityID:  entityID,
+		Payload: map[string]string{
+			"status_code":  fmt.Sprintf("%d", event.StatusCode),
+			"monitor_url":  event.MonitorURL,
+			"monitor_name": event.DisplayName(),
+			"latency_ms":   fmt.Sprintf("%d", event.LatencyMs),
+			"incident_id":  event.IncidentID.String(),
+		},
+		URLs: []zenduty.EventURL{
+			{LinkURL: event.MonitorURL, LinkText: "Affected URL"},
+		},
+	}
+	if event.RunbookURL != "" {
+		req.Payload["runbook_url"] = event.RunbookURL
+		req.URLs = append(req.URLs, zenduty.EventURL{LinkURL: event.RunbookURL, LinkText: "Runbook"})
+	}
+	return req
+}
+
+const zendutyDigestPrefix = "sofon-digest-"
+
+func zendutyDigestEntity(groupID uuid.UUID) string {
+	return zendutyDigestPrefix + groupID.String()
+}
+
+// ParseZendutyEntity reverses the entity IDs Sofon sends to Zenduty: a
+// monitor ID for single alerts, or a digest's group ID for digests.
+func ParseZendutyEntity(entityID string) (id uuid.UUID, digest bool, err error) {
+	if rest, ok := strings.CutPrefix(entityID, zendutyDigestPrefix); ok {
+		id, err = uuid.Parse(rest)
+		return id, true, err
+	}
+	id, err = uuid.Parse(entityID)
+	return id, false, err
+}
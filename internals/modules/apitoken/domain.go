@@ -0,0 +1,59 @@
+package apitoken
+
+import (
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/google/uuid"
+)
+
+// Scopes an API token can carry. A write scope covers reading the same
+// resources.
+const (
+	ScopeMonitorsRead  = "monitors:read"
+	ScopeMonitorsWrite = "monitors:write"
+	ScopeIncidentsRead = "incidents:read"
+	ScopePluginsWrite  = "plugins:write"
+)
+
+var scopes = []string{ScopeMonitorsRead, ScopeMonitorsWrite, ScopeIncidentsRead, ScopePluginsWrite}
+
+const (
+	// personal and team tokens are told apart by their prefix, e.g.
+	// sofon_pat_3f2a… and sofon_tat_9c41…
+	personalPrefix = middle.APITokenPrefix + "pat_"
+	teamPrefix     = middle.APITokenPrefix + "tat_"
+
+	// how much of the random part the listed prefix shows
+	shownSecretChars = 8
+	maxNameLen       = 100
+	// active tokens per user, or per team
+	maxTokens = 50
+)
+
+// Token is an API token as listed; the secret itself is only returned
+// when it is created. TeamID is nil for personal tokens. UserID is the
+// owner of a personal token and the creator of a team token.
+type Token struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	TeamID     *uuid.UUID
+	Name       string
+	Prefix     string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type CreateInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// Created is a new token with its secret.
+type Created struct {
+	Token
+	Secret string
+}
@@ -0,0 +1,55 @@
+package apitoken
+
+import "time"
+
+type CreateTokenRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1"`
+	// ExpiresAt is an RFC 3339 time; omitted, the token never expires.
+	ExpiresAt *string `json:"expires_at"`
+}
+
+type TokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	TeamID     *string    `json:"team_id"`
+	CreatedBy  string     `json:"created_by"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateTokenResponse carries the token itself, which is never shown
+// again.
+type CreateTokenResponse struct {
+	TokenResponse
+	Token string `json:"token"`
+}
+
+func toTokenResponse(t Token) TokenResponse {
+	res := TokenResponse{
+		ID:         t.ID.String(),
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.Scopes,
+		CreatedBy:  t.UserID.String(),
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+	if t.TeamID != nil {
+		teamID := t.TeamID.String()
+		res.TeamID = &teamID
+	}
+	return res
+}
+
+func toTokenResponses(tokens []Token) []TokenResponse {
+	out := make([]TokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		out = append(out, toTokenResponse(t))
+	}
+	return out
+}
@@ -0,0 +1,218 @@
+package apitoken
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	service   *Service
+	validator *validator.Validate
+	logger    *zerolog.Logger
+}
+
+func NewHandler(service *Service, validator *validator.Validate, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) ListPersonal(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.list_personal"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	tokens, err := h.service.ListPersonal(ctx, userID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list API tokens")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "API tokens retrieved", toTokenResponses(tokens))
+}
+
+func (h *Handler) CreatePersonal(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.create_personal"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	in, msg := h.decodeCreate(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	c, err := h.service.CreatePersonal(ctx, userID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to create API token")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "API token created, copy it now: it won't be shown again", CreateTokenResponse{
+		TokenResponse: toTokenResponse(c.Token),
+		Token:         c.Secret,
+	})
+}
+
+func (h *Handler) RevokePersonal(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.revoke_personal"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, ok := userIDFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "tokenID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid token id")
+		return
+	}
+
+	if err := h.service.RevokePersonal(ctx, userID, tokenID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to revoke API token")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "API token revoked", struct{}{})
+}
+
+func (h *Handler) ListTeam(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.list_team"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	tokens, err := h.service.ListTeam(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list team API tokens")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "API tokens retrieved", toTokenResponses(tokens))
+}
+
+func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.create_team"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	in, msg := h.decodeCreate(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	c, err := h.service.CreateTeam(ctx, tm.TeamID, tm.UserID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to create team API token")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "API token created, copy it now: it won't be shown again", CreateTokenResponse{
+		TokenResponse: toTokenResponse(c.Token),
+		Token:         c.Secret,
+	})
+}
+
+func (h *Handler) RevokeTeam(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.apitoken.revoke_team"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	tokenID, err := uuid.Parse(chi.URLParam(r, "tokenID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid token id")
+		return
+	}
+
+	if err := h.service.RevokeTeam(ctx, tm.TeamID, tokenID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to revoke team API token")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "API token revoked", struct{}{})
+}
+
+// decodeCreate reads a create request. A non-empty message means the
+// request is invalid.
+func (h *Handler) decodeCreate(r *http.Request) (CreateInput, string) {
+	var req CreateTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return CreateInput{}, "invalid request body"
+	}
+	if err := h.validator.Struct(req); err != nil {
+		return CreateInput{}, "name and at least one scope are required"
+	}
+
+	in := CreateInput{Name: req.Name, Scopes: req.Scopes}
+	if req.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			return CreateInput{}, "expires_at must be an RFC 3339 time"
+		}
+		in.ExpiresAt = &t
+	}
+	return in, ""
+}
+
+func userIDFromContext(r *http.Request) (uuid.UUID, bool) {
+	claims, ok := middle.UserFromContext(r.Context())
+	if !ok {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
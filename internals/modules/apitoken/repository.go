@@ -0,0 +1,194 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// authToken is a token looked up to authenticate a request, with the user
+// it acts as.
+type authToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	TeamID    *uuid.UUID
+	Scopes    []string
+	ExpiresAt *time.Time
+	Email     string
+	Role      string
+}
+
+func (r *Repository) Create(ctx context.Context, userID uuid.UUID, teamID *uuid.UUID, in CreateInput, prefix, hash string) (Token, error) {
+	const op string = "repo.apitoken.create"
+
+	var expiresAt pgtype.Timestamptz
+	if in.ExpiresAt != nil {
+		expiresAt = utils.ToPgTimestamptz(in.ExpiresAt.UTC())
+	}
+	row, err := r.querier.CreateAPIToken(ctx, db.CreateAPITokenParams{
+		UserID:    utils.ToPgUUID(userID),
+		TeamID:    toPgUUIDPtr(teamID),
+		Name:      in.Name,
+		Prefix:    prefix,
+		TokenHash: hash,
+		Scopes:    in.Scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return Token{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toToken(row), nil
+}
+
+func (r *Repository) ListForUser(ctx context.Context, userID uuid.UUID) ([]Token, error) {
+	const op string = "repo.apitoken.list_for_user"
+
+	rows, err := r.querier.ListUserAPITokens(ctx, utils.ToPgUUID(userID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	tokens := make([]Token, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, toToken(row))
+	}
+	return tokens, nil
+}
+
+func (r *Repository) ListForTeam(ctx context.Context, teamID uuid.UUID) ([]Token, error) {
+	const op string = "repo.apitoken.list_for_team"
+
+	rows, err := r.querier.ListTeamAPITokens(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	tokens := make([]Token, 0, len(rows))
+	for _, row := range rows {
+		tokens = append(tokens, toToken(row))
+	}
+	return tokens, nil
+}
+
+func (r *Repository) RevokeForUser(ctx context.Context, userID, tokenID uuid.UUID) error {
+	const op string = "repo.apitoken.revoke_for_user"
+
+	n, err := r.querier.RevokeUserAPIToken(ctx, db.RevokeUserAPITokenParams{
+		ID:     utils.ToPgUUID(tokenID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "API token not found"}
+	}
+	return nil
+}
+
+// RevokeForTeam revokes a team token and returns its name and prefix.
+func (r *Repository) RevokeForTeam(ctx context.Context, teamID, tokenID uuid.UUID) (Token, error) {
+	const op string = "repo.apitoken.revoke_for_team"
+
+	row, err := r.querier.RevokeTeamAPIToken(ctx, db.RevokeTeamAPITokenParams{
+		ID:     utils.ToPgUUID(tokenID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Token{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "API token not found"}
+		}
+		return Token{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return Token{ID: tokenID, TeamID: &teamID, Name: row.Name, Prefix: row.Prefix}, nil
+}
+
+// GetByHash returns the unrevoked token with the given hash.
+func (r *Repository) GetByHash(ctx context.Context, hash string) (authToken, error) {
+	const op string = "repo.apitoken.get_by_hash"
+
+	row, err := r.querier.GetAPITokenByHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return authToken{}, &apperror.Error{Kind: apperror.Unauthorised, Op: op, Message: "invalid API token"}
+		}
+		return authToken{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	t := authToken{
+		ID:     utils.FromPgUUID(row.ID),
+		UserID: utils.FromPgUUID(row.UserID),
+		TeamID: fromPgUUIDPtr(row.TeamID),
+		Scopes: row.Scopes,
+		Email:  row.Email,
+		Role:   row.Role,
+	}
+	if row.ExpiresAt.Valid {
+		exp := utils.FromPgTimestamptz(row.ExpiresAt)
+		t.ExpiresAt = &exp
+	}
+	return t, nil
+}
+
+// Touch records that the token was just used.
+func (r *Repository) Touch(ctx context.Context, tokenID uuid.UUID) error {
+	const op string = "repo.apitoken.touch"
+
+	if err := r.querier.TouchAPIToken(ctx, utils.ToPgUUID(tokenID)); err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+func toToken(row db.ApiToken) Token {
+	t := Token{
+		ID:        utils.FromPgUUID(row.ID),
+		UserID:    utils.FromPgUUID(row.UserID),
+		TeamID:    fromPgUUIDPtr(row.TeamID),
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		Scopes:    row.Scopes,
+		CreatedAt: utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if row.ExpiresAt.Valid {
+		exp := utils.FromPgTimestamptz(row.ExpiresAt)
+		t.ExpiresAt = &exp
+	}
+	if row.LastUsedAt.Valid {
+		used := utils.FromPgTimestamptz(row.LastUsedAt)
+		t.LastUsedAt = &used
+	}
+	return t
+}
+
+func toPgUUIDPtr(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return utils.ToPgUUID(*id)
+}
+
+func fromPgUUIDPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	v := utils.FromPgUUID(id)
+	return &v
+}
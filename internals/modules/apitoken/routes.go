@@ -0,0 +1,25 @@
+package apitoken
+
+import "github.com/go-chi/chi/v5"
+
+// Routes serves the caller's personal tokens.
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListPersonal)
+	r.Post("/", h.CreatePersonal)
+	r.Delete("/{tokenID}", h.RevokePersonal)
+
+	return r
+}
+
+// TeamRoutes serves a team's tokens.
+func TeamRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListTeam)
+	r.Post("/", h.CreateTeam)
+	r.Delete("/{tokenID}", h.RevokeTeam)
+
+	return r
+}
@@ -0,0 +1,174 @@
+package apitoken
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/security"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	repo   *Repository
+	logger *zerolog.Logger
+}
+
+func NewService(repo *Repository, logger *zerolog.Logger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreatePersonal creates a token that acts as the user in any of their
+// teams, limited to its scopes.
+func (s *Service) CreatePersonal(ctx context.Context, userID uuid.UUID, in CreateInput) (Created, error) {
+	const op = "service.apitoken.create_personal"
+
+	in, err := checkInput(op, in)
+	if err != nil {
+		return Created{}, err
+	}
+	existing, err := s.repo.ListForUser(ctx, userID)
+	if err != nil {
+		return Created{}, err
+	}
+	if len(existing) >= maxTokens {
+		return Created{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("a user can have at most %d API tokens", maxTokens)}
+	}
+	return s.create(ctx, op, userID, nil, in, personalPrefix)
+}
+
+// CreateTeam creates a token for the team. It works in that team only and
+// acts with the membership of the user creating it, so it stops working
+// if they leave the team or are deactivated.
+func (s *Service) CreateTeam(ctx context.Context, teamID, userID uuid.UUID, in CreateInput) (Created, error) {
+	const op = "service.apitoken.create_team"
+
+	in, err := checkInput(op, in)
+	if err != nil {
+		return Created{}, err
+	}
+	existing, err := s.repo.ListForTeam(ctx, teamID)
+	if err != nil {
+		return Created{}, err
+	}
+	if len(existing) >= maxTokens {
+		return Created{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("a team can have at most %d API tokens", maxTokens)}
+	}
+
+	c, err := s.create(ctx, op, userID, &teamID, in, teamPrefix)
+	if err != nil {
+		return Created{}, err
+	}
+	middle.SetAuditTarget(ctx, c.ID.String())
+	middle.SetAuditChange(ctx, nil, auditSummary(c.Token))
+	return c, nil
+}
+
+func (s *Service) create(ctx context.Context, op string, userID uuid.UUID, teamID *uuid.UUID, in CreateInput, kindPrefix string) (Created, error) {
+	random, err := security.NewOpaqueToken(24)
+	if err != nil {
+		return Created{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "failed to generate API token", Err: err}
+	}
+	secret := kindPrefix + random
+
+	t, err := s.repo.Create(ctx, userID, teamID, in, secret[:len(kindPrefix)+shownSecretChars], security.HashToken(secret))
+	if err != nil {
+		return Created{}, err
+	}
+	return Created{Token: t, Secret: secret}, nil
+}
+
+func (s *Service) ListPersonal(ctx context.Context, userID uuid.UUID) ([]Token, error) {
+	return s.repo.ListForUser(ctx, userID)
+}
+
+func (s *Service) ListTeam(ctx context.Context, teamID uuid.UUID) ([]Token, error) {
+	return s.repo.ListForTeam(ctx, teamID)
+}
+
+func (s *Service) RevokePersonal(ctx context.Context, userID, tokenID uuid.UUID) error {
+	return s.repo.RevokeForUser(ctx, userID, tokenID)
+}
+
+func (s *Service) RevokeTeam(ctx context.Context, teamID, tokenID uuid.UUID) error {
+	t, err := s.repo.RevokeForTeam(ctx, teamID, tokenID)
+	if err != nil {
+		return err
+	}
+
+	middle.SetAuditChange(ctx, map[string]any{"name": t.Name, "prefix": t.Prefix}, nil)
+	return nil
+}
+
+// AuthenticateAPIToken looks up a bearer token for the auth middleware and
+// notes that it was used. Revoked and expired tokens are rejected.
+func (s *Service) AuthenticateAPIToken(ctx context.Context, token string) (*middle.APIToken, *security.RequestClaims, error) {
+	const op = "service.apitoken.authenticate"
+
+	t, err := s.repo.GetByHash(ctx, security.HashToken(token))
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.ExpiresAt != nil && !time.Now().Before(*t.ExpiresAt) {
+		return nil, nil, &apperror.Error{Kind: apperror.Unauthorised, Op: op, Message: "API token has expired"}
+	}
+
+	if err := s.repo.Touch(ctx, t.ID); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("token_id", t.ID.String()).Msg("failed to record API token use")
+	}
+
+	return &middle.APIToken{ID: t.ID, TeamID: t.TeamID, Scopes: t.Scopes}, &security.RequestClaims{
+		UserID: t.UserID.String(),
+		Email:  t.Email,
+		Role:   t.Role,
+	}, nil
+}
+
+func checkInput(op string, in CreateInput) (CreateInput, error) {
+	invalid := func(msg string) (CreateInput, error) {
+		return CreateInput{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || len(in.Name) > maxNameLen {
+		return invalid(fmt.Sprintf("name must be 1 to %d characters", maxNameLen))
+	}
+
+	if len(in.Scopes) == 0 {
+		return invalid("at least one scope is required")
+	}
+	normalized := make([]string, 0, len(in.Scopes))
+	for _, sc := range in.Scopes {
+		sc = strings.ToLower(strings.TrimSpace(sc))
+		if !slices.Contains(scopes, sc) {
+			return invalid("scopes must be among " + strings.Join(scopes, ", "))
+		}
+		if !slices.Contains(normalized, sc) {
+			normalized = append(normalized, sc)
+		}
+	}
+	slices.Sort(normalized)
+	in.Scopes = normalized
+
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(time.Now()) {
+		return invalid("expires_at must be in the future")
+	}
+	return in, nil
+}
+
+// auditSummary is the part of a token the audit log shows.
+func auditSummary(t Token) map[string]any {
+	summary := map[string]any{"name": t.Name, "prefix": t.Prefix, "scopes": t.Scopes}
+	if t.ExpiresAt != nil {
+		summary["expires_at"] = t.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return summary
+}
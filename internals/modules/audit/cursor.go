@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type cursorPayload struct {
+	CreatedAt string `json:"c"`
+	EventID   string `json:"i"`
+}
+
+func EncodeCursor(c Cursor) (string, error) {
+	p := cursorPayload{
+		CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		EventID:   c.EventID,
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func DecodeCursor(v string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if p.CreatedAt == "" || p.EventID == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, p.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return &Cursor{
+		CreatedAt: created,
+		EventID:   p.EventID,
+	}, nil
+}
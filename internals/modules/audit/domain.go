@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is one entry of a team's audit log. Before and After are the JSON
+// summaries the change was recorded with, nil when it had none.
+type Event struct {
+	ID         uuid.UUID
+	ActorID    *uuid.UUID
+	ActorEmail string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	IP         string
+	Before     json.RawMessage
+	After      json.RawMessage
+	CreatedAt  time.Time
+}
+
+type Cursor struct {
+	CreatedAt time.Time
+	EventID   string
+}
+
+// ListFilters narrow the log. Action also matches the actions below it, so
+// "monitors" matches "monitors.delete".
+type ListFilters struct {
+	ActorID    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+}
+
+type ListOptions struct {
+	Limit   int32
+	Cursor  *Cursor
+	Filters ListFilters
+}
+
+type Page struct {
+	Events     []Event
+	HasMore    bool
+	NextCursor *string
+	Limit      int32
+}
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type EventResponse struct {
+	ID         string          `json:"id"`
+	ActorID    *string         `json:"actor_id"`
+	ActorEmail string          `json:"actor_email,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type ListEventsResponse struct {
+	Limit      int32           `json:"limit"`
+	HasMore    bool            `json:"has_more"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+	Events     []EventResponse `json:"events"`
+}
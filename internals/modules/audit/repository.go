@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+func (r *Repository) Create(ctx context.Context, e middle.AuditEvent) error {
+	const op string = "repo.audit.create"
+
+	before, err := marshalSummary(e.Before)
+	if err != nil {
+		return &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	after, err := marshalSummary(e.After)
+	if err != nil {
+		return &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	var actorID pgtype.UUID
+	if e.ActorID != uuid.Nil {
+		actorID = utils.ToPgUUID(e.ActorID)
+	}
+	err = r.querier.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		TeamID:     utils.ToPgUUID(e.TeamID),
+		ActorID:    actorID,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		RequestID:  e.RequestID,
+		Ip:         e.IP,
+		Before:     before,
+		After:      after,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+// List returns a page of the team's events, newest first, and whether
+// there are more.
+func (r *Repository) List(ctx context.Context, teamID uuid.UUID, opts ListOptions) ([]Event, bool, error) {
+	const op string = "repo.audit.list"
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var actorID pgtype.UUID
+	if opts.Filters.ActorID != nil {
+		actorID = utils.ToPgUUID(*opts.Filters.ActorID)
+	}
+	var fromTS, toTS pgtype.Timestamptz
+	if opts.Filters.From != nil {
+		fromTS = utils.ToPgTimestamptz(opts.Filters.From.UTC())
+	}
+	if opts.Filters.To != nil {
+		toTS = utils.ToPgTimestamptz(opts.Filters.To.UTC())
+	}
+
+	var cursorCreated pgtype.Timestamptz
+	var cursorID pgtype.UUID
+	if opts.Cursor != nil {
+		id, err := uuid.Parse(opts.Cursor.EventID)
+		if err != nil {
+			return nil, false, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "invalid cursor"}
+		}
+		cursorCreated = utils.ToPgTimestamptz(opts.Cursor.CreatedAt.UTC())
+		cursorID = utils.ToPgUUID(id)
+	}
+
+	rows, err := r.querier.ListAuditEvents(ctx, db.ListAuditEventsParams{
+		TeamID:  utils.ToPgUUID(teamID),
+		Column2: actorID,
+		Column3: opts.Filters.Action,
+		Column4: opts.Filters.TargetType,
+		Column5: opts.Filters.TargetID,
+		Column6: fromTS,
+		Column7: toTS,
+		Column8: cursorCreated,
+		Column9: cursorID,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		return nil, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		e := Event{
+			ID:         utils.FromPgUUID(row.ID),
+			ActorEmail: row.ActorEmail,
+			Action:     row.Action,
+			TargetType: row.TargetType,
+			TargetID:   row.TargetID,
+			RequestID:  row.RequestID,
+			IP:         row.Ip,
+			Before:     row.Before,
+			After:      row.After,
+			CreatedAt:  utils.FromPgTimestamptz(row.CreatedAt),
+		}
+		if row.ActorID.Valid {
+			id := utils.FromPgUUID(row.ActorID)
+			e.ActorID = &id
+		}
+		events = append(events, e)
+	}
+
+	hasMore := len(events) > int(limit)
+	if hasMore {
+		events = events[:limit]
+	}
+	return events, hasMore, nil
+}
+
+// marshalSummary encodes a change summary, leaving a missing one NULL.
+func marshalSummary(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
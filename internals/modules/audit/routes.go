@@ -0,0 +1,9 @@
+package audit
+
+import "github.com/go-chi/chi/v5"
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.ListEvents)
+	return r
+}
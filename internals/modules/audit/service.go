@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// exportPageSize is how many events an export reads at a time.
+	exportPageSize = 500
+	// maxExportEvents caps an export; narrow the filters for more.
+	maxExportEvents = 50000
+)
+
+type Service struct {
+	repo   *Repository
+	logger *zerolog.Logger
+}
+
+func NewService(repo *Repository, logger *zerolog.Logger) *Service {
+	return &Service{repo: repo, logger: logger}
+}
+
+// Record stores an event. The audit log doesn't fail the change it
+// records, so errors are only logged.
+func (s *Service) Record(ctx context.Context, e middle.AuditEvent) {
+	const op = "service.audit.record"
+
+	if err := s.repo.Create(ctx, e); err != nil {
+		s.logger.Error().
+			Str("op", op).
+			Err(err).
+			Str("team_id", e.TeamID.String()).
+			Str("action", e.Action).
+			Str("request_id", e.RequestID).
+			Msg("failed to record audit event")
+	}
+}
+
+func (s *Service) List(ctx context.Context, teamID uuid.UUID, opts ListOptions) (Page, error) {
+	const op = "service.audit.list"
+
+	events, hasMore, err := s.repo.List(ctx, teamID, opts)
+	if err != nil {
+		return Page{}, err
+	}
+
+	var nextCursor *string
+	if hasMore && len(events) > 0 {
+		last := events[len(events)-1]
+		cursor, err := EncodeCursor(Cursor{CreatedAt: last.CreatedAt, EventID: last.ID.String()})
+		if err != nil {
+			s.logger.Error().Str("op", op).Err(err).Msg("failed to encode audit cursor")
+		} else {
+			nextCursor = &cursor
+		}
+	}
+
+	return Page{
+		Events:     events,
+		HasMore:    hasMore,
+		NextCursor: nextCursor,
+		Limit:      opts.Limit,
+	}, nil
+}
+
+// Export calls fn with every event matching the filters, newest first, up
+// to maxExportEvents. It reports whether the export was cut off there.
+func (s *Service) Export(ctx context.Context, teamID uuid.UUID, filters ListFilters, fn func(Event) error) (bool, error) {
+	opts := ListOptions{Limit: exportPageSize, Filters: filters}
+	n := 0
+	for {
+		events, hasMore, err := s.repo.List(ctx, teamID, opts)
+		if err != nil {
+			return false, err
+		}
+		for _, e := range events {
+			if n == maxExportEvents {
+				return true, nil
+			}
+			if err := fn(e); err != nil {
+				return false, err
+			}
+			n++
+		}
+		if !hasMore {
+			return false, nil
+		}
+		last := events[len(events)-1]
+		opts.Cursor = &Cursor{CreatedAt: last.CreatedAt, EventID: last.ID.String()}
+	}
+}
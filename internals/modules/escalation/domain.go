@@ -0,0 +1,94 @@
+package escalation
+
+import (
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/google/uuid"
+)
+
+// Escalation statuses stored in incident_escalations.status.
+const (
+	StatusActive       = "active"
+	StatusAcknowledged = "acknowledged"
+	StatusResolved     = "resolved"
+	// StatusExhausted means the escalation stopped with the incident still
+	// open, which only an out-of-range level does.
+	StatusExhausted = "exhausted"
+)
+
+const (
+	maxLevels            = 10
+	maxLevelDelaySec     = 24 * 60 * 60
+	minRepeatIntervalSec = 5 * 60
+	// lastLevelRepeatSec is how often the last level fires again when the
+	// policy doesn't repeat, so an open incident never goes quiet.
+	lastLevelRepeatSec = 30 * 60
+	maxPolicyNameLen   = 100
+)
+
+// Level is one step of a policy. DelaySec counts from the previous level
+// firing; for the first level it counts from the incident (or the repeat)
+// starting.
+type Level struct {
+	DelaySec int32       `json:"delay_sec"`
+	Channels []string    `json:"channels"`
+	UserIDs  []uuid.UUID `json:"user_ids"`
+}
+
+type Policy struct {
+	ID          uuid.UUID
+	TeamID      uuid.UUID
+	Name        string
+	Description string
+	Levels      []Level
+	// RepeatIntervalSec restarts the policy this long after the last level
+	// fired. With 0 the last level keeps firing every lastLevelRepeatSec.
+	RepeatIntervalSec int32
+	MonitorCount      int64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type PolicyInput struct {
+	Name              string
+	Description       string
+	Levels            []Level
+	RepeatIntervalSec int32
+}
+
+// State is the persisted escalation of one incident.
+type State struct {
+	IncidentID        uuid.UUID
+	PolicyID          *uuid.UUID
+	TeamID            uuid.UUID
+	Levels            []Level
+	RepeatIntervalSec int32
+	Event             alert.AlertEvent
+	Status            string
+	NextLevel         int32
+	Cycle             int32
+	NextFireAt        *time.Time
+	LastFiredAt       *time.Time
+	NotifiedChannels  []string
+	IncidentEnded     bool
+	IncidentAcked     bool
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// advance returns where the escalation goes after the current level fired
+// at now. It stays active until the incident is acknowledged or resolved.
+func (s *State) advance(now time.Time) (nextLevel, cycle int32, status string, nextFireAt *time.Time) {
+	nextLevel, cycle = s.NextLevel+1, s.Cycle
+	if int(nextLevel) < len(s.Levels) {
+		at := now.Add(time.Duration(s.Levels[nextLevel].DelaySec) * time.Second)
+		return nextLevel, cycle, StatusActive, &at
+	}
+	if s.RepeatIntervalSec > 0 {
+		at := now.Add(time.Duration(s.RepeatIntervalSec+s.Levels[0].DelaySec) * time.Second)
+		return 0, cycle + 1, StatusActive, &at
+	}
+	at := now.Add(lastLevelRepeatSec * time.Second)
+	return s.NextLevel, cycle, StatusActive, &at
+}
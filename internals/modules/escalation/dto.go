@@ -0,0 +1,49 @@
+package escalation
+
+type LevelRequest struct {
+	DelaySec int32    `json:"delay_sec"`
+	Channels []string `json:"channels"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type PolicyRequest struct {
+	Name              string         `json:"name"`
+	Description       string         `json:"description"`
+	Levels            []LevelRequest `json:"levels"`
+	RepeatIntervalSec int32          `json:"repeat_interval_sec"`
+}
+
+type LevelResponse struct {
+	DelaySec int32    `json:"delay_sec"`
+	Channels []string `json:"channels"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type PolicyResponse struct {
+	ID                string          `json:"id"`
+	Name              string          `json:"name"`
+	Description       string          `json:"description"`
+	Levels            []LevelResponse `json:"levels"`
+	RepeatIntervalSec int32           `json:"repeat_interval_sec"`
+	MonitorCount      int64           `json:"monitor_count"`
+	CreatedAt         string          `json:"created_at"`
+	UpdatedAt         string          `json:"updated_at"`
+}
+
+type ListPoliciesResponse struct {
+	Policies []PolicyResponse `json:"policies"`
+}
+
+type IncidentEscalationResponse struct {
+	IncidentID        string          `json:"incident_id"`
+	PolicyID          *string         `json:"policy_id,omitempty"`
+	Status            string          `json:"status"`
+	NextLevel         *int32          `json:"next_level,omitempty"` // 1-based, while active
+	Cycle             int32           `json:"cycle"`
+	NextFireAt        *string         `json:"next_fire_at,omitempty"`
+	LastFiredAt       *string         `json:"last_fired_at,omitempty"`
+	NotifiedChannels  []string        `json:"notified_channels"`
+	Levels            []LevelResponse `json:"levels"`
+	RepeatIntervalSec int32           `json:"repeat_interval_sec"`
+	StartedAt         string          `json:"started_at"`
+}
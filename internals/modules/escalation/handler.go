@@ -0,0 +1,284 @@
+package escalation
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *Service, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.list_policies"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	policies, err := h.service.ListPolicies(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list escalation policies")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	items := make([]PolicyResponse, 0, len(policies))
+	for i := range policies {
+		items = append(items, toPolicyResponse(&policies[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "escalation policies retrieved", ListPoliciesResponse{Policies: items})
+}
+
+func (h *Handler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.get_policy"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	policyID, err := uuid.Parse(chi.URLParam(r, "policyID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid policy id")
+		return
+	}
+
+	p, err := h.service.GetPolicy(ctx, tm.TeamID, policyID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get escalation policy")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "escalation policy retrieved", toPolicyResponse(&p))
+}
+
+func (h *Handler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.create_policy"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	in, msg := decodePolicy(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	p, err := h.service.CreatePolicy(ctx, tm.TeamID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to create escalation policy")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	middle.SetAuditTarget(ctx, p.ID.String())
+	utils.WriteJSON(w, http.StatusCreated, reqID, "escalation policy created", toPolicyResponse(&p))
+}
+
+func (h *Handler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.update_policy"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	policyID, err := uuid.Parse(chi.URLParam(r, "policyID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid policy id")
+		return
+	}
+
+	in, msg := decodePolicy(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	p, err := h.service.UpdatePolicy(ctx, tm.TeamID, policyID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to update escalation policy")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "escalation policy updated", toPolicyResponse(&p))
+}
+
+func (h *Handler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.delete_policy"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	policyID, err := uuid.Parse(chi.URLParam(r, "policyID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid policy id")
+		return
+	}
+
+	if err := h.service.DeletePolicy(ctx, tm.TeamID, policyID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to delete escalation policy")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "escalation policy deleted", struct{}{})
+}
+
+func (h *Handler) GetIncidentEscalation(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.escalation.get_incident_escalation"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	st, err := h.service.GetIncidentEscalation(ctx, tm.TeamID, incidentID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get incident escalation")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	resp := IncidentEscalationResponse{
+		IncidentID:        st.IncidentID.String(),
+		Status:            st.Status,
+		Cycle:             st.Cycle,
+		NextFireAt:        toRFC3339Ptr(st.NextFireAt),
+		LastFiredAt:       toRFC3339Ptr(st.LastFiredAt),
+		NotifiedChannels:  st.NotifiedChannels,
+		Levels:            toLevelResponses(st.Levels),
+		RepeatIntervalSec: st.RepeatIntervalSec,
+		StartedAt:         st.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if st.Status == StatusActive {
+		next := st.NextLevel + 1
+		resp.NextLevel = &next
+	}
+	if st.PolicyID != nil {
+		id := st.PolicyID.String()
+		resp.PolicyID = &id
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incident escalation retrieved", resp)
+}
+
+func decodePolicy(r *http.Request) (PolicyInput, string) {
+	var req PolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return PolicyInput{}, "invalid request body"
+	}
+
+	levels := make([]Level, 0, len(req.Levels))
+	for _, l := range req.Levels {
+		level := Level{DelaySec: l.DelaySec, Channels: l.Channels}
+		for _, raw := range l.UserIDs {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return PolicyInput{}, "invalid user id: " + raw
+			}
+			level.UserIDs = append(level.UserIDs, id)
+		}
+		levels = append(levels, level)
+	}
+
+	return PolicyInput{
+		Name:              req.Name,
+		Description:       req.Description,
+		Levels:            levels,
+		RepeatIntervalSec: req.RepeatIntervalSec,
+	}, ""
+}
+
+func toPolicyResponse(p *Policy) PolicyResponse {
+	return PolicyResponse{
+		ID:                p.ID.String(),
+		Name:              p.Name,
+		Description:       p.Description,
+		Levels:            toLevelResponses(p.Levels),
+		RepeatIntervalSec: p.RepeatIntervalSec,
+		MonitorCount:      p.MonitorCount,
+		CreatedAt:         p.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:         p.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toLevelResponses(levels []Level) []LevelResponse {
+	out := make([]LevelResponse, 0, len(levels))
+	for _, l := range levels {
+		userIDs := make([]string, 0, len(l.UserIDs))
+		for _, id := range l.UserIDs {
+			userIDs = append(userIDs, id.String())
+		}
+		channels := l.Channels
+		if channels == nil {
+			channels = []string{}
+		}
+		out = append(out, LevelResponse{DelaySec: l.DelaySec, Channels: channels, UserIDs: userIDs})
+	}
+	return out
+}
+
+func toRFC3339Ptr(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	s := t.UTC().Format(time.RFC3339)
+	return &s
+}
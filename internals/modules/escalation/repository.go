@@ -0,0 +1,383 @@
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+func (r *Repository) ListPolicies(ctx context.Context, teamID uuid.UUID) ([]Policy, error) {
+	const op string = "repo.escalation.list_policies"
+
+	rows, err := r.querier.ListEscalationPolicies(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	policies := make([]Policy, 0, len(rows))
+	for _, row := range rows {
+		p, err := toPolicy(op, db.EscalationPolicy{
+			ID:                row.ID,
+			TeamID:            row.TeamID,
+			Name:              row.Name,
+			Description:       row.Description,
+			Levels:            row.Levels,
+			RepeatIntervalSec: row.RepeatIntervalSec,
+			CreatedAt:         row.CreatedAt,
+			UpdatedAt:         row.UpdatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		p.MonitorCount = row.MonitorCount
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (r *Repository) GetPolicy(ctx context.Context, teamID, policyID uuid.UUID) (Policy, error) {
+	const op string = "repo.escalation.get_policy"
+
+	row, err := r.querier.GetEscalationPolicy(ctx, db.GetEscalationPolicyParams{
+		ID:     utils.ToPgUUID(policyID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "escalation policy not found"}
+		}
+		return Policy{}, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	p, err := toPolicy(op, db.EscalationPolicy{
+		ID:                row.ID,
+		TeamID:            row.TeamID,
+		Name:              row.Name,
+		Description:       row.Description,
+		Levels:            row.Levels,
+		RepeatIntervalSec: row.RepeatIntervalSec,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+	})
+	if err != nil {
+		return Policy{}, err
+	}
+	p.MonitorCount = row.MonitorCount
+	return p, nil
+}
+
+func (r *Repository) CreatePolicy(ctx context.Context, teamID uuid.UUID, in PolicyInput) (Policy, error) {
+	const op string = "repo.escalation.create_policy"
+
+	levels, err := json.Marshal(in.Levels)
+	if err != nil {
+		return Policy{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	row, err := r.querier.CreateEscalationPolicy(ctx, db.CreateEscalationPolicyParams{
+		TeamID:            utils.ToPgUUID(teamID),
+		Name:              in.Name,
+		Description:       in.Description,
+		Levels:            levels,
+		RepeatIntervalSec: in.RepeatIntervalSec,
+	})
+	if err != nil {
+		return Policy{}, r.writeError(op, err)
+	}
+	return toPolicy(op, row)
+}
+
+func (r *Repository) UpdatePolicy(ctx context.Context, teamID, policyID uuid.UUID, in PolicyInput) (Policy, error) {
+	const op string = "repo.escalation.update_policy"
+
+	levels, err := json.Marshal(in.Levels)
+	if err != nil {
+		return Policy{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	row, err := r.querier.UpdateEscalationPolicy(ctx, db.UpdateEscalationPolicyParams{
+		ID:                utils.ToPgUUID(policyID),
+		TeamID:            utils.ToPgUUID(teamID),
+		Name:              in.Name,
+		Description:       in.Description,
+		Levels:            levels,
+		RepeatIntervalSec: in.RepeatIntervalSec,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "escalation policy not found"}
+		}
+		return Policy{}, r.writeError(op, err)
+	}
+	return toPolicy(op, row)
+}
+
+func (r *Repository) DeletePolicy(ctx context.Context, teamID, policyID uuid.UUID) error {
+	const op string = "repo.escalation.delete_policy"
+
+	n, err := r.querier.DeleteEscalationPolicy(ctx, db.DeleteEscalationPolicyParams{
+		ID:     utils.ToPgUUID(policyID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "escalation policy not found"}
+	}
+	return nil
+}
+
+// MonitorPolicy returns the policy attached to a monitor. found is false
+// when the monitor has none.
+func (r *Repository) MonitorPolicy(ctx context.Context, monitorID uuid.UUID) (Policy, bool, error) {
+	const op string = "repo.escalation.monitor_policy"
+
+	row, err := r.querier.GetMonitorEscalationPolicy(ctx, utils.ToPgUUID(monitorID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Policy{}, false, nil
+		}
+		return Policy{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	var levels []Level
+	if err := json.Unmarshal(row.Levels, &levels); err != nil {
+		return Policy{}, false, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	return Policy{
+		ID:                utils.FromPgUUID(row.ID),
+		Levels:            levels,
+		RepeatIntervalSec: row.RepeatIntervalSec,
+	}, true, nil
+}
+
+// MemberEmails maps the given user ids to the emails of those that are
+// active members of the team. Unknown or inactive users are left out.
+func (r *Repository) MemberEmails(ctx context.Context, teamID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	const op string = "repo.escalation.member_emails"
+
+	ids := make([]pgtype.UUID, 0, len(userIDs))
+	for _, id := range userIDs {
+		ids = append(ids, utils.ToPgUUID(id))
+	}
+
+	rows, err := r.querier.ListActiveTeamMemberEmails(ctx, db.ListActiveTeamMemberEmailsParams{
+		TeamID:  utils.ToPgUUID(teamID),
+		Column2: ids,
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	emails := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		emails[utils.FromPgUUID(row.ID)] = row.Email
+	}
+	return emails, nil
+}
+
+// CreateState starts escalating an incident. created is false when the
+// incident already has an escalation.
+func (r *Repository) CreateState(ctx context.Context, incidentID, teamID uuid.UUID, policy Policy, event []byte, firstFireAt time.Time) (bool, error) {
+	const op string = "repo.escalation.create_state"
+
+	levels, err := json.Marshal(policy.Levels)
+	if err != nil {
+		return false, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	n, err := r.querier.CreateIncidentEscalation(ctx, db.CreateIncidentEscalationParams{
+		IncidentID:        utils.ToPgUUID(incidentID),
+		PolicyID:          utils.ToPgUUID(policy.ID),
+		TeamID:            utils.ToPgUUID(teamID),
+		Levels:            levels,
+		RepeatIntervalSec: policy.RepeatIntervalSec,
+		Event:             event,
+		NextFireAt:        utils.ToPgTimestamptz(firstFireAt),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// GetState returns an incident's escalation. found is false when the
+// incident isn't escalated by a policy.
+func (r *Repository) GetState(ctx context.Context, incidentID uuid.UUID) (State, bool, error) {
+	const op string = "repo.escalation.get_state"
+
+	row, err := r.querier.GetIncidentEscalation(ctx, utils.ToPgUUID(incidentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return State{}, false, nil
+		}
+		return State{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	st := State{
+		IncidentID:        utils.FromPgUUID(row.IncidentID),
+		TeamID:            utils.FromPgUUID(row.TeamID),
+		RepeatIntervalSec: row.RepeatIntervalSec,
+		Status:            row.Status,
+		NextLevel:         row.NextLevel,
+		Cycle:             row.Cycle,
+		NextFireAt:        timePtr(row.NextFireAt),
+		LastFiredAt:       timePtr(row.LastFiredAt),
+		NotifiedChannels:  row.NotifiedChannels,
+		IncidentEnded:     row.IncidentEnded,
+		IncidentAcked:     row.IncidentAcknowledged,
+		CreatedAt:         utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:         utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+	if row.PolicyID.Valid {
+		id := utils.FromPgUUID(row.PolicyID)
+		st.PolicyID = &id
+	}
+	if err := json.Unmarshal(row.Levels, &st.Levels); err != nil {
+		return State{}, false, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	if err := json.Unmarshal(row.Event, &st.Event); err != nil {
+		return State{}, false, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	return st, true, nil
+}
+
+// Advance moves an active escalation past the level it was on. It reports
+// false when the state changed in the meantime (stopped, or advanced by
+// someone else).
+func (r *Repository) Advance(ctx context.Context, st *State, nextLevel, cycle int32, status string, nextFireAt *time.Time, notified []string) (bool, error) {
+	const op string = "repo.escalation.advance"
+
+	var fireAt pgtype.Timestamptz
+	if nextFireAt != nil {
+		fireAt = utils.ToPgTimestamptz(*nextFireAt)
+	}
+
+	n, err := r.querier.AdvanceIncidentEscalation(ctx, db.AdvanceIncidentEscalationParams{
+		IncidentID:       utils.ToPgUUID(st.IncidentID),
+		NextLevel:        st.NextLevel,
+		Cycle:            st.Cycle,
+		NextLevel_2:      nextLevel,
+		Cycle_2:          cycle,
+		Status:           status,
+		NextFireAt:       fireAt,
+		NotifiedChannels: notified,
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// Stop ends an escalation and returns the channels it notified. stopped is
+// false when the incident had no escalation or it was already resolved.
+func (r *Repository) Stop(ctx context.Context, incidentID uuid.UUID, status string) ([]string, bool, error) {
+	const op string = "repo.escalation.stop"
+
+	notified, err := r.querier.StopIncidentEscalation(ctx, db.StopIncidentEscalationParams{
+		IncidentID: utils.ToPgUUID(incidentID),
+		Status:     status,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return notified, true, nil
+}
+
+// Resume re-activates an acknowledged escalation. It reports false when the
+// escalation wasn't acknowledged.
+func (r *Repository) Resume(ctx context.Context, incidentID uuid.UUID, nextFireAt time.Time) (bool, error) {
+	const op string = "repo.escalation.resume"
+
+	n, err := r.querier.ResumeIncidentEscalation(ctx, db.ResumeIncidentEscalationParams{
+		IncidentID: utils.ToPgUUID(incidentID),
+		NextFireAt: utils.ToPgTimestamptz(nextFireAt),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// ScheduledState is the part of an active escalation the Redis schedule is
+// rebuilt from.
+type ScheduledState struct {
+	IncidentID uuid.UUID
+	NextFireAt *time.Time
+}
+
+func (r *Repository) ListActive(ctx context.Context) ([]ScheduledState, error) {
+	const op string = "repo.escalation.list_active"
+
+	rows, err := r.querier.ListActiveIncidentEscalations(ctx)
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	out := make([]ScheduledState, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ScheduledState{
+			IncidentID: utils.FromPgUUID(row.IncidentID),
+			NextFireAt: timePtr(row.NextFireAt),
+		})
+	}
+	return out, nil
+}
+
+func (r *Repository) writeError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return &apperror.Error{Kind: apperror.AlreadyExists, Op: op, Message: "an escalation policy with this name already exists"}
+	}
+	return utils.WrapRepoError(op, err, r.logger)
+}
+
+func toPolicy(op string, row db.EscalationPolicy) (Policy, error) {
+	p := Policy{
+		ID:                utils.FromPgUUID(row.ID),
+		TeamID:            utils.FromPgUUID(row.TeamID),
+		Name:              row.Name,
+		Description:       row.Description,
+		RepeatIntervalSec: row.RepeatIntervalSec,
+		CreatedAt:         utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:         utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+	if err := json.Unmarshal(row.Levels, &p.Levels); err != nil {
+		return Policy{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	return p, nil
+}
+
+func timePtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid || ts.InfinityModifier != pgtype.Finite {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
@@ -0,0 +1,23 @@
+package escalation
+
+import "github.com/go-chi/chi/v5"
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListPolicies)
+	r.Post("/", h.CreatePolicy)
+	r.Get("/{policyID}", h.GetPolicy)
+	r.Put("/{policyID}", h.UpdatePolicy)
+	r.Delete("/{policyID}", h.DeletePolicy)
+
+	return r
+}
+
+// IncidentRoutes registers the per-incident escalation state on the
+// incidents router.
+func IncidentRoutes(h *Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Get("/{incidentID}/escalation", h.GetIncidentEscalation)
+	}
+}
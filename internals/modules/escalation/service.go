@@ -0,0 +1,508 @@
+package escalation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alkush-pipania/sofon/config"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// Alerter is satisfied by *alert.AlertService.
+type Alerter interface {
+	Enqueue(ctx context.Context, event alert.AlertEvent) error
+	ChannelExists(ctx context.Context, teamID uuid.UUID, ref string) (bool, error)
+}
+
+// IncidentRecorder is satisfied by *incident.Timeline.
+type IncidentRecorder interface {
+	Record(ctx context.Context, e incident.Event)
+}
+
+// Schedule is satisfied by *redis.Client.
+type Schedule interface {
+	ScheduleEscalation(ctx context.Context, incidentID string, at time.Time) error
+	ClaimDueEscalations(ctx context.Context, now time.Time, limit int) ([]string, error)
+	DelEscalation(ctx context.Context, incidentID string) error
+}
+
+// Service manages escalation policies and drives the escalation of open
+// incidents. Postgres holds each incident's escalation state; the Redis
+// schedule only says when to look at it next, so a lost or stale schedule
+// entry can delay a level but never fire it twice or out of order.
+type Service struct {
+	pollInterval time.Duration
+	batchSize    int
+
+	workerWG sync.WaitGroup
+	stop     chan struct{}
+	wake     chan struct{}
+
+	repo     *Repository
+	alerter  Alerter
+	schedule Schedule
+	timeline IncidentRecorder
+	logger   *zerolog.Logger
+}
+
+func NewService(
+	escalationConfig *config.EscalationConfig,
+	repo *Repository,
+	alerter Alerter,
+	schedule Schedule,
+	timeline IncidentRecorder,
+	logger *zerolog.Logger,
+) *Service {
+	return &Service{
+		pollInterval: escalationConfig.PollInterval,
+		batchSize:    escalationConfig.BatchSize,
+		stop:         make(chan struct{}),
+		wake:         make(chan struct{}, 1),
+		repo:         repo,
+		alerter:      alerter,
+		schedule:     schedule,
+		timeline:     timeline,
+		logger:       logger,
+	}
+}
+
+// Start rebuilds the Redis schedule from Postgres and starts the worker.
+func (s *Service) Start() {
+	s.rehydrate()
+
+	s.workerWG.Add(1)
+	go s.run()
+	s.logger.Info().Msg("Escalation worker started")
+}
+
+// Stop signals the worker to exit once its current batch is done.
+func (s *Service) Stop() {
+	close(s.stop)
+}
+
+func (s *Service) WorkerClosingWait() {
+	s.workerWG.Wait()
+}
+
+func (s *Service) rehydrate() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	active, err := s.repo.ListActive(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to load active escalations")
+		return
+	}
+	now := time.Now()
+	for _, st := range active {
+		at := now
+		if st.NextFireAt != nil {
+			at = *st.NextFireAt
+		}
+		if err := s.schedule.ScheduleEscalation(ctx, st.IncidentID.String(), at); err != nil {
+			s.logger.Error().Err(err).Str("incident_id", st.IncidentID.String()).Msg("failed to reschedule escalation")
+		}
+	}
+	s.logger.Info().Int("count", len(active)).Msg("active escalations rescheduled")
+}
+
+func (s *Service) run() {
+	defer s.workerWG.Done()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.fireBatch() {
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// fireBatch fires one batch of due escalations and reports whether the
+// batch was full.
+func (s *Service) fireBatch() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ids, err := s.schedule.ClaimDueEscalations(ctx, time.Now(), s.batchSize)
+	cancel()
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim due escalations")
+		return false
+	}
+
+	for _, raw := range ids {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			s.logger.Error().Err(err).Str("incident_id", raw).Msg("invalid incident id in escalation schedule")
+			continue
+		}
+		s.fire(id)
+	}
+	return len(ids) == s.batchSize
+}
+
+// Trigger starts escalating a new incident if its monitor has a policy.
+// It reports false when the monitor has none, in which case the caller
+// sends the regular DOWN alert.
+func (s *Service) Trigger(ctx context.Context, event alert.AlertEvent) (bool, error) {
+	policy, found, err := s.repo.MonitorPolicy(ctx, event.MonitorID)
+	if err != nil || !found {
+		return false, err
+	}
+	if len(policy.Levels) == 0 {
+		return false, nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return false, err
+	}
+
+	firstFireAt := time.Now().Add(time.Duration(policy.Levels[0].DelaySec) * time.Second)
+	created, err := s.repo.CreateState(ctx, event.IncidentID, event.TeamID, policy, payload, firstFireAt)
+	if err != nil {
+		return false, err
+	}
+	if !created {
+		// already escalating; nothing to do
+		return true, nil
+	}
+
+	s.scheduleAt(ctx, event.IncidentID, firstFireAt)
+	if !firstFireAt.After(time.Now()) {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return true, nil
+}
+
+// Resolve stops the escalation of a resolved incident and returns the
+// channels it notified so the recovery alert can reach them too.
+func (s *Service) Resolve(ctx context.Context, incidentID uuid.UUID) ([]string, error) {
+	return s.halt(ctx, incidentID, StatusResolved)
+}
+
+// Acknowledge stops paging further levels for an acknowledged incident.
+func (s *Service) Acknowledge(ctx context.Context, incidentID uuid.UUID) error {
+	_, err := s.halt(ctx, incidentID, StatusAcknowledged)
+	return err
+}
+
+// Resume restarts the escalation of an incident whose acknowledgement was
+// withdrawn. The level that was next when it was acknowledged fires after
+// its own delay.
+func (s *Service) Resume(ctx context.Context, incidentID uuid.UUID) error {
+	st, found, err := s.repo.GetState(ctx, incidentID)
+	if err != nil || !found || st.Status != StatusAcknowledged {
+		return err
+	}
+	if int(st.NextLevel) >= len(st.Levels) {
+		return nil
+	}
+
+	at := time.Now().Add(time.Duration(st.Levels[st.NextLevel].DelaySec) * time.Second)
+	resumed, err := s.repo.Resume(ctx, incidentID, at)
+	if err != nil || !resumed {
+		return err
+	}
+	s.scheduleAt(ctx, incidentID, at)
+	return nil
+}
+
+func (s *Service) halt(ctx context.Context, incidentID uuid.UUID, status string) ([]string, error) {
+	notified, stopped, err := s.repo.Stop(ctx, incidentID, status)
+	if err != nil {
+		return nil, err
+	}
+	if stopped {
+		if err := s.schedule.DelEscalation(ctx, incidentID.String()); err != nil {
+			// harmless: the worker drops entries whose state isn't active
+			s.logger.Warn().Err(err).Str("incident_id", incidentID.String()).Msg("failed to unschedule escalation")
+		}
+	}
+	return notified, nil
+}
+
+// fire sends the level an incident is due for and schedules the next one.
+func (s *Service) fire(incidentID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	log := s.logger.With().Str("incident_id", incidentID.String()).Logger()
+
+	st, found, err := s.repo.GetState(ctx, incidentID)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to load escalation, retrying shortly")
+		s.scheduleAt(ctx, incidentID, time.Now().Add(s.pollInterval))
+		return
+	}
+	if !found || st.Status != StatusActive {
+		return
+	}
+	if st.IncidentEnded {
+		// closed outside the result processor (monitor disabled, …)
+		if _, err := s.halt(ctx, incidentID, StatusResolved); err != nil {
+			log.Error().Err(err).Msg("failed to stop escalation of closed incident")
+		}
+		return
+	}
+	if st.IncidentAcked {
+		// acknowledged, but the escalation wasn't stopped at the time
+		if _, err := s.halt(ctx, incidentID, StatusAcknowledged); err != nil {
+			log.Error().Err(err).Msg("failed to stop escalation of acknowledged incident")
+		}
+		return
+	}
+
+	now := time.Now()
+	if st.NextFireAt != nil && st.NextFireAt.After(now.Add(time.Second)) {
+		// early wake-up from a stale schedule entry
+		s.scheduleAt(ctx, incidentID, *st.NextFireAt)
+		return
+	}
+	if int(st.NextLevel) >= len(st.Levels) {
+		log.Error().Int32("level", st.NextLevel).Msg("escalation level out of range, stopping")
+		if _, err := s.halt(ctx, incidentID, StatusExhausted); err != nil {
+			log.Error().Err(err).Msg("failed to stop escalation")
+		}
+		return
+	}
+
+	level := st.Levels[st.NextLevel]
+	if err := s.notify(ctx, &st, level); err != nil {
+		log.Error().Err(err).Int32("level", st.NextLevel+1).Msg("failed to enqueue escalation alerts, retrying shortly")
+		s.scheduleAt(ctx, incidentID, now.Add(s.pollInterval))
+		return
+	}
+
+	notified := st.NotifiedChannels
+	for _, c := range level.Channels {
+		if !slices.Contains(notified, c) {
+			notified = append(notified, c)
+		}
+	}
+
+	nextLevel, cycle, status, nextFireAt := st.advance(now)
+	advanced, err := s.repo.Advance(ctx, &st, nextLevel, cycle, status, nextFireAt, notified)
+	if err != nil {
+		// retrying may page this level twice, which beats stalling
+		log.Error().Err(err).Msg("escalation level sent but failed to save progress, retrying shortly")
+		s.scheduleAt(ctx, incidentID, now.Add(s.pollInterval))
+		return
+	}
+	if !advanced {
+		log.Info().Msg("escalation changed while firing, not rescheduling")
+		return
+	}
+
+	log.Info().
+		Int32("level", st.NextLevel+1).
+		Int32("cycle", st.Cycle).
+		Str("status", status).
+		Msg("escalation level fired")
+
+	s.timeline.Record(ctx, incident.Event{
+		IncidentID: incidentID,
+		Kind:       incident.EventEscalated,
+		Message:    fmt.Sprintf("Escalated to level %d", st.NextLevel+1),
+		Data: map[string]any{
+			"level":    st.NextLevel + 1,
+			"cycle":    st.Cycle,
+			"channels": level.Channels,
+			"user_ids": level.UserIDs,
+		},
+		OccurredAt: now,
+	})
+
+	if nextFireAt != nil {
+		s.scheduleAt(ctx, incidentID, *nextFireAt)
+	}
+}
+
+// notify enqueues the alerts of one level: the regular DOWN alert to the
+// level's channels and an email to the level's users.
+func (s *Service) notify(ctx context.Context, st *State, level Level) error {
+	event := st.Event
+	event.EscalationLevel = int(st.NextLevel) + 1
+
+	if len(level.Channels) > 0 {
+		channelEvent := event
+		channelEvent.NotificationChannels = level.Channels
+		if err := s.alerter.Enqueue(ctx, channelEvent); err != nil {
+			return err
+		}
+	}
+
+	if len(level.UserIDs) > 0 {
+		emails, err := s.repo.MemberEmails(ctx, st.TeamID, level.UserIDs)
+		if err != nil {
+			return err
+		}
+		recipients := make([]string, 0, len(emails))
+		for _, id := range level.UserIDs {
+			if email, ok := emails[id]; ok {
+				recipients = append(recipients, email)
+			}
+		}
+		if len(recipients) == 0 {
+			s.logger.Warn().
+				Str("incident_id", st.IncidentID.String()).
+				Int("level", event.EscalationLevel).
+				Msg("no active team member left on escalation level")
+			return nil
+		}
+		userEvent := event
+		userEvent.NotificationChannels = []string{alert.NotifierResend}
+		userEvent.Recipients = recipients
+		if err := s.alerter.Enqueue(ctx, userEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) scheduleAt(ctx context.Context, incidentID uuid.UUID, at time.Time) {
+	if err := s.schedule.ScheduleEscalation(ctx, incidentID.String(), at); err != nil {
+		// the next restart rebuilds the schedule from Postgres
+		s.logger.Error().Err(err).Str("incident_id", incidentID.String()).Msg("failed to schedule escalation")
+	}
+}
+
+func (s *Service) ListPolicies(ctx context.Context, teamID uuid.UUID) ([]Policy, error) {
+	return s.repo.ListPolicies(ctx, teamID)
+}
+
+func (s *Service) GetPolicy(ctx context.Context, teamID, policyID uuid.UUID) (Policy, error) {
+	return s.repo.GetPolicy(ctx, teamID, policyID)
+}
+
+func (s *Service) CreatePolicy(ctx context.Context, teamID uuid.UUID, in PolicyInput) (Policy, error) {
+	const op = "service.escalation.create_policy"
+
+	in, err := s.validatePolicy(ctx, op, teamID, in)
+	if err != nil {
+		return Policy{}, err
+	}
+	return s.repo.CreatePolicy(ctx, teamID, in)
+}
+
+// UpdatePolicy replaces a policy. Incidents already escalating keep the
+// levels they started with.
+func (s *Service) UpdatePolicy(ctx context.Context, teamID, policyID uuid.UUID, in PolicyInput) (Policy, error) {
+	const op = "service.escalation.update_policy"
+
+	in, err := s.validatePolicy(ctx, op, teamID, in)
+	if err != nil {
+		return Policy{}, err
+	}
+	p, err := s.repo.UpdatePolicy(ctx, teamID, policyID, in)
+	if err != nil {
+		return Policy{}, err
+	}
+	// keep the monitor count in the response
+	return s.repo.GetPolicy(ctx, teamID, p.ID)
+}
+
+// DeletePolicy removes a policy and detaches it from its monitors.
+func (s *Service) DeletePolicy(ctx context.Context, teamID, policyID uuid.UUID) error {
+	return s.repo.DeletePolicy(ctx, teamID, policyID)
+}
+
+// GetIncidentEscalation returns the escalation of one of the team's
+// incidents.
+func (s *Service) GetIncidentEscalation(ctx context.Context, teamID, incidentID uuid.UUID) (State, error) {
+	const op = "service.escalation.get_incident_escalation"
+
+	st, found, err := s.repo.GetState(ctx, incidentID)
+	if err != nil {
+		return State{}, err
+	}
+	if !found || st.TeamID != teamID {
+		return State{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "incident is not escalated by a policy"}
+	}
+	return st, nil
+}
+
+func (s *Service) validatePolicy(ctx context.Context, op string, teamID uuid.UUID, in PolicyInput) (PolicyInput, error) {
+	invalid := func(msg string) (PolicyInput, error) {
+		return PolicyInput{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	if in.Name == "" {
+		return invalid("name is required")
+	}
+	if len(in.Name) > maxPolicyNameLen {
+		return invalid(fmt.Sprintf("name must be at most %d characters", maxPolicyNameLen))
+	}
+	if len(in.Levels) == 0 || len(in.Levels) > maxLevels {
+		return invalid(fmt.Sprintf("a policy needs between 1 and %d levels", maxLevels))
+	}
+	if in.RepeatIntervalSec != 0 && in.RepeatIntervalSec < minRepeatIntervalSec {
+		return invalid(fmt.Sprintf("repeat_interval_sec must be 0 or at least %d", minRepeatIntervalSec))
+	}
+
+	var userIDs []uuid.UUID
+	for i := range in.Levels {
+		level := &in.Levels[i]
+		if level.DelaySec < 0 || level.DelaySec > maxLevelDelaySec {
+			return invalid(fmt.Sprintf("level %d: delay_sec must be between 0 and %d", i+1, maxLevelDelaySec))
+		}
+		if len(level.Channels) == 0 && len(level.UserIDs) == 0 {
+			return invalid(fmt.Sprintf("level %d: name at least one channel or user", i+1))
+		}
+		level.Channels = dedupe(level.Channels)
+		for _, c := range level.Channels {
+			known, err := s.alerter.ChannelExists(ctx, teamID, c)
+			if err != nil {
+				return PolicyInput{}, err
+			}
+			if !known {
+				return invalid(fmt.Sprintf("level %d: unknown channel %q", i+1, c))
+			}
+		}
+		level.UserIDs = dedupe(level.UserIDs)
+		userIDs = append(userIDs, level.UserIDs...)
+	}
+
+	if len(userIDs) > 0 {
+		emails, err := s.repo.MemberEmails(ctx, teamID, userIDs)
+		if err != nil {
+			return PolicyInput{}, err
+		}
+		for _, id := range userIDs {
+			if _, ok := emails[id]; !ok {
+				return invalid(fmt.Sprintf("user %s is not an active member of this team", id))
+			}
+		}
+	}
+	return in, nil
+}
+
+func dedupe[T comparable](in []T) []T {
+	out := make([]T, 0, len(in))
+	for _, v := range in {
+		if !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
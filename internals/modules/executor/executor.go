@@ -1,8 +1,10 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net"
 	"net/http"
 	"sync"
@@ -16,6 +18,9 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// maxKeywordBodyBytes caps how much of a response body keyword checks read.
+const maxKeywordBodyBytes = 1 << 20
+
 type MonitorSvc interface {
 	LoadMonitor(context.Context, uuid.UUID) (monitor.Monitor, error)
 	ScheduleMonitor(context.Context, uuid.UUID, int32, string)
@@ -37,6 +42,10 @@ type Executor struct {
 	httpWg     sync.WaitGroup
 	httpClient *http.Client
 
+	// checking serializes the checks of one monitor whose results go to
+	// the result processor
+	checking monitorLocks
+
 	// misc
 	logger *zerolog.Logger
 }
@@ -58,6 +67,7 @@ func NewExecutor(
 		monitorSvc:  monitorSvc,
 		httpSem:     make(chan struct{}, executorConfig.HTTPSemCount), // 5k http concurrent , specify it in config
 		httpClient:  newHttpClient(),
+		checking:    monitorLocks{held: make(map[uuid.UUID]chan struct{})},
 		logger:      logger,
 	}
 }
@@ -105,7 +115,10 @@ func (ew *Executor) startWork() {
 				ew.httpWg.Done()
 			}()
 
-			result := ew.executeHTTPCheck(monitor)
+			ew.checking.lock(monitor.ID)
+			defer ew.checking.unlock(monitor.ID)
+
+			result := ew.executeHTTPCheck(context.Background(), monitor)
 			ew.logger.Info().Msg("Got HTTPResult and pushed to result channel")
 			ew.resultChan <- result
 		}()
@@ -117,7 +130,52 @@ func (ew *Executor) Stop() {
 	ew.httpWg.Wait()
 }
 
-func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
+// Check runs the monitor's check right away, outside the schedule, and
+// returns its result. With record the result also goes to the result
+// processor like a scheduled one, unless another check of the monitor is
+// already running; that one's result counts instead. It shares the
+// semaphore with scheduled checks and gives up when ctx ends first.
+func (ew *Executor) Check(ctx context.Context, m monitor.Monitor, record bool) (monitor.CheckResult, error) {
+	ew.httpWg.Add(1)
+	defer ew.httpWg.Done()
+
+	if record && !ew.checking.tryLock(m.ID) {
+		record = false
+	}
+	if record {
+		defer ew.checking.unlock(m.ID)
+	}
+
+	select {
+	case ew.httpSem <- struct{}{}:
+	case <-ctx.Done():
+		return monitor.CheckResult{}, ctx.Err()
+	}
+	result := ew.executeHTTPCheck(ctx, m)
+	<-ew.httpSem
+	if err := ctx.Err(); err != nil {
+		return monitor.CheckResult{}, err
+	}
+
+	res := monitor.CheckResult{
+		Success:   result.Success,
+		Status:    result.Status,
+		LatencyMs: result.LatencyMs,
+		Reason:    result.Reason,
+		Retryable: result.Retryable,
+		CheckedAt: result.CheckedAt,
+	}
+	if record {
+		select {
+		case ew.resultChan <- result:
+			res.Recorded = true
+		case <-ctx.Done():
+		}
+	}
+	return res, nil
+}
+
+func (ew *Executor) executeHTTPCheck(ctx context.Context, monitor monitor.Monitor) HTTPResult {
 
 	start := time.Now()
 
@@ -126,7 +184,7 @@ func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
 		timeout = 15 * time.Second
 	}
 
-	httpReqCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	httpReqCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(httpReqCtx, "GET", monitor.Url, nil)
@@ -142,15 +200,18 @@ func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
 			Msg("error in building request")
 
 		return HTTPResult{
-			MonitorID:   monitor.ID,
-			TeamID:      monitor.TeamID,
-			MonitorURL:  monitor.Url,
-			Success:     false,
-			Reason:      "INVALID_REQUEST",
-			Retryable:   false,
-			CheckedAt:   time.Now(),
-			IntervalSec: monitor.IntervalSec,
+			MonitorID:            monitor.ID,
+			TeamID:               monitor.TeamID,
+			MonitorURL:           monitor.Url,
+			MonitorName:          monitor.Name,
+			RunbookURL:           monitor.RunbookURL,
+			Success:              false,
+			Reason:               "INVALID_REQUEST",
+			Retryable:            false,
+			CheckedAt:            time.Now(),
+			IntervalSec:          monitor.IntervalSec,
 			NotificationChannels: monitor.NotificationChannels,
+			SnoozedUntil:         monitor.SnoozedUntil,
 		}
 	}
 	resp, err := ew.httpClient.Do(req)
@@ -159,17 +220,20 @@ func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
 		// this can be DNS err, network err, TLS err and context timeout(because of hanging request)
 		reason, isRetryable := ew.classifyError(err)
 		return HTTPResult{
-			MonitorID:   monitor.ID,
-			TeamID:      monitor.TeamID,
-			MonitorURL:  monitor.Url,
-			Success:     false,
-			Status:      http.StatusServiceUnavailable,
-			LatencyMs:   latency,
-			Reason:      reason,
-			Retryable:   isRetryable,
-			CheckedAt:   time.Now(),
-			IntervalSec: monitor.IntervalSec,
+			MonitorID:            monitor.ID,
+			TeamID:               monitor.TeamID,
+			MonitorURL:           monitor.Url,
+			MonitorName:          monitor.Name,
+			RunbookURL:           monitor.RunbookURL,
+			Success:              false,
+			Status:               http.StatusServiceUnavailable,
+			LatencyMs:            latency,
+			Reason:               reason,
+			Retryable:            isRetryable,
+			CheckedAt:            time.Now(),
+			IntervalSec:          monitor.IntervalSec,
 			NotificationChannels: monitor.NotificationChannels,
+			SnoozedUntil:         monitor.SnoozedUntil,
 		}
 	}
 
@@ -201,21 +265,86 @@ func (ew *Executor) executeHTTPCheck(monitor monitor.Monitor) HTTPResult {
 		latencyMatch = latency <= int64(*monitor.LatencyThresholdMs)
 	}
 
-	success := statusMatch && latencyMatch
+	reason := ""
+	if statusMatch && monitor.Keyword != "" {
+		reason = checkKeyword(resp.Body, monitor.Keyword, monitor.KeywordAbsent)
+	}
+
+	success := statusMatch && latencyMatch && reason == ""
 
 	return HTTPResult{
-		MonitorID:   monitor.ID,
-		TeamID:      monitor.TeamID,
-		MonitorURL:  monitor.Url,
-		Status:      resp.StatusCode,
-		LatencyMs:   latency,
-		Success:     success,
-		Reason:      "",
-		Retryable:   false,
-		CheckedAt:   time.Now(),
+		MonitorID:            monitor.ID,
+		TeamID:               monitor.TeamID,
+		MonitorURL:           monitor.Url,
+		MonitorName:          monitor.Name,
+		RunbookURL:           monitor.RunbookURL,
+		Status:               resp.StatusCode,
+		LatencyMs:            latency,
+		Success:              success,
+		Reason:               reason,
+		Retryable:            false,
+		CheckedAt:            time.Now(),
 		IntervalSec:          monitor.IntervalSec,
 		NotificationChannels: monitor.NotificationChannels,
+		SnoozedUntil:         monitor.SnoozedUntil,
+	}
+}
+
+// monitorLocks holds a lock per monitor, created on first use and dropped
+// once released.
+type monitorLocks struct {
+	mu   sync.Mutex
+	held map[uuid.UUID]chan struct{}
+}
+
+// lock waits until no other check of the monitor holds the lock.
+func (l *monitorLocks) lock(id uuid.UUID) {
+	for {
+		l.mu.Lock()
+		done, ok := l.held[id]
+		if !ok {
+			l.held[id] = make(chan struct{})
+			l.mu.Unlock()
+			return
+		}
+		l.mu.Unlock()
+		<-done
+	}
+}
+
+// tryLock takes the monitor's lock if it is free.
+func (l *monitorLocks) tryLock(id uuid.UUID) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.held[id]; ok {
+		return false
+	}
+	l.held[id] = make(chan struct{})
+	return true
+}
+
+func (l *monitorLocks) unlock(id uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	close(l.held[id])
+	delete(l.held, id)
+}
+
+// checkKeyword looks for keyword in the first maxKeywordBodyBytes of the
+// body and returns the failure reason, or "" when the check passes.
+func checkKeyword(body io.Reader, keyword string, absent bool) string {
+	b, err := io.ReadAll(io.LimitReader(body, maxKeywordBodyBytes))
+	if err != nil {
+		return "KEYWORD_READ_FAILED"
+	}
+	found := bytes.Contains(b, []byte(keyword))
+	switch {
+	case found && absent:
+		return "KEYWORD_FOUND"
+	case !found && !absent:
+		return "KEYWORD_NOT_FOUND"
 	}
+	return ""
 }
 
 func (_ *Executor) classifyError(err error) (string, bool) {
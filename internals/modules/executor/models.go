@@ -7,15 +7,24 @@ import (
 )
 
 type HTTPResult struct {
-	MonitorID   uuid.UUID
-	TeamID      uuid.UUID
-	MonitorURL  string
-	Success     bool
-	Status      int
-	LatencyMs   int64
-	Reason      string
-	Retryable   bool
-	CheckedAt   time.Time
-	IntervalSec int32
+	MonitorID            uuid.UUID
+	TeamID               uuid.UUID
+	MonitorURL           string
+	MonitorName          string
+	RunbookURL           string
+	Success              bool
+	Status               int
+	LatencyMs            int64
+	Reason               string
+	Retryable            bool
+	CheckedAt            time.Time
+	IntervalSec          int32
 	NotificationChannels []string
+	SnoozedUntil         *time.Time
+}
+
+// Snoozed reports whether the monitor was snoozed when it was checked, in
+// which case a failure doesn't open an incident.
+func (r HTTPResult) Snoozed() bool {
+	return r.SnoozedUntil != nil && r.CheckedAt.Before(*r.SnoozedUntil)
 }
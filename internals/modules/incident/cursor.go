@@ -8,13 +8,17 @@ import (
 )
 
 type cursorPayload struct {
+	Sort       string `json:"o,omitempty"`
 	StartTime  string `json:"s"`
+	Key        string `json:"k,omitempty"`
 	IncidentID string `json:"i"`
 }
 
 func EncodeCursor(c Cursor) (string, error) {
 	p := cursorPayload{
+		Sort:       c.Sort,
 		StartTime:  c.StartTime.UTC().Format(time.RFC3339Nano),
+		Key:        c.Key,
 		IncidentID: c.IncidentID,
 	}
 	raw, err := json.Marshal(p)
@@ -43,8 +47,15 @@ func DecodeCursor(v string) (*Cursor, error) {
 		return nil, fmt.Errorf("invalid cursor")
 	}
 
+	// Cursors issued before sorting was added carry no sort.
+	if p.Sort == "" {
+		p.Sort = SortNewest
+	}
+
 	return &Cursor{
+		Sort:       p.Sort,
 		StartTime:  start,
+		Key:        p.Key,
 		IncidentID: p.IncidentID,
 	}, nil
 }
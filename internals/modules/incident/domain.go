@@ -10,6 +10,8 @@ type Incident struct {
 	ID                 string
 	MonitorID          string
 	MonitorURL         string
+	MonitorName        string
+	MonitorTags        []string
 	StartTime          time.Time
 	EndTime            *time.Time
 	Alerted            bool
@@ -23,19 +25,45 @@ type Incident struct {
 	AlertSentAt        *time.Time
 	ExpectedStatus     int32
 	LatencyThresholdMs int32
+	AcknowledgedAt     *time.Time
+	AcknowledgedBy     string
+	AcknowledgedByName string
+	AssignedTo         string
+	AssignedToName     string
+	AssignedBy         string
+	AssignedAt         *time.Time
+
+	// sortKey is the monitor name sort value of a listed incident, used to
+	// build the next page cursor.
+	sortKey string
 }
 
+// Incident list sort orders.
+const (
+	SortNewest = "newest"
+	SortOldest = "oldest"
+	SortName   = "name"
+)
+
+// Cursor marks the last incident of a page. Key holds the monitor name sort
+// value for the name order.
 type Cursor struct {
+	Sort       string
 	StartTime  time.Time
+	Key        string
 	IncidentID string
 }
 
 type ListFilters struct {
-	Status    string
-	Query     string
-	MonitorID *uuid.UUID
-	From      *time.Time
-	To        *time.Time
+	Status     string
+	Ack        string
+	Query      string
+	MonitorID  *uuid.UUID
+	AssignedTo *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+	Tags       []string
+	Sort       string
 }
 
 type ListIncidentsOptions struct {
@@ -51,3 +79,59 @@ type ListIncidentsPage struct {
 	Applied    ListFilters
 	Limit      int32
 }
+
+// Timeline event kinds. Comments and postmortem notes are written by team
+// members; every other kind is recorded automatically.
+const (
+	EventFirstFailure     = "first_failure"
+	EventThresholdCrossed = "threshold_crossed"
+	EventIncidentCreated  = "incident_created"
+	EventAlertSent        = "alert_sent"
+	EventAlertFailed      = "alert_failed"
+	EventAlertSuppressed  = "alert_suppressed"
+	EventEscalated        = "escalated"
+	EventReminderSent     = "reminder_sent"
+	EventAcknowledged     = "acknowledged"
+	EventExternalResolved = "external_resolved"
+	EventUnacknowledged   = "unacknowledged"
+	EventAssigned         = "assigned"
+	EventUnassigned       = "unassigned"
+	EventRecovered        = "recovered"
+	EventComment          = "comment"
+	EventPostmortem       = "postmortem"
+)
+
+// Responder is the person who acted on an incident in an external incident
+// management tool.
+type Responder struct {
+	Source string // e.g. "zenduty"
+	Name   string
+	Email  string
+}
+
+// Label is the name shown for the responder in Sofon.
+func (r Responder) Label() string {
+	switch {
+	case r.Name != "":
+		return r.Name
+	case r.Email != "":
+		return r.Email
+	}
+	return r.Source
+}
+
+// maxNoteLen caps comments and postmortem notes.
+const maxNoteLen = 10000
+
+// Event is one entry of an incident's append-only timeline.
+type Event struct {
+	ID         uuid.UUID
+	IncidentID uuid.UUID
+	Kind       string
+	AuthorID   *uuid.UUID
+	AuthorName string
+	Message    string
+	Data       map[string]any
+	OccurredAt time.Time
+	CreatedAt  time.Time
+}
@@ -10,6 +10,8 @@ type IncidentResponse struct {
 	ID          string               `json:"id"`
 	MonitorID   string               `json:"monitor_id"`
 	MonitorURL  string               `json:"monitor_url"`
+	MonitorName string               `json:"monitor_name"`
+	MonitorTags []string             `json:"monitor_tags"`
 	StartTime   string               `json:"start_time"`
 	EndTime     *string              `json:"end_time,omitempty"`
 	Alerted     bool                 `json:"alerted"`
@@ -20,6 +22,20 @@ type IncidentResponse struct {
 	DurationSec int64                `json:"duration_sec"`
 	Reason      string               `json:"reason"`
 	LatestAlert *LatestAlertResponse `json:"latest_alert,omitempty"`
+
+	Acknowledged   bool            `json:"acknowledged"`
+	AcknowledgedAt *string         `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *MemberResponse `json:"acknowledged_by,omitempty"`
+	AssignedTo     *MemberResponse `json:"assigned_to,omitempty"`
+	AssignedAt     *string         `json:"assigned_at,omitempty"`
+	AssignedBy     *string         `json:"assigned_by,omitempty"`
+
+	Timeline []EventResponse `json:"timeline,omitempty"`
+}
+
+type MemberResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type ListIncidentsResponse struct {
@@ -31,9 +47,36 @@ type ListIncidentsResponse struct {
 }
 
 type AppliedFilters struct {
-	Status    string  `json:"status"`
-	Query     string  `json:"query,omitempty"`
-	MonitorID *string `json:"monitor_id,omitempty"`
-	From      *string `json:"from,omitempty"`
-	To        *string `json:"to,omitempty"`
+	Status     string   `json:"status"`
+	Ack        string   `json:"ack"`
+	Query      string   `json:"query,omitempty"`
+	MonitorID  *string  `json:"monitor_id,omitempty"`
+	AssignedTo *string  `json:"assigned_to,omitempty"`
+	From       *string  `json:"from,omitempty"`
+	To         *string  `json:"to,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Sort       string   `json:"sort"`
+}
+
+// AssignIncidentRequest assigns the incident to a team member; a null
+// user_id unassigns it.
+type AssignIncidentRequest struct {
+	UserID *string `json:"user_id"`
+}
+
+type EventResponse struct {
+	ID         string          `json:"id"`
+	Kind       string          `json:"kind"`
+	Author     *MemberResponse `json:"author,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Data       map[string]any  `json:"data,omitempty"`
+	OccurredAt string          `json:"occurred_at"`
+}
+
+type TimelineResponse struct {
+	Events []EventResponse `json:"events"`
+}
+
+type AddNoteRequest struct {
+	Message string `json:"message"`
 }
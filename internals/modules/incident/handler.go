@@ -1,6 +1,7 @@
 package incident
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/modules/monitor"
 	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/alkush-pipania/sofon/pkg/utils"
 	"github.com/go-chi/chi/v5"
@@ -59,7 +61,26 @@ func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ack := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("ack")))
+	if ack == "" {
+		ack = "all"
+	}
+	if ack != "all" && ack != "acknowledged" && ack != "unacknowledged" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid ack")
+		return
+	}
+
 	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	tags := monitor.NormalizeTags(r.URL.Query()["tag"])
+
+	sort := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("sort")))
+	if sort == "" {
+		sort = SortNewest
+	}
+	if sort != SortNewest && sort != SortOldest && sort != SortName {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid sort")
+		return
+	}
 
 	var from *time.Time
 	if fromStr := strings.TrimSpace(r.URL.Query().Get("from")); fromStr != "" {
@@ -96,6 +117,16 @@ func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 		monitorID = &id
 	}
 
+	var assignedTo *uuid.UUID
+	if assignedToStr := strings.TrimSpace(r.URL.Query().Get("assigned_to")); assignedToStr != "" {
+		id, err := uuid.Parse(assignedToStr)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid assigned_to")
+			return
+		}
+		assignedTo = &id
+	}
+
 	var cursor *Cursor
 	if cursorStr := strings.TrimSpace(r.URL.Query().Get("cursor")); cursorStr != "" {
 		decoded, err := DecodeCursor(cursorStr)
@@ -103,7 +134,7 @@ func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid cursor")
 			return
 		}
-		if _, err := uuid.Parse(decoded.IncidentID); err != nil {
+		if _, err := uuid.Parse(decoded.IncidentID); err != nil || decoded.Sort != sort {
 			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid cursor")
 			return
 		}
@@ -114,11 +145,15 @@ func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 		Limit:  limit,
 		Cursor: cursor,
 		Filters: ListFilters{
-			Status:    status,
-			Query:     query,
-			MonitorID: monitorID,
-			From:      from,
-			To:        to,
+			Status:     status,
+			Ack:        ack,
+			Query:      query,
+			MonitorID:  monitorID,
+			AssignedTo: assignedTo,
+			From:       from,
+			To:         to,
+			Tags:       tags,
+			Sort:       sort,
 		},
 	})
 	if err != nil {
@@ -134,14 +169,21 @@ func (h *Handler) ListIncidents(w http.ResponseWriter, r *http.Request) {
 
 	applied := AppliedFilters{
 		Status: page.Applied.Status,
+		Ack:    page.Applied.Ack,
 		Query:  page.Applied.Query,
 		From:   toRFC3339Ptr(page.Applied.From),
 		To:     toRFC3339Ptr(page.Applied.To),
+		Tags:   page.Applied.Tags,
+		Sort:   page.Applied.Sort,
 	}
 	if page.Applied.MonitorID != nil {
 		v := page.Applied.MonitorID.String()
 		applied.MonitorID = &v
 	}
+	if page.Applied.AssignedTo != nil {
+		v := page.Applied.AssignedTo.String()
+		applied.AssignedTo = &v
+	}
 
 	utils.WriteJSON(w, http.StatusOK, reqID, "incidents retrieved", ListIncidentsResponse{
 		Limit:          page.Limit,
@@ -170,14 +212,189 @@ func (h *Handler) GetIncident(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	inc, err := h.service.GetByIDAndTeamID(ctx, incidentID, tm.TeamID)
+	inc, events, err := h.service.GetWithTimeline(ctx, incidentID, tm.TeamID)
 	if err != nil {
 		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get incident")
 		utils.FromAppError(w, reqID, err)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, reqID, "incident retrieved", toIncidentResponse(&inc))
+	resp := toIncidentResponse(&inc)
+	resp.Timeline = toEventResponses(events)
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incident retrieved", resp)
+}
+
+func (h *Handler) Acknowledge(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.acknowledge"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	inc, err := h.service.Acknowledge(ctx, tm.TeamID, incidentID, tm.UserID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to acknowledge incident")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incident acknowledged", toIncidentResponse(&inc))
+}
+
+func (h *Handler) Unacknowledge(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.unacknowledge"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	inc, err := h.service.Unacknowledge(ctx, tm.TeamID, incidentID, tm.UserID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to unacknowledge incident")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "incident unacknowledged", toIncidentResponse(&inc))
+}
+
+func (h *Handler) Assign(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.assign"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	var req AssignIncidentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	var assignee *uuid.UUID
+	if req.UserID != nil && *req.UserID != "" {
+		id, err := uuid.Parse(*req.UserID)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid user_id")
+			return
+		}
+		assignee = &id
+	}
+
+	inc, err := h.service.Assign(ctx, tm.TeamID, incidentID, assignee, tm.UserID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to assign incident")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	msg := "incident assigned"
+	if assignee == nil {
+		msg = "incident unassigned"
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, msg, toIncidentResponse(&inc))
+}
+
+func (h *Handler) GetTimeline(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.incident.get_timeline"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	events, err := h.service.GetTimeline(ctx, tm.TeamID, incidentID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get incident timeline")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "timeline retrieved", TimelineResponse{Events: toEventResponses(events)})
+}
+
+func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) {
+	h.addNote(w, r, EventComment, "handler.incident.add_comment")
+}
+
+func (h *Handler) AddPostmortem(w http.ResponseWriter, r *http.Request) {
+	h.addNote(w, r, EventPostmortem, "handler.incident.add_postmortem")
+}
+
+func (h *Handler) addNote(w http.ResponseWriter, r *http.Request, kind, op string) {
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	incidentID, err := uuid.Parse(chi.URLParam(r, "incidentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid incident id")
+		return
+	}
+
+	var req AddNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	e, err := h.service.AddNote(ctx, tm.TeamID, incidentID, tm.UserID, kind, req.Message)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to add note")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "note added", toEventResponse(&e))
 }
 
 func deriveReason(i *Incident) string {
@@ -223,10 +440,28 @@ func toIncidentResponse(i *Incident) IncidentResponse {
 		}
 	}
 
+	var ackBy *MemberResponse
+	if i.AcknowledgedBy != "" {
+		ackBy = &MemberResponse{ID: i.AcknowledgedBy, Name: i.AcknowledgedByName}
+	}
+
+	var assignedTo *MemberResponse
+	if i.AssignedTo != "" {
+		assignedTo = &MemberResponse{ID: i.AssignedTo, Name: i.AssignedToName}
+	}
+
+	var assignedBy *string
+	if i.AssignedBy != "" {
+		v := i.AssignedBy
+		assignedBy = &v
+	}
+
 	return IncidentResponse{
 		ID:          i.ID,
 		MonitorID:   i.MonitorID,
 		MonitorURL:  i.MonitorURL,
+		MonitorName: i.MonitorName,
+		MonitorTags: i.MonitorTags,
 		StartTime:   start,
 		EndTime:     end,
 		Alerted:     i.Alerted,
@@ -237,6 +472,36 @@ func toIncidentResponse(i *Incident) IncidentResponse {
 		DurationSec: i.DurationSec,
 		Reason:      deriveReason(i),
 		LatestAlert: latestAlert,
+
+		Acknowledged:   i.AcknowledgedAt != nil,
+		AcknowledgedAt: toRFC3339Ptr(i.AcknowledgedAt),
+		AcknowledgedBy: ackBy,
+		AssignedTo:     assignedTo,
+		AssignedAt:     toRFC3339Ptr(i.AssignedAt),
+		AssignedBy:     assignedBy,
+	}
+}
+
+func toEventResponses(events []Event) []EventResponse {
+	out := make([]EventResponse, 0, len(events))
+	for i := range events {
+		out = append(out, toEventResponse(&events[i]))
+	}
+	return out
+}
+
+func toEventResponse(e *Event) EventResponse {
+	var author *MemberResponse
+	if e.AuthorID != nil {
+		author = &MemberResponse{ID: e.AuthorID.String(), Name: e.AuthorName}
+	}
+	return EventResponse{
+		ID:         e.ID.String(),
+		Kind:       e.Kind,
+		Author:     author,
+		Message:    e.Message,
+		Data:       e.Data,
+		OccurredAt: e.OccurredAt.UTC().Format(time.RFC3339),
 	}
 }
 
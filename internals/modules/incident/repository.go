@@ -2,6 +2,7 @@ package incident
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -50,8 +51,19 @@ func (r *Repository) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts Li
 		toTS = utils.ToPgTimestamptz(opts.Filters.To.UTC())
 	}
 
+	var assignedTo pgtype.UUID
+	if opts.Filters.AssignedTo != nil {
+		assignedTo = utils.ToPgUUID(*opts.Filters.AssignedTo)
+	}
+
+	tags := opts.Filters.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	var cursorStart pgtype.Timestamptz
 	var cursorID pgtype.UUID
+	var cursorKey string
 	if opts.Cursor != nil {
 		parsedCursorID, err := uuid.Parse(opts.Cursor.IncidentID)
 		if err != nil {
@@ -63,18 +75,24 @@ func (r *Repository) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts Li
 		}
 		cursorStart = utils.ToPgTimestamptz(opts.Cursor.StartTime.UTC())
 		cursorID = utils.ToPgUUID(parsedCursorID)
+		cursorKey = opts.Cursor.Key
 	}
 
 	rows, err := r.querier.ListIncidentsByTeamCursor(ctx, db.ListIncidentsByTeamCursorParams{
-		TeamID:  utils.ToPgUUID(teamID),
-		Column2: opts.Filters.Status,
-		Column3: fromTS,
-		Column4: toTS,
-		Column5: opts.Filters.Query,
-		Column6: monitorID,
-		Column7: cursorStart,
-		Column8: cursorID,
-		Limit:   fetchLimit,
+		TeamID:   utils.ToPgUUID(teamID),
+		Column2:  opts.Filters.Status,
+		Column3:  fromTS,
+		Column4:  toTS,
+		Column5:  opts.Filters.Query,
+		Column6:  monitorID,
+		Column7:  cursorStart,
+		Column8:  cursorID,
+		Column9:  opts.Filters.Ack,
+		Column10: assignedTo,
+		Limit:    fetchLimit,
+		Column12: tags,
+		Column13: opts.Filters.Sort,
+		Column14: cursorKey,
 	})
 	if err != nil {
 		return nil, false, utils.WrapRepoError(op, err, r.logger)
@@ -87,6 +105,8 @@ func (r *Repository) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts Li
 			ID:                 utils.FromPgUUID(row.ID).String(),
 			MonitorID:          utils.FromPgUUID(row.MonitorID).String(),
 			MonitorURL:         row.MonitorUrl,
+			MonitorName:        row.MonitorName,
+			MonitorTags:        row.MonitorTags,
 			StartTime:          utils.FromPgTimestamptz(row.StartTime),
 			EndTime:            timePtr(row.EndTime),
 			Alerted:            row.Alerted,
@@ -100,6 +120,14 @@ func (r *Repository) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts Li
 			AlertSentAt:        timePtr(row.AlertSentAt),
 			ExpectedStatus:     row.ExpectedStatus.Int32,
 			LatencyThresholdMs: row.LatencyThresholdMs.Int32,
+			AcknowledgedAt:     timePtr(row.AcknowledgedAt),
+			AcknowledgedBy:     uuidString(row.AcknowledgedBy),
+			AcknowledgedByName: row.AcknowledgedByName,
+			AssignedTo:         uuidString(row.AssignedTo),
+			AssignedToName:     row.AssignedToName,
+			AssignedBy:         uuidString(row.AssignedBy),
+			AssignedAt:         timePtr(row.AssignedAt),
+			sortKey:            row.SortName,
 		})
 	}
 
@@ -123,6 +151,8 @@ func (r *Repository) GetByIDAndTeamID(ctx context.Context, incidentID uuid.UUID,
 			ID:                 utils.FromPgUUID(row.ID).String(),
 			MonitorID:          utils.FromPgUUID(row.MonitorID).String(),
 			MonitorURL:         row.MonitorUrl,
+			MonitorName:        row.MonitorName,
+			MonitorTags:        row.MonitorTags,
 			StartTime:          utils.FromPgTimestamptz(row.StartTime),
 			EndTime:            timePtr(row.EndTime),
 			Alerted:            row.Alerted,
@@ -136,6 +166,13 @@ func (r *Repository) GetByIDAndTeamID(ctx context.Context, incidentID uuid.UUID,
 			AlertSentAt:        timePtr(row.AlertSentAt),
 			ExpectedStatus:     row.ExpectedStatus.Int32,
 			LatencyThresholdMs: row.LatencyThresholdMs.Int32,
+			AcknowledgedAt:     timePtr(row.AcknowledgedAt),
+			AcknowledgedBy:     uuidString(row.AcknowledgedBy),
+			AcknowledgedByName: row.AcknowledgedByName,
+			AssignedTo:         uuidString(row.AssignedTo),
+			AssignedToName:     row.AssignedToName,
+			AssignedBy:         uuidString(row.AssignedBy),
+			AssignedAt:         timePtr(row.AssignedAt),
 		}, nil
 	}
 
@@ -150,6 +187,253 @@ func (r *Repository) GetByIDAndTeamID(ctx context.Context, incidentID uuid.UUID,
 	return Incident{}, utils.WrapRepoError(op, err, r.logger)
 }
 
+// Acknowledge marks an open, unacknowledged incident as acknowledged by the
+// user, and assigns it to them if nobody owns it yet. It reports false when
+// nothing changed.
+func (r *Repository) Acknowledge(ctx context.Context, teamID, incidentID, userID uuid.UUID) (bool, error) {
+	const op string = "repo.incident.acknowledge"
+
+	n, err := r.querier.AcknowledgeIncident(ctx, db.AcknowledgeIncidentParams{
+		ID:             utils.ToPgUUID(incidentID),
+		TeamID:         utils.ToPgUUID(teamID),
+		AcknowledgedBy: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// AcknowledgeExternal acknowledges an open incident on behalf of a
+// responder from an external tool. member is the matching team member, if
+// any. It reports false when nothing changed.
+func (r *Repository) AcknowledgeExternal(ctx context.Context, teamID, incidentID uuid.UUID, member *uuid.UUID, responder string) (bool, error) {
+	const op string = "repo.incident.acknowledge_external"
+
+	var by pgtype.UUID
+	if member != nil {
+		by = utils.ToPgUUID(*member)
+	}
+
+	n, err := r.querier.AcknowledgeIncidentExternal(ctx, db.AcknowledgeIncidentExternalParams{
+		ID:                     utils.ToPgUUID(incidentID),
+		TeamID:                 utils.ToPgUUID(teamID),
+		AcknowledgedBy:         by,
+		AcknowledgedByExternal: responder,
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// MarkResolvedExternal records that an external tool resolved the open
+// incident. It reports false when that was already recorded or the
+// incident is closed.
+func (r *Repository) MarkResolvedExternal(ctx context.Context, teamID, incidentID uuid.UUID) (bool, error) {
+	const op string = "repo.incident.mark_resolved_external"
+
+	n, err := r.querier.MarkIncidentResolvedExternal(ctx, db.MarkIncidentResolvedExternalParams{
+		ID:     utils.ToPgUUID(incidentID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// OpenForMonitor lists the team's open incidents of a monitor.
+func (r *Repository) OpenForMonitor(ctx context.Context, teamID, monitorID uuid.UUID) ([]uuid.UUID, error) {
+	const op string = "repo.incident.open_for_monitor"
+
+	rows, err := r.querier.ListOpenMonitorIncidents(ctx, db.ListOpenMonitorIncidentsParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		TeamID:    utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	return fromPgUUIDs(rows), nil
+}
+
+// OpenForDigest lists the team's open incidents that were announced in the
+// alert digest groupID.
+func (r *Repository) OpenForDigest(ctx context.Context, teamID, groupID uuid.UUID) ([]uuid.UUID, error) {
+	const op string = "repo.incident.open_for_digest"
+
+	rows, err := r.querier.ListOpenDigestIncidents(ctx, db.ListOpenDigestIncidentsParams{
+		GroupID: utils.ToPgUUID(groupID),
+		TeamID:  utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	return fromPgUUIDs(rows), nil
+}
+
+// MemberByEmail finds the active team member with that email address.
+func (r *Repository) MemberByEmail(ctx context.Context, teamID uuid.UUID, email string) (uuid.UUID, bool, error) {
+	const op string = "repo.incident.member_by_email"
+
+	id, err := r.querier.GetActiveTeamMemberByEmail(ctx, db.GetActiveTeamMemberByEmailParams{
+		TeamID: utils.ToPgUUID(teamID),
+		Lower:  email,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return utils.FromPgUUID(id), true, nil
+}
+
+// Unacknowledge withdraws the acknowledgement of an open incident. It
+// reports false when nothing changed.
+func (r *Repository) Unacknowledge(ctx context.Context, teamID, incidentID uuid.UUID) (bool, error) {
+	const op string = "repo.incident.unacknowledge"
+
+	n, err := r.querier.UnacknowledgeIncident(ctx, db.UnacknowledgeIncidentParams{
+		ID:     utils.ToPgUUID(incidentID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n == 1, nil
+}
+
+// Assign sets or clears (assignee nil) the owner of an incident.
+func (r *Repository) Assign(ctx context.Context, teamID, incidentID uuid.UUID, assignee *uuid.UUID, by uuid.UUID) error {
+	const op string = "repo.incident.assign"
+
+	var assignedTo, assignedBy pgtype.UUID
+	if assignee != nil {
+		assignedTo = utils.ToPgUUID(*assignee)
+		assignedBy = utils.ToPgUUID(by)
+	}
+
+	n, err := r.querier.AssignIncident(ctx, db.AssignIncidentParams{
+		ID:         utils.ToPgUUID(incidentID),
+		TeamID:     utils.ToPgUUID(teamID),
+		AssignedTo: assignedTo,
+		AssignedBy: assignedBy,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{
+			Kind:    apperror.NotFound,
+			Op:      op,
+			Message: "incident not found",
+		}
+	}
+	return nil
+}
+
+// IsActiveMember reports whether the user is an active member of the team.
+func (r *Repository) IsActiveMember(ctx context.Context, teamID, userID uuid.UUID) (bool, error) {
+	const op string = "repo.incident.is_active_member"
+
+	m, err := r.querier.GetTeamMembership(ctx, db.GetTeamMembershipParams{
+		TeamID: utils.ToPgUUID(teamID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return m.IsActive, nil
+}
+
+// AddEvent appends an event to the incident's timeline.
+func (r *Repository) AddEvent(ctx context.Context, e Event) (Event, error) {
+	const op string = "repo.incident.add_event"
+
+	data := e.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	var authorID pgtype.UUID
+	if e.AuthorID != nil {
+		authorID = utils.ToPgUUID(*e.AuthorID)
+	}
+
+	row, err := r.querier.CreateIncidentEvent(ctx, db.CreateIncidentEventParams{
+		IncidentID: utils.ToPgUUID(e.IncidentID),
+		Kind:       e.Kind,
+		AuthorID:   authorID,
+		Message:    e.Message,
+		Data:       raw,
+		OccurredAt: utils.ToPgTimestamptz(e.OccurredAt),
+	})
+	if err != nil {
+		return Event{}, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	e.ID = utils.FromPgUUID(row.ID)
+	e.Data = data
+	e.CreatedAt = utils.FromPgTimestamptz(row.CreatedAt)
+	return e, nil
+}
+
+// ListEvents returns the incident's timeline, oldest first.
+func (r *Repository) ListEvents(ctx context.Context, incidentID uuid.UUID) ([]Event, error) {
+	const op string = "repo.incident.list_events"
+
+	rows, err := r.querier.ListIncidentEvents(ctx, utils.ToPgUUID(incidentID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	events := make([]Event, 0, len(rows))
+	for i := range rows {
+		row := &rows[i]
+		e := Event{
+			ID:         utils.FromPgUUID(row.ID),
+			IncidentID: utils.FromPgUUID(row.IncidentID),
+			Kind:       row.Kind,
+			AuthorName: row.AuthorName,
+			Message:    row.Message,
+			OccurredAt: utils.FromPgTimestamptz(row.OccurredAt),
+			CreatedAt:  utils.FromPgTimestamptz(row.CreatedAt),
+		}
+		if row.AuthorID.Valid {
+			id := utils.FromPgUUID(row.AuthorID)
+			e.AuthorID = &id
+		}
+		if err := json.Unmarshal(row.Data, &e.Data); err != nil {
+			return nil, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func fromPgUUIDs(ids []pgtype.UUID) []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, utils.FromPgUUID(id))
+	}
+	return out
+}
+
+func uuidString(id pgtype.UUID) string {
+	if !id.Valid {
+		return ""
+	}
+	return utils.FromPgUUID(id).String()
+}
+
 func timePtr(ts pgtype.Timestamptz) *time.Time {
 	if !ts.Valid || ts.InfinityModifier != pgtype.Finite {
 		return nil
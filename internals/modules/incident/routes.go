@@ -2,11 +2,22 @@ package incident
 
 import "github.com/go-chi/chi/v5"
 
-func Routes(h *Handler) chi.Router {
+func Routes(h *Handler, extra ...func(chi.Router)) chi.Router {
 	r := chi.NewRouter()
 
 	r.Get("/", h.ListIncidents)
 	r.Get("/{incidentID}", h.GetIncident)
+	r.Post("/{incidentID}/ack", h.Acknowledge)
+	r.Delete("/{incidentID}/ack", h.Unacknowledge)
+	r.Post("/{incidentID}/assign", h.Assign)
+	r.Get("/{incidentID}/timeline", h.GetTimeline)
+	r.Post("/{incidentID}/comments", h.AddComment)
+	r.Post("/{incidentID}/postmortem", h.AddPostmortem)
+
+	// Additional per-incident routes owned by other modules (alerts, etc.)
+	for _, fn := range extra {
+		fn(r)
+	}
 
 	return r
 }
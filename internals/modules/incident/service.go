@@ -2,16 +2,31 @@ package incident
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
 
+	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 )
 
+// Escalator is satisfied by *escalation.Service.
+type Escalator interface {
+	Acknowledge(ctx context.Context, incidentID uuid.UUID) error
+	Resume(ctx context.Context, incidentID uuid.UUID) error
+}
+
 type Service struct {
-	repo *Repository
+	repo      *Repository
+	timeline  *Timeline
+	escalator Escalator
+	logger    *zerolog.Logger
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, timeline *Timeline, escalator Escalator, logger *zerolog.Logger) *Service {
+	return &Service{repo: repo, timeline: timeline, escalator: escalator, logger: logger}
 }
 
 func (s *Service) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts ListIncidentsOptions) (ListIncidentsPage, error) {
@@ -23,10 +38,15 @@ func (s *Service) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts ListI
 	var nextCursor *string
 	if hasMore && len(incidents) > 0 {
 		last := incidents[len(incidents)-1]
-		cursor, err := EncodeCursor(Cursor{
+		next := Cursor{
+			Sort:       opts.Filters.Sort,
 			StartTime:  last.StartTime,
 			IncidentID: last.ID,
-		})
+		}
+		if next.Sort == SortName {
+			next.Key = last.sortKey
+		}
+		cursor, err := EncodeCursor(next)
 		if err != nil {
 			return ListIncidentsPage{}, err
 		}
@@ -45,3 +65,242 @@ func (s *Service) ListByTeamID(ctx context.Context, teamID uuid.UUID, opts ListI
 func (s *Service) GetByIDAndTeamID(ctx context.Context, incidentID uuid.UUID, teamID uuid.UUID) (Incident, error) {
 	return s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
 }
+
+// Acknowledge records that the user is working on the incident and stops its
+// escalation. Acknowledging an already acknowledged incident is a no-op.
+func (s *Service) Acknowledge(ctx context.Context, teamID, incidentID, userID uuid.UUID) (Incident, error) {
+	const op string = "service.incident.acknowledge"
+
+	changed, err := s.repo.Acknowledge(ctx, teamID, incidentID, userID)
+	if err != nil {
+		return Incident{}, err
+	}
+	if !changed {
+		return s.unchanged(ctx, op, teamID, incidentID)
+	}
+
+	s.timeline.Record(ctx, Event{IncidentID: incidentID, Kind: EventAcknowledged, AuthorID: &userID})
+
+	if err := s.escalator.Acknowledge(ctx, incidentID); err != nil {
+		// the escalation worker checks the ack before paging the next level
+		s.logger.Error().Str("op", op).Err(err).Str("incident_id", incidentID.String()).Msg("failed to stop escalation")
+	}
+
+	return s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
+}
+
+// AcknowledgeExternal records an acknowledgement made in an external
+// incident management tool. The responder is linked to the team member
+// with the same email address when there is one. It reports false when the
+// incident was already acknowledged or is resolved.
+func (s *Service) AcknowledgeExternal(ctx context.Context, teamID, incidentID uuid.UUID, r Responder) (bool, error) {
+	const op string = "service.incident.acknowledge_external"
+
+	var member *uuid.UUID
+	if r.Email != "" {
+		id, found, err := s.repo.MemberByEmail(ctx, teamID, r.Email)
+		if err != nil {
+			return false, err
+		}
+		if found {
+			member = &id
+		}
+	}
+
+	changed, err := s.repo.AcknowledgeExternal(ctx, teamID, incidentID, member, r.Label())
+	if err != nil || !changed {
+		return false, err
+	}
+
+	s.timeline.Record(ctx, Event{
+		IncidentID: incidentID,
+		Kind:       EventAcknowledged,
+		AuthorID:   member,
+		Message:    fmt.Sprintf("acknowledged in %s by %s", r.Source, r.Label()),
+		Data:       responderData(r),
+	})
+
+	if err := s.escalator.Acknowledge(ctx, incidentID); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("incident_id", incidentID.String()).Msg("failed to stop escalation")
+	}
+	return true, nil
+}
+
+// ResolveExternal records that an incident was resolved in an external
+// tool. Sofon closes incidents only when the monitor recovers, so the
+// incident is acknowledged, which stops escalation and reminders, and the
+// resolution is noted on its timeline. It reports false when neither
+// changed anything, such as for a repeated webhook.
+func (s *Service) ResolveExternal(ctx context.Context, teamID, incidentID uuid.UUID, r Responder) (bool, error) {
+	acked, err := s.AcknowledgeExternal(ctx, teamID, incidentID, r)
+	if err != nil {
+		return false, err
+	}
+	resolved, err := s.repo.MarkResolvedExternal(ctx, teamID, incidentID)
+	if err != nil || !resolved {
+		return acked, err
+	}
+
+	s.timeline.Record(ctx, Event{
+		IncidentID: incidentID,
+		Kind:       EventExternalResolved,
+		Message:    fmt.Sprintf("resolved in %s by %s", r.Source, r.Label()),
+		Data:       responderData(r),
+	})
+	return true, nil
+}
+
+// OpenIncidentsForMonitor lists the open incidents of one of the team's
+// monitors.
+func (s *Service) OpenIncidentsForMonitor(ctx context.Context, teamID, monitorID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.OpenForMonitor(ctx, teamID, monitorID)
+}
+
+// OpenIncidentsForDigest lists the open incidents announced in an alert
+// digest.
+func (s *Service) OpenIncidentsForDigest(ctx context.Context, teamID, groupID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.OpenForDigest(ctx, teamID, groupID)
+}
+
+// Unacknowledge withdraws the acknowledgement and resumes the escalation
+// where it stopped.
+func (s *Service) Unacknowledge(ctx context.Context, teamID, incidentID, userID uuid.UUID) (Incident, error) {
+	const op string = "service.incident.unacknowledge"
+
+	changed, err := s.repo.Unacknowledge(ctx, teamID, incidentID)
+	if err != nil {
+		return Incident{}, err
+	}
+	if !changed {
+		return s.unchanged(ctx, op, teamID, incidentID)
+	}
+
+	s.timeline.Record(ctx, Event{IncidentID: incidentID, Kind: EventUnacknowledged, AuthorID: &userID})
+
+	if err := s.escalator.Resume(ctx, incidentID); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("incident_id", incidentID.String()).Msg("failed to resume escalation")
+	}
+
+	return s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
+}
+
+// Assign hands the incident to an active team member, or unassigns it when
+// assignee is nil.
+func (s *Service) Assign(ctx context.Context, teamID, incidentID uuid.UUID, assignee *uuid.UUID, by uuid.UUID) (Incident, error) {
+	const op string = "service.incident.assign"
+
+	if assignee != nil {
+		ok, err := s.repo.IsActiveMember(ctx, teamID, *assignee)
+		if err != nil {
+			return Incident{}, err
+		}
+		if !ok {
+			return Incident{}, &apperror.Error{
+				Kind:    apperror.InvalidInput,
+				Op:      op,
+				Message: "assignee is not an active team member",
+			}
+		}
+	}
+
+	if err := s.repo.Assign(ctx, teamID, incidentID, assignee, by); err != nil {
+		return Incident{}, err
+	}
+
+	inc, err := s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
+	if err != nil {
+		return Incident{}, err
+	}
+
+	event := Event{IncidentID: incidentID, Kind: EventUnassigned, AuthorID: &by}
+	if assignee != nil {
+		event.Kind = EventAssigned
+		event.Message = inc.AssignedToName
+		event.Data = map[string]any{"assigned_to": assignee.String()}
+	}
+	s.timeline.Record(ctx, event)
+
+	return inc, nil
+}
+
+// GetWithTimeline returns the incident together with its timeline.
+func (s *Service) GetWithTimeline(ctx context.Context, incidentID, teamID uuid.UUID) (Incident, []Event, error) {
+	inc, err := s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
+	if err != nil {
+		return Incident{}, nil, err
+	}
+	events, err := s.repo.ListEvents(ctx, incidentID)
+	if err != nil {
+		return Incident{}, nil, err
+	}
+	return inc, events, nil
+}
+
+// GetTimeline returns the incident's events, oldest first.
+func (s *Service) GetTimeline(ctx context.Context, teamID, incidentID uuid.UUID) ([]Event, error) {
+	if _, err := s.repo.GetByIDAndTeamID(ctx, incidentID, teamID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListEvents(ctx, incidentID)
+}
+
+// AddNote posts a comment or postmortem note by a team member.
+func (s *Service) AddNote(ctx context.Context, teamID, incidentID, authorID uuid.UUID, kind, message string) (Event, error) {
+	const op string = "service.incident.add_note"
+
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return Event{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "message is required"}
+	}
+	if utf8.RuneCountInString(message) > maxNoteLen {
+		return Event{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: fmt.Sprintf("message must be at most %d characters", maxNoteLen),
+		}
+	}
+
+	if _, err := s.repo.GetByIDAndTeamID(ctx, incidentID, teamID); err != nil {
+		return Event{}, err
+	}
+
+	e, err := s.repo.AddEvent(ctx, Event{
+		IncidentID: incidentID,
+		Kind:       kind,
+		AuthorID:   &authorID,
+		Message:    message,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		return Event{}, err
+	}
+	return e, nil
+}
+
+func responderData(r Responder) map[string]any {
+	data := map[string]any{"source": r.Source}
+	if r.Name != "" {
+		data["responder"] = r.Name
+	}
+	if r.Email != "" {
+		data["responder_email"] = r.Email
+	}
+	return data
+}
+
+// unchanged explains why an ack state change didn't apply: the incident is
+// missing or resolved, or it was already in the requested state.
+func (s *Service) unchanged(ctx context.Context, op string, teamID, incidentID uuid.UUID) (Incident, error) {
+	inc, err := s.repo.GetByIDAndTeamID(ctx, incidentID, teamID)
+	if err != nil {
+		return Incident{}, err
+	}
+	if !inc.IsActive {
+		return Incident{}, &apperror.Error{
+			Kind:    apperror.InvalidInput,
+			Op:      op,
+			Message: "incident is already resolved",
+		}
+	}
+	return inc, nil
+}
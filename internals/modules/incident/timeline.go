@@ -0,0 +1,34 @@
+package incident
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Timeline records automatic events on incident timelines. Recording is
+// best effort: a failure is logged and never fails the caller, so the
+// result processor and alert workers can record freely.
+type Timeline struct {
+	repo   *Repository
+	logger *zerolog.Logger
+}
+
+func NewTimeline(repo *Repository, logger *zerolog.Logger) *Timeline {
+	return &Timeline{repo: repo, logger: logger}
+}
+
+// Record appends e to its incident's timeline. OccurredAt defaults to now.
+func (t *Timeline) Record(ctx context.Context, e Event) {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	if _, err := t.repo.AddEvent(ctx, e); err != nil {
+		t.logger.Error().
+			Err(err).
+			Str("incident_id", e.IncidentID.String()).
+			Str("kind", e.Kind).
+			Msg("failed to record incident event")
+	}
+}
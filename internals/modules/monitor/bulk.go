@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Bulk actions.
+const (
+	BulkEnable      = "enable"
+	BulkDisable     = "disable"
+	BulkDelete      = "delete"
+	BulkSetChannels = "set_notification_channels"
+	BulkSetInterval = "set_interval"
+)
+
+// Per-monitor outcomes of a bulk action.
+const (
+	BulkUpdated   = "updated"
+	BulkUnchanged = "unchanged"
+	BulkDeleted   = "deleted"
+	BulkNotFound  = "not_found"
+)
+
+// maxBulkMonitors caps the monitors a single bulk action can touch.
+const maxBulkMonitors = 1000
+
+// BulkSelector picks the team's monitors a bulk action applies to. A
+// monitor is selected when it matches every criterion given: one of IDs,
+// all of Tags (as in list filters) and, case-insensitively, URLContains.
+type BulkSelector struct {
+	IDs         []uuid.UUID
+	Tags        []string
+	URLContains string
+}
+
+// BulkAction is what to do to the selected monitors. NotificationChannels
+// and IntervalSec are only read by the actions that set them.
+type BulkAction struct {
+	Action               string
+	NotificationChannels []string
+	IntervalSec          int32
+}
+
+// BulkMonitorResult is the outcome for one selected monitor.
+type BulkMonitorResult struct {
+	MonitorID uuid.UUID
+	Name      string
+	Url       string
+	Status    string
+}
+
+type BulkResult struct {
+	Action  string
+	Results []BulkMonitorResult
+}
+
+func (sel BulkSelector) empty() bool {
+	return len(sel.IDs) == 0 && len(sel.Tags) == 0 && sel.URLContains == ""
+}
+
+func (sel BulkSelector) matches(m Monitor, ids map[uuid.UUID]bool) bool {
+	if len(ids) > 0 && !ids[m.ID] {
+		return false
+	}
+	if sel.URLContains != "" && !strings.Contains(strings.ToLower(m.Url), strings.ToLower(sel.URLContains)) {
+		return false
+	}
+	for _, f := range sel.Tags {
+		found := false
+		for _, t := range m.Tags {
+			if TagMatches(f, t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeChannels trims channel references (plugin instance IDs or
+// plugin types), dropping empty and duplicate ones while keeping their
+// order.
+func normalizeChannels(channels []string) []string {
+	out := make([]string, 0, len(channels))
+	for _, c := range channels {
+		c = strings.TrimSpace(c)
+		if c != "" && !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package monitor
+
+type BulkSelectorRequest struct {
+	IDs         []string `json:"ids"`
+	Tags        []string `json:"tags"`
+	URLContains string   `json:"url_contains"`
+}
+
+// BulkRequest applies action to the monitors the selector matches.
+// notification_channels is read by set_notification_channels and
+// interval_sec by set_interval.
+type BulkRequest struct {
+	Selector             BulkSelectorRequest `json:"selector"`
+	Action               string              `json:"action" validate:"required"`
+	NotificationChannels []string            `json:"notification_channels"`
+	IntervalSec          int32               `json:"interval_sec"`
+}
+
+type BulkMonitorResponse struct {
+	MonitorID string `json:"monitor_id"`
+	Name      string `json:"name,omitempty"`
+	Url       string `json:"url,omitempty"`
+	Status    string `json:"status"`
+}
+
+type BulkResponse struct {
+	Action   string                `json:"action"`
+	Matched  int                   `json:"matched"`
+	Changed  int                   `json:"changed"`
+	Monitors []BulkMonitorResponse `json:"monitors"`
+}
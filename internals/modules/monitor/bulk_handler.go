@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// Bulk applies one action to every monitor the selector matches and reports
+// the outcome per monitor.
+func (h *Handler) Bulk(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.bulk"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	var req BulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	sel := BulkSelector{Tags: req.Selector.Tags, URLContains: req.Selector.URLContains}
+	for _, s := range req.Selector.IDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid monitor id "+s)
+			return
+		}
+		sel.IDs = append(sel.IDs, id)
+	}
+
+	res, err := h.service.Bulk(ctx, tm.TeamID, sel, BulkAction{
+		Action:               req.Action,
+		NotificationChannels: req.NotificationChannels,
+		IntervalSec:          req.IntervalSec,
+	})
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("bulk monitor action error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	out := BulkResponse{Action: res.Action, Monitors: make([]BulkMonitorResponse, 0, len(res.Results))}
+	for _, m := range res.Results {
+		switch m.Status {
+		case BulkUpdated, BulkDeleted:
+			out.Matched++
+			out.Changed++
+		case BulkUnchanged:
+			out.Matched++
+		}
+		out.Monitors = append(out.Monitors, BulkMonitorResponse{
+			MonitorID: m.MonitorID.String(),
+			Name:      m.Name,
+			Url:       m.Url,
+			Status:    m.Status,
+		})
+	}
+	middle.SetAuditChange(ctx, nil, map[string]any{"action": out.Action, "matched": out.Matched, "changed": out.Changed})
+	utils.WriteJSON(w, http.StatusOK, reqID, "bulk action applied", out)
+}
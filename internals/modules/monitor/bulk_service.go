@@ -0,0 +1,192 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// ChannelChecker reports whether a channel reference names a plugin type or
+// one of the team's plugin instances. It is satisfied by
+// *alert.AlertService.
+type ChannelChecker interface {
+	ChannelExists(ctx context.Context, teamID uuid.UUID, ref string) (bool, error)
+}
+
+// SetChannelChecker wires the alert service in, which is built after this
+// service.
+func (s *Service) SetChannelChecker(c ChannelChecker) {
+	s.channels = c
+}
+
+// Bulk applies one action to every monitor the selector matches. The
+// database changes run in a single transaction, so either all of them
+// land or none do; the scheduler and caches are updated once it commits.
+// Listed IDs that match no monitor, because the team has no such monitor
+// or it fails the other criteria, are reported as not found.
+func (s *Service) Bulk(ctx context.Context, teamID uuid.UUID, sel BulkSelector, action BulkAction) (BulkResult, error) {
+	const op = "service.monitor.bulk"
+
+	action, err := checkBulkAction(op, action)
+	if err != nil {
+		return BulkResult{}, err
+	}
+	if action.Action == BulkSetChannels {
+		if err := s.checkChannels(ctx, op, teamID, action.NotificationChannels); err != nil {
+			return BulkResult{}, err
+		}
+	}
+	sel.Tags = NormalizeTags(sel.Tags)
+	if sel.empty() {
+		return BulkResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "selector must set ids, tags or url_contains"}
+	}
+
+	monitors, err := s.monitorRepo.ListAll(ctx, teamID)
+	if err != nil {
+		return BulkResult{}, err
+	}
+	ids := make(map[uuid.UUID]bool, len(sel.IDs))
+	for _, id := range sel.IDs {
+		ids[id] = true
+	}
+	var selected []Monitor
+	for _, m := range monitors {
+		if sel.matches(m, ids) {
+			selected = append(selected, m)
+		}
+	}
+	if len(selected) > maxBulkMonitors {
+		return BulkResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("the selector matches %d monitors, at most %d can be changed at once", len(selected), maxBulkMonitors)}
+	}
+
+	res := BulkResult{Action: action.Action, Results: make([]BulkMonitorResult, 0, len(selected))}
+	err = s.monitorRepo.InTx(ctx, func(tx *Repository) error {
+		for _, m := range selected {
+			status, err := bulkApply(ctx, tx, teamID, m, action)
+			if apperror.IsKind(err, apperror.NotFound) {
+				status = BulkNotFound
+			} else if err != nil {
+				return err
+			}
+			res.Results = append(res.Results, BulkMonitorResult{MonitorID: m.ID, Name: m.Name, Url: m.Url, Status: status})
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("team_id", teamID.String()).Str("action", action.Action).Msg("bulk action rolled back")
+		return BulkResult{}, err
+	}
+
+	for i, r := range res.Results {
+		m := selected[i]
+		switch {
+		case r.Status == BulkDeleted:
+			s.disableMonitor(ctx, m.ID)
+			if err := s.userSvc.DecrementMonitorCount(ctx, m.UserID); err != nil {
+				s.logger.Error().Str("op", op).Err(err).Msg("failed to decrement monitor count after delete")
+			}
+			s.recordSnapshot(ctx, m, RevisionDelete)
+		case r.Status != BulkUpdated:
+		case action.Action == BulkEnable:
+			s.ScheduleMonitor(ctx, m.ID, m.IntervalSec, op)
+			s.recordRevision(ctx, teamID, m.ID, RevisionEnable)
+		case action.Action == BulkDisable:
+			s.disableMonitor(ctx, m.ID)
+			s.recordRevision(ctx, teamID, m.ID, RevisionDisable)
+		default:
+			_ = s.cache.DelMonitor(ctx, m.ID)
+			s.recordRevision(ctx, teamID, m.ID, RevisionUpdate)
+		}
+	}
+	if action.Action == BulkDelete {
+		s.purgeRevisions(ctx, teamID)
+	}
+	for _, m := range selected {
+		delete(ids, m.ID)
+	}
+	for _, id := range sel.IDs {
+		if ids[id] {
+			res.Results = append(res.Results, BulkMonitorResult{MonitorID: id, Status: BulkNotFound})
+			delete(ids, id)
+		}
+	}
+	return res, nil
+}
+
+// bulkApply makes the database change for one monitor inside the bulk
+// transaction.
+func bulkApply(ctx context.Context, tx *Repository, teamID uuid.UUID, m Monitor, action BulkAction) (string, error) {
+	switch action.Action {
+	case BulkEnable, BulkDisable:
+		enable := action.Action == BulkEnable
+		if m.Enabled == enable {
+			return BulkUnchanged, nil
+		}
+		if err := tx.SetEnabled(ctx, teamID, m.ID, enable); err != nil {
+			return "", err
+		}
+		if !enable {
+			if err := tx.CloseOpenIncident(ctx, m.ID); err != nil {
+				return "", err
+			}
+		}
+		return BulkUpdated, nil
+	case BulkDelete:
+		if err := tx.Delete(ctx, teamID, m.ID); err != nil {
+			return "", err
+		}
+		return BulkDeleted, nil
+	case BulkSetChannels, BulkSetInterval:
+		cfg := monitorConfig(m)
+		if action.Action == BulkSetChannels {
+			cfg.NotificationChannels = action.NotificationChannels
+		} else {
+			cfg.IntervalSec = action.IntervalSec
+		}
+		if configEqual(monitorConfig(m), cfg) {
+			return BulkUnchanged, nil
+		}
+		if err := tx.UpdateConfig(ctx, teamID, m.ID, cfg); err != nil {
+			return "", err
+		}
+		return BulkUpdated, nil
+	}
+	return "", nil
+}
+
+// checkChannels rejects channel references that name no plugin type or
+// plugin instance of the team, so a typo can't silently leave monitors
+// without alerts.
+func (s *Service) checkChannels(ctx context.Context, op string, teamID uuid.UUID, channels []string) error {
+	for _, c := range channels {
+		known, err := s.channels.ChannelExists(ctx, teamID, c)
+		if err != nil {
+			return err
+		}
+		if !known {
+			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("unknown notification channel %q", c)}
+		}
+	}
+	return nil
+}
+
+func checkBulkAction(op string, action BulkAction) (BulkAction, error) {
+	invalid := func(msg string) (BulkAction, error) {
+		return BulkAction{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	switch action.Action {
+	case BulkEnable, BulkDisable, BulkDelete:
+	case BulkSetChannels:
+		action.NotificationChannels = normalizeChannels(action.NotificationChannels)
+	case BulkSetInterval:
+		if action.IntervalSec < 60 {
+			return invalid("interval_sec must be at least 60")
+		}
+	default:
+		return invalid("action must be one of enable, disable, delete, set_notification_channels, set_interval")
+	}
+	return action, nil
+}
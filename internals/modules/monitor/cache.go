@@ -15,4 +15,8 @@ type Cache interface {
 	DelMonitor(ctx context.Context, id uuid.UUID) error
 	DelStatus(ctx context.Context, monitorID uuid.UUID) error
 	DelSchedule(ctx context.Context, monitorID string) error
+	GetStatus(ctx context.Context, monitorID uuid.UUID) (map[string]string, error)
+	GetIncident(ctx context.Context, monitorID uuid.UUID) (map[string]string, error)
+	CloseGroupAlert(ctx context.Context, groupID uuid.UUID) ([]byte, error)
+	IncrementCheckRate(ctx context.Context, teamID uuid.UUID, windowStart time.Time, window time.Duration) (int64, error)
 }
@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// Checker runs a monitor's check on demand. It is satisfied by
+// *executor.Executor.
+type Checker interface {
+	// Check runs m's check right away. With record the result is also
+	// handed to the result processor, as if the scheduler had run it,
+	// unless a check of m is already running.
+	Check(ctx context.Context, m Monitor, record bool) (CheckResult, error)
+}
+
+// CheckResult is the outcome of an on-demand check. Reason names why it
+// failed, e.g. TIMEOUT or KEYWORD_NOT_FOUND. Recorded reports whether the
+// result went to the result processor.
+type CheckResult struct {
+	Success   bool
+	Status    int
+	LatencyMs int64
+	Reason    string
+	Retryable bool
+	CheckedAt time.Time
+	Recorded  bool
+}
+
+// SetChecker wires the executor in. The executor loads monitors through
+// this service, so it can only be attached once both exist.
+func (s *Service) SetChecker(c Checker) {
+	s.checker = c
+}
+
+// CheckNow runs the monitor's check immediately. With record the result
+// counts like a scheduled one: it can open or resolve an incident and
+// moves the monitor's next scheduled run. Only enabled monitors can record,
+// and the result isn't recorded while a scheduled check of the monitor is
+// running, since that one's result counts.
+func (s *Service) CheckNow(ctx context.Context, teamID, monitorID uuid.UUID, record bool) (CheckResult, error) {
+	const op = "service.monitor.check_now"
+
+	m, err := s.monitorRepo.Get(ctx, teamID, monitorID)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	if record && !m.Enabled {
+		return CheckResult{}, &apperror.Error{Kind: apperror.Conflict, Op: op, Message: "monitor is disabled, enable it to record check results"}
+	}
+	if err := s.allowCheck(ctx, op, teamID); err != nil {
+		return CheckResult{}, err
+	}
+	return s.runCheck(ctx, op, m, record)
+}
+
+// TestCheck runs a check for a monitor that isn't saved, so its settings
+// can be tried before creating it. Nothing is recorded.
+func (s *Service) TestCheck(ctx context.Context, data CreateMonitor) (CheckResult, error) {
+	const op = "service.monitor.test_check"
+
+	if len(data.Keyword) > maxKeywordLen {
+		return CheckResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "keyword can be at most 500 characters"}
+	}
+	if err := s.allowCheck(ctx, op, data.TeamID); err != nil {
+		return CheckResult{}, err
+	}
+	return s.runCheck(ctx, op, Monitor{
+		TeamID:             data.TeamID,
+		Url:                data.Url,
+		IntervalSec:        data.IntervalSec,
+		TimeoutSec:         data.TimeoutSec,
+		LatencyThresholdMs: data.LatencyThresholdMs,
+		ExpectedStatus:     data.ExpectedStatus,
+		Keyword:            data.Keyword,
+		KeywordAbsent:      data.KeywordAbsent && data.Keyword != "",
+		Name:               data.Name,
+		Enabled:            true,
+	}, false)
+}
+
+func (s *Service) runCheck(ctx context.Context, op string, m Monitor, record bool) (CheckResult, error) {
+	if s.checker == nil {
+		return CheckResult{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: errors.New("no checker configured")}
+	}
+	res, err := s.checker.Check(ctx, m, record)
+	if err != nil {
+		return CheckResult{}, &apperror.Error{Kind: apperror.RequestTimeout, Op: op, Message: "request cancelled or timed out", Err: err}
+	}
+	return res, nil
+}
+
+// allowCheck counts an on-demand check against the team's rate limit. If
+// the counter can't be reached the check is allowed.
+func (s *Service) allowCheck(ctx context.Context, op string, teamID uuid.UUID) error {
+	if s.checkRateLimit <= 0 {
+		return nil
+	}
+	windowStart := time.Now().Truncate(s.checkRateWindow)
+	count, err := s.cache.IncrementCheckRate(ctx, teamID, windowStart, s.checkRateWindow)
+	if err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("team_id", teamID.String()).Msg("failed to count on-demand check")
+		return nil
+	}
+	if count > int64(s.checkRateLimit) {
+		return &apperror.Error{Kind: apperror.RateLimited, Op: op, Message: "too many checks, try again in " + time.Until(windowStart.Add(s.checkRateWindow)).Round(time.Second).String()}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package monitor
+
+import "time"
+
+type CheckResponse struct {
+	Success   bool      `json:"success"`
+	Status    int       `json:"status"`
+	LatencyMs int64     `json:"latency_ms"`
+	Reason    string    `json:"reason,omitempty"`
+	Retryable bool      `json:"retryable"`
+	CheckedAt time.Time `json:"checked_at"`
+	Recorded  bool      `json:"recorded"`
+}
@@ -0,0 +1,102 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// CheckMonitor runs the monitor's check now and returns the result. With
+// ?record=true the result is processed like a scheduled one.
+func (h *Handler) CheckMonitor(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.check_monitor"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	record := r.URL.Query().Get("record") == "true"
+	if !record {
+		middle.SkipAudit(ctx)
+	}
+	res, err := h.service.CheckNow(ctx, tm.TeamID, monitorID, record)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("check monitor error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "check completed", toCheckResponse(res))
+}
+
+// TestMonitor runs a check for an unsaved monitor described by a create
+// request body.
+func (h *Handler) TestMonitor(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.test_monitor"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	var req CreateMonitorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+
+	middle.SkipAudit(ctx)
+	res, err := h.service.TestCheck(ctx, CreateMonitor{
+		TeamID:             tm.TeamID,
+		Url:                req.Url,
+		IntervalSec:        req.IntervalSec,
+		TimeoutSec:         req.TimeoutSec,
+		LatencyThresholdMs: req.LatencyThresholdMs,
+		ExpectedStatus:     req.ExpectedStatus,
+		Name:               req.Name,
+		Keyword:            req.Keyword,
+		KeywordAbsent:      req.KeywordAbsent,
+	})
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("test monitor error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "check completed", toCheckResponse(res))
+}
+
+func toCheckResponse(res CheckResult) CheckResponse {
+	return CheckResponse{
+		Success:   res.Success,
+		Status:    res.Status,
+		LatencyMs: res.LatencyMs,
+		Reason:    res.Reason,
+		Retryable: res.Retryable,
+		CheckedAt: res.CheckedAt,
+		Recorded:  res.Recorded,
+	}
+}
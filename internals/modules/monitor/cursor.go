@@ -8,15 +8,21 @@ import (
 )
 
 type cursorPayload struct {
-	CreatedAt string `json:"c"`
+	Sort      string `json:"s,omitempty"`
+	CreatedAt string `json:"c,omitempty"`
+	Key       string `json:"k,omitempty"`
 	MonitorID string `json:"m"`
 }
 
 func EncodeCursor(c Cursor) (string, error) {
 	p := cursorPayload{
-		CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Sort:      c.Sort,
+		Key:       c.Key,
 		MonitorID: c.MonitorID,
 	}
+	if c.Sort == "" || c.Sort == SortCreated {
+		p.CreatedAt = c.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
 	raw, err := json.Marshal(p)
 	if err != nil {
 		return "", err
@@ -34,17 +40,34 @@ func DecodeCursor(v string) (*Cursor, error) {
 	if err := json.Unmarshal(raw, &p); err != nil {
 		return nil, fmt.Errorf("invalid cursor")
 	}
-	if p.CreatedAt == "" || p.MonitorID == "" {
+	if p.MonitorID == "" {
 		return nil, fmt.Errorf("invalid cursor")
 	}
 
-	createdAt, err := time.Parse(time.RFC3339Nano, p.CreatedAt)
-	if err != nil {
-		return nil, fmt.Errorf("invalid cursor")
+	// Cursors issued before sorting was added carry no sort.
+	if p.Sort == "" {
+		p.Sort = SortCreated
 	}
 
-	return &Cursor{
-		CreatedAt: createdAt,
+	c := &Cursor{
+		Sort:      p.Sort,
+		Key:       p.Key,
 		MonitorID: p.MonitorID,
-	}, nil
+	}
+	switch p.Sort {
+	case SortCreated:
+		if p.CreatedAt == "" {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		createdAt, err := time.Parse(time.RFC3339Nano, p.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		c.CreatedAt = createdAt
+	case SortName, SortURL:
+	default:
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	return c, nil
 }
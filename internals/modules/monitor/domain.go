@@ -6,6 +6,43 @@ import (
 	"github.com/google/uuid"
 )
 
+// Reminder bounds; an interval of 0 disables reminders.
+const (
+	minReminderIntervalSec = 300
+	maxReminderIntervalSec = 86400
+	maxReminders           = 100
+)
+
+// Monitor severities, used by notification routing rules.
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Tag limits. A tag is either a bare key ("payments") or a key:value pair
+// ("env:prod").
+const (
+	maxTags   = 20
+	maxTagLen = 50
+)
+
+// Detail limits.
+const (
+	maxNameLen        = 100
+	maxDescriptionLen = 2000
+	maxRunbookURLLen  = 2048
+	maxSlugLen        = 63
+	maxKeywordLen     = 500
+)
+
+// Monitor list sort orders.
+const (
+	SortCreated = "created"
+	SortName    = "name"
+	SortURL     = "url"
+)
+
 type CreateMonitor struct {
 	TeamID               uuid.UUID
 	UserID               uuid.UUID
@@ -15,6 +52,17 @@ type CreateMonitor struct {
 	LatencyThresholdMs   *int32
 	ExpectedStatus       *int32
 	NotificationChannels []string
+	EscalationPolicyID   *uuid.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookURL           string
+	Slug                 string
+	Keyword              string
+	KeywordAbsent        bool
 }
 
 type Monitor struct {
@@ -30,16 +78,107 @@ type Monitor struct {
 	CreatedAt            time.Time
 	IsDown               bool
 	NotificationChannels []string
+	EscalationPolicyID   *uuid.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookURL           string
+	// Slug is the stable key monitors-as-code documents use, empty for
+	// monitors created without one.
+	Slug string
+	// Keyword must appear in the response body, or must not when
+	// KeywordAbsent is set. Empty skips the body check.
+	Keyword       string
+	KeywordAbsent bool
+	// SnoozedUntil mutes the monitor's alerts and incidents until then;
+	// checks keep running. SnoozeNotify sends a notice once it ends.
+	SnoozedUntil *time.Time
+	SnoozeNotify bool
+
+	// sortKey is the name sort value of a listed monitor, used to build
+	// the next page cursor.
+	sortKey string
+}
+
+// DisplayName is the monitor's name, or its URL when it has none.
+func (m Monitor) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.Url
+}
+
+// Snoozed reports whether the monitor's alerts are muted at now.
+func (m Monitor) Snoozed(now time.Time) bool {
+	return m.SnoozedUntil != nil && now.Before(*m.SnoozedUntil)
+}
+
+// MonitorConfig is what a monitor checks and where its alerts go.
+type MonitorConfig struct {
+	Url                  string
+	IntervalSec          int32
+	TimeoutSec           int32
+	LatencyThresholdMs   *int32
+	ExpectedStatus       *int32
+	NotificationChannels []string
+	Keyword              string
+	KeywordAbsent        bool
+}
+
+// MonitorDetails are the descriptive fields shown in the dashboard and
+// alert payloads.
+type MonitorDetails struct {
+	Name        string
+	Description string
+	RunbookURL  string
+}
+
+// MonitorSettings are the fields of a monitor that don't change what is
+// checked: its details, labels, escalation policy and reminders.
+type MonitorSettings struct {
+	Details             MonitorDetails
+	Tags                []string
+	Severity            string
+	EscalationPolicyID  *uuid.UUID
+	ReminderIntervalSec int32
+	ReminderMax         int32
+}
+
+// MonitorUpdate is a partial update of a monitor; nil fields are left as
+// they are. A nil EscalationPolicyID keeps the policy and uuid.Nil detaches
+// it. A zero SnoozeUntil ends the snooze.
+type MonitorUpdate struct {
+	Enabled             *bool
+	Name                *string
+	Description         *string
+	RunbookURL          *string
+	Tags                *[]string
+	Severity            *string
+	EscalationPolicyID  *uuid.UUID
+	ReminderIntervalSec *int32
+	ReminderMax         *int32
+	SnoozeUntil         *time.Time
+	SnoozeNotify        bool
 }
 
+// Cursor marks the last monitor of a page. Key holds the sort value for
+// the name and url orders.
 type Cursor struct {
+	Sort      string
 	CreatedAt time.Time
+	Key       string
 	MonitorID string
 }
 
 type ListMonitorsOptions struct {
 	Limit  int32
 	Cursor *Cursor
+	Query  string
+	Tags   []string
+	Sort   string
 }
 
 type ListMonitorsPage struct {
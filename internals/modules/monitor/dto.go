@@ -1,5 +1,7 @@
 package monitor
 
+import "time"
+
 type CreateMonitorRequest struct {
 	Url                  string   `json:"url" validate:"required,url"`
 	IntervalSec          int32    `json:"interval_sec" validate:"required,gte=60"`
@@ -7,6 +9,17 @@ type CreateMonitorRequest struct {
 	LatencyThresholdMs   *int32   `json:"latency_threshold_ms"`
 	ExpectedStatus       *int32   `json:"expected_status"`
 	NotificationChannels []string `json:"notification_channels"`
+	EscalationPolicyID   *string  `json:"escalation_policy_id"`
+	ReminderIntervalSec  int32    `json:"reminder_interval_sec"`
+	ReminderMax          int32    `json:"reminder_max"`
+	Tags                 []string `json:"tags"`
+	Severity             string   `json:"severity"`
+	Name                 string   `json:"name"`
+	Description          string   `json:"description"`
+	RunbookURL           string   `json:"runbook_url"`
+	Slug                 string   `json:"slug"`
+	Keyword              string   `json:"keyword"`
+	KeywordAbsent        bool     `json:"keyword_absent"`
 }
 
 type CreateMonitorResponse struct {
@@ -14,15 +27,29 @@ type CreateMonitorResponse struct {
 }
 
 type GetMonitorResponse struct {
-	ID                   string   `json:"id"`
-	Url                  string   `json:"url"`
-	IntervalSec          int32    `json:"interval_sec"`
-	TimeoutSec           int32    `json:"timeout_sec"`
-	LatencyThresholdMs   *int32   `json:"latency_threshold_ms"`
-	ExpectedStatus       *int32   `json:"expected_status"`
-	Enabled              bool     `json:"enabled"`
-	IsDown               bool     `json:"is_down"`
-	NotificationChannels []string `json:"notification_channels"`
+	ID                   string     `json:"id"`
+	Url                  string     `json:"url"`
+	IntervalSec          int32      `json:"interval_sec"`
+	TimeoutSec           int32      `json:"timeout_sec"`
+	LatencyThresholdMs   *int32     `json:"latency_threshold_ms"`
+	ExpectedStatus       *int32     `json:"expected_status"`
+	Enabled              bool       `json:"enabled"`
+	IsDown               bool       `json:"is_down"`
+	NotificationChannels []string   `json:"notification_channels"`
+	EscalationPolicyID   *string    `json:"escalation_policy_id"`
+	ReminderIntervalSec  int32      `json:"reminder_interval_sec"`
+	ReminderMax          int32      `json:"reminder_max"`
+	Tags                 []string   `json:"tags"`
+	Severity             string     `json:"severity"`
+	Name                 string     `json:"name"`
+	Description          string     `json:"description"`
+	RunbookURL           string     `json:"runbook_url"`
+	Slug                 string     `json:"slug"`
+	Keyword              string     `json:"keyword"`
+	KeywordAbsent        bool       `json:"keyword_absent"`
+	Snoozed              bool       `json:"snoozed"`
+	SnoozedUntil         *time.Time `json:"snoozed_until"`
+	SnoozeNotify         bool       `json:"snooze_notify"`
 }
 
 type ListMonitorsResponse struct {
@@ -32,6 +59,18 @@ type ListMonitorsResponse struct {
 	Monitors   []GetMonitorResponse `json:"monitors"`
 }
 
-type UpdateMonitorStatusRequest struct {
-	Enable *bool `json:"enable" validate:"required"`
+// UpdateMonitorRequest changes only the fields that are present. An empty
+// escalation_policy_id detaches the policy, an empty severity keeps the
+// current one and a reminder_interval_sec of 0 turns reminders off.
+type UpdateMonitorRequest struct {
+	Enable              *bool          `json:"enable"`
+	Name                *string        `json:"name"`
+	Description         *string        `json:"description"`
+	RunbookURL          *string        `json:"runbook_url"`
+	Tags                *[]string      `json:"tags"`
+	Severity            *string        `json:"severity"`
+	EscalationPolicyID  *string        `json:"escalation_policy_id"`
+	ReminderIntervalSec *int32         `json:"reminder_interval_sec" validate:"omitempty,gte=0"`
+	ReminderMax         *int32         `json:"reminder_max" validate:"omitempty,gte=0"`
+	Snooze              *SnoozeRequest `json:"snooze"`
 }
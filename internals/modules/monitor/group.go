@@ -0,0 +1,160 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Group alert modes: one alert per member monitor, or one alert for the
+// whole group.
+const (
+	GroupAlertMembers = "members"
+	GroupAlertGroup   = "group"
+)
+
+// When a group in group alert mode counts as down.
+const (
+	GroupAlertWhenAny = "any"
+	GroupAlertWhenAll = "all"
+)
+
+// Monitor and group health, derived from the check status and incident
+// state in Redis.
+const (
+	StatusUp       = "up"
+	StatusDegraded = "degraded"
+	StatusDown     = "down"
+	StatusPaused   = "paused"
+	StatusUnknown  = "unknown"
+)
+
+// Group limits.
+const (
+	maxGroupNameLen     = 100
+	maxGroupDescLen     = 2000
+	maxGroupDepth       = 5
+	maxGroupChannels    = 20
+	maxGroupMembersEdit = 100
+)
+
+// Group bundles the monitors of one service. A group's monitors include
+// those of its subgroups.
+type Group struct {
+	ID          uuid.UUID
+	TeamID      uuid.UUID
+	ParentID    *uuid.UUID
+	Name        string
+	Description string
+	AlertMode   string
+	AlertWhen   string
+	// Channels receive group alerts; empty uses the channels of the member
+	// that went down.
+	Channels  []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// GroupInput creates or replaces a group.
+type GroupInput struct {
+	ParentID    *uuid.UUID
+	Name        string
+	Description string
+	AlertMode   string
+	AlertWhen   string
+	Channels    []string
+}
+
+// GroupMember is a monitor added to a group directly.
+type GroupMember struct {
+	GroupID   uuid.UUID
+	MonitorID uuid.UUID
+	Url       string
+	Name      string
+	Enabled   bool
+	Status    string
+}
+
+// GroupHealth is a group with the status of its monitors.
+type GroupHealth struct {
+	Group
+	// Members are the monitors added to the group directly.
+	Members []GroupMember
+	// Subgroups are the IDs of the direct child groups.
+	Subgroups []uuid.UUID
+	// Monitors maps every monitor of the group and its subgroups to its
+	// status.
+	Monitors map[uuid.UUID]string
+}
+
+// Status is the group's aggregate health: up when every checked monitor is
+// up, down when all of them are down and degraded in between. Paused and
+// never checked monitors don't count; a group with nothing to count is
+// unknown.
+func (h GroupHealth) Status() string {
+	var counted, down, failing int
+	for _, s := range h.Monitors {
+		switch s {
+		case StatusDown:
+			down++
+		case StatusDegraded:
+			failing++
+		case StatusUp:
+		default:
+			continue
+		}
+		counted++
+	}
+	switch {
+	case counted == 0:
+		return StatusUnknown
+	case down == counted:
+		return StatusDown
+	case down > 0 || failing > 0:
+		return StatusDegraded
+	}
+	return StatusUp
+}
+
+// Counts returns how many of the group's monitors are down and how many
+// count towards its status.
+func (h GroupHealth) Counts() (down, counted int) {
+	for _, s := range h.Monitors {
+		switch s {
+		case StatusDown:
+			down++
+			counted++
+		case StatusDegraded, StatusUp:
+			counted++
+		}
+	}
+	return down, counted
+}
+
+// AlertActive reports whether a group in group alert mode should have an
+// open alert: any monitor down, or every monitor down when AlertWhen is
+// "all".
+func (h GroupHealth) AlertActive() bool {
+	down, counted := h.Counts()
+	if h.AlertWhen == GroupAlertWhenAll {
+		return counted > 0 && down == counted
+	}
+	return down > 0
+}
+
+// monitorStatus derives a monitor's health from its Redis status and
+// incident hashes: down once the failure threshold opened an incident,
+// degraded while checks fail below it.
+func monitorStatus(enabled bool, status, incident map[string]string) string {
+	switch {
+	case !enabled:
+		return StatusPaused
+	case incident["alerted"] == "true" || incident["db_incident"] == "true":
+		return StatusDown
+	case len(incident) > 0:
+		return StatusDegraded
+	case len(status) > 0:
+		return StatusUp
+	}
+	return StatusUnknown
+}
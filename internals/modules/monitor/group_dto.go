@@ -0,0 +1,44 @@
+package monitor
+
+// GroupRequest creates a monitor group or replaces its settings. A null
+// parent_id makes it a top-level group.
+type GroupRequest struct {
+	ParentID    *string  `json:"parent_id"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	AlertMode   string   `json:"alert_mode"`
+	AlertWhen   string   `json:"alert_when"`
+	Channels    []string `json:"channels"`
+}
+
+type AddGroupMembersRequest struct {
+	MonitorIDs []string `json:"monitor_ids" validate:"required,min=1"`
+}
+
+type AddGroupMembersResponse struct {
+	Added int64 `json:"added"`
+}
+
+type GroupMemberResponse struct {
+	MonitorID string `json:"monitor_id"`
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	Status    string `json:"status"`
+}
+
+type GroupResponse struct {
+	ID           string                `json:"id"`
+	ParentID     *string               `json:"parent_id"`
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	AlertMode    string                `json:"alert_mode"`
+	AlertWhen    string                `json:"alert_when"`
+	Channels     []string              `json:"channels"`
+	Status       string                `json:"status"`
+	MonitorCount int                   `json:"monitor_count"`
+	DownCount    int                   `json:"down_count"`
+	Members      []GroupMemberResponse `json:"members"`
+	Subgroups    []string              `json:"subgroups"`
+	CreatedAt    string                `json:"created_at"`
+	UpdatedAt    string                `json:"updated_at"`
+}
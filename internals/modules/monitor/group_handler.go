@@ -0,0 +1,305 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) ListGroups(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.list_groups"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groups, err := h.service.ListGroups(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("list monitor groups error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	out := make([]GroupResponse, 0, len(groups))
+	for i := range groups {
+		out = append(out, toGroupResponse(groups[i]))
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor groups retrieved", out)
+}
+
+func (h *Handler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.get_group"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid group id")
+		return
+	}
+
+	g, err := h.service.GetGroup(ctx, tm.TeamID, groupID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("get monitor group error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor group retrieved", toGroupResponse(g))
+}
+
+func (h *Handler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.create_group"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	in, ok := h.decodeGroup(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	g, err := h.service.CreateGroup(ctx, tm.TeamID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("create monitor group error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+	middle.SetAuditTarget(ctx, g.ID.String())
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "monitor group created", toGroupResponse(GroupHealth{
+		Group:     g,
+		Members:   []GroupMember{},
+		Subgroups: []uuid.UUID{},
+	}))
+}
+
+func (h *Handler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.update_group"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid group id")
+		return
+	}
+
+	in, ok := h.decodeGroup(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	if _, err := h.service.UpdateGroup(ctx, tm.TeamID, groupID, in); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("update monitor group error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	g, err := h.service.GetGroup(ctx, tm.TeamID, groupID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("get monitor group error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor group updated", toGroupResponse(g))
+}
+
+func (h *Handler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.delete_group"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid group id")
+		return
+	}
+
+	if err := h.service.DeleteGroup(ctx, tm.TeamID, groupID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("delete monitor group error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor group deleted", "ok")
+}
+
+func (h *Handler) AddGroupMembers(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.add_group_members"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid group id")
+		return
+	}
+
+	var req AddGroupMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	ids := make([]uuid.UUID, 0, len(req.MonitorIDs))
+	for _, raw := range req.MonitorIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid monitor id")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	added, err := h.service.AddGroupMembers(ctx, tm.TeamID, groupID, ids)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("add monitor group members error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitors added to group", AddGroupMembersResponse{Added: added})
+}
+
+func (h *Handler) RemoveGroupMember(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.remove_group_member"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	groupID, err := uuid.Parse(chi.URLParam(r, "groupID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid group id")
+		return
+	}
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid monitor id")
+		return
+	}
+
+	if err := h.service.RemoveGroupMember(ctx, tm.TeamID, groupID, monitorID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("remove monitor group member error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor removed from group", "ok")
+}
+
+// decodeGroup reads a GroupRequest, writing the error response itself when
+// the body is invalid.
+func (h *Handler) decodeGroup(w http.ResponseWriter, r *http.Request, reqID string) (GroupInput, bool) {
+	var req GroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return GroupInput{}, false
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return GroupInput{}, false
+	}
+	parentID, err := parseOptionalID(req.ParentID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid parent_id")
+		return GroupInput{}, false
+	}
+
+	return GroupInput{
+		ParentID:    parentID,
+		Name:        req.Name,
+		Description: req.Description,
+		AlertMode:   req.AlertMode,
+		AlertWhen:   req.AlertWhen,
+		Channels:    req.Channels,
+	}, true
+}
+
+func toGroupResponse(g GroupHealth) GroupResponse {
+	down, counted := g.Counts()
+
+	members := make([]GroupMemberResponse, 0, len(g.Members))
+	for _, m := range g.Members {
+		members = append(members, GroupMemberResponse{
+			MonitorID: m.MonitorID.String(),
+			Name:      m.Name,
+			Url:       m.Url,
+			Status:    m.Status,
+		})
+	}
+	subgroups := make([]string, 0, len(g.Subgroups))
+	for _, id := range g.Subgroups {
+		subgroups = append(subgroups, id.String())
+	}
+	channels := g.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+
+	return GroupResponse{
+		ID:           g.ID.String(),
+		ParentID:     uuidString(g.ParentID),
+		Name:         g.Name,
+		Description:  g.Description,
+		AlertMode:    g.AlertMode,
+		AlertWhen:    g.AlertWhen,
+		Channels:     channels,
+		Status:       g.Status(),
+		MonitorCount: counted,
+		DownCount:    down,
+		Members:      members,
+		Subgroups:    subgroups,
+		CreatedAt:    g.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    g.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
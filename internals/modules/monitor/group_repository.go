@@ -0,0 +1,180 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (r *Repository) CreateGroup(ctx context.Context, teamID uuid.UUID, in GroupInput) (Group, error) {
+	const op string = "repo.monitor.create_group"
+
+	row, err := r.querier.CreateMonitorGroup(ctx, db.CreateMonitorGroupParams{
+		TeamID:      utils.ToPgUUID(teamID),
+		ParentID:    toPgUUIDPtr(in.ParentID),
+		Name:        in.Name,
+		Description: in.Description,
+		AlertMode:   in.AlertMode,
+		AlertWhen:   in.AlertWhen,
+		Channels:    in.Channels,
+	})
+	if err != nil {
+		return Group{}, r.writeGroupError(op, err)
+	}
+	return toGroup(row), nil
+}
+
+func (r *Repository) GetGroup(ctx context.Context, teamID, groupID uuid.UUID) (Group, error) {
+	const op string = "repo.monitor.get_group"
+
+	row, err := r.querier.GetMonitorGroup(ctx, db.GetMonitorGroupParams{
+		ID:     utils.ToPgUUID(groupID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Group{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor group not found"}
+		}
+		return Group{}, utils.WrapRepoError(op, err, r.log)
+	}
+	return toGroup(row), nil
+}
+
+func (r *Repository) ListGroups(ctx context.Context, teamID uuid.UUID) ([]Group, error) {
+	const op string = "repo.monitor.list_groups"
+
+	rows, err := r.querier.ListMonitorGroups(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.log)
+	}
+	groups := make([]Group, 0, len(rows))
+	for _, row := range rows {
+		groups = append(groups, toGroup(row))
+	}
+	return groups, nil
+}
+
+func (r *Repository) UpdateGroup(ctx context.Context, teamID, groupID uuid.UUID, in GroupInput) (Group, error) {
+	const op string = "repo.monitor.update_group"
+
+	row, err := r.querier.UpdateMonitorGroup(ctx, db.UpdateMonitorGroupParams{
+		ID:          utils.ToPgUUID(groupID),
+		TeamID:      utils.ToPgUUID(teamID),
+		ParentID:    toPgUUIDPtr(in.ParentID),
+		Name:        in.Name,
+		Description: in.Description,
+		AlertMode:   in.AlertMode,
+		AlertWhen:   in.AlertWhen,
+		Channels:    in.Channels,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Group{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor group not found"}
+		}
+		return Group{}, r.writeGroupError(op, err)
+	}
+	return toGroup(row), nil
+}
+
+func (r *Repository) DeleteGroup(ctx context.Context, teamID, groupID uuid.UUID) error {
+	const op string = "repo.monitor.delete_group"
+
+	rows, err := r.querier.DeleteMonitorGroup(ctx, db.DeleteMonitorGroupParams{
+		ID:     utils.ToPgUUID(groupID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor group not found"}
+	}
+	return nil
+}
+
+// ListGroupMembers returns the direct members of every group of the team.
+func (r *Repository) ListGroupMembers(ctx context.Context, teamID uuid.UUID) ([]GroupMember, error) {
+	const op string = "repo.monitor.list_group_members"
+
+	rows, err := r.querier.ListMonitorGroupMembers(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.log)
+	}
+	members := make([]GroupMember, 0, len(rows))
+	for _, row := range rows {
+		members = append(members, GroupMember{
+			GroupID:   utils.FromPgUUID(row.GroupID),
+			MonitorID: utils.FromPgUUID(row.MonitorID),
+			Url:       row.Url,
+			Name:      row.Name,
+			Enabled:   row.Enabled,
+		})
+	}
+	return members, nil
+}
+
+// AddGroupMembers adds the team's monitors among monitorIDs to the group
+// and returns how many were added.
+func (r *Repository) AddGroupMembers(ctx context.Context, teamID, groupID uuid.UUID, monitorIDs []uuid.UUID) (int64, error) {
+	const op string = "repo.monitor.add_group_members"
+
+	ids := make([]pgtype.UUID, 0, len(monitorIDs))
+	for _, id := range monitorIDs {
+		ids = append(ids, utils.ToPgUUID(id))
+	}
+	n, err := r.querier.AddMonitorGroupMembers(ctx, db.AddMonitorGroupMembersParams{
+		Column1: utils.ToPgUUID(groupID),
+		TeamID:  utils.ToPgUUID(teamID),
+		Column3: ids,
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, r.log)
+	}
+	return n, nil
+}
+
+func (r *Repository) RemoveGroupMember(ctx context.Context, groupID, monitorID uuid.UUID) error {
+	const op string = "repo.monitor.remove_group_member"
+
+	rows, err := r.querier.RemoveMonitorGroupMember(ctx, db.RemoveMonitorGroupMemberParams{
+		GroupID:   utils.ToPgUUID(groupID),
+		MonitorID: utils.ToPgUUID(monitorID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor is not in this group"}
+	}
+	return nil
+}
+
+func (r *Repository) writeGroupError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return &apperror.Error{Kind: apperror.AlreadyExists, Op: op, Message: "a monitor group with this name already exists"}
+	}
+	return utils.WrapRepoError(op, err, r.log)
+}
+
+func toGroup(row db.MonitorGroup) Group {
+	return Group{
+		ID:          utils.FromPgUUID(row.ID),
+		TeamID:      utils.FromPgUUID(row.TeamID),
+		ParentID:    fromPgUUIDPtr(row.ParentID),
+		Name:        row.Name,
+		Description: row.Description,
+		AlertMode:   row.AlertMode,
+		AlertWhen:   row.AlertWhen,
+		Channels:    row.Channels,
+		CreatedAt:   utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:   utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+}
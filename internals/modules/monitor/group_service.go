@@ -0,0 +1,330 @@
+package monitor
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// CreateGroup adds a monitor group to the team, optionally nested under an
+// existing group.
+func (s *Service) CreateGroup(ctx context.Context, teamID uuid.UUID, in GroupInput) (Group, error) {
+	const op = "service.monitor.create_group"
+
+	in, err := checkGroupInput(op, in)
+	if err != nil {
+		return Group{}, err
+	}
+	if in.ParentID != nil {
+		groups, err := s.monitorRepo.ListGroups(ctx, teamID)
+		if err != nil {
+			return Group{}, err
+		}
+		if err := checkGroupParent(op, groups, uuid.Nil, *in.ParentID); err != nil {
+			return Group{}, err
+		}
+	}
+
+	return s.monitorRepo.CreateGroup(ctx, teamID, in)
+}
+
+// UpdateGroup replaces a group's settings. Leaving group alert mode closes
+// the group's open alert without a RECOVERED notification.
+func (s *Service) UpdateGroup(ctx context.Context, teamID, groupID uuid.UUID, in GroupInput) (Group, error) {
+	const op = "service.monitor.update_group"
+
+	in, err := checkGroupInput(op, in)
+	if err != nil {
+		return Group{}, err
+	}
+	if in.ParentID != nil {
+		groups, err := s.monitorRepo.ListGroups(ctx, teamID)
+		if err != nil {
+			return Group{}, err
+		}
+		if err := checkGroupParent(op, groups, groupID, *in.ParentID); err != nil {
+			return Group{}, err
+		}
+	}
+
+	g, err := s.monitorRepo.UpdateGroup(ctx, teamID, groupID, in)
+	if err != nil {
+		return Group{}, err
+	}
+	if g.AlertMode != GroupAlertGroup {
+		s.closeGroupAlert(ctx, op, groupID)
+	}
+	return g, nil
+}
+
+// DeleteGroup removes a group. Its subgroups move up to the top level and
+// its monitors are left untouched.
+func (s *Service) DeleteGroup(ctx context.Context, teamID, groupID uuid.UUID) error {
+	const op = "service.monitor.delete_group"
+
+	if err := s.monitorRepo.DeleteGroup(ctx, teamID, groupID); err != nil {
+		return err
+	}
+	s.closeGroupAlert(ctx, op, groupID)
+	return nil
+}
+
+// ListGroups returns the team's groups with their current health.
+func (s *Service) ListGroups(ctx context.Context, teamID uuid.UUID) ([]GroupHealth, error) {
+	return s.groupHealth(ctx, teamID)
+}
+
+// GetGroup returns one group with its current health.
+func (s *Service) GetGroup(ctx context.Context, teamID, groupID uuid.UUID) (GroupHealth, error) {
+	const op = "service.monitor.get_group"
+
+	all, err := s.groupHealth(ctx, teamID)
+	if err != nil {
+		return GroupHealth{}, err
+	}
+	for _, h := range all {
+		if h.ID == groupID {
+			return h, nil
+		}
+	}
+	return GroupHealth{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor group not found"}
+}
+
+// AddGroupMembers adds monitors to a group. Monitors already in the group
+// are skipped; IDs that aren't the team's monitors are rejected.
+func (s *Service) AddGroupMembers(ctx context.Context, teamID, groupID uuid.UUID, monitorIDs []uuid.UUID) (int64, error) {
+	const op = "service.monitor.add_group_members"
+
+	if len(monitorIDs) == 0 || len(monitorIDs) > maxGroupMembersEdit {
+		return 0, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "monitor_ids must hold between 1 and 100 monitors"}
+	}
+	if _, err := s.monitorRepo.GetGroup(ctx, teamID, groupID); err != nil {
+		return 0, err
+	}
+	for _, id := range monitorIDs {
+		if _, err := s.monitorRepo.Get(ctx, teamID, id); err != nil {
+			if apperror.IsKind(err, apperror.NotFound) {
+				return 0, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "monitor " + id.String() + " not found"}
+			}
+			return 0, err
+		}
+	}
+
+	return s.monitorRepo.AddGroupMembers(ctx, teamID, groupID, monitorIDs)
+}
+
+func (s *Service) RemoveGroupMember(ctx context.Context, teamID, groupID, monitorID uuid.UUID) error {
+	if _, err := s.monitorRepo.GetGroup(ctx, teamID, groupID); err != nil {
+		return err
+	}
+	return s.monitorRepo.RemoveGroupMember(ctx, groupID, monitorID)
+}
+
+// AlertGroups returns the groups in group alert mode that alert for the
+// monitor instead of the monitor itself. With nested groups only the
+// outermost group in group alert mode alerts.
+func (s *Service) AlertGroups(ctx context.Context, teamID, monitorID uuid.UUID) ([]GroupHealth, error) {
+	all, err := s.groupHealth(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]GroupHealth, len(all))
+	for _, h := range all {
+		byID[h.ID] = h
+	}
+
+	var out []GroupHealth
+	for _, h := range all {
+		if h.AlertMode != GroupAlertGroup {
+			continue
+		}
+		if _, ok := h.Monitors[monitorID]; !ok {
+			continue
+		}
+		if hasAlertingAncestor(byID, h) {
+			continue
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+func hasAlertingAncestor(byID map[uuid.UUID]GroupHealth, h GroupHealth) bool {
+	seen := map[uuid.UUID]bool{h.ID: true}
+	for p := h.ParentID; p != nil && !seen[*p]; {
+		parent, ok := byID[*p]
+		if !ok {
+			return false
+		}
+		if parent.AlertMode == GroupAlertGroup {
+			return true
+		}
+		seen[*p] = true
+		p = parent.ParentID
+	}
+	return false
+}
+
+// groupHealth loads the team's groups and derives every member monitor's
+// status from Redis, each monitor being looked up once.
+func (s *Service) groupHealth(ctx context.Context, teamID uuid.UUID) ([]GroupHealth, error) {
+	const op = "service.monitor.group_health"
+
+	groups, err := s.monitorRepo.ListGroups(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return []GroupHealth{}, nil
+	}
+	members, err := s.monitorRepo.ListGroupMembers(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[uuid.UUID]string)
+	direct := make(map[uuid.UUID][]GroupMember, len(groups))
+	for _, m := range members {
+		st, ok := statuses[m.MonitorID]
+		if !ok {
+			st = s.monitorStatus(ctx, op, m.MonitorID, m.Enabled)
+			statuses[m.MonitorID] = st
+		}
+		m.Status = st
+		direct[m.GroupID] = append(direct[m.GroupID], m)
+	}
+
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for _, g := range groups {
+		if g.ParentID != nil {
+			children[*g.ParentID] = append(children[*g.ParentID], g.ID)
+		}
+	}
+
+	out := make([]GroupHealth, 0, len(groups))
+	for _, g := range groups {
+		h := GroupHealth{
+			Group:     g,
+			Members:   direct[g.ID],
+			Subgroups: children[g.ID],
+			Monitors:  make(map[uuid.UUID]string),
+		}
+		if h.Members == nil {
+			h.Members = []GroupMember{}
+		}
+		if h.Subgroups == nil {
+			h.Subgroups = []uuid.UUID{}
+		}
+
+		seen := make(map[uuid.UUID]bool)
+		stack := []uuid.UUID{g.ID}
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			for _, m := range direct[id] {
+				h.Monitors[m.MonitorID] = m.Status
+			}
+			stack = append(stack, children[id]...)
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// monitorStatus reads a monitor's health from Redis. A lookup error leaves
+// it unknown rather than failing the whole group.
+func (s *Service) monitorStatus(ctx context.Context, op string, monitorID uuid.UUID, enabled bool) string {
+	if !enabled {
+		return StatusPaused
+	}
+	status, err := s.cache.GetStatus(ctx, monitorID)
+	if err != nil {
+		s.logger.Warn().Str("op", op).Err(err).Str("monitor_id", monitorID.String()).Msg("failed to read monitor status")
+		return StatusUnknown
+	}
+	incident, err := s.cache.GetIncident(ctx, monitorID)
+	if err != nil {
+		s.logger.Warn().Str("op", op).Err(err).Str("monitor_id", monitorID.String()).Msg("failed to read monitor incident state")
+		return StatusUnknown
+	}
+	return monitorStatus(enabled, status, incident)
+}
+
+func (s *Service) closeGroupAlert(ctx context.Context, op string, groupID uuid.UUID) {
+	if _, err := s.cache.CloseGroupAlert(ctx, groupID); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("group_id", groupID.String()).Msg("failed to close group alert")
+	}
+}
+
+// checkGroupInput trims and validates a group, filling in the default alert
+// settings.
+func checkGroupInput(op string, in GroupInput) (GroupInput, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+	if in.Name == "" || len(in.Name) > maxGroupNameLen {
+		return in, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "name is required and can be at most 100 characters"}
+	}
+	if len(in.Description) > maxGroupDescLen {
+		return in, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "description can be at most 2000 characters"}
+	}
+
+	if in.AlertMode == "" {
+		in.AlertMode = GroupAlertMembers
+	}
+	if in.AlertMode != GroupAlertMembers && in.AlertMode != GroupAlertGroup {
+		return in, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "alert_mode must be members or group"}
+	}
+	if in.AlertWhen == "" {
+		in.AlertWhen = GroupAlertWhenAny
+	}
+	if in.AlertWhen != GroupAlertWhenAny && in.AlertWhen != GroupAlertWhenAll {
+		return in, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "alert_when must be any or all"}
+	}
+
+	channels := make([]string, 0, len(in.Channels))
+	for _, c := range in.Channels {
+		c = strings.TrimSpace(c)
+		if c == "" || slices.Contains(channels, c) {
+			continue
+		}
+		channels = append(channels, c)
+	}
+	if len(channels) > maxGroupChannels {
+		return in, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "a group can have at most 20 channels"}
+	}
+	in.Channels = channels
+	return in, nil
+}
+
+// checkGroupParent makes sure parentID is one of the team's groups, that
+// nesting groupID under it doesn't create a cycle and that the parent isn't
+// already nested too deep.
+func checkGroupParent(op string, groups []Group, groupID, parentID uuid.UUID) error {
+	byID := make(map[uuid.UUID]Group, len(groups))
+	for _, g := range groups {
+		byID[g.ID] = g
+	}
+	if _, ok := byID[parentID]; !ok {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "parent group not found"}
+	}
+
+	depth := 1
+	for id := &parentID; id != nil; id = byID[*id].ParentID {
+		if *id == groupID {
+			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "a group cannot be nested inside itself"}
+		}
+		depth++
+		if depth > maxGroupDepth {
+			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "groups can be nested at most 5 levels deep"}
+		}
+	}
+	return nil
+}
@@ -2,8 +2,10 @@ package monitor
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	middle "github.com/alkush-pipania/sofon/internals/middleware"
 	"github.com/alkush-pipania/sofon/pkg/apperror"
@@ -61,6 +63,12 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	policyID, err := parseOptionalID(req.EscalationPolicyID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid escalation_policy_id")
+		return
+	}
+
 	mID, err := h.service.CreateMonitor(ctx, CreateMonitor{
 		TeamID:               tm.TeamID,
 		UserID:               userID,
@@ -70,6 +78,17 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		LatencyThresholdMs:   req.LatencyThresholdMs,
 		ExpectedStatus:       req.ExpectedStatus,
 		NotificationChannels: req.NotificationChannels,
+		EscalationPolicyID:   policyID,
+		ReminderIntervalSec:  req.ReminderIntervalSec,
+		ReminderMax:          req.ReminderMax,
+		Tags:                 req.Tags,
+		Severity:             req.Severity,
+		Name:                 req.Name,
+		Description:          req.Description,
+		RunbookURL:           req.RunbookURL,
+		Slug:                 req.Slug,
+		Keyword:              req.Keyword,
+		KeywordAbsent:        req.KeywordAbsent,
 	})
 	if err != nil {
 		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("create monitor error")
@@ -77,6 +96,7 @@ func (h *Handler) CreateMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	middle.SetAuditTarget(ctx, mID.String())
 	utils.WriteJSON(w, http.StatusCreated, reqID, "monitor created successfully", CreateMonitorResponse{MonitorID: mID.String()})
 }
 
@@ -104,6 +124,7 @@ func (h *Handler) GetMonitor(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	now := time.Now()
 	utils.WriteJSON(w, http.StatusOK, reqID, "monitor retrieved", GetMonitorResponse{
 		ID:                   mon.ID.String(),
 		Url:                  mon.Url,
@@ -113,6 +134,20 @@ func (h *Handler) GetMonitor(w http.ResponseWriter, r *http.Request) {
 		ExpectedStatus:       mon.ExpectedStatus,
 		Enabled:              mon.Enabled,
 		NotificationChannels: mon.NotificationChannels,
+		EscalationPolicyID:   uuidString(mon.EscalationPolicyID),
+		ReminderIntervalSec:  mon.ReminderIntervalSec,
+		ReminderMax:          mon.ReminderMax,
+		Tags:                 mon.Tags,
+		Severity:             mon.Severity,
+		Name:                 mon.Name,
+		Description:          mon.Description,
+		RunbookURL:           mon.RunbookURL,
+		Slug:                 mon.Slug,
+		Keyword:              mon.Keyword,
+		KeywordAbsent:        mon.KeywordAbsent,
+		Snoozed:              mon.Snoozed(now),
+		SnoozedUntil:         mon.SnoozedUntil,
+		SnoozeNotify:         mon.SnoozeNotify,
 	})
 }
 
@@ -147,9 +182,13 @@ func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 		cursor = decoded
 	}
 
+	query := r.URL.Query()
 	page, err := h.service.GetAllMonitors(ctx, tm.TeamID, ListMonitorsOptions{
 		Limit:  limit,
 		Cursor: cursor,
+		Query:  query.Get("q"),
+		Tags:   query["tag"],
+		Sort:   query.Get("sort"),
 	})
 	if err != nil {
 		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("retrieving all monitors error")
@@ -158,6 +197,7 @@ func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 	}
 
 	m := make([]GetMonitorResponse, 0, len(page.Monitors))
+	now := time.Now()
 	for i := range page.Monitors {
 		mon := &page.Monitors[i]
 		m = append(m, GetMonitorResponse{
@@ -170,6 +210,20 @@ func (h *Handler) GetAllMonitors(w http.ResponseWriter, r *http.Request) {
 			Enabled:              mon.Enabled,
 			IsDown:               mon.IsDown,
 			NotificationChannels: mon.NotificationChannels,
+			EscalationPolicyID:   uuidString(mon.EscalationPolicyID),
+			ReminderIntervalSec:  mon.ReminderIntervalSec,
+			ReminderMax:          mon.ReminderMax,
+			Tags:                 mon.Tags,
+			Severity:             mon.Severity,
+			Name:                 mon.Name,
+			Description:          mon.Description,
+			RunbookURL:           mon.RunbookURL,
+			Slug:                 mon.Slug,
+			Keyword:              mon.Keyword,
+			KeywordAbsent:        mon.KeywordAbsent,
+			Snoozed:              mon.Snoozed(now),
+			SnoozedUntil:         mon.SnoozedUntil,
+			SnoozeNotify:         mon.SnoozeNotify,
 		})
 	}
 
@@ -207,8 +261,8 @@ func (h *Handler) DeleteMonitor(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, reqID, "monitor deleted successfully", "")
 }
 
-func (h *Handler) UpdateMonitorStatus(w http.ResponseWriter, r *http.Request) {
-	const op string = "handler.monitor.update_monitor_status"
+func (h *Handler) UpdateMonitor(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.update_monitor"
 	ctx := r.Context()
 	reqID := middleware.GetReqID(ctx)
 
@@ -224,7 +278,7 @@ func (h *Handler) UpdateMonitorStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req UpdateMonitorStatusRequest
+	var req UpdateMonitorRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
 		return
@@ -234,11 +288,90 @@ func (h *Handler) UpdateMonitorStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := h.service.UpdateMonitorStatus(ctx, tm.TeamID, monitorID, *req.Enable); err != nil {
-		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("updating monitor status error")
+	upd := MonitorUpdate{
+		Enabled:             req.Enable,
+		Name:                req.Name,
+		Description:         req.Description,
+		RunbookURL:          req.RunbookURL,
+		Tags:                req.Tags,
+		Severity:            req.Severity,
+		ReminderIntervalSec: req.ReminderIntervalSec,
+		ReminderMax:         req.ReminderMax,
+	}
+	if req.EscalationPolicyID != nil {
+		policyID, err := parseOptionalID(req.EscalationPolicyID)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid escalation_policy_id")
+			return
+		}
+		upd.EscalationPolicyID = &uuid.Nil
+		if policyID != nil {
+			upd.EscalationPolicyID = policyID
+		}
+	}
+	if req.Snooze != nil {
+		until, err := snoozeUntil(*req.Snooze)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, err.Error())
+			return
+		}
+		upd.SnoozeUntil = &until
+		upd.SnoozeNotify = req.Snooze.Notify
+	}
+	if upd == (MonitorUpdate{}) {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "nothing to update")
+		return
+	}
+
+	if upd.Enabled != nil && onlyStatus(upd) {
+		if *upd.Enabled {
+			middle.SetAuditAction(ctx, "monitors.enable")
+		} else {
+			middle.SetAuditAction(ctx, "monitors.disable")
+		}
+	}
+	if err := h.service.UpdateMonitor(ctx, tm.TeamID, monitorID, upd); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("updating monitor error")
 		utils.FromAppError(w, reqID, err)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, reqID, "monitor status updated successfully", "ok")
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor updated successfully", "ok")
+}
+
+// snoozeUntil returns when the requested snooze ends, or the zero time when
+// the request ends the snooze.
+func snoozeUntil(req SnoozeRequest) (time.Time, error) {
+	switch {
+	case req.Until != nil && req.Duration != "":
+		return time.Time{}, errors.New("set either snooze.until or snooze.duration, not both")
+	case req.Until != nil:
+		return *req.Until, nil
+	case req.Duration != "":
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 {
+			return time.Time{}, errors.New("invalid snooze.duration")
+		}
+		return time.Now().Add(d), nil
+	}
+	return time.Time{}, nil
+}
+
+func parseOptionalID(raw *string) (*uuid.UUID, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
 }
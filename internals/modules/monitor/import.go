@@ -0,0 +1,177 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Import sources.
+const (
+	ImportUptimeKuma  = "uptime-kuma"
+	ImportUptimeRobot = "uptimerobot"
+)
+
+// Sofon's lower bounds, which imported checks are raised to.
+const (
+	minIntervalSec = 60
+	minTimeoutSec  = 120
+)
+
+// maxImportMonitors caps the monitors read from a single export.
+const maxImportMonitors = 1000
+
+// ImportedMonitor is one monitor of an export mapped onto Sofon's fields.
+// Warnings list what couldn't be carried over; Skipped is set when the
+// monitor can't be imported at all.
+type ImportedMonitor struct {
+	// SourceName is the monitor's name in the export.
+	SourceName string
+	Monitor    ManifestMonitor
+	Warnings   []string
+	Skipped    string
+	// MonitorID is set once the monitor is created, Error when creating
+	// it failed.
+	MonitorID uuid.UUID
+	Error     string
+}
+
+// ImportOptions control an import. Without Commit it is only a preview.
+type ImportOptions struct {
+	Commit bool
+	// Channels are set as the notification channels of every imported
+	// monitor, since the export's notification settings don't carry over.
+	Channels []string
+}
+
+// ImportResult is the outcome, or preview, of an import.
+type ImportResult struct {
+	Source    string
+	Committed bool
+	Monitors  []ImportedMonitor
+}
+
+func (m *ImportedMonitor) warn(format string, args ...any) {
+	m.Warnings = append(m.Warnings, fmt.Sprintf(format, args...))
+}
+
+// setInterval applies an interval in seconds, raising it to Sofon's
+// minimum.
+func (m *ImportedMonitor) setInterval(sec int) {
+	switch {
+	case sec <= 0:
+		m.Monitor.IntervalSec = minIntervalSec
+	case sec < minIntervalSec:
+		m.warn("interval of %ds raised to the %ds minimum", sec, minIntervalSec)
+		m.Monitor.IntervalSec = minIntervalSec
+	default:
+		m.Monitor.IntervalSec = int32(sec)
+	}
+}
+
+// setTimeout applies a timeout in seconds, raising it to Sofon's minimum.
+func (m *ImportedMonitor) setTimeout(sec int) {
+	switch {
+	case sec <= 0:
+		m.Monitor.TimeoutSec = minTimeoutSec
+	case sec < minTimeoutSec:
+		m.warn("timeout of %ds raised to the %ds minimum", sec, minTimeoutSec)
+		m.Monitor.TimeoutSec = minTimeoutSec
+	default:
+		m.Monitor.TimeoutSec = int32(sec)
+	}
+}
+
+// setStatusCodes maps accepted status codes and ranges ("200", "200-299").
+// Sofon either expects one exact code or counts any 2xx or 3xx as up.
+func (m *ImportedMonitor) setStatusCodes(codes []string) {
+	var exact []int
+	loose := true
+	for _, c := range codes {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(c, "-")
+		from, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		to, err2 := from, error(nil)
+		if isRange {
+			to, err2 = strconv.Atoi(strings.TrimSpace(hi))
+		}
+		if err1 != nil || err2 != nil {
+			m.warn("accepted status code %q is not a code or range, ignored", c)
+			continue
+		}
+		if from == to {
+			exact = append(exact, from)
+		}
+		if from < 200 || to > 399 {
+			loose = false
+		}
+	}
+
+	switch {
+	case len(codes) == 0:
+	case len(exact) == 1 && len(codes) == 1:
+		code := int32(exact[0])
+		m.Monitor.ExpectedStatus = &code
+	case !loose:
+		m.warn("accepted status codes %s can't be mapped; any 2xx or 3xx response counts as up", strings.Join(codes, ", "))
+	case len(exact) > 0:
+		m.warn("accepted status codes %s can't be mapped exactly; any 2xx or 3xx response counts as up", strings.Join(codes, ", "))
+	}
+}
+
+// setTag adds a tag, dropping it with a warning when Sofon can't store it.
+func (m *ImportedMonitor) setTag(name, value string) {
+	tag := strings.ReplaceAll(strings.TrimSpace(name), " ", "-")
+	if v := strings.TrimSpace(value); v != "" {
+		tag += ":" + v
+	}
+	if len(m.Monitor.Tags) >= maxTags {
+		m.warn("tag %q dropped, a monitor can have at most %d tags", tag, maxTags)
+		return
+	}
+	tags, err := checkLabels("", []string{tag}, "")
+	if err != nil || len(tags) == 0 {
+		m.warn("tag %q can't be used as a Sofon tag, dropped", tag)
+		return
+	}
+	m.Monitor.Tags = append(m.Monitor.Tags, tags[0])
+}
+
+// flexBool reads the booleans exports write as true/false, 0/1 or "0"/"1".
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(bytes.Trim(data, `"`)) {
+	case "true", "1":
+		*b = true
+	case "false", "0", "null", "":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+// flexInt reads numbers exports write either as numbers or as strings.
+type flexInt int
+
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	var f float64
+	if err := json.Unmarshal([]byte(s), &f); err != nil {
+		return fmt.Errorf("invalid number %s", data)
+	}
+	*n = flexInt(f)
+	return nil
+}
@@ -0,0 +1,20 @@
+package monitor
+
+type ImportedMonitorResponse struct {
+	SourceName string          `json:"source_name"`
+	Monitor    ManifestMonitor `json:"monitor"`
+	Warnings   []string        `json:"warnings"`
+	Skipped    string          `json:"skipped,omitempty"`
+	MonitorID  *string         `json:"monitor_id"`
+	Error      string          `json:"error,omitempty"`
+}
+
+type ImportResponse struct {
+	Source     string                    `json:"source"`
+	Committed  bool                      `json:"committed"`
+	Importable int                       `json:"importable"`
+	Created    int                       `json:"created"`
+	Skipped    int                       `json:"skipped"`
+	Failed     int                       `json:"failed"`
+	Monitors   []ImportedMonitorResponse `json:"monitors"`
+}
@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// maxImportBytes caps the size of an uploaded export.
+const maxImportBytes = 5 << 20
+
+// Import reads an Uptime Kuma backup or UptimeRobot export from the body,
+// ?source=uptime-kuma|uptimerobot, and previews the monitors it maps to.
+// With ?commit=true they are created. ?channels=a,b sets the notification
+// channels of every imported monitor.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.import"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	claims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	q := r.URL.Query()
+	opts := ImportOptions{Commit: q.Get("commit") == "true"}
+	if !opts.Commit {
+		middle.SkipAudit(ctx)
+	}
+	if c := q.Get("channels"); c != "" {
+		opts.Channels = strings.Split(c, ",")
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, reqID, apperror.InvalidInput, "export is larger than 5 MB")
+			return
+		}
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request")
+		return
+	}
+	if len(data) == 0 {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "export is empty")
+		return
+	}
+
+	res, err := h.service.Import(ctx, tm.TeamID, userID, q.Get("source"), data, opts)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("import monitors error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	msg := "monitors imported"
+	if !opts.Commit {
+		msg = "preview, nothing imported"
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, msg, toImportResponse(res))
+}
+
+func toImportResponse(res ImportResult) ImportResponse {
+	out := ImportResponse{
+		Source:    res.Source,
+		Committed: res.Committed,
+		Monitors:  make([]ImportedMonitorResponse, 0, len(res.Monitors)),
+	}
+	for _, im := range res.Monitors {
+		switch {
+		case im.Skipped != "":
+			out.Skipped++
+		case im.Error != "":
+			out.Failed++
+		default:
+			out.Importable++
+			if im.MonitorID != uuid.Nil {
+				out.Created++
+			}
+		}
+
+		item := ImportedMonitorResponse{
+			SourceName: im.SourceName,
+			Monitor:    im.Monitor,
+			Warnings:   im.Warnings,
+			Skipped:    im.Skipped,
+			Error:      im.Error,
+		}
+		if item.Warnings == nil {
+			item.Warnings = []string{}
+		}
+		if im.MonitorID != uuid.Nil {
+			id := im.MonitorID.String()
+			item.MonitorID = &id
+		}
+		out.Monitors = append(out.Monitors, item)
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// kumaBackup is the JSON backup Uptime Kuma exports from its settings.
+type kumaBackup struct {
+	Version     string        `json:"version"`
+	MonitorList []kumaMonitor `json:"monitorList"`
+}
+
+type kumaMonitor struct {
+	Name                string          `json:"name"`
+	Description         string          `json:"description"`
+	Type                string          `json:"type"`
+	URL                 string          `json:"url"`
+	Method              string          `json:"method"`
+	Interval            flexInt         `json:"interval"`
+	Timeout             flexInt         `json:"timeout"`
+	Active              *flexBool       `json:"active"`
+	Keyword             string          `json:"keyword"`
+	InvertKeyword       flexBool        `json:"invertKeyword"`
+	UpsideDown          flexBool        `json:"upsideDown"`
+	IgnoreTLS           flexBool        `json:"ignoreTls"`
+	AcceptedStatusCodes []string        `json:"accepted_statuscodes"`
+	Headers             string          `json:"headers"`
+	Body                string          `json:"body"`
+	BasicAuthUser       string          `json:"basic_auth_user"`
+	NotificationIDList  map[string]bool `json:"notificationIDList"`
+	Tags                []kumaTag       `json:"tags"`
+}
+
+type kumaTag struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// parseUptimeKuma maps the monitors of an Uptime Kuma backup. Only HTTP and
+// keyword monitors have a Sofon equivalent.
+func parseUptimeKuma(data []byte) ([]ImportedMonitor, error) {
+	var backup kumaBackup
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, errors.New("not an Uptime Kuma backup: " + err.Error())
+	}
+	if backup.MonitorList == nil {
+		return nil, errors.New("not an Uptime Kuma backup: monitorList is missing")
+	}
+
+	out := make([]ImportedMonitor, 0, len(backup.MonitorList))
+	for _, km := range backup.MonitorList {
+		im := ImportedMonitor{
+			SourceName: km.Name,
+			Monitor: ManifestMonitor{
+				Name:        strings.TrimSpace(km.Name),
+				Description: strings.TrimSpace(km.Description),
+				Url:         strings.TrimSpace(km.URL),
+			},
+		}
+
+		switch km.Type {
+		case "http":
+		case "keyword":
+			im.Monitor.Keyword = km.Keyword
+			im.Monitor.KeywordAbsent = bool(km.InvertKeyword)
+		case "json-query":
+			im.warn("JSON query checks aren't supported, imported as a plain HTTP check")
+		default:
+			im.Skipped = "Uptime Kuma " + km.Type + " monitors have no Sofon equivalent"
+			out = append(out, im)
+			continue
+		}
+		if km.UpsideDown {
+			im.Skipped = "upside down mode has no Sofon equivalent"
+			out = append(out, im)
+			continue
+		}
+
+		if km.Active != nil && !*km.Active {
+			disabled := false
+			im.Monitor.Enabled = &disabled
+		}
+		im.setInterval(int(km.Interval))
+		im.setTimeout(int(km.Timeout))
+		im.setStatusCodes(km.AcceptedStatusCodes)
+		for _, t := range km.Tags {
+			im.setTag(t.Name, t.Value)
+		}
+
+		if m := strings.ToUpper(km.Method); m != "" && m != "GET" {
+			im.warn("%s requests aren't supported, checked with GET", m)
+		}
+		if km.IgnoreTLS {
+			im.warn("TLS errors are no longer ignored")
+		}
+		if strings.TrimSpace(km.Headers) != "" || strings.TrimSpace(km.Body) != "" {
+			im.warn("custom request headers and body aren't supported, dropped")
+		}
+		if km.BasicAuthUser != "" {
+			im.warn("basic auth isn't supported, dropped")
+		}
+		for _, on := range km.NotificationIDList {
+			if on {
+				im.warn("notifications aren't imported; set channels on the import instead")
+				break
+			}
+		}
+		out = append(out, im)
+	}
+	return out, nil
+}
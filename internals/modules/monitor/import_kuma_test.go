@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUptimeKuma(t *testing.T) {
+	data, err := os.ReadFile("testdata/kuma-backup.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parseUptimeKuma(data)
+	if err != nil {
+		t.Fatalf("parseUptimeKuma: %v", err)
+	}
+
+	want := []ImportedMonitor{
+		{
+			SourceName: "API",
+			Monitor: ManifestMonitor{
+				Name:        "API",
+				Description: "Public API health",
+				Url:         "https://api.example.com/health",
+				IntervalSec: 60,
+				TimeoutSec:  120,
+				Tags:        []string{"env:prod", "team-payments"},
+			},
+			Warnings: []string{
+				"interval of 30s raised to the 60s minimum",
+				"timeout of 48s raised to the 120s minimum",
+				"notifications aren't imported; set channels on the import instead",
+			},
+		},
+		{
+			SourceName: "Homepage",
+			Monitor: ManifestMonitor{
+				Name:           "Homepage",
+				Url:            "https://www.example.com",
+				IntervalSec:    120,
+				TimeoutSec:     130,
+				ExpectedStatus: int32Ptr(200),
+				Keyword:        "Welcome",
+				Enabled:        boolPtr(false),
+			},
+			Warnings: []string{
+				"POST requests aren't supported, checked with GET",
+				"TLS errors are no longer ignored",
+				"custom request headers and body aren't supported, dropped",
+				"basic auth isn't supported, dropped",
+			},
+		},
+		{
+			SourceName: "Postgres",
+			Monitor:    ManifestMonitor{Name: "Postgres"},
+			Skipped:    "Uptime Kuma port monitors have no Sofon equivalent",
+		},
+		{
+			SourceName: "Maintenance page",
+			Monitor:    ManifestMonitor{Name: "Maintenance page", Url: "https://www.example.com/maintenance"},
+			Skipped:    "upside down mode has no Sofon equivalent",
+		},
+		{
+			SourceName: "Status JSON",
+			Monitor: ManifestMonitor{
+				Name:        "Status JSON",
+				Url:         "https://status.example.com/api",
+				IntervalSec: 60,
+				TimeoutSec:  120,
+			},
+			Warnings: []string{
+				"JSON query checks aren't supported, imported as a plain HTTP check",
+				"accepted status codes 200, 201 can't be mapped exactly; any 2xx or 3xx response counts as up",
+				`tag "bad-tag:" can't be used as a Sofon tag, dropped`,
+			},
+		},
+	}
+	compareImported(t, got, want)
+}
+
+func TestParseUptimeKumaRejectsOtherFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "not json", data: "name,url\n", want: "not an Uptime Kuma backup"},
+		{name: "no monitor list", data: `{"version": "1.23.11"}`, want: "monitorList is missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUptimeKuma([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// compareImported reports every monitor of got that differs from want,
+// including its warnings, which make up the unmapped-field report.
+func compareImported(t *testing.T, got, want []ImportedMonitor) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d monitors, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i].Monitor, want[i].Monitor) {
+			t.Errorf("%s: monitor\n got %+v\nwant %+v", want[i].SourceName, got[i].Monitor, want[i].Monitor)
+		}
+		if got[i].SourceName != want[i].SourceName {
+			t.Errorf("monitor %d: source name %q, want %q", i, got[i].SourceName, want[i].SourceName)
+		}
+		if got[i].Skipped != want[i].Skipped {
+			t.Errorf("%s: skipped %q, want %q", want[i].SourceName, got[i].Skipped, want[i].Skipped)
+		}
+		if !reflect.DeepEqual(got[i].Warnings, want[i].Warnings) {
+			t.Errorf("%s: warnings\n got %q\nwant %q", want[i].SourceName, got[i].Warnings, want[i].Warnings)
+		}
+	}
+}
+
+func int32Ptr(n int32) *int32 { return &n }
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// Import maps the monitors of an Uptime Kuma or UptimeRobot export onto
+// Sofon monitors. Without opts.Commit nothing is created and the result
+// previews what would be. Monitors whose URL the team already checks are
+// skipped, so running an import twice doesn't duplicate them. A monitor
+// that fails to create is reported and the rest are still imported.
+func (s *Service) Import(ctx context.Context, teamID, userID uuid.UUID, source string, data []byte, opts ImportOptions) (ImportResult, error) {
+	const op = "service.monitor.import"
+
+	var (
+		monitors []ImportedMonitor
+		err      error
+	)
+	switch source {
+	case ImportUptimeKuma:
+		monitors, err = parseUptimeKuma(data)
+	case ImportUptimeRobot:
+		monitors, err = parseUptimeRobot(data)
+	default:
+		return ImportResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "source must be one of uptime-kuma, uptimerobot"}
+	}
+	if err != nil {
+		return ImportResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: err.Error()}
+	}
+	if len(monitors) > maxImportMonitors {
+		return ImportResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("an import can hold at most %d monitors", maxImportMonitors)}
+	}
+
+	existing, err := s.monitorRepo.ListAll(ctx, teamID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	slugs := make(map[string]bool, len(existing)+len(monitors))
+	urls := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		if m.Slug != "" {
+			slugs[m.Slug] = true
+		}
+		urls[m.Url] = true
+	}
+
+	res := ImportResult{Source: source, Committed: opts.Commit, Monitors: monitors}
+	entries := make([]*manifestEntry, len(monitors))
+	for i := range res.Monitors {
+		im := &res.Monitors[i]
+		if im.Skipped != "" {
+			continue
+		}
+		if urls[im.Monitor.Url] {
+			im.Skipped = "a monitor for this URL already exists"
+			continue
+		}
+		im.Monitor.Slug = suggestSlug(Monitor{Name: im.Monitor.Name, Url: im.Monitor.Url}, slugs)
+		im.Monitor.NotificationChannels = opts.Channels
+
+		e, err := checkManifestMonitor(op, im.Monitor)
+		if err != nil {
+			im.Skipped = skipReason(err)
+			continue
+		}
+		slugs[e.Slug] = true
+		urls[e.Config.Url] = true
+		entries[i] = &e
+	}
+	if !opts.Commit {
+		return res, nil
+	}
+
+	for i, e := range entries {
+		if e == nil {
+			continue
+		}
+		im := &res.Monitors[i]
+		id, err := s.createFromManifest(ctx, teamID, userID, *e)
+		if err != nil {
+			s.logger.Warn().Str("op", op).Err(err).Str("team_id", teamID.String()).Str("slug", e.Slug).Msg("failed to create imported monitor")
+			im.Error = skipReason(err)
+		}
+		im.MonitorID = id
+	}
+	return res, nil
+}
+
+// skipReason is the message shown for a monitor that couldn't be imported.
+// Only validation and conflict messages are passed on.
+func skipReason(err error) string {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		switch appErr.Kind {
+		case apperror.InvalidInput, apperror.AlreadyExists, apperror.Conflict, apperror.Forbidden:
+			return appErr.Message
+		}
+	}
+	return "internal error"
+}
@@ -0,0 +1,239 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// UptimeRobot monitor types.
+const (
+	robotHTTP      = 1
+	robotKeyword   = 2
+	robotPing      = 3
+	robotPort      = 4
+	robotHeartbeat = 5
+)
+
+// UptimeRobot keyword types. "Exists" alerts when the keyword is on the
+// page, so it maps to keyword_absent.
+const (
+	robotKeywordExists    = 1
+	robotKeywordNotExists = 2
+)
+
+// robotMonitor is a monitor as UptimeRobot's getMonitors API returns it.
+type robotMonitor struct {
+	FriendlyName string  `json:"friendly_name"`
+	URL          string  `json:"url"`
+	Type         flexInt `json:"type"`
+	KeywordType  flexInt `json:"keyword_type"`
+	KeywordValue string  `json:"keyword_value"`
+	Interval     flexInt `json:"interval"`
+	Timeout      flexInt `json:"timeout"`
+	Status       *int    `json:"status"`
+	HTTPMethod   flexInt `json:"http_method"`
+	HTTPUsername string  `json:"http_username"`
+}
+
+// robotHTTPGet is the http_method value of GET requests; HEAD (1) is
+// treated the same, as both only check the response.
+const robotHTTPGet = 2
+
+// parseUptimeRobot maps the monitors of an UptimeRobot export: either the
+// CSV the dashboard exports or the JSON of the getMonitors API.
+func parseUptimeRobot(data []byte) ([]ImportedMonitor, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	if len(data) > 0 && (data[0] == '{' || data[0] == '[') {
+		return parseUptimeRobotJSON(data)
+	}
+	return parseUptimeRobotCSV(data)
+}
+
+func parseUptimeRobotJSON(data []byte) ([]ImportedMonitor, error) {
+	var list []robotMonitor
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, errors.New("not an UptimeRobot export: " + err.Error())
+		}
+	} else {
+		var resp struct {
+			Monitors []robotMonitor `json:"monitors"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return nil, errors.New("not an UptimeRobot export: " + err.Error())
+		}
+		if resp.Monitors == nil {
+			return nil, errors.New("not an UptimeRobot export: monitors is missing")
+		}
+		list = resp.Monitors
+	}
+
+	out := make([]ImportedMonitor, 0, len(list))
+	for _, rm := range list {
+		im := robotImport(rm.FriendlyName, rm.URL, int(rm.Type), int(rm.KeywordType), rm.KeywordValue, rm.Status != nil && *rm.Status == 0)
+		if im.Skipped == "" {
+			im.setInterval(int(rm.Interval))
+			im.setTimeout(int(rm.Timeout))
+			if m := int(rm.HTTPMethod); m > robotHTTPGet {
+				im.warn("only GET requests are supported, checked with GET")
+			}
+			if rm.HTTPUsername != "" {
+				im.warn("HTTP auth isn't supported, dropped")
+			}
+		}
+		out = append(out, im)
+	}
+	return out, nil
+}
+
+// robotColumns maps the CSV headers UptimeRobot has used to the fields they
+// hold.
+var robotColumns = map[string]string{
+	"friendly name":       "name",
+	"name":                "name",
+	"url":                 "url",
+	"url/ip":              "url",
+	"type":                "type",
+	"monitor type":        "type",
+	"keyword type":        "keyword_type",
+	"keyword value":       "keyword",
+	"keyword":             "keyword",
+	"interval":            "interval",
+	"monitoring interval": "interval",
+	"status":              "status",
+}
+
+func parseUptimeRobotCSV(data []byte) ([]ImportedMonitor, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, errors.New("not an UptimeRobot export: the CSV has no header")
+	}
+	cols := make(map[string]int)
+	for i, h := range header {
+		if f, ok := robotColumns[strings.ToLower(strings.TrimSpace(h))]; ok {
+			if _, dup := cols[f]; !dup {
+				cols[f] = i
+			}
+		}
+	}
+	if _, ok := cols["url"]; !ok {
+		return nil, errors.New("not an UptimeRobot export: the CSV has no URL column")
+	}
+
+	var out []ImportedMonitor
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid CSV: " + err.Error())
+		}
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+		if field("name") == "" && field("url") == "" {
+			continue
+		}
+
+		status := strings.ToLower(field("status"))
+		im := robotImport(field("name"), field("url"), robotType(field("type")), robotKeywordType(field("keyword_type")), field("keyword"), status == "paused" || status == "0")
+		if im.Skipped == "" {
+			im.setInterval(robotInterval(field("interval")))
+			im.setTimeout(0)
+		}
+		out = append(out, im)
+	}
+	return out, nil
+}
+
+// robotImport maps the fields both UptimeRobot formats share.
+func robotImport(name, url string, typ, keywordType int, keyword string, paused bool) ImportedMonitor {
+	im := ImportedMonitor{
+		SourceName: name,
+		Monitor:    ManifestMonitor{Name: strings.TrimSpace(name), Url: strings.TrimSpace(url)},
+	}
+	switch typ {
+	case robotHTTP:
+	case robotKeyword:
+		im.Monitor.Keyword = keyword
+		switch keywordType {
+		case robotKeywordExists:
+			im.Monitor.KeywordAbsent = true
+		case robotKeywordNotExists:
+		default:
+			im.warn("unknown keyword type, the keyword is expected on the page")
+		}
+	case robotPing:
+		im.Skipped = "UptimeRobot ping monitors have no Sofon equivalent"
+	case robotPort:
+		im.Skipped = "UptimeRobot port monitors have no Sofon equivalent"
+	case robotHeartbeat:
+		im.Skipped = "UptimeRobot heartbeat monitors have no Sofon equivalent"
+	default:
+		im.Skipped = "unknown UptimeRobot monitor type"
+	}
+	if paused {
+		disabled := false
+		im.Monitor.Enabled = &disabled
+	}
+	return im
+}
+
+// robotType reads a CSV monitor type, written either as a name or as the
+// API's number.
+func robotType(s string) int {
+	s = strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(s, "http"):
+		return robotHTTP
+	case strings.HasPrefix(s, "keyword"):
+		return robotKeyword
+	case s == "ping":
+		return robotPing
+	case strings.HasPrefix(s, "port"):
+		return robotPort
+	case s == "heartbeat" || s == "cron":
+		return robotHeartbeat
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func robotKeywordType(s string) int {
+	switch strings.ToLower(strings.ReplaceAll(s, " ", "")) {
+	case "exists", "1":
+		return robotKeywordExists
+	case "notexists", "2":
+		return robotKeywordNotExists
+	}
+	return 0
+}
+
+// robotInterval reads a CSV interval: seconds, or minutes when suffixed
+// ("5 min", "5m").
+func robotInterval(s string) int {
+	s = strings.ToLower(strings.TrimSpace(s))
+	mult := 1
+	for _, suffix := range []string{"minutes", "minute", "mins", "min", "m"} {
+		if strings.HasSuffix(s, suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, suffix)), 60
+			break
+		}
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(s, "seconds"), "s"))
+	n, _ := strconv.Atoi(s)
+	return n * mult
+}
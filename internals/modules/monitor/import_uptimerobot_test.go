@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseUptimeRobot(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []ImportedMonitor
+	}{
+		{
+			name: "dashboard csv",
+			file: "testdata/uptimerobot.csv",
+			want: []ImportedMonitor{
+				{
+					SourceName: "Website",
+					Monitor:    ManifestMonitor{Name: "Website", Url: "https://www.example.com", IntervalSec: 300, TimeoutSec: 120},
+				},
+				{
+					SourceName: "Login page",
+					Monitor: ManifestMonitor{
+						Name:          "Login page",
+						Url:           "https://www.example.com/login",
+						IntervalSec:   300,
+						TimeoutSec:    120,
+						Keyword:       "Error",
+						KeywordAbsent: true,
+						Enabled:       boolPtr(false),
+					},
+				},
+				{
+					SourceName: "Docs",
+					Monitor:    ManifestMonitor{Name: "Docs", Url: "https://docs.example.com", IntervalSec: 60, TimeoutSec: 120, Keyword: "Welcome"},
+					Warnings:   []string{"unknown keyword type, the keyword is expected on the page"},
+				},
+				{
+					SourceName: "API",
+					Monitor:    ManifestMonitor{Name: "API", Url: "https://api.example.com", IntervalSec: 60, TimeoutSec: 120},
+					Warnings:   []string{"interval of 30s raised to the 60s minimum"},
+				},
+				{
+					SourceName: "Mail server",
+					Monitor:    ManifestMonitor{Name: "Mail server", Url: "mail.example.com"},
+					Skipped:    "UptimeRobot port monitors have no Sofon equivalent",
+				},
+				{
+					SourceName: "Nightly job",
+					Monitor:    ManifestMonitor{Name: "Nightly job"},
+					Skipped:    "UptimeRobot heartbeat monitors have no Sofon equivalent",
+				},
+			},
+		},
+		{
+			name: "getMonitors json",
+			file: "testdata/uptimerobot.json",
+			want: []ImportedMonitor{
+				{
+					SourceName: "Website",
+					Monitor:    ManifestMonitor{Name: "Website", Url: "https://www.example.com", IntervalSec: 300, TimeoutSec: 120},
+					Warnings:   []string{"timeout of 30s raised to the 120s minimum"},
+				},
+				{
+					SourceName: "Checkout",
+					Monitor: ManifestMonitor{
+						Name:        "Checkout",
+						Url:         "https://shop.example.com/checkout",
+						IntervalSec: 600,
+						TimeoutSec:  150,
+						Keyword:     "Pay now",
+						Enabled:     boolPtr(false),
+					},
+					Warnings: []string{
+						"only GET requests are supported, checked with GET",
+						"HTTP auth isn't supported, dropped",
+					},
+				},
+				{
+					SourceName: "Gateway",
+					Monitor:    ManifestMonitor{Name: "Gateway", Url: "10.0.0.1"},
+					Skipped:    "UptimeRobot ping monitors have no Sofon equivalent",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseUptimeRobot(data)
+			if err != nil {
+				t.Fatalf("parseUptimeRobot: %v", err)
+			}
+			compareImported(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseUptimeRobotJSONArray(t *testing.T) {
+	got, err := parseUptimeRobot([]byte("\xef\xbb\xbf" + `[{"friendly_name": "A", "url": "https://a.example.com", "type": "1", "interval": 120}]`))
+	if err != nil {
+		t.Fatalf("parseUptimeRobot: %v", err)
+	}
+	compareImported(t, got, []ImportedMonitor{{
+		SourceName: "A",
+		Monitor:    ManifestMonitor{Name: "A", Url: "https://a.example.com", IntervalSec: 120, TimeoutSec: 120},
+	}})
+}
+
+func TestParseUptimeRobotRejectsOtherFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "empty", data: "", want: "the CSV has no header"},
+		{name: "csv without url column", data: "Friendly Name,Type\nWebsite,HTTP(s)\n", want: "the CSV has no URL column"},
+		{name: "api error", data: `{"stat": "fail", "error": {"type": "invalid_parameter"}}`, want: "monitors is missing"},
+		{name: "broken json", data: `{"monitors": [`, want: "not an UptimeRobot export"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseUptimeRobot([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ManifestVersion is the monitors-as-code document version this server
+// reads and writes.
+const ManifestVersion = 1
+
+// maxManifestMonitors caps the monitors a single document can declare.
+const maxManifestMonitors = 500
+
+// Apply plan actions.
+const (
+	PlanCreate = "create"
+	PlanUpdate = "update"
+	PlanDelete = "delete"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Manifest is a monitors-as-code document: every monitor a team manages
+// declaratively, keyed by slug. Monitors of the team that have a slug but
+// are missing from the document are deleted when it is applied.
+type Manifest struct {
+	Version  int               `yaml:"version" json:"version"`
+	Monitors []ManifestMonitor `yaml:"monitors" json:"monitors"`
+}
+
+// ManifestMonitor declares one monitor. NotificationChannels binds it to
+// the team's plugins by instance name. Enabled defaults to true.
+type ManifestMonitor struct {
+	Slug string `yaml:"slug" json:"slug"`
+	// ID adopts an existing monitor that has no slug yet; exports set it on
+	// such monitors so that applying the export doesn't duplicate them.
+	ID                   string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Name                 string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Description          string   `yaml:"description,omitempty" json:"description,omitempty"`
+	RunbookURL           string   `yaml:"runbook_url,omitempty" json:"runbook_url,omitempty"`
+	Url                  string   `yaml:"url" json:"url"`
+	IntervalSec          int32    `yaml:"interval_sec" json:"interval_sec"`
+	TimeoutSec           int32    `yaml:"timeout_sec" json:"timeout_sec"`
+	LatencyThresholdMs   *int32   `yaml:"latency_threshold_ms,omitempty" json:"latency_threshold_ms,omitempty"`
+	ExpectedStatus       *int32   `yaml:"expected_status,omitempty" json:"expected_status,omitempty"`
+	Keyword              string   `yaml:"keyword,omitempty" json:"keyword,omitempty"`
+	KeywordAbsent        bool     `yaml:"keyword_absent,omitempty" json:"keyword_absent,omitempty"`
+	Enabled              *bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	NotificationChannels []string `yaml:"notification_channels,omitempty" json:"notification_channels,omitempty"`
+	EscalationPolicyID   string   `yaml:"escalation_policy_id,omitempty" json:"escalation_policy_id,omitempty"`
+	ReminderIntervalSec  int32    `yaml:"reminder_interval_sec,omitempty" json:"reminder_interval_sec,omitempty"`
+	ReminderMax          int32    `yaml:"reminder_max,omitempty" json:"reminder_max,omitempty"`
+	Tags                 []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Severity             string   `yaml:"severity,omitempty" json:"severity,omitempty"`
+}
+
+// ApplyPlan is what applying a manifest changes, or would change on a dry
+// run.
+type ApplyPlan struct {
+	DryRun  bool
+	Changes []PlanChange
+	// Unchanged holds the slugs of monitors that already match.
+	Unchanged []string
+	// Unmanaged counts the team's monitors without a slug that the
+	// document doesn't adopt; they are left alone.
+	Unmanaged int
+}
+
+// PlanChange is one monitor to create, update or delete. MonitorID is
+// unset for creates on a dry run.
+type PlanChange struct {
+	Action    string
+	Slug      string
+	MonitorID uuid.UUID
+	Diff      []FieldDiff
+}
+
+// FieldDiff is a field whose value changes, named as in the document.
+type FieldDiff struct {
+	Field string
+	From  any
+	To    any
+}
+
+// manifestEntry is a validated document monitor.
+type manifestEntry struct {
+	Slug                string
+	ID                  *uuid.UUID
+	Config              MonitorConfig
+	Details             MonitorDetails
+	Enabled             bool
+	EscalationPolicyID  *uuid.UUID
+	ReminderIntervalSec int32
+	ReminderMax         int32
+	Tags                []string
+	Severity            string
+}
+
+// validSlug reports whether s can key a monitor: lowercase letters, digits
+// and inner hyphens.
+func validSlug(s string) bool {
+	return len(s) <= maxSlugLen && slugPattern.MatchString(s)
+}
+
+// suggestSlug derives a slug from the monitor's name or URL that isn't in
+// taken yet.
+func suggestSlug(m Monitor, taken map[string]bool) string {
+	src := m.Name
+	if src == "" {
+		src = strings.TrimPrefix(strings.TrimPrefix(m.Url, "https://"), "http://")
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(src) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	base := strings.Trim(b.String(), "-")
+	if len(base) > maxSlugLen-4 {
+		base = strings.TrimRight(base[:maxSlugLen-4], "-")
+	}
+	if base == "" {
+		base = "monitor"
+	}
+
+	slug := base
+	for n := 2; taken[slug]; n++ {
+		slug = base + "-" + strconv.Itoa(n)
+	}
+	return slug
+}
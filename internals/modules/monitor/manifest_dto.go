@@ -0,0 +1,24 @@
+package monitor
+
+type FieldDiffResponse struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type PlanChangeResponse struct {
+	Action    string              `json:"action"`
+	Slug      string              `json:"slug"`
+	MonitorID *string             `json:"monitor_id"`
+	Diff      []FieldDiffResponse `json:"diff,omitempty"`
+}
+
+type ApplyResponse struct {
+	DryRun    bool                 `json:"dry_run"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Deleted   int                  `json:"deleted"`
+	Changes   []PlanChangeResponse `json:"changes"`
+	Unchanged []string             `json:"unchanged"`
+	Unmanaged int                  `json:"unmanaged"`
+}
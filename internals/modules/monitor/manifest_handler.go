@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"go.yaml.in/yaml/v3"
+)
+
+// maxManifestBytes caps the size of an applied document.
+const maxManifestBytes = 1 << 20
+
+// Export writes the team's monitors as a monitors-as-code document, YAML
+// unless ?format=json. The document is the raw body, ready to commit.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.export"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "yaml" && format != "json" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "format must be yaml or json")
+		return
+	}
+
+	doc, err := h.service.Export(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("export monitors error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	var body []byte
+	if format == "json" {
+		body, err = json.MarshalIndent(doc, "", "  ")
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		body, err = yaml.Marshal(doc)
+		w.Header().Set("Content-Type", "application/yaml")
+	}
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("encode monitors error")
+		w.Header().Del("Content-Type")
+		utils.WriteError(w, http.StatusInternalServerError, reqID, apperror.Internal, "internal server error")
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// Apply makes the team's monitors match a YAML or JSON document. With
+// ?dry_run=true it only reports the changes.
+func (h *Handler) Apply(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.apply"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	claims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	if dryRun {
+		middle.SkipAudit(ctx)
+	}
+
+	// YAML is a superset of JSON, so one decoder reads both
+	var doc Manifest
+	dec := yaml.NewDecoder(http.MaxBytesReader(w, r.Body, maxManifestBytes))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, reqID, apperror.InvalidInput, "document is larger than 1 MB")
+		case errors.Is(err, io.EOF):
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "document is empty")
+		default:
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid document: "+err.Error())
+		}
+		return
+	}
+
+	plan, err := h.service.Apply(ctx, tm.TeamID, userID, doc, dryRun)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("apply monitors error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	msg := "monitors applied"
+	if dryRun {
+		msg = "dry run, nothing changed"
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, msg, toApplyResponse(plan))
+}
+
+func toApplyResponse(plan ApplyPlan) ApplyResponse {
+	res := ApplyResponse{
+		DryRun:    plan.DryRun,
+		Changes:   make([]PlanChangeResponse, 0, len(plan.Changes)),
+		Unchanged: plan.Unchanged,
+		Unmanaged: plan.Unmanaged,
+	}
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case PlanCreate:
+			res.Created++
+		case PlanUpdate:
+			res.Updated++
+		case PlanDelete:
+			res.Deleted++
+		}
+
+		change := PlanChangeResponse{Action: c.Action, Slug: c.Slug}
+		if c.MonitorID != uuid.Nil {
+			id := c.MonitorID.String()
+			change.MonitorID = &id
+		}
+		change.Diff = toFieldDiffResponses(c.Diff)
+		res.Changes = append(res.Changes, change)
+	}
+	return res
+}
@@ -0,0 +1,469 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// Export returns the team's monitors as a manifest. Monitors without a slug
+// get a suggested one and their ID, so applying the export adopts them.
+func (s *Service) Export(ctx context.Context, teamID uuid.UUID) (Manifest, error) {
+	monitors, err := s.monitorRepo.ListAll(ctx, teamID)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	taken := make(map[string]bool, len(monitors))
+	for _, m := range monitors {
+		if m.Slug != "" {
+			taken[m.Slug] = true
+		}
+	}
+
+	doc := Manifest{Version: ManifestVersion, Monitors: make([]ManifestMonitor, 0, len(monitors))}
+	for _, m := range monitors {
+		mm := manifestMonitor(m)
+		if mm.Slug == "" {
+			mm.Slug = suggestSlug(m, taken)
+			mm.ID = m.ID.String()
+			taken[mm.Slug] = true
+		}
+		doc.Monitors = append(doc.Monitors, mm)
+	}
+	return doc, nil
+}
+
+// Apply creates, updates and deletes the team's monitors to match the
+// manifest. The whole document is validated before anything changes; a
+// failure part way through leaves the changes made so far in place, and
+// applying the document again finishes the job.
+func (s *Service) Apply(ctx context.Context, teamID, userID uuid.UUID, doc Manifest, dryRun bool) (ApplyPlan, error) {
+	const op = "service.monitor.apply"
+
+	entries, err := s.checkManifest(ctx, op, teamID, doc)
+	if err != nil {
+		return ApplyPlan{}, err
+	}
+	existing, err := s.monitorRepo.ListAll(ctx, teamID)
+	if err != nil {
+		return ApplyPlan{}, err
+	}
+
+	plan, err := planManifest(op, entries, existing)
+	if err != nil {
+		return ApplyPlan{}, err
+	}
+	plan.DryRun = dryRun
+	if dryRun {
+		return plan, nil
+	}
+
+	byID := make(map[uuid.UUID]Monitor, len(existing))
+	for _, m := range existing {
+		byID[m.ID] = m
+	}
+	bySlug := make(map[string]manifestEntry, len(entries))
+	for _, e := range entries {
+		bySlug[e.Slug] = e
+	}
+
+	// deletes go last so that a failed apply removes as little as possible
+	for _, action := range []string{PlanUpdate, PlanCreate, PlanDelete} {
+		for i := range plan.Changes {
+			c := &plan.Changes[i]
+			if c.Action != action {
+				continue
+			}
+			switch action {
+			case PlanCreate:
+				id, err := s.createFromManifest(ctx, teamID, userID, bySlug[c.Slug])
+				if err != nil {
+					return plan, manifestError(op, c.Slug, err)
+				}
+				c.MonitorID = id
+			case PlanUpdate:
+				if err := s.updateFromManifest(ctx, teamID, byID[c.MonitorID], bySlug[c.Slug]); err != nil {
+					return plan, manifestError(op, c.Slug, err)
+				}
+				s.recordRevision(ctx, teamID, c.MonitorID, RevisionUpdate)
+			case PlanDelete:
+				if err := s.DeleteMonitor(ctx, teamID, c.MonitorID); err != nil && !apperror.IsKind(err, apperror.NotFound) {
+					return plan, manifestError(op, c.Slug, err)
+				}
+			}
+		}
+	}
+	return plan, nil
+}
+
+func (s *Service) createFromManifest(ctx context.Context, teamID, userID uuid.UUID, e manifestEntry) (uuid.UUID, error) {
+	const op = "service.monitor.apply"
+
+	id, err := s.createMonitor(ctx, CreateMonitor{
+		TeamID:               teamID,
+		UserID:               userID,
+		Url:                  e.Config.Url,
+		IntervalSec:          e.Config.IntervalSec,
+		TimeoutSec:           e.Config.TimeoutSec,
+		LatencyThresholdMs:   e.Config.LatencyThresholdMs,
+		ExpectedStatus:       e.Config.ExpectedStatus,
+		NotificationChannels: e.Config.NotificationChannels,
+		Keyword:              e.Config.Keyword,
+		KeywordAbsent:        e.Config.KeywordAbsent,
+		EscalationPolicyID:   e.EscalationPolicyID,
+		ReminderIntervalSec:  e.ReminderIntervalSec,
+		ReminderMax:          e.ReminderMax,
+		Tags:                 e.Tags,
+		Severity:             e.Severity,
+		Name:                 e.Details.Name,
+		Description:          e.Details.Description,
+		RunbookURL:           e.Details.RunbookURL,
+		Slug:                 e.Slug,
+	})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	if !e.Enabled {
+		if _, err := s.updateMonitor(ctx, teamID, id, MonitorUpdate{Enabled: &e.Enabled}); err != nil {
+			s.logger.Error().Str("op", op).Err(err).Str("monitor_id", id.String()).Msg("failed to disable monitor created from manifest")
+			s.recordRevision(ctx, teamID, id, RevisionCreate)
+			return id, err
+		}
+	}
+
+	s.recordRevision(ctx, teamID, id, RevisionCreate)
+	return id, nil
+}
+
+// updateFromManifest brings m in line with e, touching only the parts that
+// differ. The caller records the revision.
+func (s *Service) updateFromManifest(ctx context.Context, teamID uuid.UUID, m Monitor, e manifestEntry) error {
+	if m.Slug != e.Slug {
+		if err := s.setSlug(ctx, teamID, m.ID, e.Slug); err != nil {
+			return err
+		}
+	}
+	if !configEqual(monitorConfig(m), e.Config) {
+		if err := s.updateConfig(ctx, teamID, m.ID, e.Config); err != nil {
+			return err
+		}
+	}
+	policyID := uuid.Nil
+	if e.EscalationPolicyID != nil {
+		policyID = *e.EscalationPolicyID
+	}
+	_, err := s.updateMonitor(ctx, teamID, m.ID, MonitorUpdate{
+		Enabled:             &e.Enabled,
+		Name:                &e.Details.Name,
+		Description:         &e.Details.Description,
+		RunbookURL:          &e.Details.RunbookURL,
+		Tags:                &e.Tags,
+		Severity:            &e.Severity,
+		EscalationPolicyID:  &policyID,
+		ReminderIntervalSec: &e.ReminderIntervalSec,
+		ReminderMax:         &e.ReminderMax,
+	})
+	return err
+}
+
+// updateConfig replaces what a monitor checks. The new interval applies
+// from the monitor's next run on.
+func (s *Service) updateConfig(ctx context.Context, teamID, monitorID uuid.UUID, cfg MonitorConfig) error {
+	if err := s.monitorRepo.UpdateConfig(ctx, teamID, monitorID, cfg); err != nil {
+		return err
+	}
+
+	_ = s.cache.DelMonitor(ctx, monitorID)
+	return nil
+}
+
+func (s *Service) setSlug(ctx context.Context, teamID, monitorID uuid.UUID, slug string) error {
+	if err := s.monitorRepo.SetSlug(ctx, teamID, monitorID, slug); err != nil {
+		return err
+	}
+
+	_ = s.cache.DelMonitor(ctx, monitorID)
+	return nil
+}
+
+// checkManifest validates every monitor of the document and fills in the
+// defaults the API would apply.
+func (s *Service) checkManifest(ctx context.Context, op string, teamID uuid.UUID, doc Manifest) ([]manifestEntry, error) {
+	if doc.Version != ManifestVersion {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("version must be %d", ManifestVersion)}
+	}
+	if len(doc.Monitors) > maxManifestMonitors {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "a document can declare at most 500 monitors"}
+	}
+
+	entries := make([]manifestEntry, 0, len(doc.Monitors))
+	slugs := make(map[string]bool, len(doc.Monitors))
+	ids := make(map[uuid.UUID]bool)
+	policies := make(map[uuid.UUID]bool)
+	for _, mm := range doc.Monitors {
+		e, err := checkManifestMonitor(op, mm)
+		if err != nil {
+			return nil, manifestError(op, mm.Slug, err)
+		}
+		if slugs[e.Slug] {
+			return nil, manifestError(op, e.Slug, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "slug is declared more than once"})
+		}
+		slugs[e.Slug] = true
+		if e.ID != nil {
+			if ids[*e.ID] {
+				return nil, manifestError(op, e.Slug, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "id is declared more than once"})
+			}
+			ids[*e.ID] = true
+		}
+		if e.EscalationPolicyID != nil && !policies[*e.EscalationPolicyID] {
+			if err := s.checkEscalationPolicy(ctx, teamID, *e.EscalationPolicyID); err != nil {
+				return nil, manifestError(op, e.Slug, err)
+			}
+			policies[*e.EscalationPolicyID] = true
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func checkManifestMonitor(op string, mm ManifestMonitor) (manifestEntry, error) {
+	invalid := func(msg string) (manifestEntry, error) {
+		return manifestEntry{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	if !validSlug(mm.Slug) {
+		return invalid("slug must be 1 to 63 lowercase letters, digits or inner hyphens")
+	}
+	e := manifestEntry{Slug: mm.Slug, Enabled: mm.Enabled == nil || *mm.Enabled}
+	if mm.ID != "" {
+		id, err := uuid.Parse(mm.ID)
+		if err != nil {
+			return invalid("invalid id")
+		}
+		e.ID = &id
+	}
+
+	u, err := url.Parse(strings.TrimSpace(mm.Url))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return invalid("url must be an http or https URL")
+	}
+	if mm.IntervalSec < 60 {
+		return invalid("interval_sec must be at least 60")
+	}
+	if mm.TimeoutSec < 120 {
+		return invalid("timeout_sec must be at least 120")
+	}
+	if len(mm.Keyword) > maxKeywordLen {
+		return invalid("keyword can be at most 500 characters")
+	}
+	e.Config = MonitorConfig{
+		Url:                  strings.TrimSpace(mm.Url),
+		IntervalSec:          mm.IntervalSec,
+		TimeoutSec:           mm.TimeoutSec,
+		LatencyThresholdMs:   mm.LatencyThresholdMs,
+		ExpectedStatus:       mm.ExpectedStatus,
+		NotificationChannels: normalizeChannels(mm.NotificationChannels),
+		Keyword:              mm.Keyword,
+		KeywordAbsent:        mm.KeywordAbsent && mm.Keyword != "",
+	}
+
+	e.Details, err = checkDetails(op, MonitorDetails{Name: mm.Name, Description: mm.Description, RunbookURL: mm.RunbookURL})
+	if err != nil {
+		return manifestEntry{}, err
+	}
+	e.Tags, err = checkLabels(op, mm.Tags, mm.Severity)
+	if err != nil {
+		return manifestEntry{}, err
+	}
+	e.Severity = mm.Severity
+	if e.Severity == "" {
+		e.Severity = SeverityCritical
+	}
+	if err := checkReminders(op, mm.ReminderIntervalSec, mm.ReminderMax); err != nil {
+		return manifestEntry{}, err
+	}
+	e.ReminderIntervalSec, e.ReminderMax = mm.ReminderIntervalSec, mm.ReminderMax
+	if e.ReminderIntervalSec == 0 {
+		e.ReminderMax = 0
+	}
+	if mm.EscalationPolicyID != "" {
+		id, err := uuid.Parse(mm.EscalationPolicyID)
+		if err != nil {
+			return invalid("invalid escalation_policy_id")
+		}
+		e.EscalationPolicyID = &id
+	}
+	return e, nil
+}
+
+// planManifest matches the document against the team's monitors: by slug,
+// or by ID for monitors the document adopts.
+func planManifest(op string, entries []manifestEntry, existing []Monitor) (ApplyPlan, error) {
+	bySlug := make(map[string]Monitor, len(existing))
+	byID := make(map[uuid.UUID]Monitor, len(existing))
+	for _, m := range existing {
+		if m.Slug != "" {
+			bySlug[m.Slug] = m
+		}
+		byID[m.ID] = m
+	}
+
+	plan := ApplyPlan{Changes: []PlanChange{}, Unchanged: []string{}}
+	claimed := make(map[uuid.UUID]bool, len(entries))
+	for _, e := range entries {
+		m, ok := bySlug[e.Slug]
+		if !ok && e.ID != nil {
+			m, ok = byID[*e.ID]
+			if !ok {
+				return ApplyPlan{}, manifestError(op, e.Slug, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "monitor " + e.ID.String() + " not found"})
+			}
+			if m.Slug != "" {
+				return ApplyPlan{}, manifestError(op, e.Slug, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "monitor " + e.ID.String() + " already has slug " + m.Slug})
+			}
+		}
+		if !ok {
+			plan.Changes = append(plan.Changes, PlanChange{Action: PlanCreate, Slug: e.Slug})
+			continue
+		}
+		claimed[m.ID] = true
+
+		diff := diffMonitor(m, e)
+		if len(diff) == 0 {
+			plan.Unchanged = append(plan.Unchanged, e.Slug)
+			continue
+		}
+		plan.Changes = append(plan.Changes, PlanChange{Action: PlanUpdate, Slug: e.Slug, MonitorID: m.ID, Diff: diff})
+	}
+
+	for _, m := range existing {
+		switch {
+		case claimed[m.ID]:
+		case m.Slug == "":
+			plan.Unmanaged++
+		default:
+			plan.Changes = append(plan.Changes, PlanChange{Action: PlanDelete, Slug: m.Slug, MonitorID: m.ID})
+		}
+	}
+	return plan, nil
+}
+
+func diffMonitor(m Monitor, e manifestEntry) []FieldDiff {
+	var diff []FieldDiff
+	add := func(field string, from, to any) {
+		diff = append(diff, FieldDiff{Field: field, From: from, To: to})
+	}
+
+	if m.Slug != e.Slug {
+		add("slug", m.Slug, e.Slug)
+	}
+	if m.Name != e.Details.Name {
+		add("name", m.Name, e.Details.Name)
+	}
+	if m.Description != e.Details.Description {
+		add("description", m.Description, e.Details.Description)
+	}
+	if m.RunbookURL != e.Details.RunbookURL {
+		add("runbook_url", m.RunbookURL, e.Details.RunbookURL)
+	}
+	if m.Url != e.Config.Url {
+		add("url", m.Url, e.Config.Url)
+	}
+	if m.IntervalSec != e.Config.IntervalSec {
+		add("interval_sec", m.IntervalSec, e.Config.IntervalSec)
+	}
+	if m.TimeoutSec != e.Config.TimeoutSec {
+		add("timeout_sec", m.TimeoutSec, e.Config.TimeoutSec)
+	}
+	if !int32PtrEqual(m.LatencyThresholdMs, e.Config.LatencyThresholdMs) {
+		add("latency_threshold_ms", m.LatencyThresholdMs, e.Config.LatencyThresholdMs)
+	}
+	if !int32PtrEqual(m.ExpectedStatus, e.Config.ExpectedStatus) {
+		add("expected_status", m.ExpectedStatus, e.Config.ExpectedStatus)
+	}
+	if m.Keyword != e.Config.Keyword {
+		add("keyword", m.Keyword, e.Config.Keyword)
+	}
+	if m.KeywordAbsent != e.Config.KeywordAbsent {
+		add("keyword_absent", m.KeywordAbsent, e.Config.KeywordAbsent)
+	}
+	if m.Enabled != e.Enabled {
+		add("enabled", m.Enabled, e.Enabled)
+	}
+	if !slices.Equal(m.NotificationChannels, e.Config.NotificationChannels) {
+		add("notification_channels", m.NotificationChannels, e.Config.NotificationChannels)
+	}
+	if !uuidPtrEqual(m.EscalationPolicyID, e.EscalationPolicyID) {
+		add("escalation_policy_id", uuidString(m.EscalationPolicyID), uuidString(e.EscalationPolicyID))
+	}
+	if m.ReminderIntervalSec != e.ReminderIntervalSec {
+		add("reminder_interval_sec", m.ReminderIntervalSec, e.ReminderIntervalSec)
+	}
+	if m.ReminderMax != e.ReminderMax {
+		add("reminder_max", m.ReminderMax, e.ReminderMax)
+	}
+	if !slices.Equal(m.Tags, e.Tags) {
+		add("tags", m.Tags, e.Tags)
+	}
+	if m.Severity != e.Severity {
+		add("severity", m.Severity, e.Severity)
+	}
+	return diff
+}
+
+// manifestError prefixes an error's message with the slug of the document
+// monitor it is about.
+func manifestError(op, slug string, err error) error {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		return err
+	}
+	if slug == "" {
+		slug = "(no slug)"
+	}
+	return &apperror.Error{Kind: appErr.Kind, Op: op, Message: "monitor " + slug + ": " + appErr.Message, Err: err}
+}
+
+func monitorConfig(m Monitor) MonitorConfig {
+	return MonitorConfig{
+		Url:                  m.Url,
+		IntervalSec:          m.IntervalSec,
+		TimeoutSec:           m.TimeoutSec,
+		LatencyThresholdMs:   m.LatencyThresholdMs,
+		ExpectedStatus:       m.ExpectedStatus,
+		NotificationChannels: m.NotificationChannels,
+		Keyword:              m.Keyword,
+		KeywordAbsent:        m.KeywordAbsent,
+	}
+}
+
+func configEqual(a, b MonitorConfig) bool {
+	return a.Url == b.Url &&
+		a.IntervalSec == b.IntervalSec &&
+		a.TimeoutSec == b.TimeoutSec &&
+		int32PtrEqual(a.LatencyThresholdMs, b.LatencyThresholdMs) &&
+		int32PtrEqual(a.ExpectedStatus, b.ExpectedStatus) &&
+		slices.Equal(a.NotificationChannels, b.NotificationChannels) &&
+		a.Keyword == b.Keyword &&
+		a.KeywordAbsent == b.KeywordAbsent
+}
+
+func int32PtrEqual(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func uuidPtrEqual(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
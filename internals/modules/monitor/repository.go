@@ -17,17 +17,48 @@ import (
 )
 
 type Repository struct {
+	db      db.DBTX
 	querier *db.Queries
 	log     *zerolog.Logger
 }
 
 func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
 	return &Repository{
+		db:      dbExecutor,
 		querier: db.New(dbExecutor),
 		log:     logger,
 	}
 }
 
+// txBeginner is satisfied by *pgxpool.Pool and pgx.Tx.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// InTx runs fn with a repository bound to a single transaction, committed
+// when fn returns nil and rolled back otherwise.
+func (r *Repository) InTx(ctx context.Context, fn func(tx *Repository) error) error {
+	const op string = "repo.monitor.in_tx"
+
+	b, ok := r.db.(txBeginner)
+	if !ok {
+		return &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: errors.New("database handle can't begin transactions")}
+	}
+	tx, err := b.Begin(ctx)
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(&Repository{db: tx, querier: r.querier.WithTx(tx), log: r.log}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	return nil
+}
+
 func (r *Repository) Create(ctx context.Context, monitor CreateMonitor) (uuid.UUID, error) {
 	const op string = "repo.monitor.create"
 
@@ -41,6 +72,17 @@ func (r *Repository) Create(ctx context.Context, monitor CreateMonitor) (uuid.UU
 		ExpectedStatus:       utils.ToPgInt4(monitor.ExpectedStatus),
 		AlertEmail:           utils.ToPgText(""),
 		NotificationChannels: channelsToString(monitor.NotificationChannels),
+		EscalationPolicyID:   toPgUUIDPtr(monitor.EscalationPolicyID),
+		ReminderIntervalSec:  monitor.ReminderIntervalSec,
+		ReminderMax:          monitor.ReminderMax,
+		Tags:                 monitor.Tags,
+		Severity:             monitor.Severity,
+		Name:                 monitor.Name,
+		Description:          monitor.Description,
+		RunbookUrl:           monitor.RunbookURL,
+		Slug:                 utils.ToPgText(monitor.Slug),
+		Keyword:              monitor.Keyword,
+		KeywordAbsent:        monitor.KeywordAbsent,
 	})
 	if err == nil {
 		return utils.FromPgUUID(monitorID), nil
@@ -55,6 +97,13 @@ func (r *Repository) Create(ctx context.Context, monitor CreateMonitor) (uuid.UU
 	}
 
 	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return uuid.UUID{}, &apperror.Error{
+			Kind:    apperror.AlreadyExists,
+			Op:      op,
+			Message: "a monitor with this slug already exists",
+		}
+	}
 	if errors.As(err, &pgErr) {
 		r.log.Error().
 			Str("code", pgErr.Code).
@@ -95,6 +144,19 @@ func (r *Repository) GetByID(ctx context.Context, monitorID uuid.UUID) (Monitor,
 			ExpectedStatus:       utils.FromPgInt4(monitor.ExpectedStatus),
 			Enabled:              monitor.Enabled,
 			NotificationChannels: channelsFromString(monitor.NotificationChannels),
+			EscalationPolicyID:   fromPgUUIDPtr(monitor.EscalationPolicyID),
+			ReminderIntervalSec:  monitor.ReminderIntervalSec,
+			ReminderMax:          monitor.ReminderMax,
+			Tags:                 monitor.Tags,
+			Severity:             monitor.Severity,
+			Name:                 monitor.Name,
+			Description:          monitor.Description,
+			RunbookURL:           monitor.RunbookUrl,
+			Slug:                 utils.FromPgText(monitor.Slug),
+			Keyword:              monitor.Keyword,
+			KeywordAbsent:        monitor.KeywordAbsent,
+			SnoozedUntil:         fromPgTimePtr(monitor.SnoozedUntil),
+			SnoozeNotify:         monitor.SnoozeNotify,
 		}, nil
 	}
 
@@ -151,6 +213,19 @@ func (r *Repository) Get(ctx context.Context, teamID, monitorID uuid.UUID) (Moni
 			ExpectedStatus:       utils.FromPgInt4(monitor.ExpectedStatus),
 			Enabled:              monitor.Enabled,
 			NotificationChannels: channelsFromString(monitor.NotificationChannels),
+			EscalationPolicyID:   fromPgUUIDPtr(monitor.EscalationPolicyID),
+			ReminderIntervalSec:  monitor.ReminderIntervalSec,
+			ReminderMax:          monitor.ReminderMax,
+			Tags:                 monitor.Tags,
+			Severity:             monitor.Severity,
+			Name:                 monitor.Name,
+			Description:          monitor.Description,
+			RunbookURL:           monitor.RunbookUrl,
+			Slug:                 utils.FromPgText(monitor.Slug),
+			Keyword:              monitor.Keyword,
+			KeywordAbsent:        monitor.KeywordAbsent,
+			SnoozedUntil:         fromPgTimePtr(monitor.SnoozedUntil),
+			SnoozeNotify:         monitor.SnoozeNotify,
 		}, nil
 	}
 
@@ -195,18 +270,29 @@ func (r *Repository) GetAll(ctx context.Context, teamID uuid.UUID, opts ListMoni
 
 	var cursorTS pgtype.Timestamptz
 	var cursorID pgtype.UUID
+	var cursorKey string
 	if opts.Cursor != nil {
 		cursorTS = utils.ToPgTimestamptz(opts.Cursor.CreatedAt.UTC())
+		cursorKey = opts.Cursor.Key
 		parsedID, err := uuid.Parse(opts.Cursor.MonitorID)
 		if err == nil {
 			cursorID = utils.ToPgUUID(parsedID)
 		}
 	}
 
+	tags := opts.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
 	rows, err := r.querier.ListMonitorsByTeamCursor(ctx, db.ListMonitorsByTeamCursorParams{
 		TeamID:  utils.ToPgUUID(teamID),
-		Column2: cursorTS,
-		Column3: cursorID,
+		Column2: opts.Query,
+		Column3: tags,
+		Column4: cursorID,
+		Column5: opts.Sort,
+		Column6: cursorTS,
+		Column7: cursorKey,
 		Limit:   fetchLimit,
 	})
 	if err == nil {
@@ -224,6 +310,20 @@ func (r *Repository) GetAll(ctx context.Context, teamID uuid.UUID, opts ListMoni
 				ExpectedStatus:       utils.FromPgInt4(row.ExpectedStatus),
 				Enabled:              row.Enabled,
 				NotificationChannels: channelsFromString(row.NotificationChannels),
+				EscalationPolicyID:   fromPgUUIDPtr(row.EscalationPolicyID),
+				ReminderIntervalSec:  row.ReminderIntervalSec,
+				ReminderMax:          row.ReminderMax,
+				Tags:                 row.Tags,
+				Severity:             row.Severity,
+				Name:                 row.Name,
+				Description:          row.Description,
+				RunbookURL:           row.RunbookUrl,
+				Slug:                 utils.FromPgText(row.Slug),
+				Keyword:              row.Keyword,
+				KeywordAbsent:        row.KeywordAbsent,
+				SnoozedUntil:         fromPgTimePtr(row.SnoozedUntil),
+				SnoozeNotify:         row.SnoozeNotify,
+				sortKey:              row.SortName,
 				CreatedAt:            utils.FromPgTimestamptz(row.CreatedAt),
 				IsDown:               row.IsDown,
 			})
@@ -362,6 +462,198 @@ func (r *Repository) SetEnabled(ctx context.Context, teamID, monitorID uuid.UUID
 	}
 }
 
+// EscalationPolicyExists reports whether the policy belongs to the team.
+func (r *Repository) EscalationPolicyExists(ctx context.Context, teamID, policyID uuid.UUID) (bool, error) {
+	const op string = "repo.monitor.escalation_policy_exists"
+
+	exists, err := r.querier.EscalationPolicyExistsForTeam(ctx, db.EscalationPolicyExistsForTeamParams{
+		ID:     utils.ToPgUUID(policyID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.log)
+	}
+	return exists, nil
+}
+
+// Lock locks the monitor until the surrounding transaction ends. Use it
+// within InTx before reading the monitor that is about to be changed.
+func (r *Repository) Lock(ctx context.Context, teamID, monitorID uuid.UUID) error {
+	const op string = "repo.monitor.lock"
+
+	_, err := r.querier.LockMonitor(ctx, db.LockMonitorParams{
+		ID:     utils.ToPgUUID(monitorID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	return nil
+}
+
+// UpdateSettings replaces the monitor's details, labels, escalation policy
+// and reminders.
+func (r *Repository) UpdateSettings(ctx context.Context, teamID, monitorID uuid.UUID, settings MonitorSettings) error {
+	const op string = "repo.monitor.update_settings"
+
+	rows, err := r.querier.UpdateMonitorSettings(ctx, db.UpdateMonitorSettingsParams{
+		ID:                  utils.ToPgUUID(monitorID),
+		TeamID:              utils.ToPgUUID(teamID),
+		Name:                settings.Details.Name,
+		Description:         settings.Details.Description,
+		RunbookUrl:          settings.Details.RunbookURL,
+		Tags:                settings.Tags,
+		Severity:            settings.Severity,
+		EscalationPolicyID:  toPgUUIDPtr(settings.EscalationPolicyID),
+		ReminderIntervalSec: settings.ReminderIntervalSec,
+		ReminderMax:         settings.ReminderMax,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	return nil
+}
+
+// ListAll returns every monitor of the team, oldest first.
+func (r *Repository) ListAll(ctx context.Context, teamID uuid.UUID) ([]Monitor, error) {
+	const op string = "repo.monitor.list_all"
+
+	rows, err := r.querier.ListTeamMonitors(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.log)
+	}
+	monitors := make([]Monitor, 0, len(rows))
+	for _, row := range rows {
+		monitors = append(monitors, Monitor{
+			ID:                   utils.FromPgUUID(row.ID),
+			TeamID:               utils.FromPgUUID(row.TeamID),
+			UserID:               utils.FromPgUUID(row.UserID),
+			Url:                  row.Url,
+			IntervalSec:          row.IntervalSec,
+			TimeoutSec:           row.TimeoutSec,
+			LatencyThresholdMs:   utils.FromPgInt4(row.LatencyThresholdMs),
+			ExpectedStatus:       utils.FromPgInt4(row.ExpectedStatus),
+			Enabled:              row.Enabled,
+			NotificationChannels: channelsFromString(row.NotificationChannels),
+			EscalationPolicyID:   fromPgUUIDPtr(row.EscalationPolicyID),
+			ReminderIntervalSec:  row.ReminderIntervalSec,
+			ReminderMax:          row.ReminderMax,
+			Tags:                 row.Tags,
+			Severity:             row.Severity,
+			Name:                 row.Name,
+			Description:          row.Description,
+			RunbookURL:           row.RunbookUrl,
+			Slug:                 utils.FromPgText(row.Slug),
+			Keyword:              row.Keyword,
+			KeywordAbsent:        row.KeywordAbsent,
+			SnoozedUntil:         fromPgTimePtr(row.SnoozedUntil),
+			SnoozeNotify:         row.SnoozeNotify,
+		})
+	}
+	return monitors, nil
+}
+
+// UpdateConfig replaces what the monitor checks and its notification
+// channels.
+func (r *Repository) UpdateConfig(ctx context.Context, teamID, monitorID uuid.UUID, cfg MonitorConfig) error {
+	const op string = "repo.monitor.update_config"
+
+	rows, err := r.querier.UpdateMonitorConfig(ctx, db.UpdateMonitorConfigParams{
+		ID:                   utils.ToPgUUID(monitorID),
+		TeamID:               utils.ToPgUUID(teamID),
+		Url:                  cfg.Url,
+		IntervalSec:          cfg.IntervalSec,
+		TimeoutSec:           cfg.TimeoutSec,
+		LatencyThresholdMs:   utils.ToPgInt4(cfg.LatencyThresholdMs),
+		ExpectedStatus:       utils.ToPgInt4(cfg.ExpectedStatus),
+		NotificationChannels: channelsToString(cfg.NotificationChannels),
+		Keyword:              cfg.Keyword,
+		KeywordAbsent:        cfg.KeywordAbsent,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	return nil
+}
+
+// SetSnooze mutes the monitor until the given time, or unmutes it when
+// until is nil.
+func (r *Repository) SetSnooze(ctx context.Context, teamID, monitorID uuid.UUID, until *time.Time, notify bool) error {
+	const op string = "repo.monitor.set_snooze"
+
+	var snoozedUntil pgtype.Timestamptz
+	if until != nil {
+		snoozedUntil = utils.ToPgTimestamptz(*until)
+	}
+	rows, err := r.querier.SetMonitorSnooze(ctx, db.SetMonitorSnoozeParams{
+		ID:           utils.ToPgUUID(monitorID),
+		TeamID:       utils.ToPgUUID(teamID),
+		SnoozedUntil: snoozedUntil,
+		SnoozeNotify: notify,
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	return nil
+}
+
+// SetSlug sets the monitor's slug, or clears it when slug is empty.
+func (r *Repository) SetSlug(ctx context.Context, teamID, monitorID uuid.UUID, slug string) error {
+	const op string = "repo.monitor.set_slug"
+
+	rows, err := r.querier.SetMonitorSlug(ctx, db.SetMonitorSlugParams{
+		ID:     utils.ToPgUUID(monitorID),
+		TeamID: utils.ToPgUUID(teamID),
+		Slug:   utils.ToPgText(slug),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return &apperror.Error{Kind: apperror.AlreadyExists, Op: op, Message: "a monitor with this slug already exists"}
+		}
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	if rows == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	return nil
+}
+
+func toPgUUIDPtr(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return utils.ToPgUUID(*id)
+}
+
+func fromPgUUIDPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := utils.FromPgUUID(id)
+	return &u
+}
+
+func fromPgTimePtr(ts pgtype.Timestamptz) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
+
 func channelsToString(channels []string) string {
 	return strings.Join(channels, ",")
 }
@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revision actions, the kind of change that produced a revision.
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionEnable  = "enable"
+	RevisionDisable = "disable"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// Revision is one numbered version of a monitor. Monitor holds the monitor
+// as it was after the change, or just before it for deletes, and Diff what
+// changed since the previous revision.
+type Revision struct {
+	Revision   int32
+	Action     string
+	ActorID    *uuid.UUID
+	ActorEmail string
+	Monitor    ManifestMonitor
+	Diff       []FieldDiff
+	CreatedAt  time.Time
+}
+
+type RevisionPage struct {
+	Revisions []Revision
+	HasMore   bool
+	// NextBefore is passed as before to fetch the next, older page.
+	NextBefore *int32
+}
+
+// RestoreResult is what restoring a revision changed. A deleted monitor is
+// recreated under a new ID that takes over its history.
+type RestoreResult struct {
+	MonitorID uuid.UUID
+	Recreated bool
+	// Revision is the new revision recording the restore, 0 when the
+	// monitor already matched.
+	Revision int32
+	Diff     []FieldDiff
+}
+
+// manifestMonitor is m in monitors-as-code form, without an ID. Enabled is
+// only set on disabled monitors.
+func manifestMonitor(m Monitor) ManifestMonitor {
+	mm := ManifestMonitor{
+		Slug:                 m.Slug,
+		Name:                 m.Name,
+		Description:          m.Description,
+		RunbookURL:           m.RunbookURL,
+		Url:                  m.Url,
+		IntervalSec:          m.IntervalSec,
+		TimeoutSec:           m.TimeoutSec,
+		LatencyThresholdMs:   m.LatencyThresholdMs,
+		ExpectedStatus:       m.ExpectedStatus,
+		Keyword:              m.Keyword,
+		KeywordAbsent:        m.KeywordAbsent,
+		NotificationChannels: m.NotificationChannels,
+		ReminderIntervalSec:  m.ReminderIntervalSec,
+		ReminderMax:          m.ReminderMax,
+		Tags:                 m.Tags,
+		Severity:             m.Severity,
+	}
+	if !m.Enabled {
+		disabled := false
+		mm.Enabled = &disabled
+	}
+	if m.EscalationPolicyID != nil {
+		mm.EscalationPolicyID = m.EscalationPolicyID.String()
+	}
+	return mm
+}
+
+// snapshotEntry reads a stored revision back. Snapshots were taken from
+// saved monitors, so they aren't validated again.
+func snapshotEntry(mm ManifestMonitor) manifestEntry {
+	e := manifestEntry{
+		Slug: mm.Slug,
+		Config: MonitorConfig{
+			Url:                  mm.Url,
+			IntervalSec:          mm.IntervalSec,
+			TimeoutSec:           mm.TimeoutSec,
+			LatencyThresholdMs:   mm.LatencyThresholdMs,
+			ExpectedStatus:       mm.ExpectedStatus,
+			NotificationChannels: normalizeChannels(mm.NotificationChannels),
+			Keyword:              mm.Keyword,
+			KeywordAbsent:        mm.KeywordAbsent,
+		},
+		Details:             MonitorDetails{Name: mm.Name, Description: mm.Description, RunbookURL: mm.RunbookURL},
+		Enabled:             mm.Enabled == nil || *mm.Enabled,
+		ReminderIntervalSec: mm.ReminderIntervalSec,
+		ReminderMax:         mm.ReminderMax,
+		Tags:                NormalizeTags(mm.Tags),
+		Severity:            mm.Severity,
+	}
+	if id, err := uuid.Parse(mm.EscalationPolicyID); err == nil {
+		e.EscalationPolicyID = &id
+	}
+	return e
+}
+
+// entryMonitor is the monitor a snapshot describes.
+func entryMonitor(e manifestEntry) Monitor {
+	return Monitor{
+		Slug:                 e.Slug,
+		Name:                 e.Details.Name,
+		Description:          e.Details.Description,
+		RunbookURL:           e.Details.RunbookURL,
+		Url:                  e.Config.Url,
+		IntervalSec:          e.Config.IntervalSec,
+		TimeoutSec:           e.Config.TimeoutSec,
+		LatencyThresholdMs:   e.Config.LatencyThresholdMs,
+		ExpectedStatus:       e.Config.ExpectedStatus,
+		Keyword:              e.Config.Keyword,
+		KeywordAbsent:        e.Config.KeywordAbsent,
+		Enabled:              e.Enabled,
+		NotificationChannels: e.Config.NotificationChannels,
+		EscalationPolicyID:   e.EscalationPolicyID,
+		ReminderIntervalSec:  e.ReminderIntervalSec,
+		ReminderMax:          e.ReminderMax,
+		Tags:                 e.Tags,
+		Severity:             e.Severity,
+	}
+}
+
+// diffRevisions lists the fields that differ between two snapshots, in the
+// form a manifest apply reports them.
+func diffRevisions(from, to ManifestMonitor) []FieldDiff {
+	return diffMonitor(entryMonitor(snapshotEntry(from)), snapshotEntry(to))
+}
@@ -0,0 +1,29 @@
+package monitor
+
+import "time"
+
+// RevisionResponse is one revision of a monitor. Monitor is the monitor as
+// of the revision in monitors-as-code form; diff is empty on the oldest
+// revision of a page that has no older one to compare with.
+type RevisionResponse struct {
+	Revision   int32               `json:"revision"`
+	Action     string              `json:"action"`
+	ActorID    *string             `json:"actor_id"`
+	ActorEmail string              `json:"actor_email,omitempty"`
+	Monitor    ManifestMonitor     `json:"monitor"`
+	Diff       []FieldDiffResponse `json:"diff,omitempty"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+type RevisionsResponse struct {
+	HasMore    bool               `json:"has_more"`
+	NextBefore *int32             `json:"next_before,omitempty"`
+	Revisions  []RevisionResponse `json:"revisions"`
+}
+
+type RestoreResponse struct {
+	MonitorID string              `json:"monitor_id"`
+	Recreated bool                `json:"recreated"`
+	Revision  int32               `json:"revision,omitempty"`
+	Diff      []FieldDiffResponse `json:"diff"`
+}
@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"net/http"
+	"strconv"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+func (h *Handler) ListRevisions(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.list_revisions"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 100 {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid limit")
+			return
+		}
+		limit = l
+	}
+	var before int32
+	if beforeStr := r.URL.Query().Get("before"); beforeStr != "" {
+		b, err := strconv.ParseInt(beforeStr, 10, 32)
+		if err != nil || b <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid before")
+			return
+		}
+		before = int32(b)
+	}
+
+	page, err := h.service.ListRevisions(ctx, tm.TeamID, monitorID, before, limit)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("list monitor revisions error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	res := RevisionsResponse{
+		HasMore:    page.HasMore,
+		NextBefore: page.NextBefore,
+		Revisions:  make([]RevisionResponse, 0, len(page.Revisions)),
+	}
+	for _, rev := range page.Revisions {
+		res.Revisions = append(res.Revisions, RevisionResponse{
+			Revision:   rev.Revision,
+			Action:     rev.Action,
+			ActorID:    uuidString(rev.ActorID),
+			ActorEmail: rev.ActorEmail,
+			Monitor:    rev.Monitor,
+			Diff:       toFieldDiffResponses(rev.Diff),
+			CreatedAt:  rev.CreatedAt,
+		})
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, "monitor revisions retrieved", res)
+}
+
+func (h *Handler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.monitor.restore_revision"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+	claims, ok := middle.UserFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "user unauthorised")
+		return
+	}
+
+	monitorID, err := uuid.Parse(chi.URLParam(r, "monitorID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+	rev, err := strconv.ParseInt(chi.URLParam(r, "rev"), 10, 32)
+	if err != nil || rev <= 0 {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid input")
+		return
+	}
+
+	res, err := h.service.Restore(ctx, tm.TeamID, userID, monitorID, int32(rev))
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("restore monitor revision error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	middle.SetAuditChange(ctx, nil, map[string]any{"revision": rev, "recreated": res.Recreated, "monitor_id": res.MonitorID.String()})
+	msg := "monitor restored"
+	if res.Recreated {
+		msg = "deleted monitor recreated"
+	} else if len(res.Diff) == 0 {
+		msg = "monitor already matches the revision"
+	}
+	diff := toFieldDiffResponses(res.Diff)
+	if diff == nil {
+		diff = []FieldDiffResponse{}
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, msg, RestoreResponse{
+		MonitorID: res.MonitorID.String(),
+		Recreated: res.Recreated,
+		Revision:  res.Revision,
+		Diff:      diff,
+	})
+}
+
+func toFieldDiffResponses(diff []FieldDiff) []FieldDiffResponse {
+	var out []FieldDiffResponse
+	for _, d := range diff {
+		out = append(out, FieldDiffResponse{Field: d.Field, From: d.From, To: d.To})
+	}
+	return out
+}
@@ -0,0 +1,155 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// maxRevisionAttempts bounds how often CreateRevision retries a revision
+// number another change took first.
+const maxRevisionAttempts = 5
+
+// CreateRevision stores the next revision of a monitor and returns its
+// number. Two changes recorded at once can pick the same number; the one
+// that loses tries again with the next.
+func (r *Repository) CreateRevision(ctx context.Context, teamID, monitorID uuid.UUID, action string, actorID *uuid.UUID, snapshot ManifestMonitor) (int32, error) {
+	const op string = "repo.monitor.create_revision"
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return 0, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	for attempt := 1; ; attempt++ {
+		rev, err := r.querier.CreateMonitorRevision(ctx, db.CreateMonitorRevisionParams{
+			MonitorID: utils.ToPgUUID(monitorID),
+			TeamID:    utils.ToPgUUID(teamID),
+			Action:    action,
+			ActorID:   toPgUUIDPtr(actorID),
+			Snapshot:  data,
+		})
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && attempt < maxRevisionAttempts {
+			continue
+		}
+		if err != nil {
+			return 0, utils.WrapRepoError(op, err, r.log)
+		}
+		return rev, nil
+	}
+}
+
+// ListRevisions returns up to limit revisions older than before, newest
+// first. A before of 0 starts at the latest one.
+func (r *Repository) ListRevisions(ctx context.Context, teamID, monitorID uuid.UUID, before int32, limit int) ([]Revision, error) {
+	const op string = "repo.monitor.list_revisions"
+
+	rows, err := r.querier.ListMonitorRevisions(ctx, db.ListMonitorRevisionsParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		TeamID:    utils.ToPgUUID(teamID),
+		Column3:   before,
+		Limit:     int32(limit),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.log)
+	}
+
+	revisions := make([]Revision, 0, len(rows))
+	for _, row := range rows {
+		rev := Revision{
+			Revision:   row.Revision,
+			Action:     row.Action,
+			ActorID:    fromPgUUIDPtr(row.ActorID),
+			ActorEmail: row.ActorEmail,
+			CreatedAt:  utils.FromPgTimestamptz(row.CreatedAt),
+		}
+		if err := json.Unmarshal(row.Snapshot, &rev.Monitor); err != nil {
+			return nil, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+func (r *Repository) GetRevision(ctx context.Context, teamID, monitorID uuid.UUID, revision int32) (Revision, error) {
+	const op string = "repo.monitor.get_revision"
+
+	row, err := r.querier.GetMonitorRevision(ctx, db.GetMonitorRevisionParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		TeamID:    utils.ToPgUUID(teamID),
+		Revision:  revision,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Revision{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "revision not found"}
+		}
+		return Revision{}, utils.WrapRepoError(op, err, r.log)
+	}
+
+	rev := Revision{
+		Revision:  row.Revision,
+		Action:    row.Action,
+		CreatedAt: utils.FromPgTimestamptz(row.CreatedAt),
+	}
+	if err := json.Unmarshal(row.Snapshot, &rev.Monitor); err != nil {
+		return Revision{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	return rev, nil
+}
+
+// DeletedAt returns when the monitor was last deleted. found is false when
+// its history holds no delete.
+func (r *Repository) DeletedAt(ctx context.Context, teamID, monitorID uuid.UUID) (time.Time, bool, error) {
+	const op string = "repo.monitor.deleted_at"
+
+	ts, err := r.querier.GetMonitorDeletedAt(ctx, db.GetMonitorDeletedAtParams{
+		MonitorID: utils.ToPgUUID(monitorID),
+		TeamID:    utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, utils.WrapRepoError(op, err, r.log)
+	}
+	return utils.FromPgTimestamptz(ts), true, nil
+}
+
+// MoveRevisions hands the history of monitor from over to monitor to.
+func (r *Repository) MoveRevisions(ctx context.Context, teamID, from, to uuid.UUID) error {
+	const op string = "repo.monitor.move_revisions"
+
+	_, err := r.querier.MoveMonitorRevisions(ctx, db.MoveMonitorRevisionsParams{
+		MonitorID:   utils.ToPgUUID(from),
+		MonitorID_2: utils.ToPgUUID(to),
+		TeamID:      utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.log)
+	}
+	return nil
+}
+
+// PurgeDeletedRevisions drops the history of the team's monitors last deleted
+// before the cutoff.
+func (r *Repository) PurgeDeletedRevisions(ctx context.Context, teamID uuid.UUID, cutoff time.Time) (int64, error) {
+	const op string = "repo.monitor.purge_deleted_revisions"
+
+	n, err := r.querier.PurgeDeletedMonitorRevisions(ctx, db.PurgeDeletedMonitorRevisionsParams{
+		TeamID:  utils.ToPgUUID(teamID),
+		Column2: utils.ToPgTimestamptz(cutoff),
+	})
+	if err != nil {
+		return 0, utils.WrapRepoError(op, err, r.log)
+	}
+	return n, nil
+}
@@ -0,0 +1,220 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// ListRevisions returns a page of the monitor's history, newest first, each
+// revision with what changed since the one before it. The history of a
+// deleted monitor can still be listed until it is purged.
+func (s *Service) ListRevisions(ctx context.Context, teamID, monitorID uuid.UUID, before int32, limit int) (RevisionPage, error) {
+	// one more than the page, to tell whether there is a next page and to
+	// diff the page's oldest revision against
+	revisions, err := s.monitorRepo.ListRevisions(ctx, teamID, monitorID, before, limit+1)
+	if err != nil {
+		return RevisionPage{}, err
+	}
+	if len(revisions) == 0 && before == 0 {
+		// monitors created before history was kept have none yet
+		if _, err := s.monitorRepo.Get(ctx, teamID, monitorID); err != nil {
+			return RevisionPage{}, err
+		}
+	}
+
+	for i := range revisions {
+		if i+1 < len(revisions) {
+			revisions[i].Diff = diffRevisions(revisions[i+1].Monitor, revisions[i].Monitor)
+		}
+	}
+
+	page := RevisionPage{Revisions: revisions}
+	if len(revisions) > limit {
+		page.Revisions = revisions[:limit]
+		page.HasMore = true
+		next := page.Revisions[limit-1].Revision
+		page.NextBefore = &next
+	}
+	return page, nil
+}
+
+// Restore brings the monitor back to how it was at the given revision and
+// records that as a new revision. A deleted monitor is recreated, owned by
+// userID, as long as it was deleted within the retention period; the new
+// monitor has a new ID and takes over the old one's history.
+func (s *Service) Restore(ctx context.Context, teamID, userID, monitorID uuid.UUID, revision int32) (RestoreResult, error) {
+	const op = "service.monitor.restore"
+
+	m, err := s.monitorRepo.Get(ctx, teamID, monitorID)
+	if apperror.IsKind(err, apperror.NotFound) {
+		return s.restoreDeleted(ctx, teamID, userID, monitorID, revision)
+	}
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	rev, err := s.monitorRepo.GetRevision(ctx, teamID, monitorID, revision)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	e := snapshotEntry(rev.Monitor)
+	res := RestoreResult{MonitorID: monitorID, Diff: diffMonitor(m, e)}
+	if len(res.Diff) == 0 {
+		return res, nil
+	}
+	if err := s.updateFromManifest(ctx, teamID, m, e); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("monitor_id", monitorID.String()).Msg("restore left the monitor partly updated")
+		s.recordRevision(ctx, teamID, monitorID, RevisionUpdate)
+		return RestoreResult{}, err
+	}
+
+	res.Revision = s.recordRevision(ctx, teamID, monitorID, RevisionRestore)
+	return res, nil
+}
+
+func (s *Service) restoreDeleted(ctx context.Context, teamID, userID, monitorID uuid.UUID, revision int32) (RestoreResult, error) {
+	const op = "service.monitor.restore"
+
+	deletedAt, found, err := s.monitorRepo.DeletedAt(ctx, teamID, monitorID)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if !found {
+		return RestoreResult{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	if time.Since(deletedAt) > s.revisionRetention {
+		return RestoreResult{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "the monitor was deleted too long ago to be restored"}
+	}
+
+	rev, err := s.monitorRepo.GetRevision(ctx, teamID, monitorID, revision)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	latest, err := s.monitorRepo.ListRevisions(ctx, teamID, monitorID, 0, 1)
+	if err != nil {
+		return RestoreResult{}, err
+	}
+
+	e := snapshotEntry(rev.Monitor)
+	data, err := s.checkCreate(ctx, op, CreateMonitor{
+		TeamID:               teamID,
+		UserID:               userID,
+		Url:                  e.Config.Url,
+		IntervalSec:          e.Config.IntervalSec,
+		TimeoutSec:           e.Config.TimeoutSec,
+		LatencyThresholdMs:   e.Config.LatencyThresholdMs,
+		ExpectedStatus:       e.Config.ExpectedStatus,
+		NotificationChannels: e.Config.NotificationChannels,
+		Keyword:              e.Config.Keyword,
+		KeywordAbsent:        e.Config.KeywordAbsent,
+		EscalationPolicyID:   e.EscalationPolicyID,
+		ReminderIntervalSec:  e.ReminderIntervalSec,
+		ReminderMax:          e.ReminderMax,
+		Tags:                 e.Tags,
+		Severity:             e.Severity,
+		Name:                 e.Details.Name,
+		Description:          e.Details.Description,
+		RunbookURL:           e.Details.RunbookURL,
+		Slug:                 e.Slug,
+	})
+	if err != nil {
+		return RestoreResult{}, err
+	}
+	if err := s.userSvc.IncrementMonitorCount(ctx, userID); err != nil {
+		return RestoreResult{}, err
+	}
+
+	// the new monitor takes over the history in the same transaction, so a
+	// restore never leaves a monitor without it or a history without one
+	var newID uuid.UUID
+	err = s.monitorRepo.InTx(ctx, func(tx *Repository) error {
+		id, err := tx.Create(ctx, data)
+		if err != nil {
+			return err
+		}
+		if err := tx.MoveRevisions(ctx, teamID, monitorID, id); err != nil {
+			return err
+		}
+		if !e.Enabled {
+			if err := tx.SetEnabled(ctx, teamID, id, false); err != nil {
+				return err
+			}
+		}
+		newID = id
+		return nil
+	})
+	if err != nil {
+		if err := s.userSvc.DecrementMonitorCount(ctx, userID); err != nil {
+			s.logger.Error().Str("op", op).Err(err).Msg("failed to decrement monitor count after failed restore")
+		}
+		return RestoreResult{}, err
+	}
+	if e.Enabled {
+		s.ScheduleMonitor(ctx, newID, data.IntervalSec, op)
+	}
+
+	res := RestoreResult{MonitorID: newID, Recreated: true}
+	if len(latest) > 0 {
+		res.Diff = diffRevisions(latest[0].Monitor, rev.Monitor)
+	}
+	res.Revision = s.recordRevision(ctx, teamID, newID, RevisionRestore)
+	return res, nil
+}
+
+// recordRevision stores the monitor as it is now as its next revision and
+// returns the revision number, 0 when none was stored.
+func (s *Service) recordRevision(ctx context.Context, teamID, monitorID uuid.UUID, action string) int32 {
+	const op = "service.monitor.record_revision"
+
+	m, err := s.monitorRepo.Get(ctx, teamID, monitorID)
+	if err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("monitor_id", monitorID.String()).Msg("failed to load monitor for revision")
+		return 0
+	}
+	return s.recordSnapshot(ctx, m, action)
+}
+
+// recordSnapshot stores m as the monitor's next revision, crediting the
+// user making the request. History is best effort: a failure is logged and
+// doesn't fail the change it records. An update that leaves the monitor as
+// the previous revision has it isn't recorded.
+func (s *Service) recordSnapshot(ctx context.Context, m Monitor, action string) int32 {
+	const op = "service.monitor.record_revision"
+
+	mm := manifestMonitor(m)
+	if action == RevisionUpdate {
+		latest, err := s.monitorRepo.ListRevisions(ctx, m.TeamID, m.ID, 0, 1)
+		if err == nil && len(latest) > 0 && len(diffRevisions(latest[0].Monitor, mm)) == 0 {
+			return 0
+		}
+	}
+
+	var actorID *uuid.UUID
+	if claims, ok := middle.UserFromContext(ctx); ok {
+		if id, err := uuid.Parse(claims.UserID); err == nil {
+			actorID = &id
+		}
+	}
+
+	rev, err := s.monitorRepo.CreateRevision(ctx, m.TeamID, m.ID, action, actorID, mm)
+	if err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("monitor_id", m.ID.String()).Str("action", action).Msg("failed to record monitor revision")
+		return 0
+	}
+	return rev
+}
+
+// purgeRevisions drops the history of the team's monitors deleted longer
+// ago than the retention period, which can no longer be restored.
+func (s *Service) purgeRevisions(ctx context.Context, teamID uuid.UUID) {
+	const op = "service.monitor.purge_revisions"
+
+	if _, err := s.monitorRepo.PurgeDeletedRevisions(ctx, teamID, time.Now().Add(-s.revisionRetention)); err != nil {
+		s.logger.Error().Str("op", op).Err(err).Str("team_id", teamID.String()).Msg("failed to purge deleted monitor history")
+	}
+}
@@ -7,9 +7,31 @@ func Routes(h *Handler) chi.Router {
 
 	r.Post("/", h.CreateMonitor)
 	r.Get("/", h.GetAllMonitors)
+	r.Get("/export", h.Export)
+	r.Post("/apply", h.Apply)
+	r.Post("/import", h.Import)
+	r.Post("/bulk", h.Bulk)
+	r.Post("/test", h.TestMonitor)
 	r.Get("/{monitorID}", h.GetMonitor)
-	r.Patch("/{monitorID}", h.UpdateMonitorStatus)
+	r.Patch("/{monitorID}", h.UpdateMonitor)
 	r.Delete("/{monitorID}", h.DeleteMonitor)
+	r.Post("/{monitorID}/check", h.CheckMonitor)
+	r.Get("/{monitorID}/revisions", h.ListRevisions)
+	r.Post("/{monitorID}/revisions/{rev}/restore", h.RestoreRevision)
+
+	return r
+}
+
+func GroupRoutes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListGroups)
+	r.Post("/", h.CreateGroup)
+	r.Get("/{groupID}", h.GetGroup)
+	r.Put("/{groupID}", h.UpdateGroup)
+	r.Delete("/{groupID}", h.DeleteGroup)
+	r.Post("/{groupID}/members", h.AddGroupMembers)
+	r.Delete("/{groupID}/members/{monitorID}", h.RemoveGroupMember)
 
 	return r
 }
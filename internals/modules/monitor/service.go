@@ -2,8 +2,14 @@ package monitor
 
 import (
 	"context"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/alkush-pipania/sofon/config"
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
@@ -17,22 +23,50 @@ type Service struct {
 	monitorRepo *Repository
 	cache       Cache
 	userSvc     UserService
+	checker     Checker
+	channels    ChannelChecker
 	logger      *zerolog.Logger
+
+	checkRateLimit  int
+	checkRateWindow time.Duration
+
+	revisionRetention time.Duration
 }
 
-func NewService(monitorRepo *Repository, cache Cache, userSvc UserService, logger *zerolog.Logger) *Service {
+func NewService(monitorConfig *config.MonitorConfig, monitorRepo *Repository, cache Cache, userSvc UserService, logger *zerolog.Logger) *Service {
 	return &Service{
-		monitorRepo: monitorRepo,
-		userSvc:     userSvc,
-		cache:       cache,
-		logger:      logger,
+		monitorRepo:     monitorRepo,
+		userSvc:         userSvc,
+		cache:           cache,
+		logger:          logger,
+		checkRateLimit:  monitorConfig.CheckRateLimit,
+		checkRateWindow: monitorConfig.CheckRateWindow,
+
+		revisionRetention: monitorConfig.RevisionRetention,
 	}
 }
 
 func (s *Service) CreateMonitor(ctx context.Context, data CreateMonitor) (uuid.UUID, error) {
+	monitorID, err := s.createMonitor(ctx, data)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	s.recordRevision(ctx, data.TeamID, monitorID, RevisionCreate)
+	return monitorID, nil
+}
+
+// createMonitor validates and saves a new monitor without recording a
+// revision.
+func (s *Service) createMonitor(ctx context.Context, data CreateMonitor) (uuid.UUID, error) {
 	const op string = "service.monitor.create_monitor"
 
-	err := s.userSvc.IncrementMonitorCount(ctx, data.UserID)
+	data, err := s.checkCreate(ctx, op, data)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	err = s.userSvc.IncrementMonitorCount(ctx, data.UserID)
 	if err != nil {
 		return uuid.UUID{}, err
 	}
@@ -47,6 +81,41 @@ func (s *Service) CreateMonitor(ctx context.Context, data CreateMonitor) (uuid.U
 	return monitorID, nil
 }
 
+// checkCreate validates a new monitor and fills in its defaults.
+func (s *Service) checkCreate(ctx context.Context, op string, data CreateMonitor) (CreateMonitor, error) {
+	if data.EscalationPolicyID != nil {
+		if err := s.checkEscalationPolicy(ctx, data.TeamID, *data.EscalationPolicyID); err != nil {
+			return data, err
+		}
+	}
+	if err := checkReminders(op, data.ReminderIntervalSec, data.ReminderMax); err != nil {
+		return data, err
+	}
+	tags, err := checkLabels(op, data.Tags, data.Severity)
+	if err != nil {
+		return data, err
+	}
+	data.Tags = tags
+	if data.Severity == "" {
+		data.Severity = SeverityCritical
+	}
+	details, err := checkDetails(op, MonitorDetails{Name: data.Name, Description: data.Description, RunbookURL: data.RunbookURL})
+	if err != nil {
+		return data, err
+	}
+	data.Name, data.Description, data.RunbookURL = details.Name, details.Description, details.RunbookURL
+	if data.Slug != "" && !validSlug(data.Slug) {
+		return data, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "slug must be 1 to 63 lowercase letters, digits or inner hyphens"}
+	}
+	if len(data.Keyword) > maxKeywordLen {
+		return data, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "keyword can be at most 500 characters"}
+	}
+	if data.Keyword == "" {
+		data.KeywordAbsent = false
+	}
+	return data, nil
+}
+
 func (s *Service) GetMonitor(ctx context.Context, teamID uuid.UUID, monitorID uuid.UUID) (Monitor, error) {
 	const op string = "service.monitor.get_monitor"
 
@@ -91,6 +160,18 @@ func (s *Service) LoadMonitor(ctx context.Context, monitorID uuid.UUID) (Monitor
 func (s *Service) GetAllMonitors(ctx context.Context, teamID uuid.UUID, opts ListMonitorsOptions) (ListMonitorsPage, error) {
 	const op = "service.monitor.get_all_monitors"
 
+	if opts.Sort == "" {
+		opts.Sort = SortCreated
+	}
+	if !validSort(opts.Sort) {
+		return ListMonitorsPage{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "sort must be one of created, name, url"}
+	}
+	if opts.Cursor != nil && opts.Cursor.Sort != opts.Sort {
+		return ListMonitorsPage{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "cursor does not match sort"}
+	}
+	opts.Query = strings.TrimSpace(opts.Query)
+	opts.Tags = NormalizeTags(opts.Tags)
+
 	monitors, hasMore, err := s.monitorRepo.GetAll(ctx, teamID, opts)
 	if err != nil {
 		return ListMonitorsPage{}, err
@@ -99,10 +180,18 @@ func (s *Service) GetAllMonitors(ctx context.Context, teamID uuid.UUID, opts Lis
 	var nextCursor *string
 	if hasMore && len(monitors) > 0 {
 		last := monitors[len(monitors)-1]
-		encoded, err := EncodeCursor(Cursor{
+		next := Cursor{
+			Sort:      opts.Sort,
 			CreatedAt: last.CreatedAt,
 			MonitorID: last.ID.String(),
-		})
+		}
+		switch opts.Sort {
+		case SortName:
+			next.Key = last.sortKey
+		case SortURL:
+			next.Key = last.Url
+		}
+		encoded, err := EncodeCursor(next)
 		if err != nil {
 			s.logger.Error().Str("op", op).Err(err).Msg("failed to encode monitor cursor")
 		} else {
@@ -118,32 +207,294 @@ func (s *Service) GetAllMonitors(ctx context.Context, teamID uuid.UUID, opts Lis
 	}, nil
 }
 
-func (s *Service) UpdateMonitorStatus(ctx context.Context, teamID, monitorID uuid.UUID, enable bool) (bool, error) {
-	const op = "service.monitor.update_status"
-
-	m, err := s.monitorRepo.Get(ctx, teamID, monitorID)
+// UpdateMonitor changes the fields set in upd and leaves the others as
+// they are. All of them are validated before anything is written, and they
+// are written together while the monitor is locked. Changing the
+// escalation policy or reminders doesn't affect incidents that are already
+// open.
+func (s *Service) UpdateMonitor(ctx context.Context, teamID, monitorID uuid.UUID, upd MonitorUpdate) error {
+	before, err := s.updateMonitor(ctx, teamID, monitorID, upd)
 	if err != nil {
-		return false, err
+		return err
 	}
 
-	if m.Enabled == enable {
-		return true, nil
+	action := RevisionUpdate
+	if upd.Enabled != nil && *upd.Enabled != before.Enabled && onlyStatus(upd) {
+		action = RevisionDisable
+		if *upd.Enabled {
+			action = RevisionEnable
+		}
 	}
+	s.recordRevision(ctx, teamID, monitorID, action)
+	return nil
+}
+
+// updateMonitor applies upd and returns the monitor as it was before. The
+// caller records the revision.
+func (s *Service) updateMonitor(ctx context.Context, teamID, monitorID uuid.UUID, upd MonitorUpdate) (Monitor, error) {
+	const op = "service.monitor.update"
 
-	if err := s.monitorRepo.SetEnabled(ctx, teamID, monitorID, enable); err != nil {
-		return false, err
+	if upd.SnoozeUntil != nil {
+		if err := checkSnooze(op, *upd.SnoozeUntil); err != nil {
+			return Monitor{}, err
+		}
+	}
+	if upd.EscalationPolicyID != nil && *upd.EscalationPolicyID != uuid.Nil {
+		if err := s.checkEscalationPolicy(ctx, teamID, *upd.EscalationPolicyID); err != nil {
+			return Monitor{}, err
+		}
 	}
 
-	if enable {
-		s.ScheduleMonitor(ctx, m.ID, m.IntervalSec, op)
-	} else {
-		if err := s.monitorRepo.CloseOpenIncident(ctx, monitorID); err != nil {
-			s.logger.Error().Str("op", op).Err(err).Msg("failed to close open incident on disable")
+	var before Monitor
+	err := s.monitorRepo.InTx(ctx, func(tx *Repository) error {
+		if err := tx.Lock(ctx, teamID, monitorID); err != nil {
+			return err
+		}
+		m, err := tx.Get(ctx, teamID, monitorID)
+		if err != nil {
+			return err
+		}
+		before = m
+
+		settings, err := applySettings(op, monitorSettings(m), upd)
+		if err != nil {
+			return err
+		}
+		if !settingsEqual(settings, monitorSettings(m)) {
+			if err := tx.UpdateSettings(ctx, teamID, monitorID, settings); err != nil {
+				return err
+			}
+		}
+		if upd.SnoozeUntil != nil {
+			var until *time.Time
+			if !upd.SnoozeUntil.IsZero() {
+				until = upd.SnoozeUntil
+			}
+			if err := tx.SetSnooze(ctx, teamID, monitorID, until, until != nil && upd.SnoozeNotify); err != nil {
+				return err
+			}
+		}
+		if upd.Enabled != nil && *upd.Enabled != m.Enabled {
+			if err := tx.SetEnabled(ctx, teamID, monitorID, *upd.Enabled); err != nil {
+				return err
+			}
+			if !*upd.Enabled {
+				if err := tx.CloseOpenIncident(ctx, monitorID); err != nil {
+					return err
+				}
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return Monitor{}, err
+	}
+
+	switch {
+	case upd.Enabled == nil || *upd.Enabled == before.Enabled:
+		_ = s.cache.DelMonitor(ctx, monitorID)
+	case *upd.Enabled:
+		_ = s.cache.DelMonitor(ctx, monitorID)
+		s.ScheduleMonitor(ctx, monitorID, before.IntervalSec, op)
+	default:
 		s.disableMonitor(ctx, monitorID)
 	}
+	return before, nil
+}
+
+// applySettings returns settings with the fields set in upd replaced and
+// validated. An empty severity keeps the current one, and turning
+// reminders off clears their cap.
+func applySettings(op string, settings MonitorSettings, upd MonitorUpdate) (MonitorSettings, error) {
+	if upd.Name != nil {
+		settings.Details.Name = *upd.Name
+	}
+	if upd.Description != nil {
+		settings.Details.Description = *upd.Description
+	}
+	if upd.RunbookURL != nil {
+		settings.Details.RunbookURL = *upd.RunbookURL
+	}
+	details, err := checkDetails(op, settings.Details)
+	if err != nil {
+		return settings, err
+	}
+	settings.Details = details
 
-	return true, nil
+	if upd.Tags != nil {
+		settings.Tags = *upd.Tags
+	}
+	if upd.Severity != nil && *upd.Severity != "" {
+		settings.Severity = *upd.Severity
+	}
+	tags, err := checkLabels(op, settings.Tags, settings.Severity)
+	if err != nil {
+		return settings, err
+	}
+	settings.Tags = tags
+
+	if upd.EscalationPolicyID != nil {
+		settings.EscalationPolicyID = nil
+		if *upd.EscalationPolicyID != uuid.Nil {
+			settings.EscalationPolicyID = upd.EscalationPolicyID
+		}
+	}
+
+	if upd.ReminderIntervalSec != nil {
+		settings.ReminderIntervalSec = *upd.ReminderIntervalSec
+	}
+	if upd.ReminderMax != nil {
+		settings.ReminderMax = *upd.ReminderMax
+	}
+	if err := checkReminders(op, settings.ReminderIntervalSec, settings.ReminderMax); err != nil {
+		return settings, err
+	}
+	if settings.ReminderIntervalSec == 0 {
+		settings.ReminderMax = 0
+	}
+	return settings, nil
+}
+
+func monitorSettings(m Monitor) MonitorSettings {
+	return MonitorSettings{
+		Details:             MonitorDetails{Name: m.Name, Description: m.Description, RunbookURL: m.RunbookURL},
+		Tags:                m.Tags,
+		Severity:            m.Severity,
+		EscalationPolicyID:  m.EscalationPolicyID,
+		ReminderIntervalSec: m.ReminderIntervalSec,
+		ReminderMax:         m.ReminderMax,
+	}
+}
+
+func settingsEqual(a, b MonitorSettings) bool {
+	return a.Details == b.Details &&
+		slices.Equal(a.Tags, b.Tags) &&
+		a.Severity == b.Severity &&
+		uuidPtrEqual(a.EscalationPolicyID, b.EscalationPolicyID) &&
+		a.ReminderIntervalSec == b.ReminderIntervalSec &&
+		a.ReminderMax == b.ReminderMax
+}
+
+// onlyStatus reports whether upd changes nothing but the enabled state.
+func onlyStatus(upd MonitorUpdate) bool {
+	rest := upd
+	rest.Enabled = nil
+	return rest == MonitorUpdate{}
+}
+
+// checkDetails trims and validates monitor details. The runbook URL must be
+// an absolute http(s) URL.
+func checkDetails(op string, d MonitorDetails) (MonitorDetails, error) {
+	d.Name = strings.TrimSpace(d.Name)
+	d.Description = strings.TrimSpace(d.Description)
+	d.RunbookURL = strings.TrimSpace(d.RunbookURL)
+
+	if len(d.Name) > maxNameLen {
+		return d, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "name can be at most 100 characters"}
+	}
+	if len(d.Description) > maxDescriptionLen {
+		return d, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "description can be at most 2000 characters"}
+	}
+	if d.RunbookURL != "" {
+		u, err := url.Parse(d.RunbookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(d.RunbookURL) > maxRunbookURLLen {
+			return d, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "runbook_url must be an http or https URL"}
+		}
+	}
+	return d, nil
+}
+
+func validSort(sort string) bool {
+	switch sort {
+	case SortCreated, SortName, SortURL:
+		return true
+	}
+	return false
+}
+
+// checkLabels validates a severity and returns the normalized tags.
+func checkLabels(op string, tags []string, severity string) ([]string, error) {
+	if severity != "" && !ValidSeverity(severity) {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "severity must be one of critical, warning, info"}
+	}
+	tags = NormalizeTags(tags)
+	if len(tags) > maxTags {
+		return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "a monitor can have at most 20 tags"}
+	}
+	for _, t := range tags {
+		if len(t) > maxTagLen {
+			return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "tags can be at most 50 characters"}
+		}
+		key, value, hasValue := strings.Cut(t, ":")
+		if key == "" || (hasValue && value == "") || strings.ContainsAny(key, " ,") {
+			return nil, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "tags must be a key or a key:value pair"}
+		}
+	}
+	return tags, nil
+}
+
+// TagMatches reports whether a monitor tag satisfies a tag filter. A bare
+// key filter matches the key itself and every key:value tag with that key.
+func TagMatches(filter, tag string) bool {
+	if filter == tag {
+		return true
+	}
+	if strings.Contains(filter, ":") {
+		return false
+	}
+	key, _, _ := strings.Cut(tag, ":")
+	return key == filter
+}
+
+// ValidSeverity reports whether s is a known monitor severity.
+func ValidSeverity(s string) bool {
+	switch s {
+	case SeverityCritical, SeverityWarning, SeverityInfo:
+		return true
+	}
+	return false
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and duplicate
+// ones while keeping their order.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+func checkReminders(op string, intervalSec, maxCount int32) error {
+	if intervalSec == 0 {
+		return nil
+	}
+	if intervalSec < minReminderIntervalSec || intervalSec > maxReminderIntervalSec {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "reminder interval must be between 300 and 86400 seconds"}
+	}
+	if maxCount < 1 || maxCount > maxReminders {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "reminder max must be between 1 and 100"}
+	}
+	return nil
+}
+
+func (s *Service) checkEscalationPolicy(ctx context.Context, teamID, policyID uuid.UUID) error {
+	const op = "service.monitor.check_escalation_policy"
+
+	exists, err := s.monitorRepo.EscalationPolicyExists(ctx, teamID, policyID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "escalation policy not found"}
+	}
+	return nil
 }
 
 func (s *Service) ScheduleMonitor(ctx context.Context, mID uuid.UUID, intervalSec int32, op string) {
@@ -175,6 +526,9 @@ func (s *Service) DeleteMonitor(ctx context.Context, teamID, monitorID uuid.UUID
 		s.logger.Error().Str("op", op).Err(err).Msg("failed to decrement monitor count after delete")
 	}
 
+	s.recordSnapshot(ctx, m, RevisionDelete)
+	s.purgeRevisions(ctx, teamID)
+	middle.SetAuditChange(ctx, map[string]any{"name": m.Name, "url": m.Url, "slug": m.Slug}, nil)
 	return nil
 }
 
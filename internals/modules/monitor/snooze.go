@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+)
+
+// maxSnooze is how far ahead a monitor can be snoozed.
+const maxSnooze = 30 * 24 * time.Hour
+
+// checkSnooze validates when a snooze ends; the zero time ends it now. A
+// snoozed monitor is still checked and its results recorded, but failures
+// don't open incidents or notify anyone until the snooze ends on its own.
+func checkSnooze(op string, until time.Time) error {
+	if until.IsZero() {
+		return nil
+	}
+
+	now := time.Now()
+	if !until.After(now) {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "snooze must end in the future"}
+	}
+	if until.Sub(now) > maxSnooze {
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "a monitor can be snoozed for at most 30 days"}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package monitor
+
+import "time"
+
+// SnoozeRequest snoozes a monitor until a time or for a duration such as
+// "2h"; at most one of them is set, and neither ends the snooze.
+type SnoozeRequest struct {
+	Until    *time.Time `json:"until"`
+	Duration string     `json:"duration"`
+	Notify   bool       `json:"notify"`
+}
@@ -8,23 +8,14 @@ import (
 
 type PluginType string
 
-const (
-	PluginTypeResend  PluginType = "resend"
-	PluginTypeZenduty PluginType = "zenduty"
-)
+const maxNameLen = 100
 
 type Plugin struct {
 	ID         uuid.UUID
 	TeamID     uuid.UUID
 	Type       PluginType
+	Name       string
 	Enabled    bool
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
-
-// ResendConfig holds decrypted Resend credentials.
-type ResendConfig struct {
-	APIKey          string   `json:"api_key"`
-	SenderEmail     string   `json:"sender_email"`
-	RecipientEmails []string `json:"recipient_emails"`
-}
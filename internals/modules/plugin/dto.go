@@ -1,13 +1,42 @@
 package plugin
 
-type UpsertPluginRequest struct {
+type CreatePluginRequest struct {
+	Type string `json:"type"`
+	// Name is optional and defaults to the type.
+	Name    string            `json:"name"`
 	Enabled bool              `json:"enabled"`
 	Config  map[string]string `json:"config"`
 }
 
+// UpdatePluginRequest changes only the fields that are present.
+type UpdatePluginRequest struct {
+	Name    *string           `json:"name,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+	Config  map[string]string `json:"config,omitempty"`
+}
+
+type TestPluginRequest struct {
+	// Config is optional; when omitted the stored config is tested.
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// TestConfigRequest tests a config that has not been saved.
+type TestConfigRequest struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+}
+
+type TestPluginResponse struct {
+	ID         string `json:"id,omitempty"`
+	Type       string `json:"type"`
+	Recipient  string `json:"recipient,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
 type PluginResponse struct {
 	ID        string            `json:"id"`
 	Type      string            `json:"type"`
+	Name      string            `json:"name"`
 	Enabled   bool              `json:"enabled"`
 	Config    map[string]string `json:"config,omitempty"`
 	UpdatedAt string            `json:"updated_at"`
@@ -16,3 +45,24 @@ type PluginResponse struct {
 type ListPluginsResponse struct {
 	Plugins []PluginResponse `json:"plugins"`
 }
+
+type PluginFieldResponse struct {
+	Key         string `json:"key"`
+	Label       string `json:"label"`
+	Placeholder string `json:"placeholder,omitempty"`
+	Required    bool   `json:"required"`
+	Secret      bool   `json:"secret"`
+	MultiValue  bool   `json:"multi_value"`
+}
+
+type PluginTypeResponse struct {
+	Type        string                `json:"type"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Category    string                `json:"category"`
+	Fields      []PluginFieldResponse `json:"fields"`
+}
+
+type ListPluginTypesResponse struct {
+	Types []PluginTypeResponse `json:"types"`
+}
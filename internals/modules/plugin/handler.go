@@ -2,15 +2,19 @@ package plugin
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 	"time"
 
 	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/alkush-pipania/sofon/pkg/utils"
 	"github.com/go-chi/chi/v5"
 	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
@@ -49,8 +53,19 @@ func (h *Handler) ListPlugins(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, reqID, "plugins retrieved", ListPluginsResponse{Plugins: items})
 }
 
+func (h *Handler) ListPluginTypes(w http.ResponseWriter, r *http.Request) {
+	reqID := chimw.GetReqID(r.Context())
+
+	schemas := h.service.ListTypes()
+	items := make([]PluginTypeResponse, 0, len(schemas))
+	for i := range schemas {
+		items = append(items, toTypeResponse(&schemas[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "plugin types retrieved", ListPluginTypesResponse{Types: items})
+}
+
 func (h *Handler) GetPlugin(w http.ResponseWriter, r *http.Request) {
-	const op = "handler.plugin.get"
 	ctx := r.Context()
 	reqID := chimw.GetReqID(ctx)
 
@@ -60,23 +75,23 @@ func (h *Handler) GetPlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pluginType := PluginType(chi.URLParam(r, "pluginType"))
-	if !isValidType(pluginType) {
-		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "unsupported plugin type")
+	pluginID, err := uuid.Parse(chi.URLParam(r, "pluginID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid plugin id")
 		return
 	}
 
-	p, configMap, err := h.service.GetPlugin(ctx, tm.TeamID, pluginType)
+	p, configMap, err := h.service.GetPlugin(ctx, tm.TeamID, pluginID)
 	if err != nil {
 		utils.FromAppError(w, reqID, err)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, reqID, "plugin retrieved", toResponse(&p, maskConfig(configMap)))
+	utils.WriteJSON(w, http.StatusOK, reqID, "plugin retrieved", toResponse(&p, h.service.MaskConfig(p.Type, configMap)))
 }
 
-func (h *Handler) UpsertPlugin(w http.ResponseWriter, r *http.Request) {
-	const op = "handler.plugin.upsert"
+func (h *Handler) CreatePlugin(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.plugin.create"
 	ctx := r.Context()
 	reqID := chimw.GetReqID(ctx)
 
@@ -86,30 +101,144 @@ func (h *Handler) UpsertPlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pluginType := PluginType(chi.URLParam(r, "pluginType"))
-	if !isValidType(pluginType) {
+	var req CreatePluginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	pluginType := PluginType(req.Type)
+	if !h.service.IsSupported(pluginType) {
 		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "unsupported plugin type")
 		return
 	}
+	if req.Config == nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "config is required")
+		return
+	}
 
-	var req UpsertPluginRequest
+	p, err := h.service.CreatePlugin(ctx, tm.TeamID, pluginType, req.Name, req.Enabled, req.Config)
+	if err != nil {
+		h.logger.Error().Str("op", op).Err(err).Msg("create plugin")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, reqID, "plugin created", toResponse(&p, nil))
+}
+
+func (h *Handler) UpdatePlugin(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.plugin.update"
+	ctx := r.Context()
+	reqID := chimw.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	pluginID, err := uuid.Parse(chi.URLParam(r, "pluginID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid plugin id")
+		return
+	}
+
+	var req UpdatePluginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
 		return
 	}
+
+	p, err := h.service.UpdatePlugin(ctx, tm.TeamID, pluginID, req.Name, req.Enabled, req.Config)
+	if err != nil {
+		h.logger.Error().Str("op", op).Err(err).Msg("update plugin")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "plugin saved", toResponse(&p, nil))
+}
+
+func (h *Handler) TestPlugin(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.plugin.test"
+	ctx := r.Context()
+	reqID := chimw.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	pluginID, err := uuid.Parse(chi.URLParam(r, "pluginID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid plugin id")
+		return
+	}
+
+	// the body is optional
+	var req TestPluginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	middle.SkipAudit(ctx)
+	p, delivery, err := h.service.TestPlugin(ctx, tm.TeamID, pluginID, req.Config)
+	if err != nil {
+		h.logger.Error().Str("op", op).Err(err).Msg("test plugin")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "test notification sent", TestPluginResponse{
+		ID:         p.ID.String(),
+		Type:       string(p.Type),
+		Recipient:  delivery.Target,
+		ExternalID: delivery.ExternalID,
+	})
+}
+
+// TestConfig sends a test notification with a config before it is saved.
+func (h *Handler) TestConfig(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.plugin.test_config"
+	ctx := r.Context()
+	reqID := chimw.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	var req TestConfigRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	pluginType := PluginType(req.Type)
+	if !h.service.IsSupported(pluginType) {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "unsupported plugin type")
+		return
+	}
 	if req.Config == nil {
 		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "config is required")
 		return
 	}
 
-	p, err := h.service.UpsertPlugin(ctx, tm.TeamID, pluginType, req.Enabled, req.Config)
+	middle.SkipAudit(ctx)
+	delivery, err := h.service.TestConfig(ctx, tm.TeamID, pluginType, req.Config)
 	if err != nil {
-		h.logger.Error().Str("op", op).Err(err).Msg("upsert plugin")
+		h.logger.Error().Str("op", op).Err(err).Msg("test plugin config")
 		utils.FromAppError(w, reqID, err)
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, reqID, "plugin saved", toResponse(&p, nil))
+	utils.WriteJSON(w, http.StatusOK, reqID, "test notification sent", TestPluginResponse{
+		Type:       string(pluginType),
+		Recipient:  delivery.Target,
+		ExternalID: delivery.ExternalID,
+	})
 }
 
 func (h *Handler) DeletePlugin(w http.ResponseWriter, r *http.Request) {
@@ -123,13 +252,13 @@ func (h *Handler) DeletePlugin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pluginType := PluginType(chi.URLParam(r, "pluginType"))
-	if !isValidType(pluginType) {
-		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "unsupported plugin type")
+	pluginID, err := uuid.Parse(chi.URLParam(r, "pluginID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid plugin id")
 		return
 	}
 
-	if err := h.service.DeletePlugin(ctx, tm.TeamID, pluginType); err != nil {
+	if err := h.service.DeletePlugin(ctx, tm.TeamID, pluginID); err != nil {
 		h.logger.Error().Str("op", op).Err(err).Msg("delete plugin")
 		utils.FromAppError(w, reqID, err)
 		return
@@ -138,34 +267,36 @@ func (h *Handler) DeletePlugin(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, reqID, "plugin deleted", struct{}{})
 }
 
-func isValidType(t PluginType) bool {
-	return t == PluginTypeResend
-}
-
 func toResponse(p *Plugin, config map[string]string) PluginResponse {
 	return PluginResponse{
 		ID:        p.ID.String(),
 		Type:      string(p.Type),
+		Name:      p.Name,
 		Enabled:   p.Enabled,
 		Config:    config,
 		UpdatedAt: p.UpdatedAt.UTC().Format(time.RFC3339),
 	}
 }
 
-// maskConfig returns the config map with sensitive values partially masked.
-func maskConfig(m map[string]string) map[string]string {
-	if m == nil {
-		return nil
+func toTypeResponse(s *alert.Schema) PluginTypeResponse {
+	fields := make([]PluginFieldResponse, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		fields = append(fields, PluginFieldResponse{
+			Key:         f.Key,
+			Label:       f.Label,
+			Placeholder: f.Placeholder,
+			Required:    f.Required,
+			Secret:      f.Secret,
+			MultiValue:  f.MultiValue,
+		})
 	}
-	out := make(map[string]string, len(m))
-	for k, v := range m {
-		if k == "api_key" {
-			out[k] = maskKey(v)
-		} else {
-			out[k] = v
-		}
+	return PluginTypeResponse{
+		Type:        s.Type,
+		Name:        s.Name,
+		Description: s.Description,
+		Category:    s.Category,
+		Fields:      fields,
 	}
-	return out
 }
 
 func maskKey(key string) string {
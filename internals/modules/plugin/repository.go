@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"strings"
 
 	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/alkush-pipania/sofon/pkg/apperror"
@@ -13,6 +12,7 @@ import (
 	"github.com/alkush-pipania/sofon/pkg/utils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rs/zerolog"
 )
 
@@ -30,42 +30,63 @@ func NewRepository(dbExecutor db.DBTX, enc *crypto.Encryptor, logger *zerolog.Lo
 	}
 }
 
-func (r *Repository) Upsert(ctx context.Context, teamID uuid.UUID, pluginType PluginType, enabled bool, configMap map[string]string) (Plugin, error) {
-	const op = "repo.plugin.upsert"
+func (r *Repository) Create(ctx context.Context, teamID uuid.UUID, pluginType PluginType, name string, enabled bool, configMap map[string]string) (Plugin, error) {
+	const op = "repo.plugin.create"
 
-	raw, err := json.Marshal(configMap)
+	enc, err := r.encrypt(configMap, op)
 	if err != nil {
-		return Plugin{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "failed to encode config", Err: err}
+		return Plugin{}, err
 	}
 
-	enc, err := r.encryptor.Encrypt(string(raw))
-	if err != nil {
-		return Plugin{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "failed to encrypt config", Err: err}
-	}
-
-	row, err := r.querier.UpsertPlugin(ctx, db.UpsertPluginParams{
+	row, err := r.querier.CreatePlugin(ctx, db.CreatePluginParams{
 		TeamID:     utils.ToPgUUID(teamID),
 		PluginType: string(pluginType),
+		Name:       name,
 		Enabled:    enabled,
 		ConfigEnc:  enc,
 	})
 	if err != nil {
-		return Plugin{}, utils.WrapRepoError(op, err, r.log)
+		return Plugin{}, r.writeError(op, err)
 	}
 
 	return rowToPlugin(row), nil
 }
 
-func (r *Repository) Get(ctx context.Context, teamID uuid.UUID, pluginType PluginType) (Plugin, map[string]string, error) {
+func (r *Repository) Update(ctx context.Context, teamID, pluginID uuid.UUID, name string, enabled bool, configMap map[string]string) (Plugin, error) {
+	const op = "repo.plugin.update"
+
+	enc, err := r.encrypt(configMap, op)
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	row, err := r.querier.UpdatePlugin(ctx, db.UpdatePluginParams{
+		ID:        utils.ToPgUUID(pluginID),
+		TeamID:    utils.ToPgUUID(teamID),
+		Name:      name,
+		Enabled:   enabled,
+		ConfigEnc: enc,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Plugin{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "plugin not found"}
+		}
+		return Plugin{}, r.writeError(op, err)
+	}
+
+	return rowToPlugin(row), nil
+}
+
+func (r *Repository) Get(ctx context.Context, teamID, pluginID uuid.UUID) (Plugin, map[string]string, error) {
 	const op = "repo.plugin.get"
 
 	row, err := r.querier.GetPlugin(ctx, db.GetPluginParams{
-		TeamID:     utils.ToPgUUID(teamID),
-		PluginType: string(pluginType),
+		ID:     utils.ToPgUUID(pluginID),
+		TeamID: utils.ToPgUUID(teamID),
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return Plugin{}, nil, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "plugin not configured"}
+			return Plugin{}, nil, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "plugin not found"}
 		}
 		return Plugin{}, nil, utils.WrapRepoError(op, err, r.log)
 	}
@@ -78,74 +99,74 @@ func (r *Repository) Get(ctx context.Context, teamID uuid.UUID, pluginType Plugi
 	return rowToPlugin(row), configMap, nil
 }
 
-// GetZendutyConfig satisfies the alert.PluginConfigGetter interface.
-func (r *Repository) GetZendutyConfig(ctx context.Context, teamID uuid.UUID) (alert.ZendutyConfig, bool, error) {
-	const op = "repo.plugin.get_zenduty_config"
+// GetByID loads a plugin of any team with its decrypted config. It is used
+// by inbound webhooks, which identify the plugin before the team is known.
+func (r *Repository) GetByID(ctx context.Context, pluginID uuid.UUID) (Plugin, map[string]string, error) {
+	const op = "repo.plugin.get_by_id"
 
-	row, err := r.querier.GetPlugin(ctx, db.GetPluginParams{
-		TeamID:     utils.ToPgUUID(teamID),
-		PluginType: string(PluginTypeZenduty),
-	})
+	row, err := r.querier.GetPluginByID(ctx, utils.ToPgUUID(pluginID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return alert.ZendutyConfig{}, false, nil
+			return Plugin{}, nil, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "plugin not found"}
 		}
-		return alert.ZendutyConfig{}, false, utils.WrapRepoError(op, err, r.log)
-	}
-
-	if !row.Enabled {
-		return alert.ZendutyConfig{}, false, nil
+		return Plugin{}, nil, utils.WrapRepoError(op, err, r.log)
 	}
 
 	configMap, err := r.decrypt(row.ConfigEnc, op)
 	if err != nil {
-		return alert.ZendutyConfig{}, false, err
+		return Plugin{}, nil, err
 	}
 
-	return alert.ZendutyConfig{
-		IntegrationURL: configMap["integration_url"],
-	}, true, nil
+	return rowToPlugin(row), configMap, nil
 }
 
-// GetResendConfig is called by the alert service at send-time.
+// GetPluginConfig is called by the alert service at send-time.
 // It satisfies the alert.PluginConfigGetter interface.
-func (r *Repository) GetResendConfig(ctx context.Context, teamID uuid.UUID) (alert.ResendEmailConfig, bool, error) {
-	const op = "repo.plugin.get_resend_config"
+func (r *Repository) GetPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) (map[string]string, bool, error) {
+	const op = "repo.plugin.get_config"
 
 	row, err := r.querier.GetPlugin(ctx, db.GetPluginParams{
-		TeamID:     utils.ToPgUUID(teamID),
-		PluginType: string(PluginTypeResend),
+		ID:     utils.ToPgUUID(pluginID),
+		TeamID: utils.ToPgUUID(teamID),
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return alert.ResendEmailConfig{}, false, nil
+			return nil, false, nil
 		}
-		return alert.ResendEmailConfig{}, false, utils.WrapRepoError(op, err, r.log)
+		return nil, false, utils.WrapRepoError(op, err, r.log)
 	}
 
 	if !row.Enabled {
-		return alert.ResendEmailConfig{}, false, nil
+		return nil, false, nil
 	}
 
 	configMap, err := r.decrypt(row.ConfigEnc, op)
 	if err != nil {
-		return alert.ResendEmailConfig{}, false, err
+		return nil, false, err
 	}
+	return configMap, true, nil
+}
 
-	var recipientEmails []string
-	if raw := configMap["recipient_emails"]; raw != "" {
-		for _, e := range strings.Split(raw, ",") {
-			if trimmed := strings.TrimSpace(e); trimmed != "" {
-				recipientEmails = append(recipientEmails, trimmed)
-			}
-		}
+// ListPluginInstances lists the team's plugins without their configs. It
+// satisfies the alert.PluginConfigGetter interface.
+func (r *Repository) ListPluginInstances(ctx context.Context, teamID uuid.UUID) ([]alert.PluginInstance, error) {
+	const op = "repo.plugin.list_instances"
+
+	rows, err := r.querier.ListPluginInstances(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.log)
 	}
 
-	return alert.ResendEmailConfig{
-		APIKey:          configMap["api_key"],
-		SenderEmail:     configMap["sender_email"],
-		RecipientEmails: recipientEmails,
-	}, true, nil
+	instances := make([]alert.PluginInstance, 0, len(rows))
+	for _, row := range rows {
+		instances = append(instances, alert.PluginInstance{
+			ID:      utils.FromPgUUID(row.ID),
+			Type:    row.PluginType,
+			Name:    row.Name,
+			Enabled: row.Enabled,
+		})
+	}
+	return instances, nil
 }
 
 func (r *Repository) List(ctx context.Context, teamID uuid.UUID) ([]Plugin, error) {
@@ -163,19 +184,34 @@ func (r *Repository) List(ctx context.Context, teamID uuid.UUID) ([]Plugin, erro
 	return plugins, nil
 }
 
-func (r *Repository) Delete(ctx context.Context, teamID uuid.UUID, pluginType PluginType) error {
+func (r *Repository) Delete(ctx context.Context, teamID, pluginID uuid.UUID) error {
 	const op = "repo.plugin.delete"
 
-	err := r.querier.DeletePlugin(ctx, db.DeletePluginParams{
-		TeamID:     utils.ToPgUUID(teamID),
-		PluginType: string(pluginType),
+	n, err := r.querier.DeletePlugin(ctx, db.DeletePluginParams{
+		ID:     utils.ToPgUUID(pluginID),
+		TeamID: utils.ToPgUUID(teamID),
 	})
 	if err != nil {
 		return utils.WrapRepoError(op, err, r.log)
 	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "plugin not found"}
+	}
 	return nil
 }
 
+func (r *Repository) encrypt(configMap map[string]string, op string) (string, error) {
+	raw, err := json.Marshal(configMap)
+	if err != nil {
+		return "", &apperror.Error{Kind: apperror.Internal, Op: op, Message: "failed to encode config", Err: err}
+	}
+	enc, err := r.encryptor.Encrypt(string(raw))
+	if err != nil {
+		return "", &apperror.Error{Kind: apperror.Internal, Op: op, Message: "failed to encrypt config", Err: err}
+	}
+	return enc, nil
+}
+
 func (r *Repository) decrypt(enc, op string) (map[string]string, error) {
 	plain, err := r.encryptor.Decrypt(enc)
 	if err != nil {
@@ -188,11 +224,20 @@ func (r *Repository) decrypt(enc, op string) (map[string]string, error) {
 	return m, nil
 }
 
+func (r *Repository) writeError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return &apperror.Error{Kind: apperror.AlreadyExists, Op: op, Message: "a plugin with this name already exists"}
+	}
+	return utils.WrapRepoError(op, err, r.log)
+}
+
 func rowToPlugin(row db.Plugin) Plugin {
 	return Plugin{
 		ID:        utils.FromPgUUID(row.ID),
 		TeamID:    utils.FromPgUUID(row.TeamID),
 		Type:      PluginType(row.PluginType),
+		Name:      row.Name,
 		Enabled:   row.Enabled,
 		CreatedAt: utils.FromPgTimestamptz(row.CreatedAt),
 		UpdatedAt: utils.FromPgTimestamptz(row.UpdatedAt),
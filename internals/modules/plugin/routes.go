@@ -5,8 +5,12 @@ import "github.com/go-chi/chi/v5"
 func Routes(h *Handler) chi.Router {
 	r := chi.NewRouter()
 	r.Get("/", h.ListPlugins)
-	r.Get("/{pluginType}", h.GetPlugin)
-	r.Put("/{pluginType}", h.UpsertPlugin)
-	r.Delete("/{pluginType}", h.DeletePlugin)
+	r.Get("/types", h.ListPluginTypes)
+	r.Post("/", h.CreatePlugin)
+	r.Post("/test", h.TestConfig)
+	r.Get("/{pluginID}", h.GetPlugin)
+	r.Put("/{pluginID}", h.UpdatePlugin)
+	r.Delete("/{pluginID}", h.DeletePlugin)
+	r.Post("/{pluginID}/test", h.TestPlugin)
 	return r
 }
@@ -2,52 +2,225 @@ package plugin
 
 import (
 	"context"
-	"net/mail"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/google/uuid"
 )
 
+// ConfigCache is satisfied by *redis.Client. Cached configs are dropped on
+// every write so the alert service never sends with stale credentials.
+type ConfigCache interface {
+	DelCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) error
+}
+
+// AlertTester is satisfied by *alert.AlertService.
+type AlertTester interface {
+	SendTest(ctx context.Context, teamID uuid.UUID, pluginType string, cfg map[string]string) (alert.Delivery, error)
+}
+
 type Service struct {
-	repo *Repository
+	repo      *Repository
+	notifiers *alert.Registry
+	cache     ConfigCache
+	tester    AlertTester
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+func NewService(repo *Repository, notifiers *alert.Registry, cache ConfigCache, tester AlertTester) *Service {
+	return &Service{repo: repo, notifiers: notifiers, cache: cache, tester: tester}
 }
 
-func (s *Service) UpsertPlugin(ctx context.Context, teamID uuid.UUID, pluginType PluginType, enabled bool, config map[string]string) (Plugin, error) {
-	if err := validateConfig(pluginType, config); err != nil {
+// CreatePlugin configures a new instance of a plugin type. The name
+// defaults to the type so a team's first instance needs no name.
+func (s *Service) CreatePlugin(ctx context.Context, teamID uuid.UUID, pluginType PluginType, name string, enabled bool, config map[string]string) (Plugin, error) {
+	const op = "service.plugin.create"
+
+	name, err := validateName(op, name, string(pluginType))
+	if err != nil {
+		return Plugin{}, err
+	}
+	if err := s.validateConfig(pluginType, config); err != nil {
+		return Plugin{}, err
+	}
+	p, err := s.repo.Create(ctx, teamID, pluginType, name, enabled, config)
+	if err != nil {
 		return Plugin{}, err
 	}
-	return s.repo.Upsert(ctx, teamID, pluginType, enabled, config)
+
+	middle.SetAuditTarget(ctx, p.ID.String())
+	middle.SetAuditChange(ctx, nil, auditSummary(p))
+	return p, nil
 }
 
-func (s *Service) GetPlugin(ctx context.Context, teamID uuid.UUID, pluginType PluginType) (Plugin, map[string]string, error) {
-	return s.repo.Get(ctx, teamID, pluginType)
+// UpdatePlugin changes an instance's name, enabled flag or config. Fields
+// left nil keep their current values.
+func (s *Service) UpdatePlugin(ctx context.Context, teamID, pluginID uuid.UUID, name *string, enabled *bool, config map[string]string) (Plugin, error) {
+	const op = "service.plugin.update"
+
+	current, stored, err := s.repo.Get(ctx, teamID, pluginID)
+	if err != nil {
+		return Plugin{}, err
+	}
+	changedKeys := changedConfigKeys(stored, config)
+
+	newName := current.Name
+	if name != nil {
+		if newName, err = validateName(op, *name, string(current.Type)); err != nil {
+			return Plugin{}, err
+		}
+	}
+	newEnabled := current.Enabled
+	if enabled != nil {
+		newEnabled = *enabled
+	}
+	if config != nil {
+		if err := s.validateConfig(current.Type, config); err != nil {
+			return Plugin{}, err
+		}
+		stored = config
+	}
+
+	p, err := s.repo.Update(ctx, teamID, pluginID, newName, newEnabled, stored)
+	if err != nil {
+		return Plugin{}, err
+	}
+	_ = s.cache.DelCachedPluginConfig(ctx, teamID, pluginID)
+
+	after := auditSummary(p)
+	if len(changedKeys) > 0 {
+		// which settings changed, never their values: they hold credentials
+		after["config_changed"] = changedKeys
+	}
+	middle.SetAuditChange(ctx, auditSummary(current), after)
+	return p, nil
+}
+
+func (s *Service) GetPlugin(ctx context.Context, teamID, pluginID uuid.UUID) (Plugin, map[string]string, error) {
+	return s.repo.Get(ctx, teamID, pluginID)
 }
 
 func (s *Service) ListPlugins(ctx context.Context, teamID uuid.UUID) ([]Plugin, error) {
 	return s.repo.List(ctx, teamID)
 }
 
-func (s *Service) DeletePlugin(ctx context.Context, teamID uuid.UUID, pluginType PluginType) error {
-	return s.repo.Delete(ctx, teamID, pluginType)
+func (s *Service) DeletePlugin(ctx context.Context, teamID, pluginID uuid.UUID) error {
+	current, _, err := s.repo.Get(ctx, teamID, pluginID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, teamID, pluginID); err != nil {
+		return err
+	}
+	_ = s.cache.DelCachedPluginConfig(ctx, teamID, pluginID)
+
+	middle.SetAuditChange(ctx, auditSummary(current), nil)
+	return nil
 }
 
-func validateConfig(pluginType PluginType, config map[string]string) error {
-	const op = "service.plugin.validate"
-	switch pluginType {
-	case PluginTypeResend:
-		if strings.TrimSpace(config["api_key"]) == "" {
-			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "resend api_key is required"}
+// TestPlugin sends a test notification through a configured instance. A
+// non-nil config is validated and used as-is without being saved;
+// otherwise the stored config is used, even if the plugin is disabled.
+func (s *Service) TestPlugin(ctx context.Context, teamID, pluginID uuid.UUID, config map[string]string) (Plugin, alert.Delivery, error) {
+	p, stored, err := s.repo.Get(ctx, teamID, pluginID)
+	if err != nil {
+		return Plugin{}, alert.Delivery{}, err
+	}
+	if config != nil {
+		if err := s.validateConfig(p.Type, config); err != nil {
+			return Plugin{}, alert.Delivery{}, err
 		}
-		if _, err := mail.ParseAddress(config["sender_email"]); err != nil {
-			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "sender_email must be a valid email address"}
+		stored = config
+	}
+	delivery, err := s.tester.SendTest(ctx, teamID, string(p.Type), stored)
+	return p, delivery, err
+}
+
+// TestConfig sends a test notification with a config that has not been
+// saved yet.
+func (s *Service) TestConfig(ctx context.Context, teamID uuid.UUID, pluginType PluginType, config map[string]string) (alert.Delivery, error) {
+	if err := s.validateConfig(pluginType, config); err != nil {
+		return alert.Delivery{}, err
+	}
+	return s.tester.SendTest(ctx, teamID, string(pluginType), config)
+}
+
+// ListTypes describes every registered notifier.
+func (s *Service) ListTypes() []alert.Schema {
+	return s.notifiers.Schemas()
+}
+
+func (s *Service) IsSupported(pluginType PluginType) bool {
+	_, ok := s.notifiers.Get(string(pluginType))
+	return ok
+}
+
+// MaskConfig returns the config map with the notifier's secret fields
+// partially masked.
+func (s *Service) MaskConfig(pluginType PluginType, m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	secret := s.notifiers.SecretKeys(string(pluginType))
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if secret[k] {
+			out[k] = maskKey(v)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func validateName(op, name, pluginType string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = pluginType
+	}
+	if len(name) > maxNameLen {
+		return "", &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: fmt.Sprintf("name must be at most %d characters", maxNameLen)}
+	}
+	return name, nil
+}
+
+func (s *Service) validateConfig(pluginType PluginType, config map[string]string) error {
+	const op = "service.plugin.validate"
+	if err := s.notifiers.Validate(string(pluginType), config); err != nil {
+		if errors.Is(err, alert.ErrUnknownNotifier) {
+			return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "unsupported plugin type"}
 		}
-	default:
-		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: "unsupported plugin type"}
+		return &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: err.Error(), Err: err}
 	}
 	return nil
 }
+
+// auditSummary is the part of a plugin the audit log shows.
+func auditSummary(p Plugin) map[string]any {
+	return map[string]any{"type": string(p.Type), "name": p.Name, "enabled": p.Enabled}
+}
+
+// changedConfigKeys lists the settings a config update adds, changes or
+// removes, sorted. A nil update changes nothing.
+func changedConfigKeys(stored, update map[string]string) []string {
+	if update == nil {
+		return nil
+	}
+	var keys []string
+	for k, v := range update {
+		if old, ok := stored[k]; !ok || old != v {
+			keys = append(keys, k)
+		}
+	}
+	for k := range stored {
+		if _, ok := update[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
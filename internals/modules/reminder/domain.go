@@ -0,0 +1,27 @@
+package reminder
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Due is an open incident whose reminder was claimed by the worker.
+type Due struct {
+	IncidentID           uuid.UUID
+	MonitorID            uuid.UUID
+	TeamID               uuid.UUID
+	MonitorURL           string
+	MonitorName          string
+	RunbookURL           string
+	NotificationChannels []string
+	StartTime            time.Time
+	StatusCode           int
+	LatencyMs            int64
+	Reason               string
+	// Count is the 1-based number of this reminder.
+	Count int
+	// Enabled is false when the monitor's reminders were turned off after
+	// this one was scheduled; such a claim is dropped instead of sent.
+	Enabled bool
+}
@@ -0,0 +1,58 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// ClaimDue claims up to limit incidents whose next reminder is due and
+// moves each one's schedule forward, so a reminder is claimed once even
+// with several API instances running.
+func (r *Repository) ClaimDue(ctx context.Context, limit int) ([]Due, error) {
+	const op string = "repo.reminder.claim_due"
+
+	rows, err := r.querier.ClaimDueReminders(ctx, int32(limit))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	due := make([]Due, 0, len(rows))
+	for _, row := range rows {
+		var channels []string
+		if row.NotificationChannels != "" {
+			channels = strings.Split(row.NotificationChannels, ",")
+		}
+		due = append(due, Due{
+			IncidentID:           utils.FromPgUUID(row.ID),
+			MonitorID:            utils.FromPgUUID(row.MonitorID),
+			TeamID:               utils.FromPgUUID(row.TeamID),
+			MonitorURL:           row.MonitorUrl,
+			MonitorName:          row.MonitorName,
+			RunbookURL:           row.RunbookUrl,
+			NotificationChannels: channels,
+			StartTime:            row.StartTime.Time,
+			StatusCode:           int(row.HttpStatus),
+			LatencyMs:            int64(row.LatencyMs),
+			Reason:               row.Reason,
+			Count:                int(row.RemindersSent),
+			Enabled:              row.RemindersEnabled,
+		})
+	}
+	return due, nil
+}
@@ -0,0 +1,154 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/alkush-pipania/sofon/config"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/rs/zerolog"
+)
+
+// Alerter is satisfied by *alert.AlertService.
+type Alerter interface {
+	Enqueue(ctx context.Context, event alert.AlertEvent) error
+}
+
+// IncidentRecorder is satisfied by *incident.Timeline.
+type IncidentRecorder interface {
+	Record(ctx context.Context, e incident.Event)
+}
+
+// Service re-notifies a monitor's channels while one of its incidents stays
+// open and unacknowledged. The schedule lives on the incident row itself:
+// acknowledging or resolving an incident is enough to stop its reminders,
+// and a restart picks up where it left off.
+type Service struct {
+	pollInterval time.Duration
+	batchSize    int
+
+	workerWG sync.WaitGroup
+	stop     chan struct{}
+
+	repo     *Repository
+	alerter  Alerter
+	timeline IncidentRecorder
+	logger   *zerolog.Logger
+}
+
+func NewService(
+	reminderConfig *config.ReminderConfig,
+	repo *Repository,
+	alerter Alerter,
+	timeline IncidentRecorder,
+	logger *zerolog.Logger,
+) *Service {
+	return &Service{
+		pollInterval: reminderConfig.PollInterval,
+		batchSize:    reminderConfig.BatchSize,
+		stop:         make(chan struct{}),
+		repo:         repo,
+		alerter:      alerter,
+		timeline:     timeline,
+		logger:       logger,
+	}
+}
+
+func (s *Service) Start() {
+	s.workerWG.Add(1)
+	go s.run()
+	s.logger.Info().Msg("Reminder worker started")
+}
+
+// Stop signals the worker to exit once its current batch is done.
+func (s *Service) Stop() {
+	close(s.stop)
+}
+
+func (s *Service) WorkerClosingWait() {
+	s.workerWG.Wait()
+}
+
+func (s *Service) run() {
+	defer s.workerWG.Done()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.sendBatch() {
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendBatch sends one batch of due reminders and reports whether the batch
+// was full.
+func (s *Service) sendBatch() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	due, err := s.repo.ClaimDue(ctx, s.batchSize)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim due reminders")
+		return false
+	}
+
+	now := time.Now()
+	for _, d := range due {
+		if !d.Enabled {
+			continue
+		}
+		s.send(ctx, d, now)
+	}
+	return len(due) == s.batchSize
+}
+
+func (s *Service) send(ctx context.Context, d Due, now time.Time) {
+	downtime := int64(now.Sub(d.StartTime).Seconds())
+
+	err := s.alerter.Enqueue(ctx, alert.AlertEvent{
+		IncidentID:           d.IncidentID,
+		Type:                 alert.AlertTypeReminder,
+		MonitorID:            d.MonitorID,
+		TeamID:               d.TeamID,
+		MonitorURL:           d.MonitorURL,
+		MonitorName:          d.MonitorName,
+		RunbookURL:           d.RunbookURL,
+		NotificationChannels: d.NotificationChannels,
+		Reason:               d.Reason,
+		StatusCode:           d.StatusCode,
+		LatencyMs:            d.LatencyMs,
+		CheckedAt:            now,
+		DowntimeSec:          downtime,
+		ReminderCount:        d.Count,
+	})
+	if err != nil {
+		// the schedule has already moved on; the next reminder will go out
+		s.logger.Error().
+			Err(err).
+			Str("incident_id", d.IncidentID.String()).
+			Int("reminder", d.Count).
+			Msg("failed to enqueue reminder")
+		return
+	}
+
+	s.timeline.Record(ctx, incident.Event{
+		IncidentID: d.IncidentID,
+		Kind:       incident.EventReminderSent,
+		Message:    fmt.Sprintf("Reminder %d sent, down for %s", d.Count, alert.FormatDowntime(downtime)),
+		Data: map[string]any{
+			"reminder":     d.Count,
+			"downtime_sec": downtime,
+		},
+		OccurredAt: now,
+	})
+}
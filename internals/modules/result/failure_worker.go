@@ -1,12 +1,17 @@
 package result
 
 import (
+	"context"
 	"time"
 
 	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/alkush-pipania/sofon/internals/modules/executor"
 )
 
+// alertRetrySec is how soon a monitor is checked again when its alert
+// couldn't be queued, so that the alert is retried.
+const alertRetrySec = 5
+
 func (rp *ResultProcessor) failureWorker() {
 	defer rp.workerWG.Done()
 
@@ -37,6 +42,15 @@ func (rp *ResultProcessor) handleFailure(r executor.HTTPResult) {
 		return
 	}
 
+	// snoozed monitors keep their status but don't open an incident
+	if r.Snoozed() {
+		rp.logger.Debug().Str("monitor_id", r.MonitorID.String()).Msg("Monitor is snoozed, not opening an incident")
+		if err := rp.redisSvc.StoreStatus(ctx, r.MonitorID, r.Status, r.LatencyMs, r.CheckedAt); err != nil {
+			rp.logger.Error().Err(err).Msg("failed to store status in redis")
+		}
+		return
+	}
+
 	// Case 2 => retry path : retrying Re-schedule ( 5 sec)
 	if r.Retryable {
 		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Retryable Failure")
@@ -79,41 +93,84 @@ func (rp *ResultProcessor) handleFailure(r executor.HTTPResult) {
 
 	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Int64("fail_count", failCount).Msg("Fail count is greater than threshold, will alert and create DB incident")
 
-	// Atomic alert decision
-	shouldAlert, err := rp.redisSvc.MarkIncidentAlertedIfNotSet(ctx, r.MonitorID)
+	// the alerted flag is only set once the alert is queued, so when
+	// queueing fails the monitor is checked again soon and retries it
+	state, err := rp.redisSvc.GetIncident(ctx, r.MonitorID)
 	if err != nil {
-		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to set alert decision in redis")
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to get alert decision from redis")
 		return
 	}
-	if !shouldAlert {
+	if state["alerted"] == "true" {
 		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Monitor already alerted")
 		return
 	}
 
 	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Now we alert Monitor")
 
-	incidentID, err := rp.incidentRepo.Create(ctx, time.Now(), r)
-	if err != nil {
-		rp.logger.Error().Err(err).Msg("failed to create incident in DB")
+	if err := rp.alertDown(ctx, r, failCount); err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to alert for monitor, retrying shortly")
+		reschedule = false
+		rp.monitorSvc.ScheduleMonitor(ctx, r.MonitorID, alertRetrySec, "result.failure_worker")
 		return
 	}
 
-	if err := rp.redisSvc.MarkDBIncidentCreated(ctx, r.MonitorID); err != nil {
-		rp.logger.Error().Err(err).Msg("failed to mark db_incident")
+	if _, err := rp.redisSvc.MarkIncidentAlertedIfNotSet(ctx, r.MonitorID); err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to set alert decision in redis")
+	}
+}
+
+// alertDown opens the monitor's DB incident and queues its DOWN alert,
+// through the monitor's groups, its escalation policy or directly. Every
+// step is safe to repeat, so a failed alert can be retried by the next
+// check.
+func (rp *ResultProcessor) alertDown(ctx context.Context, r executor.HTTPResult, failCount int64) error {
+	incidentID, created, err := rp.incidentRepo.Open(ctx, time.Now(), r)
+	if err != nil {
+		return err
 	}
-	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Created incident in DB")
 
-	rp.alertChan <- alert.AlertEvent{
+	if err := rp.redisSvc.MarkDBIncidentCreated(ctx, r.MonitorID, incidentID); err != nil {
+		return err
+	}
+	if created {
+		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Created incident in DB")
+		rp.recordIncidentStart(incidentID, failCount, r)
+	}
+
+	event := alert.AlertEvent{
 		IncidentID:           incidentID,
 		Type:                 alert.AlertTypeDown,
 		MonitorID:            r.MonitorID,
 		TeamID:               r.TeamID,
 		MonitorURL:           r.MonitorURL,
+		MonitorName:          r.MonitorName,
+		RunbookURL:           r.RunbookURL,
 		NotificationChannels: r.NotificationChannels,
 		Reason:               r.Reason,
 		StatusCode:           r.Status,
 		LatencyMs:            r.LatencyMs,
 		CheckedAt:            r.CheckedAt,
 	}
-	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Send Alert to alert channel")
+
+	// monitors in a group with group alerting alert through the group
+	grouped, err := rp.groupDown(ctx, event)
+	if grouped || err != nil {
+		return err
+	}
+
+	// monitors with an escalation policy are paged level by level instead
+	escalated, err := rp.escalator.Trigger(ctx, event)
+	if err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to start escalation, falling back to down alert")
+	}
+	if escalated {
+		rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Escalation started")
+		return nil
+	}
+
+	if err := rp.alerter.Enqueue(ctx, event); err != nil {
+		return err
+	}
+	rp.logger.Info().Str("monitor_id", r.MonitorID.String()).Msg("Down alert enqueued")
+	return nil
 }
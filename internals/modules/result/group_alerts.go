@@ -0,0 +1,144 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/executor"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/alkush-pipania/sofon/internals/modules/monitor"
+	"github.com/google/uuid"
+)
+
+// groupDown hands a DOWN alert to the monitor's groups in group alert mode.
+// Each group sends one DOWN alert when it goes down, with the failing
+// monitor's details, and the monitor's own alert and escalation are skipped.
+// It reports false when no group alerts for the monitor, and an error when a
+// group's alert couldn't be queued; that group's alert is left closed so a
+// retry opens it again.
+func (rp *ResultProcessor) groupDown(ctx context.Context, event alert.AlertEvent) (bool, error) {
+	groups, err := rp.monitorSvc.AlertGroups(ctx, event.TeamID, event.MonitorID)
+	if err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", event.MonitorID.String()).Msg("failed to load monitor groups, alerting for the monitor")
+		return false, nil
+	}
+	if len(groups) == 0 {
+		return false, nil
+	}
+
+	var enqueueErr error
+
+	for _, g := range groups {
+		g.Monitors[event.MonitorID] = monitor.StatusDown
+		if !g.AlertActive() {
+			rp.recordGroupSuppressed(ctx, event.IncidentID, g, fmt.Sprintf("monitor group %s is not down yet", g.Name))
+			continue
+		}
+
+		groupEvent := event
+		groupEvent.GroupName = g.Name
+		if len(g.Channels) > 0 {
+			groupEvent.NotificationChannels = g.Channels
+		}
+		payload, err := json.Marshal(groupEvent)
+		if err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to encode group alert")
+			continue
+		}
+		opened, err := rp.redisSvc.OpenGroupAlert(ctx, g.ID, payload)
+		if err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to open group alert")
+			continue
+		}
+		if !opened {
+			rp.recordGroupSuppressed(ctx, event.IncidentID, g, fmt.Sprintf("alert already sent for monitor group %s", g.Name))
+			continue
+		}
+
+		if err := rp.alerter.Enqueue(ctx, groupEvent); err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to enqueue group down alert")
+			enqueueErr = err
+			if _, err := rp.redisSvc.CloseGroupAlert(ctx, g.ID); err != nil {
+				rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to release group alert")
+			}
+			continue
+		}
+		rp.logger.Info().Str("group_id", g.ID.String()).Str("monitor_id", event.MonitorID.String()).Msg("Group down alert enqueued")
+	}
+	return true, enqueueErr
+}
+
+// groupRecovered sends the RECOVERED alert of every group in group alert
+// mode that is back up now that the monitor recovered. It reports whether
+// any group alerts for the monitor, in which case the monitor's own
+// RECOVERED alert is skipped, and an error when a group's alert couldn't be
+// queued; that group's alert stays open so a retry sends it.
+func (rp *ResultProcessor) groupRecovered(ctx context.Context, r executor.HTTPResult, incidentID uuid.UUID) (bool, error) {
+	groups, err := rp.monitorSvc.AlertGroups(ctx, r.TeamID, r.MonitorID)
+	if err != nil {
+		rp.logger.Error().Err(err).Str("monitor_id", r.MonitorID.String()).Msg("failed to load monitor groups, alerting for the monitor")
+		return false, nil
+	}
+	if len(groups) == 0 {
+		return false, nil
+	}
+
+	var enqueueErr error
+
+	for _, g := range groups {
+		g.Monitors[r.MonitorID] = monitor.StatusUp
+		if g.AlertActive() {
+			rp.recordGroupSuppressed(ctx, incidentID, g, fmt.Sprintf("monitor group %s is still down", g.Name))
+			continue
+		}
+
+		payload, err := rp.redisSvc.CloseGroupAlert(ctx, g.ID)
+		if err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to close group alert")
+			continue
+		}
+		if payload == nil {
+			continue
+		}
+		var down alert.AlertEvent
+		if err := json.Unmarshal(payload, &down); err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to decode group alert")
+			continue
+		}
+
+		recovered := down
+		recovered.Type = alert.AlertTypeRecovered
+		recovered.Reason = "RECOVERED"
+		recovered.StatusCode = r.Status
+		recovered.LatencyMs = r.LatencyMs
+		recovered.CheckedAt = r.CheckedAt
+		recovered.EscalationLevel = 0
+		if err := rp.alerter.Enqueue(ctx, recovered); err != nil {
+			rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to enqueue group recovery alert")
+			enqueueErr = err
+			if _, err := rp.redisSvc.OpenGroupAlert(ctx, g.ID, payload); err != nil {
+				rp.logger.Error().Err(err).Str("group_id", g.ID.String()).Msg("failed to restore group alert")
+			}
+			continue
+		}
+		rp.logger.Info().Str("group_id", g.ID.String()).Msg("Group recovery alert enqueued")
+	}
+	return true, enqueueErr
+}
+
+func (rp *ResultProcessor) recordGroupSuppressed(ctx context.Context, incidentID uuid.UUID, g monitor.GroupHealth, msg string) {
+	down, counted := g.Counts()
+	rp.timeline.Record(ctx, incident.Event{
+		IncidentID: incidentID,
+		Kind:       incident.EventAlertSuppressed,
+		Message:    msg,
+		Data: map[string]any{
+			"group_id":   g.ID.String(),
+			"group_name": g.Name,
+			"down":       down,
+			"monitors":   counted,
+		},
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/alkush-pipania/sofon/config"
 	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/alkush-pipania/sofon/internals/modules/executor"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
 	"github.com/alkush-pipania/sofon/internals/modules/monitor"
 	"github.com/alkush-pipania/sofon/pkg/redis"
 	"github.com/google/uuid"
@@ -16,6 +17,25 @@ import (
 type MonitorService interface {
 	LoadMonitor(context.Context, uuid.UUID) (monitor.Monitor, error)
 	ScheduleMonitor(context.Context, uuid.UUID, int32, string)
+	AlertGroups(ctx context.Context, teamID, monitorID uuid.UUID) ([]monitor.GroupHealth, error)
+}
+
+// AlertEnqueuer is satisfied by *alert.AlertService.
+type AlertEnqueuer interface {
+	Enqueue(ctx context.Context, event alert.AlertEvent) error
+}
+
+// Escalator is satisfied by *escalation.Service. Trigger reports false when
+// the monitor has no escalation policy, in which case the plain DOWN alert is
+// sent instead.
+type Escalator interface {
+	Trigger(ctx context.Context, event alert.AlertEvent) (bool, error)
+	Resolve(ctx context.Context, incidentID uuid.UUID) ([]string, error)
+}
+
+// IncidentRecorder is satisfied by *incident.Timeline.
+type IncidentRecorder interface {
+	Record(ctx context.Context, e incident.Event)
 }
 
 type ResultProcessor struct {
@@ -33,12 +53,14 @@ type ResultProcessor struct {
 	redisSvc     *redis.Client
 	monitorSvc   MonitorService
 	incidentRepo *MonitorIncidentRepository // here should be MonitorIncidentService, make a seperate module for Monitor Incident
+	alerter      AlertEnqueuer
+	escalator    Escalator
+	timeline     IncidentRecorder
 
 	// channels
 	resultChan  chan executor.HTTPResult
 	successChan chan executor.HTTPResult
 	failureChan chan executor.HTTPResult
-	alertChan   chan alert.AlertEvent
 
 	// misc
 	logger *zerolog.Logger
@@ -51,7 +73,9 @@ func NewResultProcessor(
 	resultChan chan executor.HTTPResult,
 	incidentRepo *MonitorIncidentRepository,
 	monitorSvc MonitorService,
-	alertChan chan alert.AlertEvent,
+	alerter AlertEnqueuer,
+	escalator Escalator,
+	timeline IncidentRecorder,
 	logger *zerolog.Logger,
 ) *ResultProcessor {
 	return &ResultProcessor{
@@ -60,7 +84,9 @@ func NewResultProcessor(
 		resultChan:         resultChan,
 		incidentRepo:       incidentRepo,
 		monitorSvc:         monitorSvc,
-		alertChan:          alertChan,
+		alerter:            alerter,
+		escalator:          escalator,
+		timeline:           timeline,
 		successChan:        make(chan executor.HTTPResult, resProcessorConfig.SuccessChannelSize),
 		failureChan:        make(chan executor.HTTPResult, resProcessorConfig.FailureChannelSize),
 		successWorkerCount: resProcessorConfig.SuccessWorkerCount,
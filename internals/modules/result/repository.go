@@ -47,6 +47,27 @@ func (r *MonitorIncidentRepository) Create(ctx context.Context, startTime time.T
 	return uuid.UUID{}, utils.WrapRepoError(op, err, r.logger)
 }
 
+// Open returns the monitor's open incident, creating one when there is
+// none. It reports whether the incident was created, so an alert that
+// failed to go out can be retried without opening a second incident.
+func (r *MonitorIncidentRepository) Open(ctx context.Context, startTime time.Time, e executor.HTTPResult) (uuid.UUID, bool, error) {
+	const op string = "repo.monitor_incident.open"
+
+	incidentID, err := r.querier.GetOpenMonitorIncident(ctx, utils.ToPgUUID(e.MonitorID))
+	if err == nil {
+		return utils.FromPgUUID(incidentID), false, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	id, err := r.Create(ctx, startTime, e)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	return id, true, nil
+}
+
 func (r *MonitorIncidentRepository) GetByID(ctx context.Context, incidentID uuid.UUID) (MonitorIncident, error) {
 	const op string = "repo.monitor_incident.get"
 
@@ -1,6 +1,8 @@
 package result
 
 import (
+	"context"
+	"slices"
 	"time"
 
 	"github.com/alkush-pipania/sofon/internals/modules/alert"
@@ -18,10 +20,11 @@ func (rp *ResultProcessor) successWorker() {
 
 func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 	ctx := rp.ctx
+	interval := r.IntervalSec
 
 	defer func() {
 		// ALWAYS re-schedule at last
-		rp.monitorSvc.ScheduleMonitor(ctx, r.MonitorID, r.IntervalSec, "result.success_worker")
+		rp.monitorSvc.ScheduleMonitor(ctx, r.MonitorID, interval, "result.success_worker")
 	}()
 
 	// store success in redis
@@ -61,44 +64,59 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 				Msg("failed to close incident in DB, keeping redis incident")
 			return
 		}
-		if !closed {
+		if closed {
+			closedIncidentID = incidentID
+			rp.recordRecovery(closedIncidentID, r)
+		} else if id, err := uuid.Parse(incident["incident_id"]); err == nil {
+			// closed by an earlier check whose recovery alert failed
+			closedIncidentID = id
+		} else {
 			rp.logger.Warn().
 				Str("monitor_id", r.MonitorID.String()).
 				Msg("recovery detected but no open DB incident found")
-		} else {
-			closedIncidentID = incidentID
 		}
 	}
 
-	if dbIncident && closedIncidentID != uuid.Nil {
-		shouldSendRecovered, err := rp.redisSvc.MarkIncidentRecoveredAlertedIfNotSet(ctx, r.MonitorID)
+	channels := r.NotificationChannels
+	if closedIncidentID != uuid.Nil {
+		notified, err := rp.escalator.Resolve(ctx, closedIncidentID)
 		if err != nil {
 			rp.logger.Error().
 				Err(err).
-				Str("monitor_id", r.MonitorID.String()).
-				Msg("failed to mark recovered alert decision")
-		} else if shouldSendRecovered {
-			rp.alertChan <- alert.AlertEvent{
-				IncidentID:           closedIncidentID,
-				Type:                 alert.AlertTypeRecovered,
-				MonitorID:            r.MonitorID,
-				TeamID:               r.TeamID,
-				MonitorURL:           r.MonitorURL,
-				NotificationChannels: r.NotificationChannels,
-				Reason:               "RECOVERED",
-				StatusCode:           r.Status,
-				LatencyMs:            r.LatencyMs,
-				CheckedAt:            r.CheckedAt,
-			}
-			rp.logger.Info().
-				Str("monitor_id", r.MonitorID.String()).
 				Str("incident_id", closedIncidentID.String()).
-				Msg("recovery alert sent to alert channel")
-		} else {
+				Msg("failed to resolve escalation")
+		}
+		// an empty list already means every enabled channel
+		if len(channels) > 0 {
+			channels = mergeChannels(channels, notified)
+		}
+	}
+
+	// only incidents whose DOWN alert went out get a RECOVERED alert. The
+	// flag is set once the alert is queued; until then the redis incident
+	// is kept so the next check retries it.
+	if closedIncidentID != uuid.Nil && incident["alerted"] == "true" {
+		if incident["recovered_alerted"] == "true" {
 			rp.logger.Info().
 				Str("monitor_id", r.MonitorID.String()).
 				Str("incident_id", closedIncidentID.String()).
 				Msg("recovery alert already sent for this incident")
+		} else {
+			if err := rp.alertRecovered(ctx, r, closedIncidentID, channels); err != nil {
+				rp.logger.Error().
+					Err(err).
+					Str("monitor_id", r.MonitorID.String()).
+					Str("incident_id", closedIncidentID.String()).
+					Msg("failed to enqueue recovery alert, retrying shortly")
+				interval = alertRetrySec
+				return
+			}
+			if _, err := rp.redisSvc.MarkIncidentRecoveredAlertedIfNotSet(ctx, r.MonitorID); err != nil {
+				rp.logger.Error().
+					Err(err).
+					Str("monitor_id", r.MonitorID.String()).
+					Msg("failed to mark recovered alert decision")
+			}
 		}
 	}
 
@@ -118,3 +136,54 @@ func (rp *ResultProcessor) handleSuccess(r executor.HTTPResult) {
 			Msg("failed to clear retry state from redis")
 	}
 }
+
+// alertRecovered queues the RECOVERED alert of a closed incident, through
+// the monitor's groups or directly.
+func (rp *ResultProcessor) alertRecovered(ctx context.Context, r executor.HTTPResult, incidentID uuid.UUID, channels []string) error {
+	grouped, err := rp.groupRecovered(ctx, r, incidentID)
+	if err != nil {
+		return err
+	}
+	if grouped {
+		rp.logger.Info().
+			Str("monitor_id", r.MonitorID.String()).
+			Str("incident_id", incidentID.String()).
+			Msg("recovery handled by monitor groups")
+		return nil
+	}
+
+	err = rp.alerter.Enqueue(ctx, alert.AlertEvent{
+		IncidentID:           incidentID,
+		Type:                 alert.AlertTypeRecovered,
+		MonitorID:            r.MonitorID,
+		TeamID:               r.TeamID,
+		MonitorURL:           r.MonitorURL,
+		MonitorName:          r.MonitorName,
+		RunbookURL:           r.RunbookURL,
+		NotificationChannels: channels,
+		Reason:               "RECOVERED",
+		StatusCode:           r.Status,
+		LatencyMs:            r.LatencyMs,
+		CheckedAt:            r.CheckedAt,
+	})
+	if err != nil {
+		return err
+	}
+	rp.logger.Info().
+		Str("monitor_id", r.MonitorID.String()).
+		Str("incident_id", incidentID.String()).
+		Msg("recovery alert enqueued")
+	return nil
+}
+
+// mergeChannels appends the channels an escalation paged so that everyone who
+// heard about the incident also hears about the recovery.
+func mergeChannels(base, extra []string) []string {
+	out := append([]string(nil), base...)
+	for _, c := range extra {
+		if !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
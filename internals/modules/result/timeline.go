@@ -0,0 +1,64 @@
+package result
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/modules/executor"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/google/uuid"
+)
+
+// recordIncidentStart writes the first events of a new incident's timeline:
+// the first failed check, the check that crossed the failure threshold and
+// the incident itself.
+func (rp *ResultProcessor) recordIncidentStart(incidentID uuid.UUID, failCount int64, r executor.HTTPResult) {
+	ctx := rp.ctx
+
+	if state, err := rp.redisSvc.GetIncident(ctx, r.MonitorID); err == nil && state != nil {
+		if sec, err := strconv.ParseInt(state["first_failure_at"], 10, 64); err == nil {
+			rp.timeline.Record(ctx, incident.Event{
+				IncidentID: incidentID,
+				Kind:       incident.EventFirstFailure,
+				Message:    "First failed check",
+				OccurredAt: time.Unix(sec, 0),
+			})
+		}
+	}
+
+	rp.timeline.Record(ctx, incident.Event{
+		IncidentID: incidentID,
+		Kind:       incident.EventThresholdCrossed,
+		Message:    fmt.Sprintf("%d consecutive failed checks", failCount),
+		Data: map[string]any{
+			"failure_count": failCount,
+			"threshold":     rp.failureThreshold,
+		},
+		OccurredAt: r.CheckedAt,
+	})
+
+	rp.timeline.Record(ctx, incident.Event{
+		IncidentID: incidentID,
+		Kind:       incident.EventIncidentCreated,
+		Message:    r.Reason,
+		Data: map[string]any{
+			"status_code": r.Status,
+			"latency_ms":  r.LatencyMs,
+			"reason":      r.Reason,
+		},
+	})
+}
+
+func (rp *ResultProcessor) recordRecovery(incidentID uuid.UUID, r executor.HTTPResult) {
+	rp.timeline.Record(rp.ctx, incident.Event{
+		IncidentID: incidentID,
+		Kind:       incident.EventRecovered,
+		Message:    "Monitor recovered",
+		Data: map[string]any{
+			"status_code": r.Status,
+			"latency_ms":  r.LatencyMs,
+		},
+		OccurredAt: r.CheckedAt,
+	})
+}
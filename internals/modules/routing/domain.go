@@ -0,0 +1,149 @@
+package routing
+
+import (
+	"slices"
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/modules/monitor"
+	"github.com/google/uuid"
+)
+
+// Time windows a rule can be limited to.
+const (
+	WindowAlways        = "always"
+	WindowBusinessHours = "business_hours"
+	WindowOffHours      = "off_hours"
+)
+
+const (
+	maxRuleNameLen = 100
+	maxRuleTags    = 20
+)
+
+// Rule routes the alerts of the team's monitors. Empty match lists match
+// anything. A matching rule with neither channels nor users suppresses
+// the alert.
+type Rule struct {
+	ID              uuid.UUID
+	TeamID          uuid.UUID
+	Name            string
+	Position        int32
+	Enabled         bool
+	MatchTags       []string
+	MatchSeverities []string
+	MatchAlertTypes []string
+	TimeWindow      string
+	Channels        []string
+	UserIDs         []uuid.UUID
+	// Stop skips the rules after this one when it matches.
+	Stop      bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type RuleInput struct {
+	Name            string
+	Position        int32
+	Enabled         bool
+	MatchTags       []string
+	MatchSeverities []string
+	MatchAlertTypes []string
+	TimeWindow      string
+	Channels        []string
+	UserIDs         []uuid.UUID
+	Stop            bool
+}
+
+// BusinessHours is the team's working week. Days are ISO weekdays
+// (1 = Monday) and the minutes count from midnight in Timezone; EndMinute
+// is exclusive.
+type BusinessHours struct {
+	TeamID      uuid.UUID
+	Timezone    string
+	Days        []int32
+	StartMinute int32
+	EndMinute   int32
+	UpdatedAt   time.Time
+}
+
+func defaultBusinessHours(teamID uuid.UUID) BusinessHours {
+	return BusinessHours{
+		TeamID:      teamID,
+		Timezone:    "UTC",
+		Days:        []int32{1, 2, 3, 4, 5},
+		StartMinute: 9 * 60,
+		EndMinute:   17 * 60,
+	}
+}
+
+// Contains reports whether t falls within business hours. An unknown
+// timezone is treated as UTC.
+func (b BusinessHours) Contains(t time.Time) bool {
+	loc, err := time.LoadLocation(b.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	weekday := int32(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	if !slices.Contains(b.Days, weekday) {
+		return false
+	}
+	minute := int32(t.Hour()*60 + t.Minute())
+	return minute >= b.StartMinute && minute < b.EndMinute
+}
+
+// Labels are the monitor fields rules match on.
+type Labels struct {
+	MonitorID uuid.UUID
+	Tags      []string
+	Severity  string
+	// NotificationChannels apply when no rule matches.
+	NotificationChannels []string
+}
+
+// matches reports whether the rule's tag, severity and alert type
+// conditions hold. The time window is checked separately since it needs
+// the team's business hours.
+func (r *Rule) matches(labels Labels, alertType string) bool {
+	if len(r.MatchTags) > 0 && !overlaps(r.MatchTags, labels.Tags) {
+		return false
+	}
+	if len(r.MatchSeverities) > 0 && !slices.Contains(r.MatchSeverities, labels.Severity) {
+		return false
+	}
+	if len(r.MatchAlertTypes) > 0 && !slices.Contains(r.MatchAlertTypes, alertType) {
+		return false
+	}
+	return true
+}
+
+// Decision is the outcome of evaluating a team's rules for one alert.
+type Decision struct {
+	Matched bool
+	// BusinessHours is whether the alert fell within business hours; only
+	// known when a matching rule has a time window.
+	BusinessHours *bool
+	Rules         []Rule
+	Channels      []string
+	UserIDs       []uuid.UUID
+	Recipients    []string
+	// Fallback holds the monitor's own channels, used when nothing matched.
+	Fallback []string
+}
+
+// overlaps reports whether any of the rule's tag filters matches one of the
+// monitor's tags; a bare key filter matches every value of that key.
+func overlaps(filters, tags []string) bool {
+	for _, f := range filters {
+		for _, t := range tags {
+			if monitor.TagMatches(f, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package routing
+
+type RuleRequest struct {
+	Name            string   `json:"name"`
+	Position        int32    `json:"position"`
+	Enabled         *bool    `json:"enabled"`
+	MatchTags       []string `json:"match_tags"`
+	MatchSeverities []string `json:"match_severities"`
+	MatchAlertTypes []string `json:"match_alert_types"`
+	TimeWindow      string   `json:"time_window"`
+	Channels        []string `json:"channels"`
+	UserIDs         []string `json:"user_ids"`
+	Stop            bool     `json:"stop"`
+}
+
+type RuleResponse struct {
+	ID              string   `json:"id"`
+	Name            string   `json:"name"`
+	Position        int32    `json:"position"`
+	Enabled         bool     `json:"enabled"`
+	MatchTags       []string `json:"match_tags"`
+	MatchSeverities []string `json:"match_severities"`
+	MatchAlertTypes []string `json:"match_alert_types"`
+	TimeWindow      string   `json:"time_window"`
+	Channels        []string `json:"channels"`
+	UserIDs         []string `json:"user_ids"`
+	Stop            bool     `json:"stop"`
+	CreatedAt       string   `json:"created_at"`
+	UpdatedAt       string   `json:"updated_at"`
+}
+
+type ListRulesResponse struct {
+	Rules []RuleResponse `json:"rules"`
+}
+
+// BusinessHoursRequest uses "HH:MM" times in the given IANA timezone.
+type BusinessHoursRequest struct {
+	Timezone string  `json:"timezone"`
+	Days     []int32 `json:"days"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+}
+
+type BusinessHoursResponse struct {
+	Timezone string  `json:"timezone"`
+	Days     []int32 `json:"days"`
+	Start    string  `json:"start"`
+	End      string  `json:"end"`
+}
+
+// EvaluateRequest dry-runs the rules for an alert of a monitor; at defaults
+// to now.
+type EvaluateRequest struct {
+	MonitorID string  `json:"monitor_id"`
+	AlertType string  `json:"alert_type"`
+	At        *string `json:"at"`
+}
+
+type MatchedRuleResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type EvaluateResponse struct {
+	Matched       bool                  `json:"matched"`
+	Suppressed    bool                  `json:"suppressed"`
+	BusinessHours *bool                 `json:"business_hours,omitempty"`
+	Rules         []MatchedRuleResponse `json:"rules"`
+	// Channels are the routed channels, or the monitor's own channels when
+	// no rule matched (empty meaning every configured plugin).
+	Channels   []string `json:"channels"`
+	Recipients []string `json:"recipients"`
+}
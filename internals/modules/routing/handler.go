@@ -0,0 +1,390 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type Handler struct {
+	service *Service
+	logger  *zerolog.Logger
+}
+
+func NewHandler(service *Service, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *Handler) ListRules(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.list_rules"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	rules, err := h.service.ListRules(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to list routing rules")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	items := make([]RuleResponse, 0, len(rules))
+	for i := range rules {
+		items = append(items, toRuleResponse(&rules[i]))
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "routing rules retrieved", ListRulesResponse{Rules: items})
+}
+
+func (h *Handler) GetRule(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.get_rule"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid rule id")
+		return
+	}
+
+	rule, err := h.service.GetRule(ctx, tm.TeamID, ruleID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get routing rule")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "routing rule retrieved", toRuleResponse(&rule))
+}
+
+func (h *Handler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.create_rule"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	in, msg := decodeRule(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	rule, err := h.service.CreateRule(ctx, tm.TeamID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to create routing rule")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	middle.SetAuditTarget(ctx, rule.ID.String())
+	utils.WriteJSON(w, http.StatusCreated, reqID, "routing rule created", toRuleResponse(&rule))
+}
+
+func (h *Handler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.update_rule"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid rule id")
+		return
+	}
+
+	in, msg := decodeRule(r)
+	if msg != "" {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, msg)
+		return
+	}
+
+	rule, err := h.service.UpdateRule(ctx, tm.TeamID, ruleID, in)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to update routing rule")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "routing rule updated", toRuleResponse(&rule))
+}
+
+func (h *Handler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.delete_rule"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	ruleID, err := uuid.Parse(chi.URLParam(r, "ruleID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid rule id")
+		return
+	}
+
+	if err := h.service.DeleteRule(ctx, tm.TeamID, ruleID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to delete routing rule")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "routing rule deleted", struct{}{})
+}
+
+func (h *Handler) GetBusinessHours(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.get_business_hours"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	bh, err := h.service.GetBusinessHours(ctx, tm.TeamID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to get business hours")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "business hours retrieved", toBusinessHoursResponse(bh))
+}
+
+func (h *Handler) SetBusinessHours(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.set_business_hours"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	var req BusinessHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	start, err := parseClock(req.Start)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "start must be a HH:MM time")
+		return
+	}
+	end, err := parseClock(req.End)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "end must be a HH:MM time")
+		return
+	}
+
+	bh, err := h.service.SetBusinessHours(ctx, BusinessHours{
+		TeamID:      tm.TeamID,
+		Timezone:    req.Timezone,
+		Days:        req.Days,
+		StartMinute: start,
+		EndMinute:   end,
+	})
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to set business hours")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "business hours updated", toBusinessHoursResponse(bh))
+}
+
+// Evaluate shows which channels and people an alert of a monitor would be
+// routed to, without sending anything.
+func (h *Handler) Evaluate(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.routing.evaluate"
+
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	tm, ok := middle.TeamMemberFromContext(ctx)
+	if !ok {
+		utils.WriteError(w, http.StatusForbidden, reqID, apperror.Forbidden, "team access required")
+		return
+	}
+
+	middle.SkipAudit(ctx)
+
+	var req EvaluateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	monitorID, err := uuid.Parse(req.MonitorID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid monitor_id")
+		return
+	}
+	alertType := alert.AlertType(req.AlertType)
+	switch alertType {
+	case "":
+		alertType = alert.AlertTypeDown
+	case alert.AlertTypeDown, alert.AlertTypeRecovered, alert.AlertTypeReminder:
+	default:
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "alert_type must be one of DOWN, RECOVERED, REMINDER")
+		return
+	}
+	at := time.Now()
+	if req.At != nil {
+		at, err = time.Parse(time.RFC3339, *req.At)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "at must be an RFC 3339 time")
+			return
+		}
+	}
+
+	d, err := h.service.Evaluate(ctx, tm.TeamID, monitorID, string(alertType), at)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("failed to evaluate routing rules")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	resp := EvaluateResponse{
+		Matched:       d.Matched,
+		BusinessHours: d.BusinessHours,
+		Rules:         make([]MatchedRuleResponse, 0, len(d.Rules)),
+		Channels:      d.Channels,
+		Recipients:    d.Recipients,
+	}
+	for _, rule := range d.Rules {
+		resp.Rules = append(resp.Rules, MatchedRuleResponse{ID: rule.ID.String(), Name: rule.Name})
+	}
+	if !d.Matched {
+		resp.Channels = d.Fallback
+	}
+	resp.Suppressed = d.Matched && len(d.Channels) == 0 && len(d.Recipients) == 0
+	if resp.Channels == nil {
+		resp.Channels = []string{}
+	}
+	if resp.Recipients == nil {
+		resp.Recipients = []string{}
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "routing evaluated", resp)
+}
+
+func decodeRule(r *http.Request) (RuleInput, string) {
+	var req RuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return RuleInput{}, "invalid request body"
+	}
+
+	in := RuleInput{
+		Name:            req.Name,
+		Position:        req.Position,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+		MatchTags:       req.MatchTags,
+		MatchSeverities: req.MatchSeverities,
+		MatchAlertTypes: req.MatchAlertTypes,
+		TimeWindow:      req.TimeWindow,
+		Channels:        req.Channels,
+		Stop:            req.Stop,
+	}
+	for _, raw := range req.UserIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return RuleInput{}, "invalid user id: " + raw
+		}
+		in.UserIDs = append(in.UserIDs, id)
+	}
+	return in, ""
+}
+
+func toRuleResponse(rule *Rule) RuleResponse {
+	userIDs := make([]string, 0, len(rule.UserIDs))
+	for _, id := range rule.UserIDs {
+		userIDs = append(userIDs, id.String())
+	}
+	return RuleResponse{
+		ID:              rule.ID.String(),
+		Name:            rule.Name,
+		Position:        rule.Position,
+		Enabled:         rule.Enabled,
+		MatchTags:       rule.MatchTags,
+		MatchSeverities: rule.MatchSeverities,
+		MatchAlertTypes: rule.MatchAlertTypes,
+		TimeWindow:      rule.TimeWindow,
+		Channels:        rule.Channels,
+		UserIDs:         userIDs,
+		Stop:            rule.Stop,
+		CreatedAt:       rule.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:       rule.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toBusinessHoursResponse(bh BusinessHours) BusinessHoursResponse {
+	return BusinessHoursResponse{
+		Timezone: bh.Timezone,
+		Days:     bh.Days,
+		Start:    formatClock(bh.StartMinute),
+		End:      formatClock(bh.EndMinute),
+	}
+}
+
+// parseClock turns "HH:MM" into minutes after midnight; "24:00" is allowed
+// as an end of day.
+func parseClock(s string) (int32, error) {
+	var h, m int32
+	if _, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil {
+		return 0, err
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+func formatClock(minutes int32) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
@@ -0,0 +1,266 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+func (r *Repository) ListRules(ctx context.Context, teamID uuid.UUID) ([]Rule, error) {
+	const op string = "repo.routing.list_rules"
+
+	rows, err := r.querier.ListRoutingRules(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toRules(rows), nil
+}
+
+// ListEnabledRules returns the team's enabled rules in evaluation order.
+func (r *Repository) ListEnabledRules(ctx context.Context, teamID uuid.UUID) ([]Rule, error) {
+	const op string = "repo.routing.list_enabled_rules"
+
+	rows, err := r.querier.ListEnabledRoutingRules(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toRules(rows), nil
+}
+
+func (r *Repository) GetRule(ctx context.Context, teamID, ruleID uuid.UUID) (Rule, error) {
+	const op string = "repo.routing.get_rule"
+
+	row, err := r.querier.GetRoutingRule(ctx, db.GetRoutingRuleParams{
+		ID:     utils.ToPgUUID(ruleID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rule{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "routing rule not found"}
+		}
+		return Rule{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toRule(row), nil
+}
+
+func (r *Repository) CreateRule(ctx context.Context, teamID uuid.UUID, in RuleInput) (Rule, error) {
+	const op string = "repo.routing.create_rule"
+
+	row, err := r.querier.CreateRoutingRule(ctx, db.CreateRoutingRuleParams{
+		TeamID:          utils.ToPgUUID(teamID),
+		Name:            in.Name,
+		Position:        in.Position,
+		Enabled:         in.Enabled,
+		MatchTags:       in.MatchTags,
+		MatchSeverities: in.MatchSeverities,
+		MatchAlertTypes: in.MatchAlertTypes,
+		TimeWindow:      in.TimeWindow,
+		Channels:        in.Channels,
+		UserIds:         toPgUUIDs(in.UserIDs),
+		Stop:            in.Stop,
+	})
+	if err != nil {
+		return Rule{}, r.writeError(op, err)
+	}
+	return toRule(row), nil
+}
+
+func (r *Repository) UpdateRule(ctx context.Context, teamID, ruleID uuid.UUID, in RuleInput) (Rule, error) {
+	const op string = "repo.routing.update_rule"
+
+	row, err := r.querier.UpdateRoutingRule(ctx, db.UpdateRoutingRuleParams{
+		ID:              utils.ToPgUUID(ruleID),
+		TeamID:          utils.ToPgUUID(teamID),
+		Name:            in.Name,
+		Position:        in.Position,
+		Enabled:         in.Enabled,
+		MatchTags:       in.MatchTags,
+		MatchSeverities: in.MatchSeverities,
+		MatchAlertTypes: in.MatchAlertTypes,
+		TimeWindow:      in.TimeWindow,
+		Channels:        in.Channels,
+		UserIds:         toPgUUIDs(in.UserIDs),
+		Stop:            in.Stop,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rule{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "routing rule not found"}
+		}
+		return Rule{}, r.writeError(op, err)
+	}
+	return toRule(row), nil
+}
+
+func (r *Repository) DeleteRule(ctx context.Context, teamID, ruleID uuid.UUID) error {
+	const op string = "repo.routing.delete_rule"
+
+	n, err := r.querier.DeleteRoutingRule(ctx, db.DeleteRoutingRuleParams{
+		ID:     utils.ToPgUUID(ruleID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "routing rule not found"}
+	}
+	return nil
+}
+
+// GetBusinessHours returns the team's business hours, or the default
+// Monday to Friday, 09:00 to 17:00 UTC when none are set.
+func (r *Repository) GetBusinessHours(ctx context.Context, teamID uuid.UUID) (BusinessHours, error) {
+	const op string = "repo.routing.get_business_hours"
+
+	row, err := r.querier.GetTeamBusinessHours(ctx, utils.ToPgUUID(teamID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return defaultBusinessHours(teamID), nil
+		}
+		return BusinessHours{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toBusinessHours(row), nil
+}
+
+func (r *Repository) SetBusinessHours(ctx context.Context, bh BusinessHours) (BusinessHours, error) {
+	const op string = "repo.routing.set_business_hours"
+
+	row, err := r.querier.UpsertTeamBusinessHours(ctx, db.UpsertTeamBusinessHoursParams{
+		TeamID:      utils.ToPgUUID(bh.TeamID),
+		Timezone:    bh.Timezone,
+		Days:        bh.Days,
+		StartMinute: bh.StartMinute,
+		EndMinute:   bh.EndMinute,
+	})
+	if err != nil {
+		return BusinessHours{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return toBusinessHours(row), nil
+}
+
+// MonitorLabels loads what rules match on for one of the team's monitors.
+// found is false when the monitor doesn't exist or belongs to another team.
+func (r *Repository) MonitorLabels(ctx context.Context, teamID, monitorID uuid.UUID) (Labels, bool, error) {
+	const op string = "repo.routing.monitor_labels"
+
+	row, err := r.querier.GetMonitorRoutingLabels(ctx, db.GetMonitorRoutingLabelsParams{
+		ID:     utils.ToPgUUID(monitorID),
+		TeamID: utils.ToPgUUID(teamID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Labels{}, false, nil
+		}
+		return Labels{}, false, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	channels := []string{}
+	if row.NotificationChannels != "" {
+		channels = strings.Split(row.NotificationChannels, ",")
+	}
+	return Labels{
+		MonitorID:            utils.FromPgUUID(row.ID),
+		Tags:                 row.Tags,
+		Severity:             row.Severity,
+		NotificationChannels: channels,
+	}, true, nil
+}
+
+// MemberEmails maps the given user ids to the emails of those that are
+// active members of the team. Unknown or inactive users are left out.
+func (r *Repository) MemberEmails(ctx context.Context, teamID uuid.UUID, userIDs []uuid.UUID) (map[uuid.UUID]string, error) {
+	const op string = "repo.routing.member_emails"
+
+	rows, err := r.querier.ListActiveTeamMemberEmails(ctx, db.ListActiveTeamMemberEmailsParams{
+		TeamID:  utils.ToPgUUID(teamID),
+		Column2: toPgUUIDs(userIDs),
+	})
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	emails := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		emails[utils.FromPgUUID(row.ID)] = row.Email
+	}
+	return emails, nil
+}
+
+func (r *Repository) writeError(op string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return &apperror.Error{Kind: apperror.AlreadyExists, Op: op, Message: "a routing rule with this name already exists"}
+	}
+	return utils.WrapRepoError(op, err, r.logger)
+}
+
+func toRules(rows []db.RoutingRule) []Rule {
+	rules := make([]Rule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, toRule(row))
+	}
+	return rules
+}
+
+func toRule(row db.RoutingRule) Rule {
+	userIDs := make([]uuid.UUID, 0, len(row.UserIds))
+	for _, id := range row.UserIds {
+		userIDs = append(userIDs, utils.FromPgUUID(id))
+	}
+	return Rule{
+		ID:              utils.FromPgUUID(row.ID),
+		TeamID:          utils.FromPgUUID(row.TeamID),
+		Name:            row.Name,
+		Position:        row.Position,
+		Enabled:         row.Enabled,
+		MatchTags:       row.MatchTags,
+		MatchSeverities: row.MatchSeverities,
+		MatchAlertTypes: row.MatchAlertTypes,
+		TimeWindow:      row.TimeWindow,
+		Channels:        row.Channels,
+		UserIDs:         userIDs,
+		Stop:            row.Stop,
+		CreatedAt:       utils.FromPgTimestamptz(row.CreatedAt),
+		UpdatedAt:       utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+}
+
+func toBusinessHours(row db.TeamBusinessHour) BusinessHours {
+	return BusinessHours{
+		TeamID:      utils.FromPgUUID(row.TeamID),
+		Timezone:    row.Timezone,
+		Days:        row.Days,
+		StartMinute: row.StartMinute,
+		EndMinute:   row.EndMinute,
+		UpdatedAt:   utils.FromPgTimestamptz(row.UpdatedAt),
+	}
+}
+
+func toPgUUIDs(ids []uuid.UUID) []pgtype.UUID {
+	out := make([]pgtype.UUID, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, utils.ToPgUUID(id))
+	}
+	return out
+}
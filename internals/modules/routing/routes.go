@@ -0,0 +1,18 @@
+package routing
+
+import "github.com/go-chi/chi/v5"
+
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.ListRules)
+	r.Post("/", h.CreateRule)
+	r.Get("/business-hours", h.GetBusinessHours)
+	r.Put("/business-hours", h.SetBusinessHours)
+	r.Post("/evaluate", h.Evaluate)
+	r.Get("/{ruleID}", h.GetRule)
+	r.Put("/{ruleID}", h.UpdateRule)
+	r.Delete("/{ruleID}", h.DeleteRule)
+
+	return r
+}
@@ -0,0 +1,286 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	// business hours are evaluated in the team's timezone, which must not
+	// depend on the zoneinfo of the host
+	_ "time/tzdata"
+
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/monitor"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ChannelRegistry is satisfied by *alert.Registry.
+type ChannelRegistry interface {
+	KnownChannel(ref string, instances []alert.PluginInstance) bool
+}
+
+// PluginLister is satisfied by *plugin.Repository.
+type PluginLister interface {
+	ListPluginInstances(ctx context.Context, teamID uuid.UUID) ([]alert.PluginInstance, error)
+}
+
+// Service manages a team's notification routing rules and evaluates them
+// for the alert service before an alert is enqueued.
+type Service struct {
+	repo     *Repository
+	channels ChannelRegistry
+	plugins  PluginLister
+	logger   *zerolog.Logger
+}
+
+func NewService(repo *Repository, channels ChannelRegistry, plugins PluginLister, logger *zerolog.Logger) *Service {
+	return &Service{
+		repo:     repo,
+		channels: channels,
+		plugins:  plugins,
+		logger:   logger,
+	}
+}
+
+// Route evaluates the team's rules for an alert. A monitor that no longer
+// exists is reported as unmatched.
+func (s *Service) Route(ctx context.Context, in alert.RoutingInput) (alert.Routing, error) {
+	d, err := s.Evaluate(ctx, in.TeamID, in.MonitorID, string(in.Type), in.At)
+	if err != nil {
+		var appErr *apperror.Error
+		if errors.As(err, &appErr) && appErr.Kind == apperror.NotFound {
+			return alert.Routing{}, nil
+		}
+		return alert.Routing{}, err
+	}
+	if !d.Matched {
+		return alert.Routing{}, nil
+	}
+
+	names := make([]string, 0, len(d.Rules))
+	for _, r := range d.Rules {
+		names = append(names, r.Name)
+	}
+	return alert.Routing{
+		Matched:    true,
+		Channels:   d.Channels,
+		Recipients: d.Recipients,
+		Rules:      names,
+	}, nil
+}
+
+// Evaluate walks the team's enabled rules in position order. Every rule
+// whose conditions hold contributes its channels and users, until a
+// matching rule has Stop set.
+func (s *Service) Evaluate(ctx context.Context, teamID, monitorID uuid.UUID, alertType string, at time.Time) (Decision, error) {
+	const op = "service.routing.evaluate"
+
+	labels, found, err := s.repo.MonitorLabels(ctx, teamID, monitorID)
+	if err != nil {
+		return Decision{}, err
+	}
+	if !found {
+		return Decision{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "monitor not found"}
+	}
+	d := Decision{Fallback: labels.NotificationChannels}
+
+	rules, err := s.repo.ListEnabledRules(ctx, teamID)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.matches(labels, alertType) {
+			continue
+		}
+		if rule.TimeWindow != WindowAlways {
+			if d.BusinessHours == nil {
+				bh, err := s.repo.GetBusinessHours(ctx, teamID)
+				if err != nil {
+					return Decision{}, err
+				}
+				in := bh.Contains(at)
+				d.BusinessHours = &in
+			}
+			if (rule.TimeWindow == WindowBusinessHours) != *d.BusinessHours {
+				continue
+			}
+		}
+
+		d.Matched = true
+		d.Rules = append(d.Rules, *rule)
+		d.Channels = union(d.Channels, rule.Channels)
+		d.UserIDs = union(d.UserIDs, rule.UserIDs)
+		if rule.Stop {
+			break
+		}
+	}
+
+	if len(d.UserIDs) > 0 {
+		emails, err := s.repo.MemberEmails(ctx, teamID, d.UserIDs)
+		if err != nil {
+			return Decision{}, err
+		}
+		for _, id := range d.UserIDs {
+			if email, ok := emails[id]; ok {
+				d.Recipients = append(d.Recipients, email)
+			} else {
+				s.logger.Warn().
+					Str("team_id", teamID.String()).
+					Str("user_id", id.String()).
+					Msg("routing rule names a user who is no longer an active team member")
+			}
+		}
+	}
+	return d, nil
+}
+
+func (s *Service) ListRules(ctx context.Context, teamID uuid.UUID) ([]Rule, error) {
+	return s.repo.ListRules(ctx, teamID)
+}
+
+func (s *Service) GetRule(ctx context.Context, teamID, ruleID uuid.UUID) (Rule, error) {
+	return s.repo.GetRule(ctx, teamID, ruleID)
+}
+
+func (s *Service) CreateRule(ctx context.Context, teamID uuid.UUID, in RuleInput) (Rule, error) {
+	const op = "service.routing.create_rule"
+
+	in, err := s.validateRule(ctx, op, teamID, in)
+	if err != nil {
+		return Rule{}, err
+	}
+	return s.repo.CreateRule(ctx, teamID, in)
+}
+
+func (s *Service) UpdateRule(ctx context.Context, teamID, ruleID uuid.UUID, in RuleInput) (Rule, error) {
+	const op = "service.routing.update_rule"
+
+	in, err := s.validateRule(ctx, op, teamID, in)
+	if err != nil {
+		return Rule{}, err
+	}
+	return s.repo.UpdateRule(ctx, teamID, ruleID, in)
+}
+
+func (s *Service) DeleteRule(ctx context.Context, teamID, ruleID uuid.UUID) error {
+	return s.repo.DeleteRule(ctx, teamID, ruleID)
+}
+
+func (s *Service) GetBusinessHours(ctx context.Context, teamID uuid.UUID) (BusinessHours, error) {
+	return s.repo.GetBusinessHours(ctx, teamID)
+}
+
+func (s *Service) SetBusinessHours(ctx context.Context, bh BusinessHours) (BusinessHours, error) {
+	const op = "service.routing.set_business_hours"
+
+	invalid := func(msg string) (BusinessHours, error) {
+		return BusinessHours{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	if bh.Timezone == "" {
+		bh.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(bh.Timezone); err != nil {
+		return invalid(fmt.Sprintf("unknown timezone %q", bh.Timezone))
+	}
+	bh.Days = union(nil, bh.Days)
+	if len(bh.Days) == 0 {
+		return invalid("name at least one business day")
+	}
+	for _, d := range bh.Days {
+		if d < 1 || d > 7 {
+			return invalid("days must be ISO weekdays between 1 (Monday) and 7 (Sunday)")
+		}
+	}
+	slices.Sort(bh.Days)
+	if bh.StartMinute < 0 || bh.EndMinute > 24*60 || bh.StartMinute >= bh.EndMinute {
+		return invalid("business hours must start before they end")
+	}
+	return s.repo.SetBusinessHours(ctx, bh)
+}
+
+func (s *Service) validateRule(ctx context.Context, op string, teamID uuid.UUID, in RuleInput) (RuleInput, error) {
+	invalid := func(msg string) (RuleInput, error) {
+		return RuleInput{}, &apperror.Error{Kind: apperror.InvalidInput, Op: op, Message: msg}
+	}
+
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" {
+		return invalid("name is required")
+	}
+	if len(in.Name) > maxRuleNameLen {
+		return invalid(fmt.Sprintf("name must be at most %d characters", maxRuleNameLen))
+	}
+
+	in.MatchTags = monitor.NormalizeTags(in.MatchTags)
+	if len(in.MatchTags) > maxRuleTags {
+		return invalid(fmt.Sprintf("a rule can match at most %d tags", maxRuleTags))
+	}
+
+	in.MatchSeverities = union([]string{}, in.MatchSeverities)
+	for _, sev := range in.MatchSeverities {
+		if !monitor.ValidSeverity(sev) {
+			return invalid(fmt.Sprintf("unknown severity %q", sev))
+		}
+	}
+
+	in.MatchAlertTypes = union([]string{}, in.MatchAlertTypes)
+	for _, t := range in.MatchAlertTypes {
+		switch alert.AlertType(t) {
+		case alert.AlertTypeDown, alert.AlertTypeRecovered, alert.AlertTypeReminder:
+		default:
+			return invalid(fmt.Sprintf("unknown alert type %q", t))
+		}
+	}
+
+	switch in.TimeWindow {
+	case "":
+		in.TimeWindow = WindowAlways
+	case WindowAlways, WindowBusinessHours, WindowOffHours:
+	default:
+		return invalid("time_window must be one of always, business_hours, off_hours")
+	}
+
+	in.Channels = union([]string{}, in.Channels)
+	if len(in.Channels) > 0 {
+		instances, err := s.plugins.ListPluginInstances(ctx, teamID)
+		if err != nil {
+			return RuleInput{}, err
+		}
+		for _, c := range in.Channels {
+			if !s.channels.KnownChannel(c, instances) {
+				return invalid(fmt.Sprintf("unknown channel %q", c))
+			}
+		}
+	}
+
+	in.UserIDs = union([]uuid.UUID{}, in.UserIDs)
+	if len(in.UserIDs) > 0 {
+		emails, err := s.repo.MemberEmails(ctx, teamID, in.UserIDs)
+		if err != nil {
+			return RuleInput{}, err
+		}
+		for _, id := range in.UserIDs {
+			if _, ok := emails[id]; !ok {
+				return invalid(fmt.Sprintf("user %s is not an active member of this team", id))
+			}
+		}
+	}
+	return in, nil
+}
+
+// union appends the values of add missing from dst, keeping their order.
+func union[T comparable](dst, add []T) []T {
+	for _, v := range add {
+		if !slices.Contains(dst, v) {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
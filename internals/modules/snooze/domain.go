@@ -0,0 +1,21 @@
+package snooze
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ended is a monitor whose snooze ran out and that asked to be told.
+type Ended struct {
+	MonitorID            uuid.UUID
+	TeamID               uuid.UUID
+	MonitorURL           string
+	MonitorName          string
+	RunbookURL           string
+	NotificationChannels []string
+	SnoozedUntil         time.Time
+	// Enabled is false when the monitor was disabled while snoozed; such
+	// a claim is dropped instead of sent.
+	Enabled bool
+}
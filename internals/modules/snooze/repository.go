@@ -0,0 +1,53 @@
+package snooze
+
+import (
+	"context"
+	"strings"
+
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/rs/zerolog"
+)
+
+type Repository struct {
+	querier *db.Queries
+	logger  *zerolog.Logger
+}
+
+func NewRepository(dbExecutor db.DBTX, logger *zerolog.Logger) *Repository {
+	return &Repository{
+		querier: db.New(dbExecutor),
+		logger:  logger,
+	}
+}
+
+// ClaimEnded claims up to limit monitors whose snooze has ended and clears
+// their notify flag, so each end is notified once even with several API
+// instances running.
+func (r *Repository) ClaimEnded(ctx context.Context, limit int) ([]Ended, error) {
+	const op string = "repo.snooze.claim_ended"
+
+	rows, err := r.querier.ClaimEndedSnoozes(ctx, int32(limit))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+
+	ended := make([]Ended, 0, len(rows))
+	for _, row := range rows {
+		var channels []string
+		if row.NotificationChannels != "" {
+			channels = strings.Split(row.NotificationChannels, ",")
+		}
+		ended = append(ended, Ended{
+			MonitorID:            utils.FromPgUUID(row.ID),
+			TeamID:               utils.FromPgUUID(row.TeamID),
+			MonitorURL:           row.Url,
+			MonitorName:          row.Name,
+			RunbookURL:           row.RunbookUrl,
+			NotificationChannels: channels,
+			SnoozedUntil:         row.SnoozedUntil.Time,
+			Enabled:              row.Enabled,
+		})
+	}
+	return ended, nil
+}
@@ -0,0 +1,121 @@
+package snooze
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/alkush-pipania/sofon/config"
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/rs/zerolog"
+)
+
+// Alerter is satisfied by *alert.AlertService.
+type Alerter interface {
+	Notify(ctx context.Context, event alert.AlertEvent) error
+}
+
+// Service tells a monitor's channels when its snooze ends. Snoozes end on
+// their own, since muting only compares against snoozed_until; this worker
+// just sends the notice for monitors snoozed with notify set.
+type Service struct {
+	pollInterval time.Duration
+	batchSize    int
+
+	workerWG sync.WaitGroup
+	stop     chan struct{}
+
+	repo    *Repository
+	alerter Alerter
+	logger  *zerolog.Logger
+}
+
+func NewService(
+	snoozeConfig *config.SnoozeConfig,
+	repo *Repository,
+	alerter Alerter,
+	logger *zerolog.Logger,
+) *Service {
+	return &Service{
+		pollInterval: snoozeConfig.PollInterval,
+		batchSize:    snoozeConfig.BatchSize,
+		stop:         make(chan struct{}),
+		repo:         repo,
+		alerter:      alerter,
+		logger:       logger,
+	}
+}
+
+func (s *Service) Start() {
+	s.workerWG.Add(1)
+	go s.run()
+	s.logger.Info().Msg("Snooze worker started")
+}
+
+// Stop signals the worker to exit once its current batch is done.
+func (s *Service) Stop() {
+	close(s.stop)
+}
+
+func (s *Service) WorkerClosingWait() {
+	s.workerWG.Wait()
+}
+
+func (s *Service) run() {
+	defer s.workerWG.Done()
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.sendBatch() {
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendBatch notifies one batch of ended snoozes and reports whether the
+// batch was full.
+func (s *Service) sendBatch() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ended, err := s.repo.ClaimEnded(ctx, s.batchSize)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("failed to claim ended snoozes")
+		return false
+	}
+
+	for _, e := range ended {
+		if !e.Enabled {
+			continue
+		}
+		s.send(ctx, e)
+	}
+	return len(ended) == s.batchSize
+}
+
+func (s *Service) send(ctx context.Context, e Ended) {
+	err := s.alerter.Notify(ctx, alert.AlertEvent{
+		Type:                 alert.AlertTypeSnoozeEnded,
+		MonitorID:            e.MonitorID,
+		TeamID:               e.TeamID,
+		MonitorURL:           e.MonitorURL,
+		MonitorName:          e.MonitorName,
+		RunbookURL:           e.RunbookURL,
+		NotificationChannels: e.NotificationChannels,
+		CheckedAt:            e.SnoozedUntil,
+	})
+	if err != nil {
+		// the claim already cleared the flag; the notice isn't retried
+		s.logger.Error().
+			Err(err).
+			Str("monitor_id", e.MonitorID.String()).
+			Msg("failed to send snooze ended notice")
+	}
+}
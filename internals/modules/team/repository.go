@@ -6,6 +6,7 @@ import (
 	"time"
 
 	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
 	"github.com/alkush-pipania/sofon/pkg/db"
 	"github.com/alkush-pipania/sofon/pkg/utils"
 	"github.com/google/uuid"
@@ -257,17 +258,22 @@ func (r *repository) AcceptInvitation(ctx context.Context, token string) error {
 	return nil
 }
 
-func (r *repository) DeleteInvitation(ctx context.Context, teamID, invID uuid.UUID) error {
+// DeleteInvitation deletes a pending invitation and returns its email and
+// role.
+func (r *repository) DeleteInvitation(ctx context.Context, teamID, invID uuid.UUID) (Invitation, error) {
 	const op = "repo.team.delete_invitation"
 
-	err := r.querier.DeleteInvitation(ctx, db.DeleteInvitationParams{
+	row, err := r.querier.DeleteInvitation(ctx, db.DeleteInvitationParams{
 		ID:     utils.ToPgUUID(invID),
 		TeamID: utils.ToPgUUID(teamID),
 	})
 	if err != nil {
-		return utils.WrapRepoError(op, err, r.logger)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Invitation{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "invitation not found"}
+		}
+		return Invitation{}, utils.WrapRepoError(op, err, r.logger)
 	}
-	return nil
+	return Invitation{ID: invID, TeamID: teamID, Email: row.Email, Role: row.Role}, nil
 }
 
 func (r *repository) CreateUser(ctx context.Context, email, name, passwordHash, role string) (uuid.UUID, error) {
@@ -5,7 +5,7 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func Routes(h *Handler, authMW *middle.AuthMiddleware, teamAccessMW *middle.TeamAccessMiddleware, teamScoped ...func(chi.Router)) chi.Router {
+func Routes(h *Handler, authMW *middle.AuthMiddleware, teamAccessMW *middle.TeamAccessMiddleware, auditMW *middle.AuditMiddleware, teamScoped ...func(chi.Router)) chi.Router {
 	r := chi.NewRouter()
 
 	// Public — used by the invite accept page (token is globally unique)
@@ -16,9 +16,10 @@ func Routes(h *Handler, authMW *middle.AuthMiddleware, teamAccessMW *middle.Team
 	r.With(authMW.Handle).Get("/", h.ListMyTeams)
 	r.With(authMW.Handle).Post("/", h.CreateTeam)
 
-	// Team-scoped — requires auth + team membership
+	// Team-scoped — requires auth + team membership; changes are audited
 	r.With(authMW.Handle).Route("/{teamID}", func(r chi.Router) {
 		r.Use(teamAccessMW.Handle)
+		r.Use(auditMW.Handle)
 
 		r.Get("/", h.GetTeam)
 		r.With(middle.RequireTeamRole(RoleOwner, RoleAdmin)).Put("/", h.UpdateTeam)
@@ -12,17 +12,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// SessionRevoker is implemented by the user service.
+type SessionRevoker interface {
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+}
+
 type Service struct {
-	repo    *repository
-	hashSvc func(string) (string, error)
-	appURL  string
+	repo     *repository
+	hashSvc  func(string) (string, error)
+	appURL   string
+	sessions SessionRevoker
 }
 
-func NewService(repo *repository, appURL string) *Service {
+func NewService(repo *repository, appURL string, sessions SessionRevoker) *Service {
 	return &Service{
-		repo:    repo,
-		hashSvc: security.HashPassword,
-		appURL:  appURL,
+		repo:     repo,
+		hashSvc:  security.HashPassword,
+		appURL:   appURL,
+		sessions: sessions,
 	}
 }
 
@@ -47,7 +54,16 @@ func (s *Service) GetTeam(ctx context.Context, teamID uuid.UUID) (Team, error) {
 }
 
 func (s *Service) UpdateTeamName(ctx context.Context, teamID uuid.UUID, name string) error {
-	return s.repo.UpdateTeamName(ctx, teamID, name)
+	team, err := s.repo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdateTeamName(ctx, teamID, name); err != nil {
+		return err
+	}
+
+	middle.SetAuditChange(ctx, map[string]any{"name": team.Name}, map[string]any{"name": name})
+	return nil
 }
 
 func (s *Service) GetMembership(ctx context.Context, userID, teamID uuid.UUID) (middle.TeamMemberCtx, error) {
@@ -68,7 +84,28 @@ func (s *Service) SetMemberActive(ctx context.Context, teamID, targetID, callerI
 			Message: "you cannot change your own status",
 		}
 	}
-	return s.repo.SetMemberActive(ctx, teamID, targetID, active)
+
+	member, err := s.repo.GetMembership(ctx, targetID, teamID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetMemberActive(ctx, teamID, targetID, active); err != nil {
+		return err
+	}
+	if !active {
+		// sign them out so their tokens can't outlive the decision
+		if err := s.sessions.RevokeAllSessions(ctx, targetID); err != nil {
+			return err
+		}
+	}
+
+	if active {
+		middle.SetAuditAction(ctx, "members.activate")
+	} else {
+		middle.SetAuditAction(ctx, "members.deactivate")
+	}
+	middle.SetAuditChange(ctx, map[string]any{"is_active": member.IsActive, "role": member.Role}, map[string]any{"is_active": active, "role": member.Role})
+	return nil
 }
 
 func (s *Service) CreateInvitation(ctx context.Context, cmd CreateInvitationCmd) (Invitation, error) {
@@ -85,7 +122,14 @@ func (s *Service) CreateInvitation(ctx context.Context, cmd CreateInvitationCmd)
 	}
 
 	expiresAt := time.Now().Add(48 * time.Hour)
-	return s.repo.CreateInvitation(ctx, cmd, token, expiresAt)
+	inv, err := s.repo.CreateInvitation(ctx, cmd, token, expiresAt)
+	if err != nil {
+		return Invitation{}, err
+	}
+
+	middle.SetAuditTarget(ctx, inv.ID.String())
+	middle.SetAuditChange(ctx, nil, map[string]any{"email": inv.Email, "role": inv.Role})
+	return inv, nil
 }
 
 func (s *Service) ListInvitations(ctx context.Context, teamID uuid.UUID) ([]Invitation, error) {
@@ -93,7 +137,13 @@ func (s *Service) ListInvitations(ctx context.Context, teamID uuid.UUID) ([]Invi
 }
 
 func (s *Service) RevokeInvitation(ctx context.Context, teamID, invID uuid.UUID) error {
-	return s.repo.DeleteInvitation(ctx, teamID, invID)
+	inv, err := s.repo.DeleteInvitation(ctx, teamID, invID)
+	if err != nil {
+		return err
+	}
+
+	middle.SetAuditChange(ctx, map[string]any{"email": inv.Email, "role": inv.Role}, nil)
+	return nil
 }
 
 func (s *Service) GetInvitationByToken(ctx context.Context, token string) (Invitation, error) {
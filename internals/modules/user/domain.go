@@ -1,6 +1,10 @@
 package user
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type User struct {
 	ID            uuid.UUID
@@ -29,9 +33,42 @@ type CreateUserCmd struct {
 type LogInUserCmd struct {
 	Email    string
 	Password string
+	Client   ClientInfo
 }
 
+// LogInUserResult is a fresh pair of tokens for a session, from signing in
+// or refreshing.
 type LogInUserResult struct {
+	UserID       uuid.UUID
+	SessionID    uuid.UUID
+	AccessToken  string
+	RefreshToken string
+	// ExpiresIn is how long the access token is valid.
+	ExpiresIn time.Duration
+}
+
+// ClientInfo describes the device a session was started or last
+// refreshed from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// Session is a sign-in that can still be refreshed.
+type Session struct {
+	ID         uuid.UUID
+	UserAgent  string
+	IP         string
+	ExpiresAt  time.Time
+	LastUsedAt time.Time
+	CreatedAt  time.Time
+}
+
+// sessionRecord is a session looked up by refresh token.
+type sessionRecord struct {
+	ID          uuid.UUID
 	UserID      uuid.UUID
-	AccessToken string
+	RefreshHash string
+	ExpiresAt   time.Time
+	Revoked     bool
 }
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 type GetProfileResponse struct {
 	ID            string            `json:"id"`
 	Name          string            `json:"name"`
@@ -31,8 +33,26 @@ type LogInRequest struct {
 }
 
 type LogInResponse struct {
-	UserID      string `json:"user_id"`
-	AccessToken string `json:"access_token"`
+	UserID       string `json:"user_id"`
+	SessionID    string `json:"session_id"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	// ExpiresIn is the access token's lifetime in seconds.
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	Current    bool      `json:"current"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	CreatedAt  time.Time `json:"created_at"`
 }
 
 type SetupStatusResponse struct {
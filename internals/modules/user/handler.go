@@ -80,6 +80,7 @@ func (h *Handler) LogIn(w http.ResponseWriter, r *http.Request) {
 	res, err := h.service.LogIn(ctx, LogInUserCmd{
 		Email:    req.Email,
 		Password: req.Password,
+		Client:   clientInfo(r),
 	})
 	if err != nil {
 		h.logger.Error().
@@ -90,12 +91,7 @@ func (h *Handler) LogIn(w http.ResponseWriter, r *http.Request) {
 		utils.FromAppError(w, reqID, err)
 		return
 	}
-	result := LogInResponse{
-		UserID:      res.UserID.String(),
-		AccessToken: res.AccessToken,
-	}
-
-	utils.WriteJSON(w, http.StatusOK, reqID, "user registered", result)
+	utils.WriteJSON(w, http.StatusOK, reqID, "user registered", toLogInResponse(res))
 }
 
 func (h *Handler) SetupStatus(w http.ResponseWriter, r *http.Request) {
@@ -185,7 +181,7 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, reqID, "password changed", struct{}{})
+	utils.WriteJSON(w, http.StatusOK, reqID, "password changed, sign in again", struct{}{})
 }
 
 func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
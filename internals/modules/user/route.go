@@ -5,15 +5,26 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func Routes(h *Handler, authMW *middle.AuthMiddleware) chi.Router {
+func Routes(h *Handler, authMW *middle.AuthMiddleware, authenticated ...func(chi.Router)) chi.Router {
 	r := chi.NewRouter()
 
 	r.Get("/setup-status", h.SetupStatus)
 	r.Post("/register", h.Register)
 	r.Post("/login", h.LogIn)
+	r.Post("/refresh", h.Refresh)
+	r.With(authMW.Handle).Post("/logout", h.LogOut)
 	r.With(authMW.Handle).Get("/get-profile", h.GetProfile)
 	r.With(authMW.Handle).Patch("/profile", h.UpdateProfile)
 	r.With(authMW.Handle).Post("/change-password", h.ChangePassword)
+	r.With(authMW.Handle).Get("/sessions", h.ListSessions)
+	r.With(authMW.Handle).Delete("/sessions/{sessionID}", h.RevokeSession)
+
+	// Additional routers for the signed-in user (API tokens)
+	r.With(authMW.Handle).Group(func(r chi.Router) {
+		for _, fn := range authenticated {
+			fn(r)
+		}
+	})
 
 	return r
 }
@@ -114,28 +114,8 @@ func (s *Service) LogIn(ctx context.Context, data LogInUserCmd) (LogInUserResult
 			Message: "incorrect email or password",
 		}
 	}
-	payload := security.RequestClaims{
-		UserID: u.ID.String(),
-		Email:  u.Email,
-		Role:   u.Role,
-	}
 
-	// generate JWT Token with 30 min expiry (as it is just basics)
-	token, err := s.tokenSvc.GenerateAccessToken(payload) // sceret , expiry is there in token service, we just pass payload
-	if err != nil {
-		return LogInUserResult{}, &apperror.Error{
-			Kind:    apperror.Internal,
-			Op:      op,
-			Message: "internal server error",
-			Err:     err,
-		}
-	}
-
-	res := LogInUserResult{
-		UserID:      u.ID,
-		AccessToken: token,
-	}
-	return res, nil
+	return s.startSession(ctx, u, data.Client)
 }
 
 func (s *Service) GetProfile(ctx context.Context, userId uuid.UUID) (User, []UserTeam, error) {
@@ -190,7 +170,12 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, currentP
 		return &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal error", Err: err}
 	}
 
-	return s.repo.UpdateUserPassword(ctx, userID, newHash)
+	if err := s.repo.UpdateUserPassword(ctx, userID, newHash); err != nil {
+		return err
+	}
+
+	// whoever knew the old password may hold a session; end them all
+	return s.repo.RevokeAllUserSessions(ctx, userID)
 }
 
 func (s *Service) IsUserActive(ctx context.Context, userID uuid.UUID) (bool, error) {
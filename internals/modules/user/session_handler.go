@@ -0,0 +1,151 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+
+	middle "github.com/alkush-pipania/sofon/internals/middleware"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+)
+
+// Refresh trades a refresh token for a new pair of tokens.
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.user.refresh"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+	if err := h.validator.Struct(req); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	res, err := h.service.Refresh(ctx, req.RefreshToken, clientInfo(r))
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("refresh error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "tokens refreshed", toLogInResponse(res))
+}
+
+// LogOut ends the session the request's access token belongs to.
+func (h *Handler) LogOut(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.user.logout"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, sessionID, ok := sessionFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	if err := h.service.RevokeSession(ctx, userID, sessionID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("logout error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "logged out", struct{}{})
+}
+
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.user.list_sessions"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, currentID, ok := sessionFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	sessions, err := h.service.ListSessions(ctx, userID)
+	if err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("list sessions error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	res := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		res = append(res, SessionResponse{
+			ID:         s.ID.String(),
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			Current:    s.ID == currentID,
+			ExpiresAt:  s.ExpiresAt,
+			LastUsedAt: s.LastUsedAt,
+			CreatedAt:  s.CreatedAt,
+		})
+	}
+	utils.WriteJSON(w, http.StatusOK, reqID, "sessions retrieved", res)
+}
+
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	const op string = "handler.user.revoke_session"
+	ctx := r.Context()
+	reqID := middleware.GetReqID(ctx)
+
+	userID, _, ok := sessionFromContext(r)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "unauthorised")
+		return
+	}
+
+	sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid session id")
+		return
+	}
+
+	if err := h.service.RevokeSession(ctx, userID, sessionID); err != nil {
+		h.logger.Error().Str("op", op).Str("req_id", reqID).Err(err).Msg("revoke session error")
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "session revoked", struct{}{})
+}
+
+func toLogInResponse(res LogInUserResult) LogInResponse {
+	return LogInResponse{
+		UserID:       res.UserID.String(),
+		SessionID:    res.SessionID.String(),
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int64(res.ExpiresIn.Seconds()),
+	}
+}
+
+func clientInfo(r *http.Request) ClientInfo {
+	return ClientInfo{UserAgent: r.UserAgent(), IP: middle.ClientIP(r)}
+}
+
+// sessionFromContext returns the signed-in user and the session their
+// access token belongs to.
+func sessionFromContext(r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	claims, ok := middle.UserFromContext(r.Context())
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, sessionID, true
+}
@@ -0,0 +1,146 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/db"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+func (r *repository) CreateSession(ctx context.Context, userID uuid.UUID, refreshHash string, client ClientInfo, expiresAt time.Time) (uuid.UUID, error) {
+	const op = "repo.user.create_session"
+
+	id, err := r.querier.CreateSession(ctx, db.CreateSessionParams{
+		UserID:      utils.ToPgUUID(userID),
+		RefreshHash: refreshHash,
+		UserAgent:   client.UserAgent,
+		Ip:          client.IP,
+		ExpiresAt:   utils.ToPgTimestamptz(expiresAt.UTC()),
+	})
+	if err != nil {
+		return uuid.Nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	return utils.FromPgUUID(id), nil
+}
+
+// GetSessionByRefreshHash finds the session a refresh token belongs to,
+// whether it is the current token or the one it replaced.
+func (r *repository) GetSessionByRefreshHash(ctx context.Context, hash string) (sessionRecord, error) {
+	const op = "repo.user.get_session_by_refresh_hash"
+
+	row, err := r.querier.GetSessionByRefreshHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sessionRecord{}, &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "session not found"}
+		}
+		return sessionRecord{}, utils.WrapRepoError(op, err, r.logger)
+	}
+	return sessionRecord{
+		ID:          utils.FromPgUUID(row.ID),
+		UserID:      utils.FromPgUUID(row.UserID),
+		RefreshHash: row.RefreshHash,
+		ExpiresAt:   utils.FromPgTimestamptz(row.ExpiresAt),
+		Revoked:     row.RevokedAt.Valid,
+	}, nil
+}
+
+// RotateSession replaces the session's refresh token and reports whether
+// it did; it doesn't when oldHash is no longer current.
+func (r *repository) RotateSession(ctx context.Context, sessionID uuid.UUID, oldHash, newHash string, client ClientInfo, expiresAt time.Time) (bool, error) {
+	const op = "repo.user.rotate_session"
+
+	n, err := r.querier.RotateSession(ctx, db.RotateSessionParams{
+		ID:            utils.ToPgUUID(sessionID),
+		RefreshHash:   newHash,
+		ExpiresAt:     utils.ToPgTimestamptz(expiresAt.UTC()),
+		UserAgent:     client.UserAgent,
+		Ip:            client.IP,
+		RefreshHash_2: oldHash,
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return n > 0, nil
+}
+
+func (r *repository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	const op = "repo.user.list_active_sessions"
+
+	rows, err := r.querier.ListActiveSessions(ctx, utils.ToPgUUID(userID))
+	if err != nil {
+		return nil, utils.WrapRepoError(op, err, r.logger)
+	}
+	sessions := make([]Session, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, Session{
+			ID:         utils.FromPgUUID(row.ID),
+			UserAgent:  row.UserAgent,
+			IP:         row.Ip,
+			ExpiresAt:  utils.FromPgTimestamptz(row.ExpiresAt),
+			LastUsedAt: utils.FromPgTimestamptz(row.LastUsedAt),
+			CreatedAt:  utils.FromPgTimestamptz(row.CreatedAt),
+		})
+	}
+	return sessions, nil
+}
+
+func (r *repository) IsSessionActive(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	const op = "repo.user.is_session_active"
+
+	active, err := r.querier.IsSessionActive(ctx, db.IsSessionActiveParams{
+		ID:     utils.ToPgUUID(sessionID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return false, utils.WrapRepoError(op, err, r.logger)
+	}
+	return active, nil
+}
+
+func (r *repository) RevokeUserSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	const op = "repo.user.revoke_user_session"
+
+	n, err := r.querier.RevokeUserSession(ctx, db.RevokeUserSessionParams{
+		ID:     utils.ToPgUUID(sessionID),
+		UserID: utils.ToPgUUID(userID),
+	})
+	if err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	if n == 0 {
+		return &apperror.Error{Kind: apperror.NotFound, Op: op, Message: "session not found"}
+	}
+	return nil
+}
+
+func (r *repository) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	const op = "repo.user.revoke_session"
+
+	if err := r.querier.RevokeSession(ctx, utils.ToPgUUID(sessionID)); err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+func (r *repository) RevokeAllUserSessions(ctx context.Context, userID uuid.UUID) error {
+	const op = "repo.user.revoke_all_user_sessions"
+
+	if err := r.querier.RevokeAllUserSessions(ctx, utils.ToPgUUID(userID)); err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
+
+func (r *repository) DeleteDeadUserSessions(ctx context.Context, userID uuid.UUID) error {
+	const op = "repo.user.delete_dead_user_sessions"
+
+	if err := r.querier.DeleteDeadUserSessions(ctx, utils.ToPgUUID(userID)); err != nil {
+		return utils.WrapRepoError(op, err, r.logger)
+	}
+	return nil
+}
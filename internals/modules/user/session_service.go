@@ -0,0 +1,122 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/alkush-pipania/sofon/internals/security"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/google/uuid"
+)
+
+// startSession opens a session for a user who just signed in and issues
+// its first pair of tokens.
+func (s *Service) startSession(ctx context.Context, u User, client ClientInfo) (LogInUserResult, error) {
+	const op = "service.user.start_session"
+
+	// sessions that can no longer be used are of no interest to anyone
+	_ = s.repo.DeleteDeadUserSessions(ctx, u.ID)
+
+	refresh, err := security.NewOpaqueToken(32)
+	if err != nil {
+		return LogInUserResult{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	sessionID, err := s.repo.CreateSession(ctx, u.ID, security.HashToken(refresh), client, time.Now().Add(s.tokenSvc.RefreshTokenTTL()))
+	if err != nil {
+		return LogInUserResult{}, err
+	}
+
+	return s.issueTokens(op, u, sessionID, refresh)
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh
+// token; the old refresh token stops working. Presenting a refresh token
+// that was already traded in means it was copied, so the session it
+// belongs to is revoked.
+func (s *Service) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (LogInUserResult, error) {
+	const op = "service.user.refresh"
+
+	unauthorised := func(msg string) (LogInUserResult, error) {
+		return LogInUserResult{}, &apperror.Error{Kind: apperror.Unauthorised, Op: op, Message: msg}
+	}
+
+	hash := security.HashToken(refreshToken)
+	sess, err := s.repo.GetSessionByRefreshHash(ctx, hash)
+	if apperror.IsKind(err, apperror.NotFound) {
+		return unauthorised("invalid or expired refresh token")
+	}
+	if err != nil {
+		return LogInUserResult{}, err
+	}
+	if sess.Revoked || !time.Now().Before(sess.ExpiresAt) {
+		return unauthorised("invalid or expired refresh token")
+	}
+	if sess.RefreshHash != hash {
+		if err := s.repo.RevokeSession(ctx, sess.ID); err != nil {
+			return LogInUserResult{}, err
+		}
+		return unauthorised("refresh token was already used; the session has been revoked")
+	}
+
+	u, err := s.repo.GetUserByID(ctx, sess.UserID)
+	if err != nil {
+		return LogInUserResult{}, err
+	}
+	if !u.IsActive {
+		return unauthorised("your account has been deactivated. Please contact the administrator.")
+	}
+
+	refresh, err := security.NewOpaqueToken(32)
+	if err != nil {
+		return LogInUserResult{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+	rotated, err := s.repo.RotateSession(ctx, sess.ID, hash, security.HashToken(refresh), client, time.Now().Add(s.tokenSvc.RefreshTokenTTL()))
+	if err != nil {
+		return LogInUserResult{}, err
+	}
+	if !rotated {
+		// another refresh with the same token won the race
+		return unauthorised("invalid or expired refresh token")
+	}
+
+	return s.issueTokens(op, u, sess.ID, refresh)
+}
+
+func (s *Service) issueTokens(op string, u User, sessionID uuid.UUID, refresh string) (LogInUserResult, error) {
+	token, err := s.tokenSvc.GenerateAccessToken(security.RequestClaims{
+		UserID:    u.ID.String(),
+		Email:     u.Email,
+		Role:      u.Role,
+		SessionID: sessionID.String(),
+	})
+	if err != nil {
+		return LogInUserResult{}, &apperror.Error{Kind: apperror.Internal, Op: op, Message: "internal server error", Err: err}
+	}
+
+	return LogInUserResult{
+		UserID:       u.ID,
+		SessionID:    sessionID,
+		AccessToken:  token,
+		RefreshToken: refresh,
+		ExpiresIn:    s.tokenSvc.AccessTokenTTL(),
+	}, nil
+}
+
+func (s *Service) ListSessions(ctx context.Context, userID uuid.UUID) ([]Session, error) {
+	return s.repo.ListActiveSessions(ctx, userID)
+}
+
+// RevokeSession ends one of the user's sessions: its refresh token stops
+// working and so do the access tokens issued for it.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	return s.repo.RevokeUserSession(ctx, userID, sessionID)
+}
+
+// RevokeAllSessions signs the user out everywhere.
+func (s *Service) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.RevokeAllUserSessions(ctx, userID)
+}
+
+func (s *Service) IsSessionActive(ctx context.Context, sessionID, userID uuid.UUID) (bool, error) {
+	return s.repo.IsSessionActive(ctx, sessionID, userID)
+}
@@ -0,0 +1,7 @@
+package webhook
+
+type WebhookResponse struct {
+	Action    string `json:"action"`
+	Incidents int    `json:"incidents"`
+	Updated   int    `json:"updated"`
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/utils"
+	"github.com/alkush-pipania/sofon/pkg/zenduty"
+	"github.com/go-chi/chi/v5"
+	chimw "github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// SecretHeader carries the webhook secret. It is not accepted in the query
+// string, which the request logger records.
+const SecretHeader = "X-Sofon-Webhook-Secret"
+
+const maxBodyBytes = 1 << 20
+
+type Handler struct {
+	service *Service
+	logger  *zerolog.Logger
+}
+
+func NewHandler(svc *Service, logger *zerolog.Logger) *Handler {
+	return &Handler{service: svc, logger: logger}
+}
+
+func (h *Handler) Zenduty(w http.ResponseWriter, r *http.Request) {
+	const op = "handler.webhook.zenduty"
+	ctx := r.Context()
+	reqID := chimw.GetReqID(ctx)
+
+	pluginID, err := uuid.Parse(chi.URLParam(r, "pluginID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, reqID, apperror.Unauthorised, "invalid webhook credentials")
+		return
+	}
+
+	secret := r.Header.Get(SecretHeader)
+
+	var event zenduty.WebhookEvent
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&event); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, reqID, apperror.InvalidInput, "invalid request body")
+		return
+	}
+
+	res, err := h.service.HandleZenduty(ctx, pluginID, secret, &event)
+	if err != nil {
+		if !apperror.IsKind(err, apperror.Unauthorised) {
+			h.logger.Error().Str("op", op).Err(err).Msg("zenduty webhook")
+		}
+		utils.FromAppError(w, reqID, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, reqID, "webhook processed", WebhookResponse{
+		Action:    res.Action,
+		Incidents: res.Incidents,
+		Updated:   res.Updated,
+	})
+}
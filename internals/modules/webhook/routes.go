@@ -0,0 +1,11 @@
+package webhook
+
+import "github.com/go-chi/chi/v5"
+
+// Routes are called by external tools and authenticate with the plugin's
+// webhook secret instead of a user session.
+func Routes(h *Handler) chi.Router {
+	r := chi.NewRouter()
+	r.Post("/zenduty/{pluginID}", h.Zenduty)
+	return r
+}
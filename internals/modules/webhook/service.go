@@ -0,0 +1,168 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+
+	"github.com/alkush-pipania/sofon/internals/modules/alert"
+	"github.com/alkush-pipania/sofon/internals/modules/incident"
+	"github.com/alkush-pipania/sofon/internals/modules/plugin"
+	"github.com/alkush-pipania/sofon/pkg/apperror"
+	"github.com/alkush-pipania/sofon/pkg/zenduty"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// PluginLookup is satisfied by *plugin.Repository.
+type PluginLookup interface {
+	GetByID(ctx context.Context, pluginID uuid.UUID) (plugin.Plugin, map[string]string, error)
+}
+
+// IncidentSyncer is satisfied by *incident.Service.
+type IncidentSyncer interface {
+	OpenIncidentsForMonitor(ctx context.Context, teamID, monitorID uuid.UUID) ([]uuid.UUID, error)
+	OpenIncidentsForDigest(ctx context.Context, teamID, groupID uuid.UUID) ([]uuid.UUID, error)
+	AcknowledgeExternal(ctx context.Context, teamID, incidentID uuid.UUID, r incident.Responder) (bool, error)
+	ResolveExternal(ctx context.Context, teamID, incidentID uuid.UUID, r incident.Responder) (bool, error)
+}
+
+// Service applies incident state changes reported by incident management
+// tools back to Sofon incidents.
+type Service struct {
+	plugins   PluginLookup
+	incidents IncidentSyncer
+	logger    *zerolog.Logger
+}
+
+func NewService(plugins PluginLookup, incidents IncidentSyncer, logger *zerolog.Logger) *Service {
+	return &Service{plugins: plugins, incidents: incidents, logger: logger}
+}
+
+// Result summarizes what an inbound event changed.
+type Result struct {
+	Action    string
+	Incidents int
+	Updated   int
+}
+
+// Actions reported in Result.
+const (
+	ActionAcknowledged = "acknowledged"
+	ActionResolved     = "resolved"
+	ActionIgnored      = "ignored"
+)
+
+// HandleZenduty authenticates a Zenduty callback against the secret of
+// the Zenduty plugin it was sent to and applies it to the open incidents
+// behind the Zenduty incident's entity IDs. Triggers are ignored: Sofon
+// opened those incidents itself.
+func (s *Service) HandleZenduty(ctx context.Context, pluginID uuid.UUID, secret string, event *zenduty.WebhookEvent) (Result, error) {
+	const op = "service.webhook.zenduty"
+
+	p, err := s.authenticate(ctx, op, pluginID, alert.NotifierZenduty, secret)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var action string
+	switch event.Status() {
+	case zenduty.IncidentAcknowledged:
+		action = ActionAcknowledged
+	case zenduty.IncidentResolved:
+		action = ActionResolved
+	default:
+		return Result{Action: ActionIgnored}, nil
+	}
+
+	incidentIDs, err := s.zendutyIncidents(ctx, p.TeamID, event.EntityIDs())
+	if err != nil {
+		return Result{}, err
+	}
+
+	responder := incident.Responder{Source: alert.NotifierZenduty}
+	if u := event.Responder(); u != nil {
+		responder.Name = u.Name()
+		responder.Email = u.Email
+	}
+
+	res := Result{Action: action, Incidents: len(incidentIDs)}
+	for _, id := range incidentIDs {
+		var changed bool
+		if action == ActionResolved {
+			changed, err = s.incidents.ResolveExternal(ctx, p.TeamID, id, responder)
+		} else {
+			changed, err = s.incidents.AcknowledgeExternal(ctx, p.TeamID, id, responder)
+		}
+		if err != nil {
+			return res, err
+		}
+		if changed {
+			res.Updated++
+		}
+	}
+
+	s.logger.Info().
+		Str("op", op).
+		Str("team_id", p.TeamID.String()).
+		Str("plugin_id", pluginID.String()).
+		Str("zenduty_incident", event.Data.Incident.UniqueID).
+		Str("action", action).
+		Int("incidents", res.Incidents).
+		Int("updated", res.Updated).
+		Msg("applied zenduty incident update")
+	return res, nil
+}
+
+// zendutyIncidents maps Zenduty entity IDs to the team's open incidents.
+// Entity IDs that Sofon did not send are skipped.
+func (s *Service) zendutyIncidents(ctx context.Context, teamID uuid.UUID, entityIDs []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool)
+	var out []uuid.UUID
+	for _, entity := range entityIDs {
+		id, digest, err := alert.ParseZendutyEntity(entity)
+		if err != nil {
+			continue
+		}
+
+		var ids []uuid.UUID
+		if digest {
+			ids, err = s.incidents.OpenIncidentsForDigest(ctx, teamID, id)
+		} else {
+			ids, err = s.incidents.OpenIncidentsForMonitor(ctx, teamID, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, incidentID := range ids {
+			if !seen[incidentID] {
+				seen[incidentID] = true
+				out = append(out, incidentID)
+			}
+		}
+	}
+	return out, nil
+}
+
+// authenticate checks that the plugin exists, has the expected type and is
+// enabled, and that its webhook_secret matches. Every mismatch is reported
+// the same way so the endpoint does not reveal which plugin IDs exist.
+func (s *Service) authenticate(ctx context.Context, op string, pluginID uuid.UUID, pluginType, secret string) (plugin.Plugin, error) {
+	unauthorised := &apperror.Error{Kind: apperror.Unauthorised, Op: op, Message: "invalid webhook credentials"}
+
+	p, cfg, err := s.plugins.GetByID(ctx, pluginID)
+	if err != nil {
+		if apperror.IsKind(err, apperror.NotFound) {
+			return plugin.Plugin{}, unauthorised
+		}
+		return plugin.Plugin{}, err
+	}
+	if string(p.Type) != pluginType || !p.Enabled {
+		return plugin.Plugin{}, unauthorised
+	}
+
+	want := cfg["webhook_secret"]
+	if want == "" || secret == "" || subtle.ConstantTimeCompare([]byte(want), []byte(secret)) != 1 {
+		return plugin.Plugin{}, unauthorised
+	}
+	return p, nil
+}
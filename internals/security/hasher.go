@@ -1,6 +1,10 @@
 package security
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
 	"github.com/alexedwards/argon2id"
 	"github.com/google/uuid"
 )
@@ -26,3 +30,21 @@ func ComparePassword(password, hash string) (bool, error) {
 	}
 	return ok, nil
 }
+
+// NewOpaqueToken returns a random hex token of n bytes, for credentials the
+// server looks up rather than verifies.
+func NewOpaqueToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// HashToken is what the database keeps of an opaque token. The tokens are
+// long and random, so a fast unsalted hash is enough to make a leaked
+// table useless.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
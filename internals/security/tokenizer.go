@@ -8,17 +8,29 @@ import (
 )
 
 type TokenService struct {
-	secret   string
-	tokenTTL time.Duration
+	secret     string
+	tokenTTL   time.Duration
+	refreshTTL time.Duration
 }
 
 func NewTokenService(authCfg *config.AuthConfig) *TokenService {
 	return &TokenService{
-		secret:   authCfg.Secret,
-		tokenTTL: authCfg.TokenTTL,
+		secret:     authCfg.Secret,
+		tokenTTL:   authCfg.TokenTTL,
+		refreshTTL: authCfg.RefreshTTL,
 	}
 }
 
+// AccessTokenTTL is how long an access token is valid.
+func (ts *TokenService) AccessTokenTTL() time.Duration {
+	return ts.tokenTTL
+}
+
+// RefreshTokenTTL is how long a session lasts without being refreshed.
+func (ts *TokenService) RefreshTokenTTL() time.Duration {
+	return ts.refreshTTL
+}
+
 func (ts *TokenService) GenerateAccessToken(payload RequestClaims) (string, error) {
 	now := time.Now()
 	expiryTime := now.Add(ts.tokenTTL)
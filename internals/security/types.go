@@ -6,5 +6,8 @@ type RequestClaims struct {
 	UserID string `json:"sub"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	// SessionID is the session an access token was issued for; empty in
+	// claims that come from an API token.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
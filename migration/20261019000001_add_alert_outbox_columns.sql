@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE alerts
+    ADD COLUMN channel         TEXT        NOT NULL DEFAULT 'resend',
+    ADD COLUMN payload         JSONB       NOT NULL DEFAULT '{}',
+    ADD COLUMN attempts        INT         NOT NULL DEFAULT 0,
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN last_error      TEXT,
+    ADD COLUMN updated_at      TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- status: 'pending', 'sent', 'failed', 'dead', 'skipped_no_plugin'
+CREATE INDEX IF NOT EXISTS idx_alerts_pending_due
+    ON alerts (next_attempt_at)
+    WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_pending_due;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS payload,
+    DROP COLUMN IF EXISTS channel;
+-- +goose StatementEnd
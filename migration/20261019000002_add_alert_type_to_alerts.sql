@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE alerts
+    ADD COLUMN alert_type TEXT NOT NULL DEFAULT 'DOWN'; -- 'DOWN', 'RECOVERED'
+
+CREATE INDEX IF NOT EXISTS idx_alerts_incident_created
+    ON alerts (incident_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_created
+    ON alerts (created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_created;
+DROP INDEX IF EXISTS idx_alerts_incident_created;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS alert_type;
+-- +goose StatementEnd
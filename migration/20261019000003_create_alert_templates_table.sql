@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS alert_templates (
+    id         UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id    UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    channel    TEXT        NOT NULL,               -- plugin type: 'resend', 'zenduty', ...
+    alert_type TEXT        NOT NULL,               -- 'DOWN', 'RECOVERED'
+    subject    TEXT        NOT NULL DEFAULT '',
+    body       TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, channel, alert_type)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS alert_templates;
+-- +goose StatementEnd
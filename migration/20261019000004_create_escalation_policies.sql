@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS escalation_policies (
+    id                  UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id             UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name                TEXT        NOT NULL,
+    description         TEXT        NOT NULL DEFAULT '',
+    levels              JSONB       NOT NULL DEFAULT '[]',   -- [{delay_sec, channels, user_ids}]
+    repeat_interval_sec INT         NOT NULL DEFAULT 0 CHECK (repeat_interval_sec >= 0),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+ALTER TABLE monitors
+    ADD COLUMN IF NOT EXISTS escalation_policy_id UUID NULL REFERENCES escalation_policies(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_monitors_escalation_policy_id
+    ON monitors (escalation_policy_id)
+    WHERE escalation_policy_id IS NOT NULL;
+
+-- One row per incident escalated by a policy. levels and event are copied
+-- when the incident starts so editing a policy doesn't affect running
+-- escalations.
+CREATE TABLE IF NOT EXISTS incident_escalations (
+    incident_id         UUID        PRIMARY KEY REFERENCES monitor_incidents(id) ON DELETE CASCADE,
+    policy_id           UUID        NULL REFERENCES escalation_policies(id) ON DELETE SET NULL,
+    team_id             UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    levels              JSONB       NOT NULL,
+    repeat_interval_sec INT         NOT NULL DEFAULT 0,
+    event               JSONB       NOT NULL,
+    status              TEXT        NOT NULL DEFAULT 'active', -- active, acknowledged, resolved, exhausted
+    next_level          INT         NOT NULL DEFAULT 0,
+    cycle               INT         NOT NULL DEFAULT 0,
+    next_fire_at        TIMESTAMPTZ NULL,
+    last_fired_at       TIMESTAMPTZ NULL,
+    notified_channels   TEXT[]      NOT NULL DEFAULT '{}',
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_escalations_active
+    ON incident_escalations (next_fire_at)
+    WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS incident_escalations;
+DROP INDEX IF EXISTS idx_monitors_escalation_policy_id;
+ALTER TABLE monitors DROP COLUMN IF EXISTS escalation_policy_id;
+DROP TABLE IF EXISTS escalation_policies;
+-- +goose StatementEnd
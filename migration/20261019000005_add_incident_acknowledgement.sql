@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitor_incidents
+    ADD COLUMN acknowledged_at TIMESTAMPTZ,
+    ADD COLUMN acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN assigned_to     UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN assigned_by     UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN assigned_at     TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_monitor_incidents_assigned_to
+    ON monitor_incidents (assigned_to)
+    WHERE assigned_to IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitor_incidents_assigned_to;
+
+ALTER TABLE monitor_incidents
+    DROP COLUMN IF EXISTS assigned_at,
+    DROP COLUMN IF EXISTS assigned_by,
+    DROP COLUMN IF EXISTS assigned_to,
+    DROP COLUMN IF EXISTS acknowledged_by,
+    DROP COLUMN IF EXISTS acknowledged_at;
+-- +goose StatementEnd
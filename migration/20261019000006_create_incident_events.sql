@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS incident_events (
+    id          UUID        PRIMARY KEY DEFAULT gen_random_uuid(),
+    incident_id UUID        NOT NULL REFERENCES monitor_incidents(id) ON DELETE CASCADE,
+    kind        TEXT        NOT NULL,               -- 'incident_created', 'alert_sent', 'comment', ...
+    author_id   UUID        REFERENCES users(id) ON DELETE SET NULL, -- NULL for automatic events
+    message     TEXT        NOT NULL DEFAULT '',
+    data        JSONB       NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_events_incident
+    ON incident_events (incident_id, occurred_at, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS incident_events;
+-- +goose StatementEnd
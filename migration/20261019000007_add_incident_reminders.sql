@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors
+    ADD COLUMN reminder_interval_sec INT NOT NULL DEFAULT 0, -- 0 = no reminders
+    ADD COLUMN reminder_max          INT NOT NULL DEFAULT 0;
+
+ALTER TABLE monitor_incidents
+    ADD COLUMN reminders_sent   INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_reminder_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_monitor_incidents_next_reminder
+    ON monitor_incidents (next_reminder_at)
+    WHERE next_reminder_at IS NOT NULL AND end_time IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitor_incidents_next_reminder;
+
+ALTER TABLE monitor_incidents
+    DROP COLUMN IF EXISTS next_reminder_at,
+    DROP COLUMN IF EXISTS reminders_sent;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS reminder_max,
+    DROP COLUMN IF EXISTS reminder_interval_sec;
+-- +goose StatementEnd
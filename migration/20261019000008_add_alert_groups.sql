@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rows delivered together in one digest share a group_id
+ALTER TABLE alerts
+    ADD COLUMN group_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_alerts_group
+    ON alerts (group_id)
+    WHERE group_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_alerts_group;
+
+ALTER TABLE alerts
+    DROP COLUMN IF EXISTS group_id;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors
+    ADD COLUMN tags     TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN severity TEXT   NOT NULL DEFAULT 'critical'; -- 'critical', 'warning', 'info'
+
+CREATE INDEX IF NOT EXISTS idx_monitors_tags ON monitors USING GIN (tags);
+
+-- Team-level notification routing, evaluated in position order before an
+-- alert is enqueued. Empty match lists match anything.
+CREATE TABLE IF NOT EXISTS routing_rules (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id           UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name              TEXT        NOT NULL,
+    position          INT         NOT NULL DEFAULT 0,
+    enabled           BOOLEAN     NOT NULL DEFAULT true,
+    match_tags        TEXT[]      NOT NULL DEFAULT '{}',
+    match_severities  TEXT[]      NOT NULL DEFAULT '{}',
+    match_alert_types TEXT[]      NOT NULL DEFAULT '{}',
+    time_window       TEXT        NOT NULL DEFAULT 'always', -- 'always', 'business_hours', 'off_hours'
+    channels          TEXT[]      NOT NULL DEFAULT '{}',
+    user_ids          UUID[]      NOT NULL DEFAULT '{}',
+    stop              BOOLEAN     NOT NULL DEFAULT false,    -- skip the rules after this one when it matches
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_routing_rules_team ON routing_rules (team_id, position);
+
+CREATE TABLE IF NOT EXISTS team_business_hours (
+    team_id      UUID PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    timezone     TEXT        NOT NULL DEFAULT 'UTC',
+    days         INT[]       NOT NULL DEFAULT '{1,2,3,4,5}', -- ISO weekdays, 1 = Monday
+    start_minute INT         NOT NULL DEFAULT 540,           -- minutes after midnight
+    end_minute   INT         NOT NULL DEFAULT 1020,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS team_business_hours;
+DROP TABLE IF EXISTS routing_rules;
+DROP INDEX IF EXISTS idx_monitors_tags;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS severity,
+    DROP COLUMN IF EXISTS tags;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Plugins become named instances: a team can configure the same plugin
+-- type several times, e.g. one Zenduty service per product.
+ALTER TABLE plugins ADD COLUMN name TEXT;
+UPDATE plugins SET name = plugin_type;
+ALTER TABLE plugins ALTER COLUMN name SET NOT NULL;
+
+ALTER TABLE plugins DROP CONSTRAINT IF EXISTS plugins_team_id_plugin_type_key;
+ALTER TABLE plugins ADD CONSTRAINT plugins_team_id_name_key UNIQUE (team_id, name);
+CREATE INDEX IF NOT EXISTS idx_plugins_team_type ON plugins (team_id, plugin_type);
+
+-- alerts.channel keeps the plugin type; plugin_id is the instance the row
+-- is delivered through.
+ALTER TABLE alerts ADD COLUMN plugin_id UUID;
+
+UPDATE alerts a
+SET plugin_id = p.id
+FROM plugins p
+WHERE p.plugin_type = a.channel
+  AND p.team_id::TEXT = a.payload->>'team_id';
+
+CREATE INDEX IF NOT EXISTS idx_alerts_plugin_pending
+    ON alerts (plugin_id, alert_type, next_attempt_at)
+    WHERE status = 'pending';
+
+-- monitors now select plugin instances by ID; plugin type names are still
+-- understood and select every instance of the type
+UPDATE monitors m
+SET notification_channels = (
+    SELECT string_agg(COALESCE(p.id::TEXT, c.name), ',' ORDER BY c.ord)
+    FROM unnest(string_to_array(m.notification_channels, ',')) WITH ORDINALITY AS c(name, ord)
+    LEFT JOIN plugins p ON p.team_id = m.team_id AND p.plugin_type = c.name
+)
+WHERE m.notification_channels <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE monitors m
+SET notification_channels = (
+    SELECT string_agg(DISTINCT COALESCE(p.plugin_type, c.name), ',')
+    FROM unnest(string_to_array(m.notification_channels, ',')) AS c(name)
+    LEFT JOIN plugins p ON p.id::TEXT = c.name
+)
+WHERE m.notification_channels <> '';
+
+DROP INDEX IF EXISTS idx_alerts_plugin_pending;
+ALTER TABLE alerts DROP COLUMN IF EXISTS plugin_id;
+
+-- keep the oldest instance of each type
+DELETE FROM plugins p
+USING plugins o
+WHERE o.team_id = p.team_id
+  AND o.plugin_type = p.plugin_type
+  AND (o.created_at, o.id) < (p.created_at, p.id);
+
+DROP INDEX IF EXISTS idx_plugins_team_type;
+ALTER TABLE plugins DROP CONSTRAINT IF EXISTS plugins_team_id_name_key;
+ALTER TABLE plugins ADD CONSTRAINT plugins_team_id_plugin_type_key UNIQUE (team_id, plugin_type);
+ALTER TABLE plugins DROP COLUMN IF EXISTS name;
+-- +goose StatementEnd
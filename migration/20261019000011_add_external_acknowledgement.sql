@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Acknowledgements that arrive from an incident management tool name a
+-- responder who may have no Sofon account. acknowledged_by is set when the
+-- responder matches a team member; the external name is kept either way.
+ALTER TABLE monitor_incidents
+    ADD COLUMN acknowledged_by_external TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitor_incidents
+    DROP COLUMN IF EXISTS acknowledged_by_external;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monitors
+    ADD COLUMN name        TEXT NOT NULL DEFAULT '',
+    ADD COLUMN description TEXT NOT NULL DEFAULT '',
+    ADD COLUMN runbook_url TEXT NOT NULL DEFAULT '';
+
+-- lists sort by the monitor's display name, its URL when it has no name
+CREATE INDEX IF NOT EXISTS idx_monitors_team_display_name
+    ON monitors (team_id, lower(COALESCE(NULLIF(name, ''), url)), id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitors_team_display_name;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS runbook_url,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS name;
+-- +goose StatementEnd
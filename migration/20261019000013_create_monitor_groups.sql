@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Monitor groups bundle the monitors of one service. Groups nest through
+-- parent_id; a group's members include those of its subgroups.
+CREATE TABLE IF NOT EXISTS monitor_groups (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    parent_id   UUID        NULL REFERENCES monitor_groups(id) ON DELETE SET NULL,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    alert_mode  TEXT        NOT NULL DEFAULT 'members', -- 'members' (one alert per monitor) or 'group'
+    alert_when  TEXT        NOT NULL DEFAULT 'any',     -- group mode: 'any' member down or 'all' members down
+    channels    TEXT[]      NOT NULL DEFAULT '{}',      -- group alerts; empty uses the member's channels
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_groups_parent ON monitor_groups (parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS monitor_group_members (
+    group_id   UUID        NOT NULL REFERENCES monitor_groups(id) ON DELETE CASCADE,
+    monitor_id UUID        NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (group_id, monitor_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_group_members_monitor ON monitor_group_members (monitor_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS monitor_group_members;
+DROP TABLE IF EXISTS monitor_groups;
+-- +goose StatementEnd
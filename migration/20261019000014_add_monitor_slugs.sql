@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- slug is the stable key monitors-as-code documents refer to a monitor by
+ALTER TABLE monitors
+    ADD COLUMN slug TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_team_slug
+    ON monitors (team_id, slug)
+    WHERE slug IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitors_team_slug;
+
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS slug;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a non-empty keyword must appear in the response body, or must not when
+-- keyword_absent is set
+ALTER TABLE monitors
+    ADD COLUMN keyword        TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN keyword_absent BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS keyword_absent,
+    DROP COLUMN IF EXISTS keyword;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a monitor snoozed until a time keeps being checked but opens no incidents
+-- and sends no alerts; snooze_notify asks for a notification once it ends
+ALTER TABLE monitors
+    ADD COLUMN snoozed_until TIMESTAMPTZ,
+    ADD COLUMN snooze_notify BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_monitors_snooze_notify
+    ON monitors (snoozed_until)
+    WHERE snooze_notify;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_monitors_snooze_notify;
+ALTER TABLE monitors
+    DROP COLUMN IF EXISTS snooze_notify,
+    DROP COLUMN IF EXISTS snoozed_until;
+-- +goose StatementEnd
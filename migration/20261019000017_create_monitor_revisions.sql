@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change to a monitor's configuration is kept as a numbered revision
+-- holding the monitor as it was afterwards, in monitors-as-code form.
+-- monitor_id has no foreign key so that a deleted monitor's history stays
+-- around, and the monitor restorable, until it is purged.
+CREATE TABLE IF NOT EXISTS monitor_revisions (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    monitor_id UUID        NOT NULL,
+    team_id    UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    revision   INT         NOT NULL,
+    action     TEXT        NOT NULL, -- 'create', 'update', 'enable', 'disable', 'delete' or 'restore'
+    actor_id   UUID        NULL REFERENCES users(id) ON DELETE SET NULL,
+    snapshot   JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (monitor_id, revision)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_revisions_deleted
+    ON monitor_revisions (team_id, created_at) WHERE action = 'delete';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS monitor_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per change made through a team's API: who made it, what it
+-- touched and a short summary of the state before and after.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    team_id     UUID        NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    actor_id    UUID        NULL REFERENCES users(id) ON DELETE SET NULL,
+    action      TEXT        NOT NULL, -- e.g. 'monitors.delete', 'members.deactivate'
+    target_type TEXT        NOT NULL DEFAULT '',
+    target_id   TEXT        NOT NULL DEFAULT '',
+    request_id  TEXT        NOT NULL DEFAULT '',
+    ip          TEXT        NOT NULL DEFAULT '',
+    before      JSONB       NULL,
+    after       JSONB       NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_team_created
+    ON audit_events (team_id, created_at DESC, id DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_events;
+-- +goose StatementEnd
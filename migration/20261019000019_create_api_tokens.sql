@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Long-lived credentials for scripts and CI. A personal token (team_id
+-- NULL) acts as its user in any of their teams; a team token works in its
+-- team only, with the membership of the user who created it. Only a hash
+-- of the token is kept.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team_id      UUID        NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    prefix       TEXT        NOT NULL, -- the start of the token, to tell tokens apart
+    token_hash   TEXT        NOT NULL UNIQUE,
+    scopes       TEXT[]      NOT NULL,
+    expires_at   TIMESTAMPTZ NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at   TIMESTAMPTZ NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens (user_id) WHERE team_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_api_tokens_team ON api_tokens (team_id) WHERE team_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_tokens;
+-- +goose StatementEnd
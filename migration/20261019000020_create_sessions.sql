@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per sign-in. The refresh token rotates on every use; the one it
+-- replaced is kept so that presenting it again, a sign it was stolen,
+-- revokes the session.
+CREATE TABLE IF NOT EXISTS sessions (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_hash  TEXT        NOT NULL UNIQUE,
+    previous_hash TEXT        NULL,
+    user_agent    TEXT        NOT NULL DEFAULT '',
+    ip            TEXT        NOT NULL DEFAULT '',
+    expires_at    TIMESTAMPTZ NOT NULL,
+    revoked_at    TIMESTAMPTZ NULL,
+    last_used_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions (user_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_previous_hash ON sessions (previous_hash) WHERE previous_hash IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Set when an incident management tool reports the incident resolved. The
+-- Sofon incident stays open until the monitor recovers; this only keeps a
+-- repeated resolution from being noted twice.
+ALTER TABLE monitor_incidents
+    ADD COLUMN resolved_externally_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE monitor_incidents
+    DROP COLUMN IF EXISTS resolved_externally_at;
+-- +goose StatementEnd
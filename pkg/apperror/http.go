@@ -22,6 +22,8 @@ func HTTPStatus(err error) int {
 		return http.StatusUnauthorized
 	case Forbidden:
 		return http.StatusForbidden
+	case RateLimited:
+		return http.StatusTooManyRequests
 	case Dependency:
 		return http.StatusBadGateway
 	case Internal:
@@ -44,6 +46,8 @@ func GetHTTPStatus(kind Kind) int {
 		return http.StatusUnauthorized
 	case Forbidden:
 		return http.StatusForbidden
+	case RateLimited:
+		return http.StatusTooManyRequests
 	case Dependency:
 		return http.StatusBadGateway
 	case Internal:
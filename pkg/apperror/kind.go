@@ -10,6 +10,7 @@ var (
 	Unauthorised   Kind = "unauthorised"
 	Forbidden      Kind = "forbidden"
 	RequestTimeout Kind = "request_timeout"
+	RateLimited    Kind = "rate_limited"
 	Internal       Kind = "internal"
 	Dependency     Kind = "dependency_failure"
 	DatabaseErr    Kind = "database_error"
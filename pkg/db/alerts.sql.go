@@ -11,6 +11,166 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimAlertGroup = `-- name: ClaimAlertGroup :many
+WITH grp AS (
+    SELECT id
+    FROM alerts
+    WHERE status = 'pending'
+      AND plugin_id = $1
+      AND alert_type = $2
+      AND payload->>'team_id' = $3::TEXT
+      AND NOT (payload ? 'escalation_level')
+      AND NOT (payload ? 'recipients')
+      AND next_attempt_at <= now() + ($4::INT * INTERVAL '1 second')
+      AND NOT (id = ANY($5::UUID[]))
+    ORDER BY next_attempt_at
+    LIMIT $6
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE alerts a
+SET
+    attempts = a.attempts + 1,
+    next_attempt_at = now() + ($7::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM grp
+WHERE a.id = grp.id
+RETURNING a.id, a.incident_id, a.channel, a.plugin_id, a.payload, a.attempts
+`
+
+type ClaimAlertGroupParams struct {
+	PluginID  pgtype.UUID
+	AlertType string
+	Column3   string
+	Column4   int32
+	Column5   []pgtype.UUID
+	Limit     int32
+	Column7   int32
+}
+
+type ClaimAlertGroupRow struct {
+	ID         pgtype.UUID
+	IncidentID pgtype.UUID
+	Channel    string
+	PluginID   pgtype.UUID
+	Payload    []byte
+	Attempts   int32
+}
+
+func (q *Queries) ClaimAlertGroup(ctx context.Context, arg ClaimAlertGroupParams) ([]ClaimAlertGroupRow, error) {
+	rows, err := q.db.Query(ctx, claimAlertGroup,
+		arg.PluginID,
+		arg.AlertType,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Limit,
+		arg.Column7,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimAlertGroupRow
+	for rows.Next() {
+		var i ClaimAlertGroupRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IncidentID,
+			&i.Channel,
+			&i.PluginID,
+			&i.Payload,
+			&i.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimDueAlerts = `-- name: ClaimDueAlerts :many
+WITH due AS (
+    SELECT id
+    FROM alerts
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE alerts a
+SET
+    attempts = a.attempts + 1,
+    next_attempt_at = now() + ($2::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM due
+WHERE a.id = due.id
+RETURNING a.id, a.incident_id, a.channel, a.plugin_id, a.payload, a.attempts
+`
+
+type ClaimDueAlertsParams struct {
+	Limit   int32
+	Column2 int32
+}
+
+type ClaimDueAlertsRow struct {
+	ID         pgtype.UUID
+	IncidentID pgtype.UUID
+	Channel    string
+	PluginID   pgtype.UUID
+	Payload    []byte
+	Attempts   int32
+}
+
+func (q *Queries) ClaimDueAlerts(ctx context.Context, arg ClaimDueAlertsParams) ([]ClaimDueAlertsRow, error) {
+	rows, err := q.db.Query(ctx, claimDueAlerts, arg.Limit, arg.Column2)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimDueAlertsRow
+	for rows.Next() {
+		var i ClaimDueAlertsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IncidentID,
+			&i.Channel,
+			&i.PluginID,
+			&i.Payload,
+			&i.Attempts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const closeAlert = `-- name: CloseAlert :exec
+UPDATE alerts
+SET
+    status = $2,
+    last_error = $3,
+    updated_at = now()
+WHERE id = $1
+`
+
+type CloseAlertParams struct {
+	ID        pgtype.UUID
+	Status    string
+	LastError pgtype.Text
+}
+
+func (q *Queries) CloseAlert(ctx context.Context, arg CloseAlertParams) error {
+	_, err := q.db.Exec(ctx, closeAlert, arg.ID, arg.Status, arg.LastError)
+	return err
+}
+
 const createAlert = `-- name: CreateAlert :exec
 INSERT INTO
     alerts (incident_id, alert_email)
@@ -27,6 +187,348 @@ func (q *Queries) CreateAlert(ctx context.Context, arg CreateAlertParams) error
 	return err
 }
 
+const deferAlerts = `-- name: DeferAlerts :exec
+UPDATE alerts
+SET
+    attempts = GREATEST(attempts - 1, 0),
+    next_attempt_at = $2,
+    updated_at = now()
+WHERE id = ANY($1::UUID[])
+`
+
+type DeferAlertsParams struct {
+	Column1       []pgtype.UUID
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) DeferAlerts(ctx context.Context, arg DeferAlertsParams) error {
+	_, err := q.db.Exec(ctx, deferAlerts, arg.Column1, arg.NextAttemptAt)
+	return err
+}
+
+const enqueueAlerts = `-- name: EnqueueAlerts :exec
+INSERT INTO
+    alerts (incident_id, channel, plugin_id, payload, alert_type, alert_email, next_attempt_at)
+SELECT $1, c.channel, c.plugin_id, $4, $5, '', now() + (c.hold * INTERVAL '1 second')
+FROM unnest($2::TEXT[], $3::UUID[], $6::INT[]) AS c(channel, plugin_id, hold)
+`
+
+type EnqueueAlertsParams struct {
+	IncidentID pgtype.UUID
+	Column2    []string
+	Column3    []pgtype.UUID
+	Payload    []byte
+	AlertType  string
+	Column6    []int32
+}
+
+func (q *Queries) EnqueueAlerts(ctx context.Context, arg EnqueueAlertsParams) error {
+	_, err := q.db.Exec(ctx, enqueueAlerts,
+		arg.IncidentID,
+		arg.Column2,
+		arg.Column3,
+		arg.Payload,
+		arg.AlertType,
+		arg.Column6,
+	)
+	return err
+}
+
+const extendAlertLeases = `-- name: ExtendAlertLeases :many
+UPDATE alerts a
+SET
+    next_attempt_at = now() + ($3::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM unnest($1::UUID[], $2::INT[]) AS c(id, attempts)
+WHERE a.id = c.id AND a.attempts = c.attempts AND a.status = 'pending'
+RETURNING a.id
+`
+
+type ExtendAlertLeasesParams struct {
+	Column1 []pgtype.UUID
+	Column2 []int32
+	Column3 int32
+}
+
+// Renews the claim on rows that are still held by the caller, i.e. still
+// pending with the attempt count it claimed them with.
+func (q *Queries) ExtendAlertLeases(ctx context.Context, arg ExtendAlertLeasesParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, extendAlertLeases, arg.Column1, arg.Column2, arg.Column3)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getIncidentDownGroup = `-- name: GetIncidentDownGroup :one
+SELECT
+    a.group_id,
+    (
+        SELECT COUNT(*)
+        FROM alerts g
+        JOIN monitor_incidents mi ON mi.id = g.incident_id
+        WHERE g.group_id = a.group_id AND mi.end_time IS NULL
+    )::INT AS open_incidents
+FROM alerts a
+WHERE a.incident_id = $1
+  AND a.plugin_id = $2
+  AND a.alert_type = 'DOWN'
+  AND a.group_id IS NOT NULL
+ORDER BY a.created_at
+LIMIT 1
+`
+
+type GetIncidentDownGroupParams struct {
+	IncidentID pgtype.UUID
+	PluginID   pgtype.UUID
+}
+
+type GetIncidentDownGroupRow struct {
+	GroupID       pgtype.UUID
+	OpenIncidents int32
+}
+
+func (q *Queries) GetIncidentDownGroup(ctx context.Context, arg GetIncidentDownGroupParams) (GetIncidentDownGroupRow, error) {
+	row := q.db.QueryRow(ctx, getIncidentDownGroup, arg.IncidentID, arg.PluginID)
+	var i GetIncidentDownGroupRow
+	err := row.Scan(&i.GroupID, &i.OpenIncidents)
+	return i, err
+}
+
+const incidentExistsForTeam = `-- name: IncidentExistsForTeam :one
+SELECT EXISTS (
+    SELECT 1
+    FROM monitor_incidents mi
+    JOIN monitors m ON m.id = mi.monitor_id
+    WHERE mi.id = $1 AND m.team_id = $2
+)
+`
+
+type IncidentExistsForTeamParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
+}
+
+func (q *Queries) IncidentExistsForTeam(ctx context.Context, arg IncidentExistsForTeamParams) (bool, error) {
+	row := q.db.QueryRow(ctx, incidentExistsForTeam, arg.ID, arg.TeamID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listAlertsByTeamCursor = `-- name: ListAlertsByTeamCursor :many
+SELECT
+    a.id,
+    a.incident_id,
+    mi.monitor_id,
+    m.url AS monitor_url,
+    a.channel,
+    a.alert_type,
+    a.status,
+    a.alert_email,
+    a.attempts,
+    a.last_error,
+    a.next_attempt_at,
+    a.sent_at,
+    a.created_at,
+    a.updated_at,
+    a.group_id,
+    a.plugin_id,
+    COALESCE(p.name, '') AS plugin_name
+FROM alerts a
+JOIN monitor_incidents mi ON mi.id = a.incident_id
+JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN plugins p ON p.id = a.plugin_id
+WHERE m.team_id = $1
+  AND ($2::text = '' OR a.channel = $2::text)
+  AND ($3::text = '' OR a.status = $3::text)
+  AND ($4::text = '' OR a.alert_type = $4::text)
+  AND ($5::uuid IS NULL OR mi.monitor_id = $5::uuid)
+  AND ($6::uuid IS NULL OR a.incident_id = $6::uuid)
+  AND ($7::timestamptz IS NULL OR a.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR a.created_at <= $8::timestamptz)
+  AND (
+    $9::timestamptz IS NULL
+        OR (a.created_at, a.id) < ($9::timestamptz, $10::uuid)
+    )
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $11
+`
+
+type ListAlertsByTeamCursorParams struct {
+	TeamID   pgtype.UUID
+	Column2  string
+	Column3  string
+	Column4  string
+	Column5  pgtype.UUID
+	Column6  pgtype.UUID
+	Column7  pgtype.Timestamptz
+	Column8  pgtype.Timestamptz
+	Column9  pgtype.Timestamptz
+	Column10 pgtype.UUID
+	Limit    int32
+}
+
+type ListAlertsByTeamCursorRow struct {
+	ID            pgtype.UUID
+	IncidentID    pgtype.UUID
+	MonitorID     pgtype.UUID
+	MonitorUrl    string
+	Channel       string
+	AlertType     string
+	Status        string
+	AlertEmail    string
+	Attempts      int32
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+	SentAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
+	GroupID       pgtype.UUID
+	PluginID      pgtype.UUID
+	PluginName    string
+}
+
+func (q *Queries) ListAlertsByTeamCursor(ctx context.Context, arg ListAlertsByTeamCursorParams) ([]ListAlertsByTeamCursorRow, error) {
+	rows, err := q.db.Query(ctx, listAlertsByTeamCursor,
+		arg.TeamID,
+		arg.Column2,
+		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
+		arg.Column8,
+		arg.Column9,
+		arg.Column10,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAlertsByTeamCursorRow
+	for rows.Next() {
+		var i ListAlertsByTeamCursorRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IncidentID,
+			&i.MonitorID,
+			&i.MonitorUrl,
+			&i.Channel,
+			&i.AlertType,
+			&i.Status,
+			&i.AlertEmail,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.SentAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.GroupID,
+			&i.PluginID,
+			&i.PluginName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAlertGroupSent = `-- name: MarkAlertGroupSent :exec
+UPDATE alerts
+SET
+    status = 'sent',
+    group_id = $2,
+    alert_email = $3,
+    sent_at = now(),
+    last_error = NULL,
+    updated_at = now()
+WHERE id = ANY($1::UUID[])
+`
+
+type MarkAlertGroupSentParams struct {
+	Column1    []pgtype.UUID
+	GroupID    pgtype.UUID
+	AlertEmail string
+}
+
+func (q *Queries) MarkAlertGroupSent(ctx context.Context, arg MarkAlertGroupSentParams) error {
+	_, err := q.db.Exec(ctx, markAlertGroupSent, arg.Column1, arg.GroupID, arg.AlertEmail)
+	return err
+}
+
+const markAlertSent = `-- name: MarkAlertSent :exec
+UPDATE alerts
+SET
+    status = 'sent',
+    alert_email = $2,
+    sent_at = now(),
+    last_error = NULL,
+    updated_at = now()
+WHERE id = $1
+`
+
+type MarkAlertSentParams struct {
+	ID         pgtype.UUID
+	AlertEmail string
+}
+
+func (q *Queries) MarkAlertSent(ctx context.Context, arg MarkAlertSentParams) error {
+	_, err := q.db.Exec(ctx, markAlertSent, arg.ID, arg.AlertEmail)
+	return err
+}
+
+const monitorSnoozed = `-- name: MonitorSnoozed :one
+SELECT EXISTS (
+    SELECT 1 FROM monitors
+    WHERE id = $1 AND snoozed_until > now()
+)
+`
+
+func (q *Queries) MonitorSnoozed(ctx context.Context, id pgtype.UUID) (bool, error) {
+	row := q.db.QueryRow(ctx, monitorSnoozed, id)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const rescheduleAlert = `-- name: RescheduleAlert :exec
+UPDATE alerts
+SET
+    last_error = $2,
+    next_attempt_at = $3,
+    updated_at = now()
+WHERE id = $1
+`
+
+type RescheduleAlertParams struct {
+	ID            pgtype.UUID
+	LastError     pgtype.Text
+	NextAttemptAt pgtype.Timestamptz
+}
+
+func (q *Queries) RescheduleAlert(ctx context.Context, arg RescheduleAlertParams) error {
+	_, err := q.db.Exec(ctx, rescheduleAlert, arg.ID, arg.LastError, arg.NextAttemptAt)
+	return err
+}
+
 const updateAlertStatus = `-- name: UpdateAlertStatus :execrows
 UPDATE alerts
 SET
@@ -71,8 +71,9 @@ func (q *Queries) CreateInvitation(ctx context.Context, arg CreateInvitationPara
 	return i, err
 }
 
-const deleteInvitation = `-- name: DeleteInvitation :exec
+const deleteInvitation = `-- name: DeleteInvitation :one
 DELETE FROM invitations WHERE id = $1 AND team_id = $2
+RETURNING email, role
 `
 
 type DeleteInvitationParams struct {
@@ -80,9 +81,16 @@ type DeleteInvitationParams struct {
 	TeamID pgtype.UUID
 }
 
-func (q *Queries) DeleteInvitation(ctx context.Context, arg DeleteInvitationParams) error {
-	_, err := q.db.Exec(ctx, deleteInvitation, arg.ID, arg.TeamID)
-	return err
+type DeleteInvitationRow struct {
+	Email string
+	Role  string
+}
+
+func (q *Queries) DeleteInvitation(ctx context.Context, arg DeleteInvitationParams) (DeleteInvitationRow, error) {
+	row := q.db.QueryRow(ctx, deleteInvitation, arg.ID, arg.TeamID)
+	var i DeleteInvitationRow
+	err := row.Scan(&i.Email, &i.Role)
+	return i, err
 }
 
 const getInvitationByToken = `-- name: GetInvitationByToken :one
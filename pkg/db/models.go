@@ -9,11 +9,98 @@ import (
 )
 
 type Alert struct {
+	ID            pgtype.UUID
+	IncidentID    pgtype.UUID
+	SentAt        pgtype.Timestamptz
+	AlertEmail    string
+	Status        string
+	CreatedAt     pgtype.Timestamptz
+	Channel       string
+	Payload       []byte
+	Attempts      int32
+	NextAttemptAt pgtype.Timestamptz
+	LastError     pgtype.Text
+	UpdatedAt     pgtype.Timestamptz
+	AlertType     string
+	GroupID       pgtype.UUID
+	PluginID      pgtype.UUID
+}
+
+type AlertTemplate struct {
+	ID        pgtype.UUID
+	TeamID    pgtype.UUID
+	Channel   string
+	AlertType string
+	Subject   string
+	Body      string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+}
+
+type ApiToken struct {
+	ID         pgtype.UUID
+	UserID     pgtype.UUID
+	TeamID     pgtype.UUID
+	Name       string
+	Prefix     string
+	TokenHash  string
+	Scopes     []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type AuditEvent struct {
+	ID         pgtype.UUID
+	TeamID     pgtype.UUID
+	ActorID    pgtype.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	Ip         string
+	Before     []byte
+	After      []byte
+	CreatedAt  pgtype.Timestamptz
+}
+
+type EscalationPolicy struct {
+	ID                pgtype.UUID
+	TeamID            pgtype.UUID
+	Name              string
+	Description       string
+	Levels            []byte
+	RepeatIntervalSec int32
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type IncidentEscalation struct {
+	IncidentID        pgtype.UUID
+	PolicyID          pgtype.UUID
+	TeamID            pgtype.UUID
+	Levels            []byte
+	RepeatIntervalSec int32
+	Event             []byte
+	Status            string
+	NextLevel         int32
+	Cycle             int32
+	NextFireAt        pgtype.Timestamptz
+	LastFiredAt       pgtype.Timestamptz
+	NotifiedChannels  []string
+	CreatedAt         pgtype.Timestamptz
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type IncidentEvent struct {
 	ID         pgtype.UUID
 	IncidentID pgtype.UUID
-	SentAt     pgtype.Timestamptz
-	AlertEmail string
-	Status     string
+	Kind       string
+	AuthorID   pgtype.UUID
+	Message    string
+	Data       []byte
+	OccurredAt pgtype.Timestamptz
 	CreatedAt  pgtype.Timestamptz
 }
 
@@ -48,17 +135,69 @@ type Monitor struct {
 	CreatedAt            pgtype.Timestamptz
 	TeamID               pgtype.UUID
 	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
+	Keyword              string
+	KeywordAbsent        bool
+	SnoozedUntil         pgtype.Timestamptz
+	SnoozeNotify         bool
+}
+
+type MonitorGroup struct {
+	ID          pgtype.UUID
+	TeamID      pgtype.UUID
+	ParentID    pgtype.UUID
+	Name        string
+	Description string
+	AlertMode   string
+	AlertWhen   string
+	Channels    []string
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+}
+
+type MonitorGroupMember struct {
+	GroupID   pgtype.UUID
+	MonitorID pgtype.UUID
+	CreatedAt pgtype.Timestamptz
 }
 
 type MonitorIncident struct {
-	ID         pgtype.UUID
-	MonitorID  pgtype.UUID
-	StartTime  pgtype.Timestamptz
-	EndTime    pgtype.Timestamptz
-	Alerted    bool
-	HttpStatus int32
-	LatencyMs  int32
-	CreatedAt  pgtype.Timestamptz
+	ID                     pgtype.UUID
+	MonitorID              pgtype.UUID
+	StartTime              pgtype.Timestamptz
+	EndTime                pgtype.Timestamptz
+	Alerted                bool
+	HttpStatus             int32
+	LatencyMs              int32
+	CreatedAt              pgtype.Timestamptz
+	AcknowledgedAt         pgtype.Timestamptz
+	AcknowledgedBy         pgtype.UUID
+	AssignedTo             pgtype.UUID
+	AssignedBy             pgtype.UUID
+	AssignedAt             pgtype.Timestamptz
+	RemindersSent          int32
+	NextReminderAt         pgtype.Timestamptz
+	AcknowledgedByExternal string
+	ResolvedExternallyAt   pgtype.Timestamptz
+}
+
+type MonitorRevision struct {
+	ID        pgtype.UUID
+	MonitorID pgtype.UUID
+	TeamID    pgtype.UUID
+	Revision  int32
+	Action    string
+	ActorID   pgtype.UUID
+	Snapshot  []byte
+	CreatedAt pgtype.Timestamptz
 }
 
 type Plugin struct {
@@ -69,6 +208,37 @@ type Plugin struct {
 	ConfigEnc  string
 	CreatedAt  pgtype.Timestamptz
 	UpdatedAt  pgtype.Timestamptz
+	Name       string
+}
+
+type RoutingRule struct {
+	ID              pgtype.UUID
+	TeamID          pgtype.UUID
+	Name            string
+	Position        int32
+	Enabled         bool
+	MatchTags       []string
+	MatchSeverities []string
+	MatchAlertTypes []string
+	TimeWindow      string
+	Channels        []string
+	UserIds         []pgtype.UUID
+	Stop            bool
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+type Session struct {
+	ID           pgtype.UUID
+	UserID       pgtype.UUID
+	RefreshHash  string
+	PreviousHash pgtype.Text
+	UserAgent    string
+	Ip           string
+	ExpiresAt    pgtype.Timestamptz
+	RevokedAt    pgtype.Timestamptz
+	LastUsedAt   pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
 }
 
 type Team struct {
@@ -78,6 +248,15 @@ type Team struct {
 	UpdatedAt pgtype.Timestamptz
 }
 
+type TeamBusinessHour struct {
+	TeamID      pgtype.UUID
+	Timezone    string
+	Days        []int32
+	StartMinute int32
+	EndMinute   int32
+	UpdatedAt   pgtype.Timestamptz
+}
+
 type TeamMember struct {
 	ID       pgtype.UUID
 	TeamID   pgtype.UUID
@@ -11,6 +11,61 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimEndedSnoozes = `-- name: ClaimEndedSnoozes :many
+UPDATE monitors
+SET snooze_notify = false
+WHERE id IN (
+    SELECT m.id FROM monitors m
+    WHERE m.snooze_notify AND m.snoozed_until <= now()
+    ORDER BY m.snoozed_until
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, team_id, url, name, runbook_url, notification_channels, snoozed_until, enabled
+`
+
+type ClaimEndedSnoozesRow struct {
+	ID                   pgtype.UUID
+	TeamID               pgtype.UUID
+	Url                  string
+	Name                 string
+	RunbookUrl           string
+	NotificationChannels string
+	SnoozedUntil         pgtype.Timestamptz
+	Enabled              bool
+}
+
+// Claims monitors whose snooze has ended and that asked to be notified,
+// clearing the flag so each end is notified once across instances.
+func (q *Queries) ClaimEndedSnoozes(ctx context.Context, limit int32) ([]ClaimEndedSnoozesRow, error) {
+	rows, err := q.db.Query(ctx, claimEndedSnoozes, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimEndedSnoozesRow
+	for rows.Next() {
+		var i ClaimEndedSnoozesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TeamID,
+			&i.Url,
+			&i.Name,
+			&i.RunbookUrl,
+			&i.NotificationChannels,
+			&i.SnoozedUntil,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createMonitor = `-- name: CreateMonitor :one
 INSERT INTO monitors (
     user_id,
@@ -21,7 +76,18 @@ INSERT INTO monitors (
     latency_threshold_ms,
     expected_status,
     alert_email,
-    notification_channels
+    notification_channels,
+    escalation_policy_id,
+    reminder_interval_sec,
+    reminder_max,
+    tags,
+    severity,
+    name,
+    description,
+    runbook_url,
+    slug,
+    keyword,
+    keyword_absent
 ) VALUES (
              $1,
              $2,
@@ -31,7 +97,18 @@ INSERT INTO monitors (
              $6,
              $7,
              $8,
-             $9
+             $9,
+             $10,
+             $11,
+             $12,
+             $13,
+             $14,
+             $15,
+             $16,
+             $17,
+             $18,
+             $19,
+             $20
          )
     RETURNING id
 `
@@ -46,6 +123,17 @@ type CreateMonitorParams struct {
 	ExpectedStatus       pgtype.Int4
 	AlertEmail           pgtype.Text
 	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
+	Keyword              string
+	KeywordAbsent        bool
 }
 
 func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (pgtype.UUID, error) {
@@ -59,6 +147,17 @@ func (q *Queries) CreateMonitor(ctx context.Context, arg CreateMonitorParams) (p
 		arg.ExpectedStatus,
 		arg.AlertEmail,
 		arg.NotificationChannels,
+		arg.EscalationPolicyID,
+		arg.ReminderIntervalSec,
+		arg.ReminderMax,
+		arg.Tags,
+		arg.Severity,
+		arg.Name,
+		arg.Description,
+		arg.RunbookUrl,
+		arg.Slug,
+		arg.Keyword,
+		arg.KeywordAbsent,
 	)
 	var id pgtype.UUID
 	err := row.Scan(&id)
@@ -84,7 +183,7 @@ func (q *Queries) DeleteMonitor(ctx context.Context, arg DeleteMonitorParams) (i
 }
 
 const getMonitorByID = `-- name: GetMonitorByID :one
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
 FROM monitors
 WHERE id = $1
 `
@@ -96,11 +195,24 @@ type GetMonitorByIDRow struct {
 	Url                  string
 	AlertEmail           pgtype.Text
 	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
 	IntervalSec          int32
 	TimeoutSec           int32
 	LatencyThresholdMs   pgtype.Int4
 	ExpectedStatus       pgtype.Int4
+	Keyword              string
+	KeywordAbsent        bool
 	Enabled              bool
+	SnoozedUntil         pgtype.Timestamptz
+	SnoozeNotify         bool
 }
 
 func (q *Queries) GetMonitorByID(ctx context.Context, id pgtype.UUID) (GetMonitorByIDRow, error) {
@@ -113,17 +225,30 @@ func (q *Queries) GetMonitorByID(ctx context.Context, id pgtype.UUID) (GetMonito
 		&i.Url,
 		&i.AlertEmail,
 		&i.NotificationChannels,
+		&i.EscalationPolicyID,
+		&i.ReminderIntervalSec,
+		&i.ReminderMax,
+		&i.Tags,
+		&i.Severity,
+		&i.Name,
+		&i.Description,
+		&i.RunbookUrl,
+		&i.Slug,
 		&i.IntervalSec,
 		&i.TimeoutSec,
 		&i.LatencyThresholdMs,
 		&i.ExpectedStatus,
+		&i.Keyword,
+		&i.KeywordAbsent,
 		&i.Enabled,
+		&i.SnoozedUntil,
+		&i.SnoozeNotify,
 	)
 	return i, err
 }
 
 const getMonitorByTeamID = `-- name: GetMonitorByTeamID :one
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
 FROM monitors
 WHERE id = $1 AND team_id = $2
 `
@@ -140,11 +265,24 @@ type GetMonitorByTeamIDRow struct {
 	Url                  string
 	AlertEmail           pgtype.Text
 	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
 	IntervalSec          int32
 	TimeoutSec           int32
 	LatencyThresholdMs   pgtype.Int4
 	ExpectedStatus       pgtype.Int4
+	Keyword              string
+	KeywordAbsent        bool
 	Enabled              bool
+	SnoozedUntil         pgtype.Timestamptz
+	SnoozeNotify         bool
 }
 
 func (q *Queries) GetMonitorByTeamID(ctx context.Context, arg GetMonitorByTeamIDParams) (GetMonitorByTeamIDRow, error) {
@@ -157,36 +295,70 @@ func (q *Queries) GetMonitorByTeamID(ctx context.Context, arg GetMonitorByTeamID
 		&i.Url,
 		&i.AlertEmail,
 		&i.NotificationChannels,
+		&i.EscalationPolicyID,
+		&i.ReminderIntervalSec,
+		&i.ReminderMax,
+		&i.Tags,
+		&i.Severity,
+		&i.Name,
+		&i.Description,
+		&i.RunbookUrl,
+		&i.Slug,
 		&i.IntervalSec,
 		&i.TimeoutSec,
 		&i.LatencyThresholdMs,
 		&i.ExpectedStatus,
+		&i.Keyword,
+		&i.KeywordAbsent,
 		&i.Enabled,
+		&i.SnoozedUntil,
+		&i.SnoozeNotify,
 	)
 	return i, err
 }
 
 const listMonitorsByTeamCursor = `-- name: ListMonitorsByTeamCursor :many
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec,
-       latency_threshold_ms, expected_status, enabled, created_at,
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec,
+       latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify, created_at,
        EXISTS (
            SELECT 1 FROM monitor_incidents mi
            WHERE mi.monitor_id = monitors.id AND mi.end_time IS NULL
-       ) AS is_down
+       ) AS is_down,
+       lower(COALESCE(NULLIF(name, ''), url))::TEXT AS sort_name
 FROM monitors
 WHERE team_id = $1
+  AND ($2::text = '' OR name ILIKE ('%' || $2 || '%') OR url ILIKE ('%' || $2 || '%'))
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS f(tag)
+    WHERE NOT EXISTS (
+        SELECT 1 FROM unnest(monitors.tags) AS t(tag)
+        WHERE t.tag = f.tag
+           OR (strpos(f.tag, ':') = 0 AND split_part(t.tag, ':', 1) = f.tag)
+    )
+  )
   AND (
-    $2::timestamptz IS NULL
-    OR (created_at, id) < ($2::timestamptz, $3::uuid)
+    $4::uuid IS NULL
+    OR ($5::text = 'created' AND (created_at, id) < ($6::timestamptz, $4::uuid))
+    OR ($5::text = 'name' AND (lower(COALESCE(NULLIF(name, ''), url)), id) > ($7::text, $4::uuid))
+    OR ($5::text = 'url' AND (url, id) > ($7::text, $4::uuid))
   )
-ORDER BY created_at DESC, id DESC
-LIMIT $4
+ORDER BY
+    CASE WHEN $5::text = 'name' THEN lower(COALESCE(NULLIF(name, ''), url)) END,
+    CASE WHEN $5::text = 'url' THEN url END,
+    CASE WHEN $5::text = 'created' THEN created_at END DESC,
+    CASE WHEN $5::text = 'created' THEN id END DESC,
+    id
+LIMIT $8
 `
 
 type ListMonitorsByTeamCursorParams struct {
 	TeamID  pgtype.UUID
-	Column2 pgtype.Timestamptz
-	Column3 pgtype.UUID
+	Column2 string
+	Column3 []string
+	Column4 pgtype.UUID
+	Column5 string
+	Column6 pgtype.Timestamptz
+	Column7 string
 	Limit   int32
 }
 
@@ -197,20 +369,41 @@ type ListMonitorsByTeamCursorRow struct {
 	Url                  string
 	AlertEmail           pgtype.Text
 	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
 	IntervalSec          int32
 	TimeoutSec           int32
 	LatencyThresholdMs   pgtype.Int4
 	ExpectedStatus       pgtype.Int4
+	Keyword              string
+	KeywordAbsent        bool
 	Enabled              bool
+	SnoozedUntil         pgtype.Timestamptz
+	SnoozeNotify         bool
 	CreatedAt            pgtype.Timestamptz
 	IsDown               bool
+	SortName             string
 }
 
+// $2 searches names and URLs, $3 holds tag filters that must all match: a
+// "key" filter matches every value of that key. $5 is the sort order:
+// 'created' (newest first), 'name' or 'url'; $4 and $6/$7 are the cursor.
 func (q *Queries) ListMonitorsByTeamCursor(ctx context.Context, arg ListMonitorsByTeamCursorParams) ([]ListMonitorsByTeamCursorRow, error) {
 	rows, err := q.db.Query(ctx, listMonitorsByTeamCursor,
 		arg.TeamID,
 		arg.Column2,
 		arg.Column3,
+		arg.Column4,
+		arg.Column5,
+		arg.Column6,
+		arg.Column7,
 		arg.Limit,
 	)
 	if err != nil {
@@ -227,13 +420,27 @@ func (q *Queries) ListMonitorsByTeamCursor(ctx context.Context, arg ListMonitors
 			&i.Url,
 			&i.AlertEmail,
 			&i.NotificationChannels,
+			&i.EscalationPolicyID,
+			&i.ReminderIntervalSec,
+			&i.ReminderMax,
+			&i.Tags,
+			&i.Severity,
+			&i.Name,
+			&i.Description,
+			&i.RunbookUrl,
+			&i.Slug,
 			&i.IntervalSec,
 			&i.TimeoutSec,
 			&i.LatencyThresholdMs,
 			&i.ExpectedStatus,
+			&i.Keyword,
+			&i.KeywordAbsent,
 			&i.Enabled,
+			&i.SnoozedUntil,
+			&i.SnoozeNotify,
 			&i.CreatedAt,
 			&i.IsDown,
+			&i.SortName,
 		); err != nil {
 			return nil, err
 		}
@@ -245,6 +452,227 @@ func (q *Queries) ListMonitorsByTeamCursor(ctx context.Context, arg ListMonitors
 	return items, nil
 }
 
+const listTeamMonitors = `-- name: ListTeamMonitors :many
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
+FROM monitors
+WHERE team_id = $1
+ORDER BY created_at, id
+`
+
+type ListTeamMonitorsRow struct {
+	ID                   pgtype.UUID
+	UserID               pgtype.UUID
+	TeamID               pgtype.UUID
+	Url                  string
+	AlertEmail           pgtype.Text
+	NotificationChannels string
+	EscalationPolicyID   pgtype.UUID
+	ReminderIntervalSec  int32
+	ReminderMax          int32
+	Tags                 []string
+	Severity             string
+	Name                 string
+	Description          string
+	RunbookUrl           string
+	Slug                 pgtype.Text
+	IntervalSec          int32
+	TimeoutSec           int32
+	LatencyThresholdMs   pgtype.Int4
+	ExpectedStatus       pgtype.Int4
+	Keyword              string
+	KeywordAbsent        bool
+	Enabled              bool
+	SnoozedUntil         pgtype.Timestamptz
+	SnoozeNotify         bool
+}
+
+func (q *Queries) ListTeamMonitors(ctx context.Context, teamID pgtype.UUID) ([]ListTeamMonitorsRow, error) {
+	rows, err := q.db.Query(ctx, listTeamMonitors, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTeamMonitorsRow
+	for rows.Next() {
+		var i ListTeamMonitorsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.TeamID,
+			&i.Url,
+			&i.AlertEmail,
+			&i.NotificationChannels,
+			&i.EscalationPolicyID,
+			&i.ReminderIntervalSec,
+			&i.ReminderMax,
+			&i.Tags,
+			&i.Severity,
+			&i.Name,
+			&i.Description,
+			&i.RunbookUrl,
+			&i.Slug,
+			&i.IntervalSec,
+			&i.TimeoutSec,
+			&i.LatencyThresholdMs,
+			&i.ExpectedStatus,
+			&i.Keyword,
+			&i.KeywordAbsent,
+			&i.Enabled,
+			&i.SnoozedUntil,
+			&i.SnoozeNotify,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockMonitor = `-- name: LockMonitor :one
+SELECT id FROM monitors
+WHERE id = $1 AND team_id = $2
+FOR UPDATE
+`
+
+type LockMonitorParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
+}
+
+// Locks the monitor until the transaction ends so that changes to it are
+// applied one at a time.
+func (q *Queries) LockMonitor(ctx context.Context, arg LockMonitorParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, lockMonitor, arg.ID, arg.TeamID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
+const setMonitorSlug = `-- name: SetMonitorSlug :execrows
+UPDATE monitors
+SET slug = $3, updated_at = now()
+WHERE id = $1 AND team_id = $2
+`
+
+type SetMonitorSlugParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
+	Slug   pgtype.Text
+}
+
+func (q *Queries) SetMonitorSlug(ctx context.Context, arg SetMonitorSlugParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setMonitorSlug, arg.ID, arg.TeamID, arg.Slug)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setMonitorSnooze = `-- name: SetMonitorSnooze :execrows
+UPDATE monitors
+SET snoozed_until = $3, snooze_notify = $4, updated_at = now()
+WHERE id = $1 AND team_id = $2
+`
+
+type SetMonitorSnoozeParams struct {
+	ID           pgtype.UUID
+	TeamID       pgtype.UUID
+	SnoozedUntil pgtype.Timestamptz
+	SnoozeNotify bool
+}
+
+func (q *Queries) SetMonitorSnooze(ctx context.Context, arg SetMonitorSnoozeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setMonitorSnooze,
+		arg.ID,
+		arg.TeamID,
+		arg.SnoozedUntil,
+		arg.SnoozeNotify,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateMonitorConfig = `-- name: UpdateMonitorConfig :execrows
+UPDATE monitors
+SET url = $3, interval_sec = $4, timeout_sec = $5, latency_threshold_ms = $6, expected_status = $7, notification_channels = $8, keyword = $9, keyword_absent = $10, updated_at = now()
+WHERE id = $1 AND team_id = $2
+`
+
+type UpdateMonitorConfigParams struct {
+	ID                   pgtype.UUID
+	TeamID               pgtype.UUID
+	Url                  string
+	IntervalSec          int32
+	TimeoutSec           int32
+	LatencyThresholdMs   pgtype.Int4
+	ExpectedStatus       pgtype.Int4
+	NotificationChannels string
+	Keyword              string
+	KeywordAbsent        bool
+}
+
+func (q *Queries) UpdateMonitorConfig(ctx context.Context, arg UpdateMonitorConfigParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMonitorConfig,
+		arg.ID,
+		arg.TeamID,
+		arg.Url,
+		arg.IntervalSec,
+		arg.TimeoutSec,
+		arg.LatencyThresholdMs,
+		arg.ExpectedStatus,
+		arg.NotificationChannels,
+		arg.Keyword,
+		arg.KeywordAbsent,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateMonitorSettings = `-- name: UpdateMonitorSettings :execrows
+UPDATE monitors
+SET name = $3, description = $4, runbook_url = $5, tags = $6, severity = $7, escalation_policy_id = $8, reminder_interval_sec = $9, reminder_max = $10, updated_at = now()
+WHERE id = $1 AND team_id = $2
+`
+
+type UpdateMonitorSettingsParams struct {
+	ID                  pgtype.UUID
+	TeamID              pgtype.UUID
+	Name                string
+	Description         string
+	RunbookUrl          string
+	Tags                []string
+	Severity            string
+	EscalationPolicyID  pgtype.UUID
+	ReminderIntervalSec int32
+	ReminderMax         int32
+}
+
+func (q *Queries) UpdateMonitorSettings(ctx context.Context, arg UpdateMonitorSettingsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateMonitorSettings,
+		arg.ID,
+		arg.TeamID,
+		arg.Name,
+		arg.Description,
+		arg.RunbookUrl,
+		arg.Tags,
+		arg.Severity,
+		arg.EscalationPolicyID,
+		arg.ReminderIntervalSec,
+		arg.ReminderMax,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateMonitorStatus = `-- name: UpdateMonitorStatus :execrows
 UPDATE monitors
 SET enabled = $2
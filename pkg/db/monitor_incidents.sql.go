@@ -11,6 +11,183 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acknowledgeIncident = `-- name: AcknowledgeIncident :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = now(),
+    acknowledged_by = $3,
+    assigned_to = COALESCE(mi.assigned_to, $3),
+    assigned_by = COALESCE(mi.assigned_by, $3),
+    assigned_at = COALESCE(mi.assigned_at, now())
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NULL
+`
+
+type AcknowledgeIncidentParams struct {
+	ID             pgtype.UUID
+	TeamID         pgtype.UUID
+	AcknowledgedBy pgtype.UUID
+}
+
+func (q *Queries) AcknowledgeIncident(ctx context.Context, arg AcknowledgeIncidentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acknowledgeIncident, arg.ID, arg.TeamID, arg.AcknowledgedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const acknowledgeIncidentExternal = `-- name: AcknowledgeIncidentExternal :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = now(),
+    acknowledged_by = $3,
+    acknowledged_by_external = $4,
+    assigned_to = COALESCE(mi.assigned_to, $3),
+    assigned_by = COALESCE(mi.assigned_by, $3),
+    assigned_at = COALESCE(mi.assigned_at, CASE WHEN $3::uuid IS NULL THEN NULL ELSE now() END)
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NULL
+`
+
+type AcknowledgeIncidentExternalParams struct {
+	ID                     pgtype.UUID
+	TeamID                 pgtype.UUID
+	AcknowledgedBy         pgtype.UUID
+	AcknowledgedByExternal string
+}
+
+func (q *Queries) AcknowledgeIncidentExternal(ctx context.Context, arg AcknowledgeIncidentExternalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acknowledgeIncidentExternal,
+		arg.ID,
+		arg.TeamID,
+		arg.AcknowledgedBy,
+		arg.AcknowledgedByExternal,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const assignIncident = `-- name: AssignIncident :execrows
+UPDATE monitor_incidents mi
+SET assigned_to = $3,
+    assigned_by = $4,
+    assigned_at = CASE WHEN $3::uuid IS NULL THEN NULL ELSE now() END
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+`
+
+type AssignIncidentParams struct {
+	ID         pgtype.UUID
+	TeamID     pgtype.UUID
+	AssignedTo pgtype.UUID
+	AssignedBy pgtype.UUID
+}
+
+func (q *Queries) AssignIncident(ctx context.Context, arg AssignIncidentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, assignIncident,
+		arg.ID,
+		arg.TeamID,
+		arg.AssignedTo,
+		arg.AssignedBy,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimDueReminders = `-- name: ClaimDueReminders :many
+WITH due AS (
+    SELECT id
+    FROM monitor_incidents
+    WHERE next_reminder_at <= now()
+      AND end_time IS NULL
+      AND acknowledged_at IS NULL
+    ORDER BY next_reminder_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE monitor_incidents mi
+SET reminders_sent = mi.reminders_sent + 1,
+    next_reminder_at = CASE
+        WHEN m.reminder_interval_sec <= 0 OR mi.reminders_sent + 1 >= m.reminder_max THEN NULL
+        ELSE now() + make_interval(secs => m.reminder_interval_sec)
+    END
+FROM due, monitors m
+WHERE mi.id = due.id AND m.id = mi.monitor_id
+RETURNING mi.id, mi.monitor_id, m.team_id, m.url AS monitor_url, m.name AS monitor_name, m.runbook_url, m.notification_channels,
+          mi.start_time, mi.http_status, mi.latency_ms, mi.reminders_sent,
+          (m.reminder_interval_sec > 0 AND m.reminder_max > 0)::BOOLEAN AS reminders_enabled,
+          COALESCE((
+              SELECT a.payload->>'reason'
+              FROM alerts a
+              WHERE a.incident_id = mi.id AND a.alert_type = 'DOWN'
+              ORDER BY a.created_at
+              LIMIT 1
+          ), '')::TEXT AS reason
+`
+
+type ClaimDueRemindersRow struct {
+	ID                   pgtype.UUID
+	MonitorID            pgtype.UUID
+	TeamID               pgtype.UUID
+	MonitorUrl           string
+	MonitorName          string
+	RunbookUrl           string
+	NotificationChannels string
+	StartTime            pgtype.Timestamptz
+	HttpStatus           int32
+	LatencyMs            int32
+	RemindersSent        int32
+	RemindersEnabled     bool
+	Reason               string
+}
+
+func (q *Queries) ClaimDueReminders(ctx context.Context, limit int32) ([]ClaimDueRemindersRow, error) {
+	rows, err := q.db.Query(ctx, claimDueReminders, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClaimDueRemindersRow
+	for rows.Next() {
+		var i ClaimDueRemindersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.MonitorID,
+			&i.TeamID,
+			&i.MonitorUrl,
+			&i.MonitorName,
+			&i.RunbookUrl,
+			&i.NotificationChannels,
+			&i.StartTime,
+			&i.HttpStatus,
+			&i.LatencyMs,
+			&i.RemindersSent,
+			&i.RemindersEnabled,
+			&i.Reason,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const closeMonitorIncident = `-- name: CloseMonitorIncident :one
 UPDATE monitor_incidents
 SET end_time = $2
@@ -31,8 +208,13 @@ func (q *Queries) CloseMonitorIncident(ctx context.Context, arg CloseMonitorInci
 }
 
 const createMonitorIncident = `-- name: CreateMonitorIncident :one
-INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms, next_reminder_at)
+VALUES ($1, $2, $3, $4, $5, (
+    SELECT CASE WHEN m.reminder_interval_sec > 0 AND m.reminder_max > 0
+                THEN $2 + make_interval(secs => m.reminder_interval_sec) END
+    FROM monitors m
+    WHERE m.id = $1
+))
 RETURNING id
 `
 
@@ -62,6 +244,7 @@ SELECT
     mi.id,
     mi.monitor_id,
     m.url AS monitor_url,
+    m.name AS monitor_name,
     mi.start_time,
     mi.end_time,
     mi.alerted,
@@ -74,9 +257,19 @@ SELECT
     COALESCE(a.alert_email, '') AS alert_email,
     a.sent_at AS alert_sent_at,
     m.expected_status,
-    m.latency_threshold_ms
+    m.latency_threshold_ms,
+    mi.acknowledged_at,
+    mi.acknowledged_by,
+    COALESCE(ack_u.name, mi.acknowledged_by_external) AS acknowledged_by_name,
+    mi.assigned_to,
+    COALESCE(asg_u.name, '') AS assigned_to_name,
+    mi.assigned_by,
+    mi.assigned_at,
+    m.tags AS monitor_tags
 FROM monitor_incidents mi
 JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN users ack_u ON ack_u.id = mi.acknowledged_by
+LEFT JOIN users asg_u ON asg_u.id = mi.assigned_to
 LEFT JOIN LATERAL (
     SELECT status, alert_email, sent_at
     FROM alerts
@@ -96,6 +289,7 @@ type GetIncidentByIDAndTeamIDRow struct {
 	ID                 pgtype.UUID
 	MonitorID          pgtype.UUID
 	MonitorUrl         string
+	MonitorName        string
 	StartTime          pgtype.Timestamptz
 	EndTime            pgtype.Timestamptz
 	Alerted            bool
@@ -109,6 +303,14 @@ type GetIncidentByIDAndTeamIDRow struct {
 	AlertSentAt        pgtype.Timestamptz
 	ExpectedStatus     pgtype.Int4
 	LatencyThresholdMs pgtype.Int4
+	AcknowledgedAt     pgtype.Timestamptz
+	AcknowledgedBy     pgtype.UUID
+	AcknowledgedByName string
+	AssignedTo         pgtype.UUID
+	AssignedToName     string
+	AssignedBy         pgtype.UUID
+	AssignedAt         pgtype.Timestamptz
+	MonitorTags        []string
 }
 
 func (q *Queries) GetIncidentByIDAndTeamID(ctx context.Context, arg GetIncidentByIDAndTeamIDParams) (GetIncidentByIDAndTeamIDRow, error) {
@@ -118,6 +320,7 @@ func (q *Queries) GetIncidentByIDAndTeamID(ctx context.Context, arg GetIncidentB
 		&i.ID,
 		&i.MonitorID,
 		&i.MonitorUrl,
+		&i.MonitorName,
 		&i.StartTime,
 		&i.EndTime,
 		&i.Alerted,
@@ -131,6 +334,14 @@ func (q *Queries) GetIncidentByIDAndTeamID(ctx context.Context, arg GetIncidentB
 		&i.AlertSentAt,
 		&i.ExpectedStatus,
 		&i.LatencyThresholdMs,
+		&i.AcknowledgedAt,
+		&i.AcknowledgedBy,
+		&i.AcknowledgedByName,
+		&i.AssignedTo,
+		&i.AssignedToName,
+		&i.AssignedBy,
+		&i.AssignedAt,
+		&i.MonitorTags,
 	)
 	return i, err
 }
@@ -141,9 +352,20 @@ FROM monitor_incidents
 WHERE id = $1
 `
 
-func (q *Queries) GetMonitorIncidentByID(ctx context.Context, id pgtype.UUID) (MonitorIncident, error) {
+type GetMonitorIncidentByIDRow struct {
+	ID         pgtype.UUID
+	MonitorID  pgtype.UUID
+	StartTime  pgtype.Timestamptz
+	EndTime    pgtype.Timestamptz
+	Alerted    bool
+	HttpStatus int32
+	LatencyMs  int32
+	CreatedAt  pgtype.Timestamptz
+}
+
+func (q *Queries) GetMonitorIncidentByID(ctx context.Context, id pgtype.UUID) (GetMonitorIncidentByIDRow, error) {
 	row := q.db.QueryRow(ctx, getMonitorIncidentByID, id)
-	var i MonitorIncident
+	var i GetMonitorIncidentByIDRow
 	err := row.Scan(
 		&i.ID,
 		&i.MonitorID,
@@ -157,11 +379,27 @@ func (q *Queries) GetMonitorIncidentByID(ctx context.Context, id pgtype.UUID) (M
 	return i, err
 }
 
+const getOpenMonitorIncident = `-- name: GetOpenMonitorIncident :one
+SELECT id
+FROM monitor_incidents
+WHERE monitor_id = $1 AND end_time IS NULL
+ORDER BY start_time DESC
+LIMIT 1
+`
+
+func (q *Queries) GetOpenMonitorIncident(ctx context.Context, monitorID pgtype.UUID) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getOpenMonitorIncident, monitorID)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const listIncidentsByTeamCursor = `-- name: ListIncidentsByTeamCursor :many
 SELECT
     mi.id,
     mi.monitor_id,
     m.url AS monitor_url,
+    m.name AS monitor_name,
     mi.start_time,
     mi.end_time,
     mi.alerted,
@@ -174,9 +412,20 @@ SELECT
     COALESCE(a.alert_email, '') AS alert_email,
     a.sent_at AS alert_sent_at,
     m.expected_status,
-    m.latency_threshold_ms
+    m.latency_threshold_ms,
+    mi.acknowledged_at,
+    mi.acknowledged_by,
+    COALESCE(ack_u.name, mi.acknowledged_by_external) AS acknowledged_by_name,
+    mi.assigned_to,
+    COALESCE(asg_u.name, '') AS assigned_to_name,
+    mi.assigned_by,
+    mi.assigned_at,
+    m.tags AS monitor_tags,
+    lower(COALESCE(NULLIF(m.name, ''), m.url))::TEXT AS sort_name
 FROM monitor_incidents mi
 JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN users ack_u ON ack_u.id = mi.acknowledged_by
+LEFT JOIN users asg_u ON asg_u.id = mi.assigned_to
 LEFT JOIN LATERAL (
     SELECT status, alert_email, sent_at
     FROM alerts
@@ -192,32 +441,60 @@ WHERE m.team_id = $1
     )
   AND ($3::timestamptz IS NULL OR mi.start_time >= $3::timestamptz)
   AND ($4::timestamptz IS NULL OR mi.start_time <= $4::timestamptz)
-  AND ($5::text = '' OR m.url ILIKE ('%' || $5 || '%'))
+  AND ($5::text = '' OR m.url ILIKE ('%' || $5 || '%') OR m.name ILIKE ('%' || $5 || '%'))
   AND ($6::uuid IS NULL OR mi.monitor_id = $6::uuid)
   AND (
-    $7::timestamptz IS NULL
-        OR (mi.start_time, mi.id) < ($7::timestamptz, $8::uuid)
+    $8::uuid IS NULL
+        OR ($13::text = 'newest' AND (mi.start_time, mi.id) < ($7::timestamptz, $8::uuid))
+        OR ($13::text = 'oldest' AND (mi.start_time, mi.id) > ($7::timestamptz, $8::uuid))
+        OR ($13::text = 'name' AND (lower(COALESCE(NULLIF(m.name, ''), m.url)), mi.id) > ($14::text, $8::uuid))
+    )
+  AND (
+    $9::text = 'all'
+        OR ($9::text = 'acknowledged' AND mi.acknowledged_at IS NOT NULL)
+        OR ($9::text = 'unacknowledged' AND mi.acknowledged_at IS NULL)
     )
-ORDER BY mi.start_time DESC, mi.id DESC
-LIMIT $9
+  AND ($10::uuid IS NULL OR mi.assigned_to = $10::uuid)
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($12::text[]) AS f(tag)
+    WHERE NOT EXISTS (
+        SELECT 1 FROM unnest(m.tags) AS t(tag)
+        WHERE t.tag = f.tag
+           OR (strpos(f.tag, ':') = 0 AND split_part(t.tag, ':', 1) = f.tag)
+    )
+  )
+ORDER BY
+    CASE WHEN $13::text = 'name' THEN lower(COALESCE(NULLIF(m.name, ''), m.url)) END,
+    CASE WHEN $13::text = 'oldest' THEN mi.start_time END,
+    CASE WHEN $13::text = 'oldest' THEN mi.id END,
+    CASE WHEN $13::text = 'newest' THEN mi.start_time END DESC,
+    CASE WHEN $13::text = 'newest' THEN mi.id END DESC,
+    mi.id
+LIMIT $11
 `
 
 type ListIncidentsByTeamCursorParams struct {
-	TeamID  pgtype.UUID
-	Column2 string
-	Column3 pgtype.Timestamptz
-	Column4 pgtype.Timestamptz
-	Column5 string
-	Column6 pgtype.UUID
-	Column7 pgtype.Timestamptz
-	Column8 pgtype.UUID
-	Limit   int32
+	TeamID   pgtype.UUID
+	Column2  string
+	Column3  pgtype.Timestamptz
+	Column4  pgtype.Timestamptz
+	Column5  string
+	Column6  pgtype.UUID
+	Column7  pgtype.Timestamptz
+	Column8  pgtype.UUID
+	Column9  string
+	Column10 pgtype.UUID
+	Limit    int32
+	Column12 []string
+	Column13 string
+	Column14 string
 }
 
 type ListIncidentsByTeamCursorRow struct {
 	ID                 pgtype.UUID
 	MonitorID          pgtype.UUID
 	MonitorUrl         string
+	MonitorName        string
 	StartTime          pgtype.Timestamptz
 	EndTime            pgtype.Timestamptz
 	Alerted            bool
@@ -231,8 +508,20 @@ type ListIncidentsByTeamCursorRow struct {
 	AlertSentAt        pgtype.Timestamptz
 	ExpectedStatus     pgtype.Int4
 	LatencyThresholdMs pgtype.Int4
+	AcknowledgedAt     pgtype.Timestamptz
+	AcknowledgedBy     pgtype.UUID
+	AcknowledgedByName string
+	AssignedTo         pgtype.UUID
+	AssignedToName     string
+	AssignedBy         pgtype.UUID
+	AssignedAt         pgtype.Timestamptz
+	MonitorTags        []string
+	SortName           string
 }
 
+// $12 holds monitor tag filters as in ListMonitorsByTeamCursor. $13 is the
+// sort order: 'newest', 'oldest' or 'name' (monitor name); $7/$14 and $8
+// are the cursor.
 func (q *Queries) ListIncidentsByTeamCursor(ctx context.Context, arg ListIncidentsByTeamCursorParams) ([]ListIncidentsByTeamCursorRow, error) {
 	rows, err := q.db.Query(ctx, listIncidentsByTeamCursor,
 		arg.TeamID,
@@ -243,7 +532,12 @@ func (q *Queries) ListIncidentsByTeamCursor(ctx context.Context, arg ListInciden
 		arg.Column6,
 		arg.Column7,
 		arg.Column8,
+		arg.Column9,
+		arg.Column10,
 		arg.Limit,
+		arg.Column12,
+		arg.Column13,
+		arg.Column14,
 	)
 	if err != nil {
 		return nil, err
@@ -256,6 +550,7 @@ func (q *Queries) ListIncidentsByTeamCursor(ctx context.Context, arg ListInciden
 			&i.ID,
 			&i.MonitorID,
 			&i.MonitorUrl,
+			&i.MonitorName,
 			&i.StartTime,
 			&i.EndTime,
 			&i.Alerted,
@@ -269,6 +564,15 @@ func (q *Queries) ListIncidentsByTeamCursor(ctx context.Context, arg ListInciden
 			&i.AlertSentAt,
 			&i.ExpectedStatus,
 			&i.LatencyThresholdMs,
+			&i.AcknowledgedAt,
+			&i.AcknowledgedBy,
+			&i.AcknowledgedByName,
+			&i.AssignedTo,
+			&i.AssignedToName,
+			&i.AssignedBy,
+			&i.AssignedAt,
+			&i.MonitorTags,
+			&i.SortName,
 		); err != nil {
 			return nil, err
 		}
@@ -279,3 +583,122 @@ func (q *Queries) ListIncidentsByTeamCursor(ctx context.Context, arg ListInciden
 	}
 	return items, nil
 }
+
+const listOpenDigestIncidents = `-- name: ListOpenDigestIncidents :many
+SELECT DISTINCT mi.id
+FROM alerts a
+JOIN monitor_incidents mi ON mi.id = a.incident_id
+JOIN monitors m ON m.id = mi.monitor_id
+WHERE a.group_id = $1
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+`
+
+type ListOpenDigestIncidentsParams struct {
+	GroupID pgtype.UUID
+	TeamID  pgtype.UUID
+}
+
+func (q *Queries) ListOpenDigestIncidents(ctx context.Context, arg ListOpenDigestIncidentsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listOpenDigestIncidents, arg.GroupID, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOpenMonitorIncidents = `-- name: ListOpenMonitorIncidents :many
+SELECT mi.id
+FROM monitor_incidents mi
+JOIN monitors m ON m.id = mi.monitor_id
+WHERE mi.monitor_id = $1
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+`
+
+type ListOpenMonitorIncidentsParams struct {
+	MonitorID pgtype.UUID
+	TeamID    pgtype.UUID
+}
+
+func (q *Queries) ListOpenMonitorIncidents(ctx context.Context, arg ListOpenMonitorIncidentsParams) ([]pgtype.UUID, error) {
+	rows, err := q.db.Query(ctx, listOpenMonitorIncidents, arg.MonitorID, arg.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []pgtype.UUID
+	for rows.Next() {
+		var id pgtype.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markIncidentResolvedExternal = `-- name: MarkIncidentResolvedExternal :execrows
+UPDATE monitor_incidents mi
+SET resolved_externally_at = now()
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.resolved_externally_at IS NULL
+`
+
+type MarkIncidentResolvedExternalParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
+}
+
+func (q *Queries) MarkIncidentResolvedExternal(ctx context.Context, arg MarkIncidentResolvedExternalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markIncidentResolvedExternal, arg.ID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unacknowledgeIncident = `-- name: UnacknowledgeIncident :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = NULL,
+    acknowledged_by = NULL,
+    acknowledged_by_external = ''
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NOT NULL
+`
+
+type UnacknowledgeIncidentParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
+}
+
+func (q *Queries) UnacknowledgeIncident(ctx context.Context, arg UnacknowledgeIncidentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unacknowledgeIncident, arg.ID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
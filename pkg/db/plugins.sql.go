@@ -11,33 +11,72 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const deletePlugin = `-- name: DeletePlugin :exec
-DELETE FROM plugins
-WHERE team_id = $1 AND plugin_type = $2
+const createPlugin = `-- name: CreatePlugin :one
+INSERT INTO plugins (team_id, plugin_type, name, enabled, config_enc)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, team_id, plugin_type, enabled, config_enc, created_at, updated_at, name
 `
 
-type DeletePluginParams struct {
+type CreatePluginParams struct {
 	TeamID     pgtype.UUID
 	PluginType string
+	Name       string
+	Enabled    bool
+	ConfigEnc  string
+}
+
+func (q *Queries) CreatePlugin(ctx context.Context, arg CreatePluginParams) (Plugin, error) {
+	row := q.db.QueryRow(ctx, createPlugin,
+		arg.TeamID,
+		arg.PluginType,
+		arg.Name,
+		arg.Enabled,
+		arg.ConfigEnc,
+	)
+	var i Plugin
+	err := row.Scan(
+		&i.ID,
+		&i.TeamID,
+		&i.PluginType,
+		&i.Enabled,
+		&i.ConfigEnc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const deletePlugin = `-- name: DeletePlugin :execrows
+DELETE FROM plugins
+WHERE id = $1 AND team_id = $2
+`
+
+type DeletePluginParams struct {
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
 }
 
-func (q *Queries) DeletePlugin(ctx context.Context, arg DeletePluginParams) error {
-	_, err := q.db.Exec(ctx, deletePlugin, arg.TeamID, arg.PluginType)
-	return err
+func (q *Queries) DeletePlugin(ctx context.Context, arg DeletePluginParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePlugin, arg.ID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getPlugin = `-- name: GetPlugin :one
-SELECT id, team_id, plugin_type, enabled, config_enc, created_at, updated_at FROM plugins
-WHERE team_id = $1 AND plugin_type = $2
+SELECT id, team_id, plugin_type, enabled, config_enc, created_at, updated_at, name FROM plugins
+WHERE id = $1 AND team_id = $2
 `
 
 type GetPluginParams struct {
-	TeamID     pgtype.UUID
-	PluginType string
+	ID     pgtype.UUID
+	TeamID pgtype.UUID
 }
 
 func (q *Queries) GetPlugin(ctx context.Context, arg GetPluginParams) (Plugin, error) {
-	row := q.db.QueryRow(ctx, getPlugin, arg.TeamID, arg.PluginType)
+	row := q.db.QueryRow(ctx, getPlugin, arg.ID, arg.TeamID)
 	var i Plugin
 	err := row.Scan(
 		&i.ID,
@@ -47,14 +86,75 @@ func (q *Queries) GetPlugin(ctx context.Context, arg GetPluginParams) (Plugin, e
 		&i.ConfigEnc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Name,
 	)
 	return i, err
 }
 
+const getPluginByID = `-- name: GetPluginByID :one
+SELECT id, team_id, plugin_type, enabled, config_enc, created_at, updated_at, name FROM plugins
+WHERE id = $1
+`
+
+func (q *Queries) GetPluginByID(ctx context.Context, id pgtype.UUID) (Plugin, error) {
+	row := q.db.QueryRow(ctx, getPluginByID, id)
+	var i Plugin
+	err := row.Scan(
+		&i.ID,
+		&i.TeamID,
+		&i.PluginType,
+		&i.Enabled,
+		&i.ConfigEnc,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+	)
+	return i, err
+}
+
+const listPluginInstances = `-- name: ListPluginInstances :many
+SELECT id, plugin_type, name, enabled
+FROM plugins
+WHERE team_id = $1
+ORDER BY plugin_type, name
+`
+
+type ListPluginInstancesRow struct {
+	ID         pgtype.UUID
+	PluginType string
+	Name       string
+	Enabled    bool
+}
+
+func (q *Queries) ListPluginInstances(ctx context.Context, teamID pgtype.UUID) ([]ListPluginInstancesRow, error) {
+	rows, err := q.db.Query(ctx, listPluginInstances, teamID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPluginInstancesRow
+	for rows.Next() {
+		var i ListPluginInstancesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PluginType,
+			&i.Name,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPlugins = `-- name: ListPlugins :many
-SELECT id, team_id, plugin_type, enabled, config_enc, created_at, updated_at FROM plugins
+SELECT id, team_id, plugin_type, enabled, config_enc, created_at, updated_at, name FROM plugins
 WHERE team_id = $1
-ORDER BY plugin_type
+ORDER BY plugin_type, name
 `
 
 func (q *Queries) ListPlugins(ctx context.Context, teamID pgtype.UUID) ([]Plugin, error) {
@@ -74,6 +174,7 @@ func (q *Queries) ListPlugins(ctx context.Context, teamID pgtype.UUID) ([]Plugin
 			&i.ConfigEnc,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Name,
 		); err != nil {
 			return nil, err
 		}
@@ -85,28 +186,30 @@ func (q *Queries) ListPlugins(ctx context.Context, teamID pgtype.UUID) ([]Plugin
 	return items, nil
 }
 
-const upsertPlugin = `-- name: UpsertPlugin :one
-INSERT INTO plugins (team_id, plugin_type, enabled, config_enc, updated_at)
-VALUES ($1, $2, $3, $4, now())
-ON CONFLICT (team_id, plugin_type)
-DO UPDATE SET
-    enabled    = EXCLUDED.enabled,
-    config_enc = EXCLUDED.config_enc,
+const updatePlugin = `-- name: UpdatePlugin :one
+UPDATE plugins
+SET
+    name       = $3,
+    enabled    = $4,
+    config_enc = $5,
     updated_at = now()
-RETURNING id, team_id, plugin_type, enabled, config_enc, created_at, updated_at
+WHERE id = $1 AND team_id = $2
+RETURNING id, team_id, plugin_type, enabled, config_enc, created_at, updated_at, name
 `
 
-type UpsertPluginParams struct {
-	TeamID     pgtype.UUID
-	PluginType string
-	Enabled    bool
-	ConfigEnc  string
+type UpdatePluginParams struct {
+	ID        pgtype.UUID
+	TeamID    pgtype.UUID
+	Name      string
+	Enabled   bool
+	ConfigEnc string
 }
 
-func (q *Queries) UpsertPlugin(ctx context.Context, arg UpsertPluginParams) (Plugin, error) {
-	row := q.db.QueryRow(ctx, upsertPlugin,
+func (q *Queries) UpdatePlugin(ctx context.Context, arg UpdatePluginParams) (Plugin, error) {
+	row := q.db.QueryRow(ctx, updatePlugin,
+		arg.ID,
 		arg.TeamID,
-		arg.PluginType,
+		arg.Name,
 		arg.Enabled,
 		arg.ConfigEnc,
 	)
@@ -119,6 +222,7 @@ func (q *Queries) UpsertPlugin(ctx context.Context, arg UpsertPluginParams) (Plu
 		&i.ConfigEnc,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Name,
 	)
 	return i, err
 }
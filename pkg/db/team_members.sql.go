@@ -37,6 +37,27 @@ func (q *Queries) AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (T
 	return i, err
 }
 
+const getActiveTeamMemberByEmail = `-- name: GetActiveTeamMemberByEmail :one
+SELECT u.id
+FROM team_members tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+  AND lower(u.email) = lower($2)
+  AND tm.is_active = true
+`
+
+type GetActiveTeamMemberByEmailParams struct {
+	TeamID pgtype.UUID
+	Lower  string
+}
+
+func (q *Queries) GetActiveTeamMemberByEmail(ctx context.Context, arg GetActiveTeamMemberByEmailParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, getActiveTeamMemberByEmail, arg.TeamID, arg.Lower)
+	var id pgtype.UUID
+	err := row.Scan(&id)
+	return id, err
+}
+
 const getTeamMembership = `-- name: GetTeamMembership :one
 SELECT tm.id, tm.team_id, tm.user_id, tm.role, tm.is_active, tm.joined_at
 FROM team_members tm
@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client executes Discord channel webhooks.
+type Client interface {
+	Send(ctx context.Context, req *WebhookRequest) (*WebhookResponse, error)
+}
+
+type clientImpl struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+func NewClient(webhookURL string) Client {
+	return &clientImpl{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *clientImpl) Send(ctx context.Context, req *WebhookRequest) (*WebhookResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("discord: marshal request: %w", err)
+	}
+
+	// wait=true makes Discord return the created message instead of 204.
+	target, err := url.Parse(c.webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("discord: parse webhook url: %w", err)
+	}
+	q := target.Query()
+	q.Set("wait", "true")
+	target.RawQuery = q.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("discord: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("discord: send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("discord: unexpected status %d: %s", resp.StatusCode, string(raw))
+	}
+
+	var result WebhookResponse
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("discord: decode response: %w", err)
+		}
+	}
+	return &result, nil
+}
@@ -0,0 +1,77 @@
+package msteams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+)
+
+// Client posts Adaptive Cards to a Microsoft Teams incoming webhook.
+type Client interface {
+	SendCard(ctx context.Context, card AdaptiveCard) error
+}
+
+type clientImpl struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+func NewClient(webhookURL string) Client {
+	return &clientImpl{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewCard returns an empty Adaptive Card with the schema fields populated.
+func NewCard(body []CardElement, actions []CardAction) AdaptiveCard {
+	return AdaptiveCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+		Body:    body,
+		Actions: actions,
+	}
+}
+
+func (c *clientImpl) SendCard(ctx context.Context, card AdaptiveCard) error {
+	msg := Message{
+		Type: "message",
+		Attachments: []Attachment{
+			{ContentType: adaptiveCardContentType, Content: card},
+		},
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("msteams: marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("msteams: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("msteams: send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("msteams: unexpected status %d: %s", resp.StatusCode, string(raw))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// IncrementAlertRate counts a notification sent through a team's plugin
+// instance in the fixed window starting at windowStart and returns the
+// count so far. The counter expires together with the window.
+func (c *Client) IncrementAlertRate(ctx context.Context, teamID, pluginID uuid.UUID, windowStart time.Time, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("alert:rate:%s:%s:%d", pluginID.String(), teamID.String(), windowStart.Unix())
+
+	// INCR and EXPIRE run in one MULTI: a failed attempt applies neither,
+	// so retrying it never counts the same notification twice
+	var incr *redis.IntCmd
+	err := retry(ctx, 2, func() error {
+		_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, window+time.Minute)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// OpenAlertWindow starts a grouping window for a plugin instance and alert
+// type. It reports false when a window is already open, in which case the
+// alert should wait to be sent in a digest.
+func (c *Client) OpenAlertWindow(ctx context.Context, pluginID uuid.UUID, alertType string, window time.Duration) (bool, error) {
+	key := fmt.Sprintf("alert:window:%s:%s", pluginID.String(), alertType)
+
+	var opened bool
+	err := retry(ctx, 2, func() error {
+		var err error
+		opened, err = c.rdb.SetNX(ctx, key, 1, window).Result()
+		return err
+	})
+	return opened, err
+}
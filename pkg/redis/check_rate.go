@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// IncrementCheckRate counts an on-demand check run by a team in the fixed
+// window starting at windowStart and returns the count so far. The counter
+// expires together with the window.
+func (c *Client) IncrementCheckRate(ctx context.Context, teamID uuid.UUID, windowStart time.Time, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("check:rate:%s:%d", teamID.String(), windowStart.Unix())
+
+	// INCR and EXPIRE run in one MULTI: a failed attempt applies neither,
+	// so retrying it never counts the same check twice
+	var incr *redis.IntCmd
+	err := retry(ctx, 2, func() error {
+		_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.Incr(ctx, key)
+			pipe.ExpireNX(ctx, key, window+time.Minute)
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// escalationKey is a sorted set of incident ids scored by the unix time
+// their next escalation level is due. Postgres holds the actual state; this
+// is only the wake-up schedule and is rebuilt from it on startup.
+const escalationKey string = "escalation:schedule"
+
+func (c *Client) ScheduleEscalation(ctx context.Context, incidentID string, at time.Time) error {
+	return retry(ctx, 3, func() error {
+		return c.rdb.ZAdd(ctx, escalationKey, redis.Z{
+			Score:  float64(at.Unix()),
+			Member: incidentID,
+		}).Err()
+	})
+}
+
+// ClaimDueEscalations returns up to limit incident ids due at or before now
+// and removes them from the schedule. An id is only returned to the caller
+// that removed it, so concurrent workers never fire the same incident.
+func (c *Client) ClaimDueEscalations(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	due, err := c.rdb.ZRangeByScore(ctx, escalationKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	claimed := make([]string, 0, len(due))
+	for _, id := range due {
+		n, err := c.rdb.ZRem(ctx, escalationKey, id).Result()
+		if err != nil {
+			return claimed, err
+		}
+		if n == 1 {
+			claimed = append(claimed, id)
+		}
+	}
+	return claimed, nil
+}
+
+func (c *Client) DelEscalation(ctx context.Context, incidentID string) error {
+	return c.rdb.ZRem(ctx, escalationKey, incidentID).Err()
+}
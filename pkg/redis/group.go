@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// OpenGroupAlert stores the DOWN alert sent for a monitor group. It reports
+// false when the group already has an open alert.
+func (c *Client) OpenGroupAlert(ctx context.Context, groupID uuid.UUID, event []byte) (bool, error) {
+	key := fmt.Sprintf("group:alert:%s", groupID.String())
+
+	var opened bool
+	err := retry(ctx, 2, func() error {
+		var err error
+		opened, err = c.rdb.SetNX(ctx, key, event, 0).Result()
+		return err
+	})
+	return opened, err
+}
+
+// CloseGroupAlert removes a group's open alert and returns the stored DOWN
+// alert, or nil when there was none. Only one caller gets the alert back.
+func (c *Client) CloseGroupAlert(ctx context.Context, groupID uuid.UUID) ([]byte, error) {
+	key := fmt.Sprintf("group:alert:%s", groupID.String())
+
+	res, err := c.rdb.GetDel(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return res, err
+}
@@ -99,11 +99,14 @@ func (c *Client) ClearIncidentIfExists(ctx context.Context, monitorID uuid.UUID)
 	return n == 1, err
 }
 
-func (c *Client) MarkDBIncidentCreated(ctx context.Context, monitorID uuid.UUID) error {
+// MarkDBIncidentCreated records the DB incident opened for the monitor, so
+// recovery can still find it if closing it and alerting are retried.
+func (c *Client) MarkDBIncidentCreated(ctx context.Context, monitorID, incidentID uuid.UUID) error {
 	key := fmt.Sprintf("monitor:incident:%v", monitorID.String())
 
 	return c.rdb.HSet(ctx, key,
 		"db_incident", "true",
+		"incident_id", incidentID.String(),
 	).Err()
 }
 
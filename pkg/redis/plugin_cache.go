@@ -6,60 +6,35 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/alkush-pipania/sofon/internals/modules/alert"
 	"github.com/google/uuid"
 )
 
-func (c *Client) GetCachedResendConfig(ctx context.Context, teamID uuid.UUID) (alert.ResendEmailConfig, bool) {
-	key := fmt.Sprintf("plugin:resend:%s", teamID.String())
-	raw, err := c.rdb.Get(ctx, key).Bytes()
-	if err != nil {
-		return alert.ResendEmailConfig{}, false
-	}
-	var cfg alert.ResendEmailConfig
-	if err := json.Unmarshal(raw, &cfg); err != nil {
-		return alert.ResendEmailConfig{}, false
-	}
-	return cfg, true
-}
-
-func (c *Client) SetCachedResendConfig(ctx context.Context, teamID uuid.UUID, cfg alert.ResendEmailConfig, ttl time.Duration) error {
-	key := fmt.Sprintf("plugin:resend:%s", teamID.String())
-	b, err := json.Marshal(cfg)
-	if err != nil {
-		return err
-	}
-	return c.rdb.Set(ctx, key, b, ttl).Err()
-}
-
-func (c *Client) DelCachedResendConfig(ctx context.Context, teamID uuid.UUID) error {
-	key := fmt.Sprintf("plugin:resend:%s", teamID.String())
-	return c.rdb.Del(ctx, key).Err()
+// pluginConfigKey is keyed on the plugin instance; a team can configure
+// the same plugin type several times.
+func pluginConfigKey(teamID, pluginID uuid.UUID) string {
+	return fmt.Sprintf("plugin:%s:%s", teamID.String(), pluginID.String())
 }
 
-func (c *Client) GetCachedZendutyConfig(ctx context.Context, teamID uuid.UUID) (alert.ZendutyConfig, bool) {
-	key := fmt.Sprintf("plugin:zenduty:%s", teamID.String())
-	raw, err := c.rdb.Get(ctx, key).Bytes()
+func (c *Client) GetCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) (map[string]string, bool) {
+	raw, err := c.rdb.Get(ctx, pluginConfigKey(teamID, pluginID)).Bytes()
 	if err != nil {
-		return alert.ZendutyConfig{}, false
+		return nil, false
 	}
-	var cfg alert.ZendutyConfig
+	var cfg map[string]string
 	if err := json.Unmarshal(raw, &cfg); err != nil {
-		return alert.ZendutyConfig{}, false
+		return nil, false
 	}
 	return cfg, true
 }
 
-func (c *Client) SetCachedZendutyConfig(ctx context.Context, teamID uuid.UUID, cfg alert.ZendutyConfig, ttl time.Duration) error {
-	key := fmt.Sprintf("plugin:zenduty:%s", teamID.String())
+func (c *Client) SetCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID, cfg map[string]string, ttl time.Duration) error {
 	b, err := json.Marshal(cfg)
 	if err != nil {
 		return err
 	}
-	return c.rdb.Set(ctx, key, b, ttl).Err()
+	return c.rdb.Set(ctx, pluginConfigKey(teamID, pluginID), b, ttl).Err()
 }
 
-func (c *Client) DelCachedZendutyConfig(ctx context.Context, teamID uuid.UUID) error {
-	key := fmt.Sprintf("plugin:zenduty:%s", teamID.String())
-	return c.rdb.Del(ctx, key).Err()
+func (c *Client) DelCachedPluginConfig(ctx context.Context, teamID, pluginID uuid.UUID) error {
+	return c.rdb.Del(ctx, pluginConfigKey(teamID, pluginID)).Err()
 }
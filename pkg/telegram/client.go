@@ -0,0 +1,62 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const apiBaseURL = "https://api.telegram.org"
+
+// Client sends messages through the Telegram Bot API.
+type Client interface {
+	SendMessage(ctx context.Context, req *SendMessageRequest) (*Message, error)
+}
+
+type clientImpl struct {
+	botToken   string
+	httpClient *http.Client
+}
+
+func NewClient(botToken string) Client {
+	return &clientImpl{
+		botToken:   botToken,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *clientImpl) SendMessage(ctx context.Context, req *SendMessageRequest) (*Message, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("telegram: marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", apiBaseURL, c.botToken)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("telegram: build request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// the request URL embeds the bot token; don't leak it through the error
+		return nil, fmt.Errorf("telegram: send request failed")
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+
+	var result apiResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("telegram: unexpected status %d: %s", resp.StatusCode, string(raw))
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("telegram: api error (status %d): %s", resp.StatusCode, result.Description)
+	}
+	return &result.Result, nil
+}
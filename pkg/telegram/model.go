@@ -0,0 +1,20 @@
+package telegram
+
+const ParseModeHTML = "HTML"
+
+type SendMessageRequest struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview,omitempty"`
+}
+
+type Message struct {
+	MessageID int64 `json:"message_id"`
+}
+
+type apiResponse struct {
+	OK          bool    `json:"ok"`
+	Description string  `json:"description"`
+	Result      Message `json:"result"`
+}
@@ -5,6 +5,7 @@ type AlertType string
 const (
 	AlertTypeCritical AlertType = "critical"
 	AlertTypeResolved AlertType = "resolved"
+	AlertTypeInfo     AlertType = "info"
 )
 
 type EventRequest struct {
@@ -0,0 +1,93 @@
+package zenduty
+
+import "strings"
+
+// Incident statuses reported by Zenduty.
+const (
+	IncidentTriggered    = 1
+	IncidentAcknowledged = 2
+	IncidentResolved     = 3
+)
+
+// WebhookEvent is the body of a Zenduty outgoing webhook sent when an
+// incident changes state.
+type WebhookEvent struct {
+	EventType string      `json:"event_type"`
+	Data      WebhookData `json:"data"`
+}
+
+type WebhookData struct {
+	Incident WebhookIncident `json:"incident"`
+	// User is the person who triggered the event, when Zenduty reports one.
+	User *WebhookUser `json:"user,omitempty"`
+}
+
+type WebhookIncident struct {
+	UniqueID       string         `json:"unique_id"`
+	IncidentNumber int            `json:"incident_number"`
+	Title          string         `json:"title"`
+	Status         int            `json:"status"`
+	EntityID       string         `json:"entity_id,omitempty"`
+	Alerts         []WebhookAlert `json:"alerts,omitempty"`
+	AssignedTo     *WebhookUser   `json:"assigned_to,omitempty"`
+}
+
+type WebhookAlert struct {
+	EntityID string `json:"entity_id"`
+}
+
+type WebhookUser struct {
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+}
+
+// Name is the user's full name, or their username when it is not set.
+func (u *WebhookUser) Name() string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+// Status is the incident state the event reports, taken from the event
+// type and falling back to the incident's status code.
+func (e *WebhookEvent) Status() int {
+	switch strings.ToLower(e.EventType) {
+	case "triggered":
+		return IncidentTriggered
+	case "acknowledged":
+		return IncidentAcknowledged
+	case "resolved":
+		return IncidentResolved
+	}
+	return e.Data.Incident.Status
+}
+
+// EntityIDs returns the entity IDs the incident was opened for, without
+// duplicates.
+func (e *WebhookEvent) EntityIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	add(e.Data.Incident.EntityID)
+	for _, a := range e.Data.Incident.Alerts {
+		add(a.EntityID)
+	}
+	return ids
+}
+
+// Responder is the user who acted on the incident: the event's user, or
+// the incident's assignee when the event names none.
+func (e *WebhookEvent) Responder() *WebhookUser {
+	if e.Data.User != nil {
+		return e.Data.User
+	}
+	return e.Data.Incident.AssignedTo
+}
@@ -0,0 +1,27 @@
+-- name: UpsertAlertTemplate :one
+INSERT INTO alert_templates (team_id, channel, alert_type, subject, body, updated_at)
+VALUES ($1, $2, $3, $4, $5, now())
+ON CONFLICT (team_id, channel, alert_type)
+DO UPDATE SET
+    subject    = EXCLUDED.subject,
+    body       = EXCLUDED.body,
+    updated_at = now()
+RETURNING *;
+
+-- name: GetAlertTemplate :one
+SELECT * FROM alert_templates
+WHERE team_id = $1 AND channel = $2 AND alert_type = $3;
+
+-- name: ListAlertTemplates :many
+SELECT * FROM alert_templates
+WHERE team_id = $1
+ORDER BY channel, alert_type;
+
+-- name: DeleteAlertTemplate :execrows
+DELETE FROM alert_templates
+WHERE team_id = $1 AND channel = $2 AND alert_type = $3;
+
+-- name: GetAlertMonitorMeta :one
+SELECT id, url, interval_sec, timeout_sec, expected_status, latency_threshold_ms, name, description, runbook_url
+FROM monitors
+WHERE id = $1;
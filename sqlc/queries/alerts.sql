@@ -7,4 +7,178 @@ VALUES ($1, $2);
 UPDATE alerts
 SET
     status = $2
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- name: EnqueueAlerts :exec
+INSERT INTO
+    alerts (incident_id, channel, plugin_id, payload, alert_type, alert_email, next_attempt_at)
+SELECT $1, c.channel, c.plugin_id, $4, $5, '', now() + (c.hold * INTERVAL '1 second')
+FROM unnest($2::TEXT[], $3::UUID[], $6::INT[]) AS c(channel, plugin_id, hold);
+
+-- name: ClaimDueAlerts :many
+WITH due AS (
+    SELECT id
+    FROM alerts
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE alerts a
+SET
+    attempts = a.attempts + 1,
+    next_attempt_at = now() + ($2::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM due
+WHERE a.id = due.id
+RETURNING a.id, a.incident_id, a.channel, a.plugin_id, a.payload, a.attempts;
+
+-- name: ClaimAlertGroup :many
+WITH grp AS (
+    SELECT id
+    FROM alerts
+    WHERE status = 'pending'
+      AND plugin_id = $1
+      AND alert_type = $2
+      AND payload->>'team_id' = $3::TEXT
+      AND NOT (payload ? 'escalation_level')
+      AND NOT (payload ? 'recipients')
+      AND next_attempt_at <= now() + ($4::INT * INTERVAL '1 second')
+      AND NOT (id = ANY($5::UUID[]))
+    ORDER BY next_attempt_at
+    LIMIT $6
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE alerts a
+SET
+    attempts = a.attempts + 1,
+    next_attempt_at = now() + ($7::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM grp
+WHERE a.id = grp.id
+RETURNING a.id, a.incident_id, a.channel, a.plugin_id, a.payload, a.attempts;
+
+-- name: ExtendAlertLeases :many
+-- Renews the claim on rows that are still held by the caller, i.e. still
+-- pending with the attempt count it claimed them with.
+UPDATE alerts a
+SET
+    next_attempt_at = now() + ($3::INT * INTERVAL '1 second'),
+    updated_at = now()
+FROM unnest($1::UUID[], $2::INT[]) AS c(id, attempts)
+WHERE a.id = c.id AND a.attempts = c.attempts AND a.status = 'pending'
+RETURNING a.id;
+
+-- name: MarkAlertSent :exec
+UPDATE alerts
+SET
+    status = 'sent',
+    alert_email = $2,
+    sent_at = now(),
+    last_error = NULL,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: MarkAlertGroupSent :exec
+UPDATE alerts
+SET
+    status = 'sent',
+    group_id = $2,
+    alert_email = $3,
+    sent_at = now(),
+    last_error = NULL,
+    updated_at = now()
+WHERE id = ANY($1::UUID[]);
+
+-- name: DeferAlerts :exec
+UPDATE alerts
+SET
+    attempts = GREATEST(attempts - 1, 0),
+    next_attempt_at = $2,
+    updated_at = now()
+WHERE id = ANY($1::UUID[]);
+
+-- name: RescheduleAlert :exec
+UPDATE alerts
+SET
+    last_error = $2,
+    next_attempt_at = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: CloseAlert :exec
+UPDATE alerts
+SET
+    status = $2,
+    last_error = $3,
+    updated_at = now()
+WHERE id = $1;
+
+-- name: ListAlertsByTeamCursor :many
+SELECT
+    a.id,
+    a.incident_id,
+    mi.monitor_id,
+    m.url AS monitor_url,
+    a.channel,
+    a.alert_type,
+    a.status,
+    a.alert_email,
+    a.attempts,
+    a.last_error,
+    a.next_attempt_at,
+    a.sent_at,
+    a.created_at,
+    a.updated_at,
+    a.group_id,
+    a.plugin_id,
+    COALESCE(p.name, '') AS plugin_name
+FROM alerts a
+JOIN monitor_incidents mi ON mi.id = a.incident_id
+JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN plugins p ON p.id = a.plugin_id
+WHERE m.team_id = $1
+  AND ($2::text = '' OR a.channel = $2::text)
+  AND ($3::text = '' OR a.status = $3::text)
+  AND ($4::text = '' OR a.alert_type = $4::text)
+  AND ($5::uuid IS NULL OR mi.monitor_id = $5::uuid)
+  AND ($6::uuid IS NULL OR a.incident_id = $6::uuid)
+  AND ($7::timestamptz IS NULL OR a.created_at >= $7::timestamptz)
+  AND ($8::timestamptz IS NULL OR a.created_at <= $8::timestamptz)
+  AND (
+    $9::timestamptz IS NULL
+        OR (a.created_at, a.id) < ($9::timestamptz, $10::uuid)
+    )
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT $11;
+
+-- name: IncidentExistsForTeam :one
+SELECT EXISTS (
+    SELECT 1
+    FROM monitor_incidents mi
+    JOIN monitors m ON m.id = mi.monitor_id
+    WHERE mi.id = $1 AND m.team_id = $2
+);
+
+-- name: MonitorSnoozed :one
+SELECT EXISTS (
+    SELECT 1 FROM monitors
+    WHERE id = $1 AND snoozed_until > now()
+);
+
+-- name: GetIncidentDownGroup :one
+SELECT
+    a.group_id,
+    (
+        SELECT COUNT(*)
+        FROM alerts g
+        JOIN monitor_incidents mi ON mi.id = g.incident_id
+        WHERE g.group_id = a.group_id AND mi.end_time IS NULL
+    )::INT AS open_incidents
+FROM alerts a
+WHERE a.incident_id = $1
+  AND a.plugin_id = $2
+  AND a.alert_type = 'DOWN'
+  AND a.group_id IS NOT NULL
+ORDER BY a.created_at
+LIMIT 1;
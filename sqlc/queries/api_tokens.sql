@@ -0,0 +1,37 @@
+-- name: CreateAPIToken :one
+INSERT INTO api_tokens (user_id, team_id, name, prefix, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListUserAPITokens :many
+-- The user's personal tokens that haven't been revoked, newest first.
+SELECT * FROM api_tokens
+WHERE user_id = $1 AND team_id IS NULL AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: ListTeamAPITokens :many
+-- The team's tokens that haven't been revoked, newest first.
+SELECT * FROM api_tokens
+WHERE team_id = $1 AND revoked_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: RevokeUserAPIToken :execrows
+UPDATE api_tokens SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND team_id IS NULL AND revoked_at IS NULL;
+
+-- name: RevokeTeamAPIToken :one
+UPDATE api_tokens SET revoked_at = now()
+WHERE id = $1 AND team_id = $2 AND revoked_at IS NULL
+RETURNING name, prefix;
+
+-- name: GetAPITokenByHash :one
+-- A token that hasn't been revoked, with the user it acts as.
+SELECT t.id, t.user_id, t.team_id, t.scopes, t.expires_at, u.email, u.role
+FROM api_tokens t
+JOIN users u ON u.id = t.user_id
+WHERE t.token_hash = $1 AND t.revoked_at IS NULL;
+
+-- name: TouchAPIToken :exec
+-- Writes at most once a minute per token, however busy it is.
+UPDATE api_tokens SET last_used_at = now()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - INTERVAL '1 minute');
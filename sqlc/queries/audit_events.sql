@@ -0,0 +1,34 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (team_id, actor_id, action, target_type, target_id, request_id, ip, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListAuditEvents :many
+-- Newest first. An action filter also matches the actions below it, so
+-- 'monitors' matches 'monitors.delete'.
+SELECT
+    e.id,
+    e.actor_id,
+    COALESCE(u.email, '')::TEXT AS actor_email,
+    e.action,
+    e.target_type,
+    e.target_id,
+    e.request_id,
+    e.ip,
+    e.before,
+    e.after,
+    e.created_at
+FROM audit_events e
+LEFT JOIN users u ON u.id = e.actor_id
+WHERE e.team_id = $1
+  AND ($2::uuid IS NULL OR e.actor_id = $2::uuid)
+  AND ($3::text = '' OR e.action = $3::text OR starts_with(e.action, $3::text || '.'))
+  AND ($4::text = '' OR e.target_type = $4::text)
+  AND ($5::text = '' OR e.target_id = $5::text)
+  AND ($6::timestamptz IS NULL OR e.created_at >= $6::timestamptz)
+  AND ($7::timestamptz IS NULL OR e.created_at <= $7::timestamptz)
+  AND (
+    $8::timestamptz IS NULL
+        OR (e.created_at, e.id) < ($8::timestamptz, $9::uuid)
+    )
+ORDER BY e.created_at DESC, e.id DESC
+LIMIT $10;
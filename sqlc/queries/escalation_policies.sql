@@ -0,0 +1,52 @@
+-- name: CreateEscalationPolicy :one
+INSERT INTO escalation_policies (team_id, name, description, levels, repeat_interval_sec)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, team_id, name, description, levels, repeat_interval_sec, created_at, updated_at;
+
+-- name: GetEscalationPolicy :one
+SELECT ep.id, ep.team_id, ep.name, ep.description, ep.levels, ep.repeat_interval_sec, ep.created_at, ep.updated_at,
+       (SELECT count(*) FROM monitors m WHERE m.escalation_policy_id = ep.id) AS monitor_count
+FROM escalation_policies ep
+WHERE ep.id = $1 AND ep.team_id = $2;
+
+-- name: ListEscalationPolicies :many
+SELECT ep.id, ep.team_id, ep.name, ep.description, ep.levels, ep.repeat_interval_sec, ep.created_at, ep.updated_at,
+       (SELECT count(*) FROM monitors m WHERE m.escalation_policy_id = ep.id) AS monitor_count
+FROM escalation_policies ep
+WHERE ep.team_id = $1
+ORDER BY ep.name;
+
+-- name: UpdateEscalationPolicy :one
+UPDATE escalation_policies
+SET name = $3,
+    description = $4,
+    levels = $5,
+    repeat_interval_sec = $6,
+    updated_at = now()
+WHERE id = $1 AND team_id = $2
+RETURNING id, team_id, name, description, levels, repeat_interval_sec, created_at, updated_at;
+
+-- name: DeleteEscalationPolicy :execrows
+DELETE FROM escalation_policies
+WHERE id = $1 AND team_id = $2;
+
+-- name: EscalationPolicyExistsForTeam :one
+SELECT EXISTS(
+    SELECT 1 FROM escalation_policies
+    WHERE id = $1 AND team_id = $2
+) AS exists;
+
+-- name: GetMonitorEscalationPolicy :one
+SELECT ep.id, ep.levels, ep.repeat_interval_sec
+FROM monitors m
+JOIN escalation_policies ep ON ep.id = m.escalation_policy_id
+WHERE m.id = $1;
+
+-- name: ListActiveTeamMemberEmails :many
+SELECT u.id, u.email
+FROM team_members tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+  AND tm.user_id = ANY($2::uuid[])
+  AND tm.is_active
+  AND u.is_active;
@@ -0,0 +1,40 @@
+-- name: CreateIncidentEscalation :execrows
+INSERT INTO incident_escalations (incident_id, policy_id, team_id, levels, repeat_interval_sec, event, next_fire_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (incident_id) DO NOTHING;
+
+-- name: GetIncidentEscalation :one
+SELECT ie.incident_id, ie.policy_id, ie.team_id, ie.levels, ie.repeat_interval_sec, ie.event, ie.status,
+       ie.next_level, ie.cycle, ie.next_fire_at, ie.last_fired_at, ie.notified_channels, ie.created_at, ie.updated_at,
+       (mi.end_time IS NOT NULL)::BOOLEAN AS incident_ended,
+       (mi.acknowledged_at IS NOT NULL)::BOOLEAN AS incident_acknowledged
+FROM incident_escalations ie
+JOIN monitor_incidents mi ON mi.id = ie.incident_id
+WHERE ie.incident_id = $1;
+
+-- name: AdvanceIncidentEscalation :execrows
+UPDATE incident_escalations
+SET next_level = $4,
+    cycle = $5,
+    status = $6,
+    next_fire_at = $7,
+    notified_channels = $8,
+    last_fired_at = now(),
+    updated_at = now()
+WHERE incident_id = $1 AND next_level = $2 AND cycle = $3 AND status = 'active';
+
+-- name: StopIncidentEscalation :one
+UPDATE incident_escalations
+SET status = $2, next_fire_at = NULL, updated_at = now()
+WHERE incident_id = $1 AND status <> 'resolved'
+RETURNING notified_channels;
+
+-- name: ResumeIncidentEscalation :execrows
+UPDATE incident_escalations
+SET status = 'active', next_fire_at = $2, updated_at = now()
+WHERE incident_id = $1 AND status = 'acknowledged';
+
+-- name: ListActiveIncidentEscalations :many
+SELECT incident_id, next_fire_at
+FROM incident_escalations
+WHERE status = 'active';
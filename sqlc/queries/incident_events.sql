@@ -0,0 +1,12 @@
+-- name: CreateIncidentEvent :one
+INSERT INTO incident_events (incident_id, kind, author_id, message, data, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at;
+
+-- name: ListIncidentEvents :many
+SELECT e.id, e.incident_id, e.kind, e.author_id, COALESCE(u.name, '') AS author_name,
+       e.message, e.data, e.occurred_at, e.created_at
+FROM incident_events e
+LEFT JOIN users u ON u.id = e.author_id
+WHERE e.incident_id = $1
+ORDER BY e.occurred_at, e.created_at, e.id;
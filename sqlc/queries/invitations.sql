@@ -17,5 +17,6 @@ ORDER BY created_at DESC;
 -- name: AcceptInvitation :exec
 UPDATE invitations SET accepted_at = now() WHERE token = $1;
 
--- name: DeleteInvitation :exec
-DELETE FROM invitations WHERE id = $1 AND team_id = $2;
+-- name: DeleteInvitation :one
+DELETE FROM invitations WHERE id = $1 AND team_id = $2
+RETURNING email, role;
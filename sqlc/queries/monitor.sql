@@ -8,7 +8,18 @@ INSERT INTO monitors (
     latency_threshold_ms,
     expected_status,
     alert_email,
-    notification_channels
+    notification_channels,
+    escalation_policy_id,
+    reminder_interval_sec,
+    reminder_max,
+    tags,
+    severity,
+    name,
+    description,
+    runbook_url,
+    slug,
+    keyword,
+    keyword_absent
 ) VALUES (
              $1,
              $2,
@@ -18,41 +29,119 @@ INSERT INTO monitors (
              $6,
              $7,
              $8,
-             $9
+             $9,
+             $10,
+             $11,
+             $12,
+             $13,
+             $14,
+             $15,
+             $16,
+             $17,
+             $18,
+             $19,
+             $20
          )
     RETURNING id;
 
 -- name: GetMonitorByID :one
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
 FROM monitors
 WHERE id = $1;
 
 -- name: GetMonitorByTeamID :one
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec, latency_threshold_ms, expected_status, enabled
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
 FROM monitors
 WHERE id = $1 AND team_id = $2;
 
 -- name: ListMonitorsByTeamCursor :many
-SELECT id, user_id, team_id, url, alert_email, notification_channels, interval_sec, timeout_sec,
-       latency_threshold_ms, expected_status, enabled, created_at,
+-- $2 searches names and URLs, $3 holds tag filters that must all match: a
+-- "key" filter matches every value of that key. $5 is the sort order:
+-- 'created' (newest first), 'name' or 'url'; $4 and $6/$7 are the cursor.
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec,
+       latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify, created_at,
        EXISTS (
            SELECT 1 FROM monitor_incidents mi
            WHERE mi.monitor_id = monitors.id AND mi.end_time IS NULL
-       ) AS is_down
+       ) AS is_down,
+       lower(COALESCE(NULLIF(name, ''), url))::TEXT AS sort_name
 FROM monitors
 WHERE team_id = $1
+  AND ($2::text = '' OR name ILIKE ('%' || $2 || '%') OR url ILIKE ('%' || $2 || '%'))
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($3::text[]) AS f(tag)
+    WHERE NOT EXISTS (
+        SELECT 1 FROM unnest(monitors.tags) AS t(tag)
+        WHERE t.tag = f.tag
+           OR (strpos(f.tag, ':') = 0 AND split_part(t.tag, ':', 1) = f.tag)
+    )
+  )
   AND (
-    $2::timestamptz IS NULL
-    OR (created_at, id) < ($2::timestamptz, $3::uuid)
+    $4::uuid IS NULL
+    OR ($5::text = 'created' AND (created_at, id) < ($6::timestamptz, $4::uuid))
+    OR ($5::text = 'name' AND (lower(COALESCE(NULLIF(name, ''), url)), id) > ($7::text, $4::uuid))
+    OR ($5::text = 'url' AND (url, id) > ($7::text, $4::uuid))
   )
-ORDER BY created_at DESC, id DESC
-LIMIT $4;
+ORDER BY
+    CASE WHEN $5::text = 'name' THEN lower(COALESCE(NULLIF(name, ''), url)) END,
+    CASE WHEN $5::text = 'url' THEN url END,
+    CASE WHEN $5::text = 'created' THEN created_at END DESC,
+    CASE WHEN $5::text = 'created' THEN id END DESC,
+    id
+LIMIT $8;
+
+-- name: ListTeamMonitors :many
+SELECT id, user_id, team_id, url, alert_email, notification_channels, escalation_policy_id, reminder_interval_sec, reminder_max, tags, severity, name, description, runbook_url, slug, interval_sec, timeout_sec, latency_threshold_ms, expected_status, keyword, keyword_absent, enabled, snoozed_until, snooze_notify
+FROM monitors
+WHERE team_id = $1
+ORDER BY created_at, id;
+
+-- name: LockMonitor :one
+-- Locks the monitor until the transaction ends so that changes to it are
+-- applied one at a time.
+SELECT id FROM monitors
+WHERE id = $1 AND team_id = $2
+FOR UPDATE;
 
 -- name: UpdateMonitorStatus :execrows
 UPDATE monitors
 SET enabled = $2
 WHERE id = $1 AND team_id = $3;
 
+-- name: UpdateMonitorSettings :execrows
+UPDATE monitors
+SET name = $3, description = $4, runbook_url = $5, tags = $6, severity = $7, escalation_policy_id = $8, reminder_interval_sec = $9, reminder_max = $10, updated_at = now()
+WHERE id = $1 AND team_id = $2;
+
+-- name: UpdateMonitorConfig :execrows
+UPDATE monitors
+SET url = $3, interval_sec = $4, timeout_sec = $5, latency_threshold_ms = $6, expected_status = $7, notification_channels = $8, keyword = $9, keyword_absent = $10, updated_at = now()
+WHERE id = $1 AND team_id = $2;
+
+-- name: SetMonitorSlug :execrows
+UPDATE monitors
+SET slug = $3, updated_at = now()
+WHERE id = $1 AND team_id = $2;
+
+-- name: SetMonitorSnooze :execrows
+UPDATE monitors
+SET snoozed_until = $3, snooze_notify = $4, updated_at = now()
+WHERE id = $1 AND team_id = $2;
+
+-- name: ClaimEndedSnoozes :many
+-- Claims monitors whose snooze has ended and that asked to be notified,
+-- clearing the flag so each end is notified once across instances.
+UPDATE monitors
+SET snooze_notify = false
+WHERE id IN (
+    SELECT m.id FROM monitors m
+    WHERE m.snooze_notify AND m.snoozed_until <= now()
+    ORDER BY m.snoozed_until
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, team_id, url, name, runbook_url, notification_channels, snoozed_until, enabled;
+
 -- name: DeleteMonitor :execrows
 DELETE FROM monitors
 WHERE id = $1 AND team_id = $2;
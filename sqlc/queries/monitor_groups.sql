@@ -0,0 +1,52 @@
+-- name: CreateMonitorGroup :one
+INSERT INTO monitor_groups (team_id, parent_id, name, description, alert_mode, alert_when, channels)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, team_id, parent_id, name, description, alert_mode, alert_when, channels, created_at, updated_at;
+
+-- name: GetMonitorGroup :one
+SELECT id, team_id, parent_id, name, description, alert_mode, alert_when, channels, created_at, updated_at
+FROM monitor_groups
+WHERE id = $1 AND team_id = $2;
+
+-- name: ListMonitorGroups :many
+SELECT id, team_id, parent_id, name, description, alert_mode, alert_when, channels, created_at, updated_at
+FROM monitor_groups
+WHERE team_id = $1
+ORDER BY lower(name), id;
+
+-- name: UpdateMonitorGroup :one
+UPDATE monitor_groups
+SET parent_id = $3,
+    name = $4,
+    description = $5,
+    alert_mode = $6,
+    alert_when = $7,
+    channels = $8,
+    updated_at = now()
+WHERE id = $1 AND team_id = $2
+RETURNING id, team_id, parent_id, name, description, alert_mode, alert_when, channels, created_at, updated_at;
+
+-- name: DeleteMonitorGroup :execrows
+DELETE FROM monitor_groups
+WHERE id = $1 AND team_id = $2;
+
+-- name: ListMonitorGroupMembers :many
+-- Every direct membership of the team's groups.
+SELECT gm.group_id, gm.monitor_id, m.url, m.name, m.enabled
+FROM monitor_group_members gm
+JOIN monitor_groups g ON g.id = gm.group_id
+JOIN monitors m ON m.id = gm.monitor_id
+WHERE g.team_id = $1
+ORDER BY lower(COALESCE(NULLIF(m.name, ''), m.url)), m.id;
+
+-- name: AddMonitorGroupMembers :execrows
+-- Monitors of other teams are skipped.
+INSERT INTO monitor_group_members (group_id, monitor_id)
+SELECT $1::uuid, m.id
+FROM monitors m
+WHERE m.team_id = $2 AND m.id = ANY($3::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveMonitorGroupMember :execrows
+DELETE FROM monitor_group_members
+WHERE group_id = $1 AND monitor_id = $2;
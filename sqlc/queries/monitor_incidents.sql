@@ -1,6 +1,11 @@
 -- name: CreateMonitorIncident :one
-INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO monitor_incidents (monitor_id, start_time, alerted, http_status, latency_ms, next_reminder_at)
+VALUES ($1, $2, $3, $4, $5, (
+    SELECT CASE WHEN m.reminder_interval_sec > 0 AND m.reminder_max > 0
+                THEN $2 + make_interval(secs => m.reminder_interval_sec) END
+    FROM monitors m
+    WHERE m.id = $1
+))
 RETURNING id;
 
 -- name: GetMonitorIncidentByID :one
@@ -8,6 +13,43 @@ SELECT id, monitor_id, start_time, end_time, alerted, http_status, latency_ms, c
 FROM monitor_incidents
 WHERE id = $1;
 
+-- name: GetOpenMonitorIncident :one
+SELECT id
+FROM monitor_incidents
+WHERE monitor_id = $1 AND end_time IS NULL
+ORDER BY start_time DESC
+LIMIT 1;
+
+-- name: ClaimDueReminders :many
+WITH due AS (
+    SELECT id
+    FROM monitor_incidents
+    WHERE next_reminder_at <= now()
+      AND end_time IS NULL
+      AND acknowledged_at IS NULL
+    ORDER BY next_reminder_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+)
+UPDATE monitor_incidents mi
+SET reminders_sent = mi.reminders_sent + 1,
+    next_reminder_at = CASE
+        WHEN m.reminder_interval_sec <= 0 OR mi.reminders_sent + 1 >= m.reminder_max THEN NULL
+        ELSE now() + make_interval(secs => m.reminder_interval_sec)
+    END
+FROM due, monitors m
+WHERE mi.id = due.id AND m.id = mi.monitor_id
+RETURNING mi.id, mi.monitor_id, m.team_id, m.url AS monitor_url, m.name AS monitor_name, m.runbook_url, m.notification_channels,
+          mi.start_time, mi.http_status, mi.latency_ms, mi.reminders_sent,
+          (m.reminder_interval_sec > 0 AND m.reminder_max > 0)::BOOLEAN AS reminders_enabled,
+          COALESCE((
+              SELECT a.payload->>'reason'
+              FROM alerts a
+              WHERE a.incident_id = mi.id AND a.alert_type = 'DOWN'
+              ORDER BY a.created_at
+              LIMIT 1
+          ), '')::TEXT AS reason;
+
 -- name: CloseMonitorIncident :one
 UPDATE monitor_incidents
 SET end_time = $2
@@ -15,10 +57,14 @@ WHERE monitor_id = $1 AND end_time IS NULL
 RETURNING id;
 
 -- name: ListIncidentsByTeamCursor :many
+-- $12 holds monitor tag filters as in ListMonitorsByTeamCursor. $13 is the
+-- sort order: 'newest', 'oldest' or 'name' (monitor name); $7/$14 and $8
+-- are the cursor.
 SELECT
     mi.id,
     mi.monitor_id,
     m.url AS monitor_url,
+    m.name AS monitor_name,
     mi.start_time,
     mi.end_time,
     mi.alerted,
@@ -31,9 +77,20 @@ SELECT
     COALESCE(a.alert_email, '') AS alert_email,
     a.sent_at AS alert_sent_at,
     m.expected_status,
-    m.latency_threshold_ms
+    m.latency_threshold_ms,
+    mi.acknowledged_at,
+    mi.acknowledged_by,
+    COALESCE(ack_u.name, mi.acknowledged_by_external) AS acknowledged_by_name,
+    mi.assigned_to,
+    COALESCE(asg_u.name, '') AS assigned_to_name,
+    mi.assigned_by,
+    mi.assigned_at,
+    m.tags AS monitor_tags,
+    lower(COALESCE(NULLIF(m.name, ''), m.url))::TEXT AS sort_name
 FROM monitor_incidents mi
 JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN users ack_u ON ack_u.id = mi.acknowledged_by
+LEFT JOIN users asg_u ON asg_u.id = mi.assigned_to
 LEFT JOIN LATERAL (
     SELECT status, alert_email, sent_at
     FROM alerts
@@ -49,20 +106,43 @@ WHERE m.team_id = $1
     )
   AND ($3::timestamptz IS NULL OR mi.start_time >= $3::timestamptz)
   AND ($4::timestamptz IS NULL OR mi.start_time <= $4::timestamptz)
-  AND ($5::text = '' OR m.url ILIKE ('%' || $5 || '%'))
+  AND ($5::text = '' OR m.url ILIKE ('%' || $5 || '%') OR m.name ILIKE ('%' || $5 || '%'))
   AND ($6::uuid IS NULL OR mi.monitor_id = $6::uuid)
   AND (
-    $7::timestamptz IS NULL
-        OR (mi.start_time, mi.id) < ($7::timestamptz, $8::uuid)
+    $8::uuid IS NULL
+        OR ($13::text = 'newest' AND (mi.start_time, mi.id) < ($7::timestamptz, $8::uuid))
+        OR ($13::text = 'oldest' AND (mi.start_time, mi.id) > ($7::timestamptz, $8::uuid))
+        OR ($13::text = 'name' AND (lower(COALESCE(NULLIF(m.name, ''), m.url)), mi.id) > ($14::text, $8::uuid))
     )
-ORDER BY mi.start_time DESC, mi.id DESC
-LIMIT $9;
+  AND (
+    $9::text = 'all'
+        OR ($9::text = 'acknowledged' AND mi.acknowledged_at IS NOT NULL)
+        OR ($9::text = 'unacknowledged' AND mi.acknowledged_at IS NULL)
+    )
+  AND ($10::uuid IS NULL OR mi.assigned_to = $10::uuid)
+  AND NOT EXISTS (
+    SELECT 1 FROM unnest($12::text[]) AS f(tag)
+    WHERE NOT EXISTS (
+        SELECT 1 FROM unnest(m.tags) AS t(tag)
+        WHERE t.tag = f.tag
+           OR (strpos(f.tag, ':') = 0 AND split_part(t.tag, ':', 1) = f.tag)
+    )
+  )
+ORDER BY
+    CASE WHEN $13::text = 'name' THEN lower(COALESCE(NULLIF(m.name, ''), m.url)) END,
+    CASE WHEN $13::text = 'oldest' THEN mi.start_time END,
+    CASE WHEN $13::text = 'oldest' THEN mi.id END,
+    CASE WHEN $13::text = 'newest' THEN mi.start_time END DESC,
+    CASE WHEN $13::text = 'newest' THEN mi.id END DESC,
+    mi.id
+LIMIT $11;
 
 -- name: GetIncidentByIDAndTeamID :one
 SELECT
     mi.id,
     mi.monitor_id,
     m.url AS monitor_url,
+    m.name AS monitor_name,
     mi.start_time,
     mi.end_time,
     mi.alerted,
@@ -75,9 +155,19 @@ SELECT
     COALESCE(a.alert_email, '') AS alert_email,
     a.sent_at AS alert_sent_at,
     m.expected_status,
-    m.latency_threshold_ms
+    m.latency_threshold_ms,
+    mi.acknowledged_at,
+    mi.acknowledged_by,
+    COALESCE(ack_u.name, mi.acknowledged_by_external) AS acknowledged_by_name,
+    mi.assigned_to,
+    COALESCE(asg_u.name, '') AS assigned_to_name,
+    mi.assigned_by,
+    mi.assigned_at,
+    m.tags AS monitor_tags
 FROM monitor_incidents mi
 JOIN monitors m ON m.id = mi.monitor_id
+LEFT JOIN users ack_u ON ack_u.id = mi.acknowledged_by
+LEFT JOIN users asg_u ON asg_u.id = mi.assigned_to
 LEFT JOIN LATERAL (
     SELECT status, alert_email, sent_at
     FROM alerts
@@ -86,3 +176,81 @@ LEFT JOIN LATERAL (
     LIMIT 1
 ) a ON true
 WHERE mi.id = $1 AND m.team_id = $2;
+
+-- name: AcknowledgeIncident :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = now(),
+    acknowledged_by = $3,
+    assigned_to = COALESCE(mi.assigned_to, $3),
+    assigned_by = COALESCE(mi.assigned_by, $3),
+    assigned_at = COALESCE(mi.assigned_at, now())
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NULL;
+
+-- name: AcknowledgeIncidentExternal :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = now(),
+    acknowledged_by = $3,
+    acknowledged_by_external = $4,
+    assigned_to = COALESCE(mi.assigned_to, $3),
+    assigned_by = COALESCE(mi.assigned_by, $3),
+    assigned_at = COALESCE(mi.assigned_at, CASE WHEN $3::uuid IS NULL THEN NULL ELSE now() END)
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NULL;
+
+-- name: MarkIncidentResolvedExternal :execrows
+UPDATE monitor_incidents mi
+SET resolved_externally_at = now()
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.resolved_externally_at IS NULL;
+
+-- name: ListOpenMonitorIncidents :many
+SELECT mi.id
+FROM monitor_incidents mi
+JOIN monitors m ON m.id = mi.monitor_id
+WHERE mi.monitor_id = $1
+  AND m.team_id = $2
+  AND mi.end_time IS NULL;
+
+-- name: ListOpenDigestIncidents :many
+SELECT DISTINCT mi.id
+FROM alerts a
+JOIN monitor_incidents mi ON mi.id = a.incident_id
+JOIN monitors m ON m.id = mi.monitor_id
+WHERE a.group_id = $1
+  AND m.team_id = $2
+  AND mi.end_time IS NULL;
+
+-- name: UnacknowledgeIncident :execrows
+UPDATE monitor_incidents mi
+SET acknowledged_at = NULL,
+    acknowledged_by = NULL,
+    acknowledged_by_external = ''
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2
+  AND mi.end_time IS NULL
+  AND mi.acknowledged_at IS NOT NULL;
+
+-- name: AssignIncident :execrows
+UPDATE monitor_incidents mi
+SET assigned_to = $3,
+    assigned_by = $4,
+    assigned_at = CASE WHEN $3::uuid IS NULL THEN NULL ELSE now() END
+FROM monitors m
+WHERE mi.id = $1
+  AND m.id = mi.monitor_id
+  AND m.team_id = $2;
@@ -0,0 +1,48 @@
+-- name: CreateMonitorRevision :one
+INSERT INTO monitor_revisions (monitor_id, team_id, revision, action, actor_id, snapshot)
+SELECT $1, $2, COALESCE(MAX(r.revision), 0) + 1, $3, $4, $5
+FROM monitor_revisions r
+WHERE r.monitor_id = $1
+RETURNING revision;
+
+-- name: ListMonitorRevisions :many
+-- Newest first. A before of 0 starts at the latest revision.
+SELECT r.revision, r.action, r.actor_id, COALESCE(u.email, '')::TEXT AS actor_email, r.snapshot, r.created_at
+FROM monitor_revisions r
+LEFT JOIN users u ON u.id = r.actor_id
+WHERE r.monitor_id = $1 AND r.team_id = $2
+  AND ($3::INT = 0 OR r.revision < $3::INT)
+ORDER BY r.revision DESC
+LIMIT $4;
+
+-- name: GetMonitorRevision :one
+SELECT revision, action, snapshot, created_at
+FROM monitor_revisions
+WHERE monitor_id = $1 AND team_id = $2 AND revision = $3;
+
+-- name: GetMonitorDeletedAt :one
+SELECT created_at
+FROM monitor_revisions
+WHERE monitor_id = $1 AND team_id = $2 AND action = 'delete'
+ORDER BY revision DESC
+LIMIT 1;
+
+-- name: MoveMonitorRevisions :execrows
+-- Hands a restored monitor the history of the deleted one it recreates.
+UPDATE monitor_revisions
+SET monitor_id = $2
+WHERE monitor_id = $1 AND team_id = $3;
+
+-- name: PurgeDeletedMonitorRevisions :execrows
+-- Drops the history of monitors last deleted before the cutoff, which can
+-- no longer be restored. A history that was restored and deleted again
+-- carries several deletes; only the latest one counts.
+DELETE FROM monitor_revisions r
+WHERE r.team_id = $1
+  AND r.monitor_id IN (
+    SELECT d.monitor_id FROM monitor_revisions d
+    WHERE d.team_id = $1 AND d.action = 'delete'
+    GROUP BY d.monitor_id
+    HAVING MAX(d.created_at) < $2::TIMESTAMPTZ
+  )
+  AND NOT EXISTS (SELECT 1 FROM monitors m WHERE m.id = r.monitor_id);
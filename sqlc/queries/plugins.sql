@@ -1,22 +1,37 @@
--- name: UpsertPlugin :one
-INSERT INTO plugins (team_id, plugin_type, enabled, config_enc, updated_at)
-VALUES ($1, $2, $3, $4, now())
-ON CONFLICT (team_id, plugin_type)
-DO UPDATE SET
-    enabled    = EXCLUDED.enabled,
-    config_enc = EXCLUDED.config_enc,
+-- name: CreatePlugin :one
+INSERT INTO plugins (team_id, plugin_type, name, enabled, config_enc)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: UpdatePlugin :one
+UPDATE plugins
+SET
+    name       = $3,
+    enabled    = $4,
+    config_enc = $5,
     updated_at = now()
+WHERE id = $1 AND team_id = $2
 RETURNING *;
 
 -- name: GetPlugin :one
 SELECT * FROM plugins
-WHERE team_id = $1 AND plugin_type = $2;
+WHERE id = $1 AND team_id = $2;
+
+-- name: GetPluginByID :one
+SELECT * FROM plugins
+WHERE id = $1;
 
 -- name: ListPlugins :many
 SELECT * FROM plugins
 WHERE team_id = $1
-ORDER BY plugin_type;
+ORDER BY plugin_type, name;
+
+-- name: ListPluginInstances :many
+SELECT id, plugin_type, name, enabled
+FROM plugins
+WHERE team_id = $1
+ORDER BY plugin_type, name;
 
--- name: DeletePlugin :exec
+-- name: DeletePlugin :execrows
 DELETE FROM plugins
-WHERE team_id = $1 AND plugin_type = $2;
+WHERE id = $1 AND team_id = $2;
@@ -0,0 +1,64 @@
+-- name: ListRoutingRules :many
+SELECT id, team_id, name, position, enabled, match_tags, match_severities, match_alert_types, time_window, channels, user_ids, stop, created_at, updated_at
+FROM routing_rules
+WHERE team_id = $1
+ORDER BY position, created_at;
+
+-- name: ListEnabledRoutingRules :many
+SELECT id, team_id, name, position, enabled, match_tags, match_severities, match_alert_types, time_window, channels, user_ids, stop, created_at, updated_at
+FROM routing_rules
+WHERE team_id = $1 AND enabled
+ORDER BY position, created_at;
+
+-- name: GetRoutingRule :one
+SELECT id, team_id, name, position, enabled, match_tags, match_severities, match_alert_types, time_window, channels, user_ids, stop, created_at, updated_at
+FROM routing_rules
+WHERE id = $1 AND team_id = $2;
+
+-- name: CreateRoutingRule :one
+INSERT INTO routing_rules (
+    team_id, name, position, enabled, match_tags, match_severities,
+    match_alert_types, time_window, channels, user_ids, stop
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, team_id, name, position, enabled, match_tags, match_severities, match_alert_types, time_window, channels, user_ids, stop, created_at, updated_at;
+
+-- name: UpdateRoutingRule :one
+UPDATE routing_rules
+SET name = $3,
+    position = $4,
+    enabled = $5,
+    match_tags = $6,
+    match_severities = $7,
+    match_alert_types = $8,
+    time_window = $9,
+    channels = $10,
+    user_ids = $11,
+    stop = $12,
+    updated_at = now()
+WHERE id = $1 AND team_id = $2
+RETURNING id, team_id, name, position, enabled, match_tags, match_severities, match_alert_types, time_window, channels, user_ids, stop, created_at, updated_at;
+
+-- name: DeleteRoutingRule :execrows
+DELETE FROM routing_rules
+WHERE id = $1 AND team_id = $2;
+
+-- name: GetTeamBusinessHours :one
+SELECT team_id, timezone, days, start_minute, end_minute, updated_at
+FROM team_business_hours
+WHERE team_id = $1;
+
+-- name: UpsertTeamBusinessHours :one
+INSERT INTO team_business_hours (team_id, timezone, days, start_minute, end_minute)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (team_id) DO UPDATE
+SET timezone = EXCLUDED.timezone,
+    days = EXCLUDED.days,
+    start_minute = EXCLUDED.start_minute,
+    end_minute = EXCLUDED.end_minute,
+    updated_at = now()
+RETURNING team_id, timezone, days, start_minute, end_minute, updated_at;
+
+-- name: GetMonitorRoutingLabels :one
+SELECT id, team_id, tags, severity, notification_channels
+FROM monitors
+WHERE id = $1 AND team_id = $2;
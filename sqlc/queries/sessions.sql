@@ -0,0 +1,51 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, refresh_hash, user_agent, ip, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;
+
+-- name: GetSessionByRefreshHash :one
+-- Matches the current refresh token and the one it replaced.
+SELECT id, user_id, refresh_hash, expires_at, revoked_at
+FROM sessions
+WHERE refresh_hash = $1 OR previous_hash = $1;
+
+-- name: RotateSession :execrows
+-- Swaps in a new refresh token, unless the old one was rotated or the
+-- session revoked in the meantime.
+UPDATE sessions
+SET previous_hash = refresh_hash,
+    refresh_hash  = $2,
+    expires_at    = $3,
+    user_agent    = $4,
+    ip            = $5,
+    last_used_at  = now()
+WHERE id = $1 AND refresh_hash = $6 AND revoked_at IS NULL;
+
+-- name: ListActiveSessions :many
+SELECT id, user_agent, ip, expires_at, last_used_at, created_at
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_used_at DESC;
+
+-- name: IsSessionActive :one
+SELECT EXISTS (
+    SELECT 1 FROM sessions
+    WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > now()
+);
+
+-- name: RevokeUserSession :execrows
+UPDATE sessions SET revoked_at = now()
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeSession :exec
+UPDATE sessions SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAllUserSessions :exec
+UPDATE sessions SET revoked_at = now()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: DeleteDeadUserSessions :exec
+-- Drops the user's revoked and expired sessions.
+DELETE FROM sessions
+WHERE user_id = $1 AND (revoked_at IS NOT NULL OR expires_at <= now());
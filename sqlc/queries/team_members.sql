@@ -8,6 +8,14 @@ SELECT tm.id, tm.team_id, tm.user_id, tm.role, tm.is_active, tm.joined_at
 FROM team_members tm
 WHERE tm.team_id = $1 AND tm.user_id = $2;
 
+-- name: GetActiveTeamMemberByEmail :one
+SELECT u.id
+FROM team_members tm
+JOIN users u ON u.id = tm.user_id
+WHERE tm.team_id = $1
+  AND lower(u.email) = lower($2)
+  AND tm.is_active = true;
+
 -- name: ListTeamMembers :many
 SELECT u.id, u.name, u.email, tm.role, tm.is_active, u.created_at
 FROM team_members tm
@@ -24,6 +24,7 @@ interface LoginResponse {
     data: {
         user_id: string;
         access_token: string;
+        refresh_token: string;
     };
 }
 
@@ -53,7 +54,7 @@ export function SignInForm() {
         setApiError(null);
         try {
             const res = await post<LoginResponse>(ENDPOINTS.AUTH.LOGIN, data);
-            tokenStore.set(res.data.access_token);
+            tokenStore.set(res.data.access_token, res.data.refresh_token);
             router.push("/monitors");
         } catch (err) {
             setApiError(parseApiError(err, "Invalid email or password."));
@@ -181,7 +181,7 @@ function PluginCard({
                 }
             </div>
             <div className="min-w-0 flex-1">
-                <p className="text-sm font-medium leading-tight">{meta.name}</p>
+                <p className="text-sm font-medium leading-tight">{plugin.name || meta.name}</p>
                 <p className="text-xs text-muted-foreground">{meta.description}</p>
             </div>
             {plugin.enabled
@@ -221,14 +221,15 @@ export default function NewMonitorPage() {
     const update = <K extends keyof MonitorFormData>(key: K, value: MonitorFormData[K]) =>
         setForm((prev) => ({ ...prev, [key]: value }));
 
-    const toggleChannel = (type: string) => {
+    // channels reference plugin instances by id
+    const toggleChannel = (id: string) => {
         setForm((prev) => {
-            const has = prev.notification_channels.includes(type);
+            const has = prev.notification_channels.includes(id);
             return {
                 ...prev,
                 notification_channels: has
-                    ? prev.notification_channels.filter((c) => c !== type)
-                    : [...prev.notification_channels, type],
+                    ? prev.notification_channels.filter((c) => c !== id)
+                    : [...prev.notification_channels, id],
             };
         });
     };
@@ -363,10 +364,10 @@ export default function NewMonitorPage() {
                             <div className="space-y-2">
                                 {activePlugins.map((plugin) => (
                                     <PluginCard
-                                        key={plugin.type}
+                                        key={plugin.id}
                                         plugin={plugin}
-                                        selected={form.notification_channels.includes(plugin.type)}
-                                        onToggle={() => toggleChannel(plugin.type)}
+                                        selected={form.notification_channels.includes(plugin.id)}
+                                        onToggle={() => toggleChannel(plugin.id)}
                                     />
                                 ))}
                                 {form.notification_channels.length === 0 && (
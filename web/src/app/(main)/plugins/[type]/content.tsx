@@ -1,7 +1,7 @@
 "use client";
 
 import { useEffect, useState, useRef } from "react";
-import { useParams, useRouter } from "next/navigation";
+import { useParams, useRouter, useSearchParams } from "next/navigation";
 import Link from "next/link";
 import {
     AlertCircle, ArrowLeft, Check, CheckCircle2,
@@ -30,6 +30,9 @@ interface FieldDef {
 const PLUGIN_LAYOUT: Record<string, string[][]> = {
     resend:   [["api_key", "sender_email"], ["recipient_emails"]],
     zenduty:  [["integration_url"]],
+    msteams:  [["webhook_url"]],
+    discord:  [["webhook_url"]],
+    telegram: [["bot_token", "chat_id"]],
 };
 
 const PLUGIN_FIELDS: Record<string, FieldDef[]> = {
@@ -41,6 +44,16 @@ const PLUGIN_FIELDS: Record<string, FieldDef[]> = {
     zenduty: [
         { key: "integration_url",  label: "Webhook URL",         placeholder: "https://events.zenduty.com/integration/…" },
     ],
+    msteams: [
+        { key: "webhook_url",      label: "Webhook URL",         placeholder: "https://….webhook.office.com/…",   type: "password" },
+    ],
+    discord: [
+        { key: "webhook_url",      label: "Webhook URL",         placeholder: "https://discord.com/api/webhooks/…", type: "password" },
+    ],
+    telegram: [
+        { key: "bot_token",        label: "Bot Token",           placeholder: "123456789:AAxxxxxxxxxxxxxxxxxxxx", type: "password" },
+        { key: "chat_id",          label: "Chat ID",             placeholder: "-1001234567890" },
+    ],
 };
 
 // ── Multi-value email list ────────────────────────────────────────────────
@@ -121,14 +134,18 @@ export default function PluginDetailPage() {
     const params = useParams<{ type: string }>();
     const router = useRouter();
     const pluginType = params.type;
+    // instances are edited by id; without one the page adds a new instance
+    const instanceId = useSearchParams().get("id");
 
     const def    = PLUGIN_REGISTRY.find((p) => p.type === pluginType);
     const fields = PLUGIN_FIELDS[pluginType] ?? [];
     const layout = PLUGIN_LAYOUT[pluginType] ?? fields.map((f) => [f.key]);
 
     const currentTeam = useTeamStore((s) => s.currentTeam);
-    const { saving, fetchPlugin, upsertPlugin, deletePlugin } = usePluginStore();
+    const { saving, fetchPlugin, createPlugin, updatePlugin, deletePlugin } = usePluginStore();
 
+    const [pluginId,     setPluginId]     = useState<string | null>(instanceId);
+    const [name,         setName]         = useState(def?.name ?? "");
     const [singleFields, setSingleFields] = useState<Record<string, string>>({});
     const [multiFields,  setMultiFields]  = useState<Record<string, string[]>>({});
     const [enabled,      setEnabled]      = useState(false);
@@ -142,9 +159,11 @@ export default function PluginDetailPage() {
         if (!currentTeam || !def) return;
         (async () => {
             setLoading(true);
-            const plugin = await fetchPlugin(pluginType);
+            setPluginId(instanceId);
+            const plugin = instanceId ? await fetchPlugin(instanceId) : null;
             if (plugin) {
                 setConfigured(true);
+                setName(plugin.name);
                 setEnabled(plugin.enabled);
                 const singles: Record<string, string>   = {};
                 const multis:  Record<string, string[]> = {};
@@ -160,6 +179,7 @@ export default function PluginDetailPage() {
                 setMultiFields(multis);
             } else {
                 setConfigured(false);
+                setName(def.name);
                 setEnabled(false);
                 const singles: Record<string, string>   = {};
                 const multis:  Record<string, string[]> = {};
@@ -172,7 +192,7 @@ export default function PluginDetailPage() {
             }
             setLoading(false);
         })();
-    }, [currentTeam?.id, pluginType]);
+    }, [currentTeam?.id, pluginType, instanceId]);
 
     if (!def) return (
         <div className="flex flex-col items-center justify-center h-64 gap-3">
@@ -189,7 +209,13 @@ export default function PluginDetailPage() {
         try {
             const config: Record<string, string> = { ...singleFields };
             for (const [key, vals] of Object.entries(multiFields)) config[key] = vals.join(",");
-            await upsertPlugin(pluginType, enabled, config);
+            if (pluginId) {
+                await updatePlugin(pluginId, name, enabled, config);
+            } else {
+                const created = await createPlugin(pluginType, name, enabled, config);
+                setPluginId(created.id);
+                router.replace(`/plugins/${pluginType}?id=${created.id}`);
+            }
             setConfigured(true);
             setSaved(true);
             setTimeout(() => setSaved(false), 2500);
@@ -199,11 +225,12 @@ export default function PluginDetailPage() {
     };
 
     const handleDelete = async () => {
+        if (!pluginId) return;
         if (!confirm("Remove this plugin? All configuration will be lost.")) return;
         setDeleting(true);
         setError(null);
         try {
-            await deletePlugin(pluginType);
+            await deletePlugin(pluginId);
             router.push("/plugins");
         } catch (err) {
             setError(err instanceof Error ? err.message : "Failed to remove");
@@ -212,7 +239,7 @@ export default function PluginDetailPage() {
     };
 
     const fieldMap     = Object.fromEntries(fields.map((f) => [f.key, f]));
-    const singlesFilled = fields.filter((f) => !f.multiValue).every((f) => singleFields[f.key]?.trim());
+    const singlesFilled = name.trim() !== "" && fields.filter((f) => !f.multiValue).every((f) => singleFields[f.key]?.trim());
 
     return (
         <div className="w-full space-y-6">
@@ -231,7 +258,7 @@ export default function PluginDetailPage() {
                         <Icon className="h-5 w-5 text-muted-foreground" />
                     </div>
                     <div>
-                        <h1 className="text-lg font-bold tracking-tight leading-none">{def.name}</h1>
+                        <h1 className="text-lg font-bold tracking-tight leading-none">{configured ? name : def.name}</h1>
                         <div className="flex items-center gap-2 mt-1.5">
                             <span className="text-xs text-muted-foreground">{def.category}</span>
                             <span className="text-muted-foreground/40 text-xs">·</span>
@@ -271,6 +298,19 @@ export default function PluginDetailPage() {
                         </div>
                         <div className="px-5 py-5 space-y-5">
 
+                            {/* Instance name, to tell several of the same plugin apart */}
+                            <div className="space-y-1.5">
+                                <Label className="text-xs font-medium text-muted-foreground uppercase tracking-wide">
+                                    Name
+                                </Label>
+                                <Input
+                                    placeholder={def.name}
+                                    value={name}
+                                    onChange={(e) => setName(e.target.value)}
+                                    className="text-sm h-9"
+                                />
+                            </div>
+
                             {/* Render rows from layout spec */}
                             {layout.map((row, rowIdx) => (
                                 <div
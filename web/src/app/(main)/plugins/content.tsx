@@ -5,6 +5,7 @@ import { useRouter } from "next/navigation";
 import { usePluginStore, type Plugin } from "@/store/plugin-store";
 import { useTeamStore } from "@/store/team-store";
 import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
 import { ChevronRight, Loader2, Plus } from "lucide-react";
 import { PLUGIN_REGISTRY } from "./registry";
 export type { PluginDef } from "./registry";
@@ -86,48 +87,72 @@ export default function PluginsPage() {
             ) : (
                 <div className="rounded-xl border border-border overflow-hidden">
                     {PLUGIN_REGISTRY.map((def, idx) => {
-                        const live = plugins.find((p) => p.type === def.type);
+                        const instances = plugins.filter((p) => p.type === def.type);
                         const Icon = def.icon;
                         return (
                             <div
                                 key={def.type}
-                                onClick={() => router.push(`/plugins/${def.type}`)}
-                                className={`group flex items-center gap-4 px-5 py-4 cursor-pointer transition-colors hover:bg-muted/30 ${
-                                    idx !== PLUGIN_REGISTRY.length - 1 ? "border-b border-border" : ""
-                                }`}
+                                className={idx !== PLUGIN_REGISTRY.length - 1 ? "border-b border-border" : ""}
                             >
-                                {/* Icon */}
-                                <div className="flex h-10 w-10 shrink-0 items-center justify-center rounded-xl bg-muted border border-border">
-                                    <Icon className="h-5 w-5 text-muted-foreground" />
-                                </div>
-
-                                {/* Info */}
-                                <div className="flex-1 min-w-0">
-                                    <div className="flex items-center gap-2">
-                                        <p className="font-medium text-sm">{def.name}</p>
-                                        <Badge variant="outline" className="text-[10px] font-normal border-zinc-700 text-zinc-500">
-                                            {def.category}
-                                        </Badge>
+                                <div className="flex items-center gap-4 px-5 py-4">
+                                    {/* Icon */}
+                                    <div className="flex h-10 w-10 shrink-0 items-center justify-center rounded-xl bg-muted border border-border">
+                                        <Icon className="h-5 w-5 text-muted-foreground" />
                                     </div>
-                                    <p className="text-xs text-muted-foreground mt-0.5 truncate">{def.description}</p>
-                                </div>
 
-                                {/* Status */}
-                                <div className="shrink-0">
-                                    <PluginStatusBadge plugin={live} />
-                                </div>
+                                    {/* Info */}
+                                    <div className="flex-1 min-w-0">
+                                        <div className="flex items-center gap-2">
+                                            <p className="font-medium text-sm">{def.name}</p>
+                                            <Badge variant="outline" className="text-[10px] font-normal border-zinc-700 text-zinc-500">
+                                                {def.category}
+                                            </Badge>
+                                        </div>
+                                        <p className="text-xs text-muted-foreground mt-0.5 truncate">{def.description}</p>
+                                    </div>
 
-                                {/* Last updated */}
-                                <div className="shrink-0 text-xs text-muted-foreground w-24 text-right hidden md:block">
-                                    {live
-                                        ? new Date(live.updated_at).toLocaleDateString(undefined, {
-                                            month: "short", day: "numeric", year: "numeric",
-                                        })
-                                        : "—"}
+                                    {instances.length === 0 && (
+                                        <div className="shrink-0">
+                                            <PluginStatusBadge plugin={undefined} />
+                                        </div>
+                                    )}
+
+                                    {/* Add an instance */}
+                                    <Button
+                                        variant="outline"
+                                        size="sm"
+                                        onClick={() => router.push(`/plugins/${def.type}`)}
+                                        className="shrink-0 h-8 gap-1.5 text-xs"
+                                    >
+                                        <Plus className="h-3.5 w-3.5" /> Add
+                                    </Button>
                                 </div>
 
-                                {/* Arrow */}
-                                <ChevronRight className="h-4 w-4 text-muted-foreground shrink-0 transition-transform group-hover:translate-x-0.5" />
+                                {/* Configured instances */}
+                                {instances.map((p) => (
+                                    <div
+                                        key={p.id}
+                                        onClick={() => router.push(`/plugins/${def.type}?id=${p.id}`)}
+                                        className="group flex items-center gap-4 border-t border-border/50 pl-[4.75rem] pr-5 py-3 cursor-pointer transition-colors hover:bg-muted/30"
+                                    >
+                                        <p className="flex-1 min-w-0 truncate text-sm">{p.name}</p>
+
+                                        {/* Status */}
+                                        <div className="shrink-0">
+                                            <PluginStatusBadge plugin={p} />
+                                        </div>
+
+                                        {/* Last updated */}
+                                        <div className="shrink-0 text-xs text-muted-foreground w-24 text-right hidden md:block">
+                                            {new Date(p.updated_at).toLocaleDateString(undefined, {
+                                                month: "short", day: "numeric", year: "numeric",
+                                            })}
+                                        </div>
+
+                                        {/* Arrow */}
+                                        <ChevronRight className="h-4 w-4 text-muted-foreground shrink-0 transition-transform group-hover:translate-x-0.5" />
+                                    </div>
+                                ))}
                             </div>
                         );
                     })}
@@ -1,4 +1,4 @@
-import { BellRing, Mail } from "lucide-react";
+import { BellRing, Mail, MessageSquare, MessagesSquare, Send } from "lucide-react";
 import type { LucideIcon } from "lucide-react";
 
 export interface PluginDef {
@@ -29,4 +29,31 @@ export const PLUGIN_REGISTRY: PluginDef[] = [
         icon: BellRing,
         category: "Incident Management",
     },
+    {
+        type: "msteams",
+        name: "Microsoft Teams",
+        description: "Post alert cards to a Teams channel via an incoming webhook.",
+        longDescription:
+            "Sofon posts an Adaptive Card to your Microsoft Teams channel when a monitor goes down, and another one when it recovers. Create an incoming webhook (or a Workflows webhook) for the channel and paste its URL here.",
+        icon: MessagesSquare,
+        category: "Chat",
+    },
+    {
+        type: "discord",
+        name: "Discord",
+        description: "Post alert embeds to a Discord channel via a webhook.",
+        longDescription:
+            "Sofon posts a colour-coded embed to your Discord channel when a monitor goes down and when it recovers. Create a webhook under Channel Settings → Integrations and paste its URL here.",
+        icon: MessageSquare,
+        category: "Chat",
+    },
+    {
+        type: "telegram",
+        name: "Telegram",
+        description: "Send alert messages to a Telegram chat via a bot.",
+        longDescription:
+            "Sofon sends a message through your Telegram bot when a monitor goes down and when it recovers. Create a bot with @BotFather, add it to the chat, and enter the bot token and chat ID here.",
+        icon: Send,
+        category: "Chat",
+    },
 ];
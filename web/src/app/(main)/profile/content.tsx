@@ -1,7 +1,7 @@
 "use client";
 
 import { useEffect, useState } from "react";
-import { get, patch, post } from "@/service/api";
+import { get, patch, post, tokenStore } from "@/service/api";
 import { ENDPOINTS } from "@/service/endpoints";
 import { Button } from "@/components/ui/button";
 import { Input } from "@/components/ui/input";
@@ -80,10 +80,9 @@ export default function ProfilePage() {
                 new_password: newPassword,
                 confirm_password: confirmPassword,
             });
-            setCurrentPassword("");
-            setNewPassword("");
-            setConfirmPassword("");
-            setPasswordMsg({ text: "Password changed.", error: false });
+            // changing the password ends every session, this one included
+            tokenStore.clear();
+            window.location.href = "/signin";
         } catch (err) {
             const msg =
                 (err as AxiosError<{ message: string }>)?.response?.data?.message ??
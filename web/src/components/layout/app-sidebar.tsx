@@ -7,7 +7,7 @@ import {
     Activity, AlertTriangle, Users, UserCircle,
     LogOut, ChevronDown, Plus, Loader2, Check, Plug,
 } from "lucide-react";
-import { tokenStore } from "@/service/api";
+import { signOut } from "@/service/api";
 import { useTeamStore, type Team } from "@/store/team-store";
 import { useUserStore } from "@/store/user-store";
 import { useState } from "react";
@@ -217,8 +217,8 @@ export function AppSidebar() {
     const clearProfile = useUserStore((s) => s.clearProfile);
     const hasTeam = teams.length > 0;
 
-    const handleSignOut = () => {
-        tokenStore.clear();
+    const handleSignOut = async () => {
+        await signOut();
         clearProfile();
         router.push("/signin");
     };
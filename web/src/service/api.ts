@@ -1,15 +1,21 @@
-import axios, { AxiosError, type AxiosRequestConfig } from "axios";
+import axios, { AxiosError, type AxiosRequestConfig, type InternalAxiosRequestConfig } from "axios";
 import { env } from "@/lib/env";
+import { ENDPOINTS } from "@/service/endpoints";
 
 // ── Token helpers ───────────────────────────────────
 // Token lives in both localStorage (for JS reads) and a cookie (for middleware reads).
+// The refresh token only lives in localStorage; it trades for a new pair
+// when the short-lived access token expires.
 const TOKEN_KEY = "sofon_token";
+const REFRESH_TOKEN_KEY = "sofon_refresh_token";
 
 // Cookie is HttpOnly=false so JS can write it, but Secure + SameSite=Lax for safety.
-// Max-age matches the backend JWT TTL (30 min). Middleware reads this cookie.
+// Max-age matches the backend session lifetime (auth.refresh_ttl, 30 days):
+// an expired access token is refreshed, so the middleware only needs to know
+// that a session exists.
 function setCookie(value: string) {
     if (typeof document === "undefined") return;
-    document.cookie = `${TOKEN_KEY}=${value}; path=/; max-age=1800; SameSite=Lax`;
+    document.cookie = `${TOKEN_KEY}=${value}; path=/; max-age=2592000; SameSite=Lax`;
 }
 
 function clearCookie() {
@@ -21,19 +27,54 @@ export const tokenStore = {
     get: (): string | null =>
         typeof window !== "undefined" ? localStorage.getItem(TOKEN_KEY) : null,
 
-    set: (token: string) => {
+    getRefresh: (): string | null =>
+        typeof window !== "undefined" ? localStorage.getItem(REFRESH_TOKEN_KEY) : null,
+
+    set: (token: string, refreshToken: string) => {
         if (typeof window === "undefined") return;
         localStorage.setItem(TOKEN_KEY, token);
+        localStorage.setItem(REFRESH_TOKEN_KEY, refreshToken);
         setCookie(token);
     },
 
     clear: () => {
         if (typeof window === "undefined") return;
         localStorage.removeItem(TOKEN_KEY);
+        localStorage.removeItem(REFRESH_TOKEN_KEY);
         clearCookie();
     },
 };
 
+interface RefreshResponse {
+    data: {
+        access_token: string;
+        refresh_token: string;
+    };
+}
+
+// One refresh at a time: requests that 401 together wait for the same one,
+// since a refresh token is only good once.
+let refreshing: Promise<string | null> | null = null;
+
+function refreshAccessToken(): Promise<string | null> {
+    const refreshToken = tokenStore.getRefresh();
+    if (!refreshToken) return Promise.resolve(null);
+
+    if (!refreshing) {
+        refreshing = axios
+            .post<RefreshResponse>(`${env.API_URL}${ENDPOINTS.AUTH.REFRESH}`, { refresh_token: refreshToken })
+            .then((res) => {
+                tokenStore.set(res.data.data.access_token, res.data.data.refresh_token);
+                return res.data.data.access_token;
+            })
+            .catch(() => null)
+            .finally(() => {
+                refreshing = null;
+            });
+    }
+    return refreshing;
+}
+
 // ── Axios instance ──────────────────────────────────
 const api = axios.create({
     baseURL: env.API_URL,
@@ -50,14 +91,25 @@ api.interceptors.request.use((config) => {
     return config;
 });
 
-// Handle 401 globally — skip for auth endpoints (login/register handle their own errors)
+// Handle 401 globally — skip for auth endpoints (login/register handle their own errors).
+// An expired access token is refreshed once and the request retried; if that
+// fails too, the session is over.
 api.interceptors.response.use(
     (res) => res,
-    (error: AxiosError) => {
-        const url = error.config?.url || "";
+    async (error: AxiosError) => {
+        const config = error.config as (InternalAxiosRequestConfig & { _retried?: boolean }) | undefined;
+        const url = config?.url || "";
         const isAuthRoute = url.includes("/users/login") || url.includes("/users/register");
 
         if (error.response?.status === 401 && !isAuthRoute) {
+            if (config && !config._retried) {
+                const token = await refreshAccessToken();
+                if (token) {
+                    config._retried = true;
+                    config.headers.Authorization = `Bearer ${token}`;
+                    return api(config);
+                }
+            }
             tokenStore.clear();
             if (typeof window !== "undefined") {
                 window.location.href = "/signin";
@@ -67,6 +119,16 @@ api.interceptors.response.use(
     },
 );
 
+// signOut ends the session on the server too, so its refresh token can't be used again.
+export async function signOut() {
+    try {
+        await api.post(ENDPOINTS.AUTH.LOGOUT);
+    } catch {
+        // already expired or revoked: nothing left to end
+    }
+    tokenStore.clear();
+}
+
 // ── Generic methods ─────────────────────────────────
 export async function get<T>(url: string, config?: AxiosRequestConfig): Promise<T> {
     const res = await api.get<T>(url, config);
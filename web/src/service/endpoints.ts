@@ -9,6 +9,8 @@ export const ENDPOINTS = {
     // ── Auth ──────────────────────────────────────────
     AUTH: {
         LOGIN: "/api/v1/users/login",
+        REFRESH: "/api/v1/users/refresh",
+        LOGOUT: "/api/v1/users/logout",
         REGISTER: "/api/v1/users/register",
         SETUP_STATUS: "/api/v1/users/setup-status",
     },
@@ -52,8 +54,9 @@ export const ENDPOINTS = {
     // ── Plugins (team-scoped) ─────────────────────────
     PLUGINS: {
         LIST:   (teamId: string) => `/api/v1/teams/${teamId}/plugins`,
-        GET:    (teamId: string, type: string) => `/api/v1/teams/${teamId}/plugins/${type}`,
-        UPSERT: (teamId: string, type: string) => `/api/v1/teams/${teamId}/plugins/${type}`,
-        DELETE: (teamId: string, type: string) => `/api/v1/teams/${teamId}/plugins/${type}`,
+        CREATE: (teamId: string) => `/api/v1/teams/${teamId}/plugins`,
+        GET:    (teamId: string, id: string) => `/api/v1/teams/${teamId}/plugins/${id}`,
+        UPDATE: (teamId: string, id: string) => `/api/v1/teams/${teamId}/plugins/${id}`,
+        DELETE: (teamId: string, id: string) => `/api/v1/teams/${teamId}/plugins/${id}`,
     },
 } as const;
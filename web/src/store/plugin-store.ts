@@ -1,11 +1,12 @@
 import { create } from "zustand";
-import { get, put, del } from "@/service/api";
+import { get, post, put, del } from "@/service/api";
 import { ENDPOINTS } from "@/service/endpoints";
 import { useTeamStore } from "@/store/team-store";
 
 export interface Plugin {
     id: string;
     type: string;
+    name: string;
     enabled: boolean;
     config?: Record<string, string>;
     updated_at: string;
@@ -28,9 +29,10 @@ interface PluginState {
     error: string | null;
 
     fetchPlugins: () => Promise<void>;
-    fetchPlugin: (type: string) => Promise<Plugin | null>;
-    upsertPlugin: (type: string, enabled: boolean, config: Record<string, string>) => Promise<Plugin>;
-    deletePlugin: (type: string) => Promise<void>;
+    fetchPlugin: (id: string) => Promise<Plugin | null>;
+    createPlugin: (type: string, name: string, enabled: boolean, config: Record<string, string>) => Promise<Plugin>;
+    updatePlugin: (id: string, name: string, enabled: boolean, config: Record<string, string>) => Promise<Plugin>;
+    deletePlugin: (id: string) => Promise<void>;
 }
 
 function currentTeamId(): string | null {
@@ -55,31 +57,48 @@ export const usePluginStore = create<PluginState>((set, getState) => ({
         }
     },
 
-    fetchPlugin: async (type: string) => {
+    fetchPlugin: async (id: string) => {
         const teamId = currentTeamId();
         if (!teamId) return null;
         try {
-            const res = await get<PluginSingleResponse>(ENDPOINTS.PLUGINS.GET(teamId, type));
+            const res = await get<PluginSingleResponse>(ENDPOINTS.PLUGINS.GET(teamId, id));
             return res.data;
         } catch {
             return null;
         }
     },
 
-    upsertPlugin: async (type: string, enabled: boolean, config: Record<string, string>) => {
+    createPlugin: async (type: string, name: string, enabled: boolean, config: Record<string, string>) => {
+        const teamId = currentTeamId();
+        if (!teamId) throw new Error("No team selected");
+        set({ saving: true, error: null });
+        try {
+            const res = await post<PluginSingleResponse>(
+                ENDPOINTS.PLUGINS.CREATE(teamId),
+                { type, name, enabled, config },
+            );
+            const created = res.data;
+            set((state) => ({ plugins: [...state.plugins, created], saving: false }));
+            return created;
+        } catch (err: unknown) {
+            const msg = err instanceof Error ? err.message : "Failed to save plugin";
+            set({ saving: false, error: msg });
+            throw err;
+        }
+    },
+
+    updatePlugin: async (id: string, name: string, enabled: boolean, config: Record<string, string>) => {
         const teamId = currentTeamId();
         if (!teamId) throw new Error("No team selected");
         set({ saving: true, error: null });
         try {
             const res = await put<PluginSingleResponse>(
-                ENDPOINTS.PLUGINS.UPSERT(teamId, type),
-                { enabled, config },
+                ENDPOINTS.PLUGINS.UPDATE(teamId, id),
+                { name, enabled, config },
             );
             const updated = res.data;
             set((state) => ({
-                plugins: state.plugins.some((p) => p.type === type)
-                    ? state.plugins.map((p) => (p.type === type ? updated : p))
-                    : [...state.plugins, updated],
+                plugins: state.plugins.map((p) => (p.id === id ? updated : p)),
                 saving: false,
             }));
             return updated;
@@ -90,12 +109,12 @@ export const usePluginStore = create<PluginState>((set, getState) => ({
         }
     },
 
-    deletePlugin: async (type: string) => {
+    deletePlugin: async (id: string) => {
         const teamId = currentTeamId();
         if (!teamId) throw new Error("No team selected");
         try {
-            await del(ENDPOINTS.PLUGINS.DELETE(teamId, type));
-            set((state) => ({ plugins: state.plugins.filter((p) => p.type !== type) }));
+            await del(ENDPOINTS.PLUGINS.DELETE(teamId, id));
+            set((state) => ({ plugins: state.plugins.filter((p) => p.id !== id) }));
         } catch (err: unknown) {
             const msg = err instanceof Error ? err.message : "Failed to delete plugin";
             set({ error: msg });